@@ -0,0 +1,134 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// aclPath returns the API path of the access control list of the object of the specified kind
+// (ie. "collections" or "containers") identified by ref.
+func aclPath(kind, ref string) string {
+	return "v1/" + kind + "/" + ref + "/_acl"
+}
+
+// getACL returns the access control list of the object of the specified kind identified by ref.
+func (c *Client) getACL(ctx context.Context, kind, ref string) ([]ACLEntry, error) {
+	b, err := c.apiGet(ctx, aclPath(kind, ref))
+	if err != nil {
+		return nil, err
+	}
+	var res ACLResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("error decoding access control list: %v", err)
+	}
+	return res.Data, nil
+}
+
+// setACL replaces the access control list of the object of the specified kind identified by ref.
+func (c *Client) setACL(ctx context.Context, kind, ref string, acl []ACLEntry) error {
+	for _, e := range acl {
+		if err := e.validate(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := c.apiUpdate(ctx, aclPath(kind, ref), ACLRequest{Entries: acl}); err != nil {
+		return fmt.Errorf("error setting access control list: %w", err)
+	}
+	return nil
+}
+
+// grantAccess adds (or replaces) the access granted by e to the object of the specified kind
+// identified by ref.
+func (c *Client) grantAccess(ctx context.Context, kind, ref string, e ACLEntry) error {
+	if err := e.validate(); err != nil {
+		return err
+	}
+
+	if _, err := c.apiCreate(ctx, aclPath(kind, ref), e); err != nil {
+		return fmt.Errorf("error granting access: %w", err)
+	}
+	return nil
+}
+
+// revokeAccess revokes any access granted to the specified principal to the object of the
+// specified kind identified by ref.
+func (c *Client) revokeAccess(ctx context.Context, kind, ref string, pt PrincipalType, principal string) error {
+	if pt == "" || principal == "" {
+		return errors.New("principal type and principal are required")
+	}
+
+	path := aclPath(kind, ref) + "/" + url.PathEscape(string(pt)) + "/" + url.PathEscape(principal)
+
+	if _, err := c.doDeleteRequest(ctx, path); err != nil {
+		return fmt.Errorf("error revoking access: %w", err)
+	}
+	return nil
+}
+
+// validate returns an error if e is incomplete.
+func (e ACLEntry) validate() error {
+	if e.PrincipalType == "" || e.Principal == "" {
+		return errors.New("principal type and principal are required")
+	}
+	if e.Access != AccessRead && e.Access != AccessWrite {
+		return fmt.Errorf("invalid access level %q", e.Access)
+	}
+	return nil
+}
+
+// GetCollectionACL returns the access control list of the collection identified by ref (ie.
+// "entity/collection"). Returns ErrNotFound if the collection is not found.
+func (c *Client) GetCollectionACL(ctx context.Context, ref string) ([]ACLEntry, error) {
+	return c.getACL(ctx, "collections", ref)
+}
+
+// SetCollectionACL replaces the access control list of the collection identified by ref (ie.
+// "entity/collection") with acl.
+func (c *Client) SetCollectionACL(ctx context.Context, ref string, acl []ACLEntry) error {
+	return c.setACL(ctx, "collections", ref, acl)
+}
+
+// GrantCollectionAccess grants the access described by e to the collection identified by ref (ie.
+// "entity/collection"), replacing any access previously granted to the same principal.
+func (c *Client) GrantCollectionAccess(ctx context.Context, ref string, e ACLEntry) error {
+	return c.grantAccess(ctx, "collections", ref, e)
+}
+
+// RevokeCollectionAccess revokes any access granted to the principal of type pt to the collection
+// identified by ref (ie. "entity/collection").
+func (c *Client) RevokeCollectionAccess(ctx context.Context, ref string, pt PrincipalType, principal string) error {
+	return c.revokeAccess(ctx, "collections", ref, pt, principal)
+}
+
+// GetContainerACL returns the access control list of the container identified by ref (ie.
+// "entity/collection/container"). Returns ErrNotFound if the container is not found.
+func (c *Client) GetContainerACL(ctx context.Context, ref string) ([]ACLEntry, error) {
+	return c.getACL(ctx, "containers", ref)
+}
+
+// SetContainerACL replaces the access control list of the container identified by ref (ie.
+// "entity/collection/container") with acl.
+func (c *Client) SetContainerACL(ctx context.Context, ref string, acl []ACLEntry) error {
+	return c.setACL(ctx, "containers", ref, acl)
+}
+
+// GrantContainerAccess grants the access described by e to the container identified by ref (ie.
+// "entity/collection/container"), replacing any access previously granted to the same principal.
+func (c *Client) GrantContainerAccess(ctx context.Context, ref string, e ACLEntry) error {
+	return c.grantAccess(ctx, "containers", ref, e)
+}
+
+// RevokeContainerAccess revokes any access granted to the principal of type pt to the container
+// identified by ref (ie. "entity/collection/container").
+func (c *Client) RevokeContainerAccess(ctx context.Context, ref string, pt PrincipalType, principal string) error {
+	return c.revokeAccess(ctx, "containers", ref, pt, principal)
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func TestACL(t *testing.T) {
+	acl := []ACLEntry{
+		{PrincipalType: PrincipalUser, Principal: "alice", Access: AccessWrite},
+		{PrincipalType: PrincipalTeam, Principal: "consumers", Access: AccessRead},
+	}
+
+	tests := []struct {
+		name       string
+		fn         func(c *Client) error
+		wantMethod string
+		wantPath   string
+		wantBody   interface{}
+		wantErr    bool
+	}{
+		{
+			name: "GetCollectionACL",
+			fn: func(c *Client) error {
+				got, err := c.GetCollectionACL(context.Background(), "entity/collection")
+				if err == nil && !reflect.DeepEqual(got, acl) {
+					t.Errorf("got ACL %+v, want %+v", got, acl)
+				}
+				return err
+			},
+			wantMethod: http.MethodGet,
+			wantPath:   "/v1/collections/entity/collection/_acl",
+		},
+		{
+			name: "SetCollectionACL",
+			fn: func(c *Client) error {
+				return c.SetCollectionACL(context.Background(), "entity/collection", acl)
+			},
+			wantMethod: http.MethodPut,
+			wantPath:   "/v1/collections/entity/collection/_acl",
+			wantBody:   &ACLRequest{Entries: acl},
+		},
+		{
+			name: "GrantCollectionAccess",
+			fn: func(c *Client) error {
+				return c.GrantCollectionAccess(context.Background(), "entity/collection", acl[1])
+			},
+			wantMethod: http.MethodPost,
+			wantPath:   "/v1/collections/entity/collection/_acl",
+			wantBody:   &acl[1],
+		},
+		{
+			name: "RevokeCollectionAccess",
+			fn: func(c *Client) error {
+				return c.RevokeCollectionAccess(context.Background(), "entity/collection", PrincipalTeam, "consumers")
+			},
+			wantMethod: http.MethodDelete,
+			wantPath:   "/v1/collections/entity/collection/_acl/team/consumers",
+		},
+		{
+			name: "GetContainerACL",
+			fn: func(c *Client) error {
+				got, err := c.GetContainerACL(context.Background(), "entity/collection/container")
+				if err == nil && !reflect.DeepEqual(got, acl) {
+					t.Errorf("got ACL %+v, want %+v", got, acl)
+				}
+				return err
+			},
+			wantMethod: http.MethodGet,
+			wantPath:   "/v1/containers/entity/collection/container/_acl",
+		},
+		{
+			name: "SetContainerACL",
+			fn: func(c *Client) error {
+				return c.SetContainerACL(context.Background(), "entity/collection/container", acl)
+			},
+			wantMethod: http.MethodPut,
+			wantPath:   "/v1/containers/entity/collection/container/_acl",
+			wantBody:   &ACLRequest{Entries: acl},
+		},
+		{
+			name: "GrantContainerAccess",
+			fn: func(c *Client) error {
+				return c.GrantContainerAccess(context.Background(), "entity/collection/container", acl[0])
+			},
+			wantMethod: http.MethodPost,
+			wantPath:   "/v1/containers/entity/collection/container/_acl",
+			wantBody:   &acl[0],
+		},
+		{
+			name: "RevokeContainerAccess",
+			fn: func(c *Client) error {
+				return c.RevokeContainerAccess(context.Background(), "entity/collection/container", PrincipalUser, "alice")
+			},
+			wantMethod: http.MethodDelete,
+			wantPath:   "/v1/containers/entity/collection/container/_acl/user/alice",
+		},
+		{
+			name: "GrantInvalidAccess",
+			fn: func(c *Client) error {
+				return c.GrantContainerAccess(context.Background(), "entity/collection/container", ACLEntry{PrincipalType: PrincipalUser, Principal: "alice", Access: "admin"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "RevokeMissingPrincipal",
+			fn: func(c *Client) error {
+				return c.RevokeContainerAccess(context.Background(), "entity/collection/container", PrincipalUser, "")
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.wantErr {
+					t.Errorf("unexpected request to %v", r.URL.Path)
+				}
+				if got, want := r.Method, tt.wantMethod; got != want {
+					t.Errorf("got method %v, want %v", got, want)
+				}
+				if got, want := r.URL.Path, tt.wantPath; got != want {
+					t.Errorf("got path %v, want %v", got, want)
+				}
+
+				if tt.wantBody != nil {
+					got := reflect.New(reflect.TypeOf(tt.wantBody).Elem()).Interface()
+					if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+						t.Errorf("error decoding request: %v", err)
+					}
+					if !reflect.DeepEqual(got, tt.wantBody) {
+						t.Errorf("got body %+v, want %+v", got, tt.wantBody)
+					}
+				}
+
+				if err := jsonresp.WriteResponse(w, acl, http.StatusOK); err != nil {
+					t.Errorf("error writing JSON response: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			if err := tt.fn(c); (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetACLNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	if _, err := c.GetCollectionACL(context.Background(), "entity/collection"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, ErrNotFound)
+	}
+}
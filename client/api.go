@@ -12,13 +12,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-
-	jsonresp "github.com/sylabs/json-resp"
 )
 
-// getEntity returns the specified entity; returns ErrNotFound if entity is not
+// GetEntity returns the specified entity; returns ErrNotFound if entity is not
 // found, otherwise error
-func (c *Client) getEntity(ctx context.Context, entityRef string) (*Entity, error) {
+func (c *Client) GetEntity(ctx context.Context, entityRef string) (*Entity, error) {
 	entJSON, err := c.apiGet(ctx, "v1/entities/"+entityRef)
 	if err != nil {
 		return nil, err
@@ -30,9 +28,9 @@ func (c *Client) getEntity(ctx context.Context, entityRef string) (*Entity, erro
 	return &res.Data, nil
 }
 
-// getCollection returns the specified collection; returns ErrNotFound if
-// collection is not found, otherwise error.
-func (c *Client) getCollection(ctx context.Context, collectionRef string) (*Collection, error) {
+// GetCollection returns the specified collection (ie. "entity/collection"); returns ErrNotFound
+// if collection is not found, otherwise error.
+func (c *Client) GetCollection(ctx context.Context, collectionRef string) (*Collection, error) {
 	colJSON, err := c.apiGet(ctx, "v1/collections/"+collectionRef)
 	if err != nil {
 		return nil, err
@@ -44,9 +42,9 @@ func (c *Client) getCollection(ctx context.Context, collectionRef string) (*Coll
 	return &res.Data, nil
 }
 
-// getContainer returns container by ref id; returns ErrNotFound if container
-// is not found, otherwise error.
-func (c *Client) getContainer(ctx context.Context, containerRef string) (*Container, error) {
+// GetContainer returns container by ref (ie. "entity/collection/container"); returns ErrNotFound
+// if container is not found, otherwise error.
+func (c *Client) GetContainer(ctx context.Context, containerRef string) (*Container, error) {
 	conJSON, err := c.apiGet(ctx, "v1/containers/"+containerRef)
 	if err != nil {
 		return nil, err
@@ -93,12 +91,14 @@ func (c *Client) createCollection(ctx context.Context, name string, entityID str
 	return &res.Data, nil
 }
 
-// createContainer creates a container in the specified collection
-func (c *Client) createContainer(ctx context.Context, name string, collectionID string) (*Container, error) {
+// createContainer creates a container in the specified collection, which is private if private
+// is set.
+func (c *Client) createContainer(ctx context.Context, name string, collectionID string, private bool) (*Container, error) {
 	newContainer := Container{
 		Name:        name,
 		Description: "No description",
 		Collection:  collectionID,
+		Private:     private,
 	}
 	conJSON, err := c.apiCreate(ctx, "v1/containers", newContainer)
 	if err != nil {
@@ -112,11 +112,12 @@ func (c *Client) createContainer(ctx context.Context, name string, collectionID
 }
 
 // createImage creates a new image
-func (c *Client) createImage(ctx context.Context, hash string, containerID string, description string) (*Image, error) {
+func (c *Client) createImage(ctx context.Context, hash string, containerID string, description string, labels map[string]string) (*Image, error) {
 	i := Image{
 		Hash:        hash,
 		Description: description,
 		Container:   containerID,
+		Labels:      labels,
 	}
 	imgJSON, err := c.apiCreate(ctx, "v1/images", i)
 	if err != nil {
@@ -129,31 +130,36 @@ func (c *Client) createImage(ctx context.Context, hash string, containerID strin
 	return &res.Data, nil
 }
 
-// setTags applies tags to the specified container
-func (c *Client) setTags(ctx context.Context, containerID, imageID string, tags []string) error {
+// setTags applies tags to the specified container, and returns the changes made. If replace is
+// false and any tag refers to another image, no tags are applied.
+func (c *Client) setTags(ctx context.Context, containerID, imageID string, tags []string, replace bool) ([]TagChange, error) {
 	// Get existing tags, so we know which will be replaced
 	existingTags, err := c.getTags(ctx, containerID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	changes, err := planTagChanges("", imageID, tags, existingTags, replace)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, tag := range tags {
-		c.logger.Logf("Setting tag %s", tag)
+	var tr tagResults
+	applied := make([]TagChange, 0, len(changes))
 
-		if _, ok := existingTags[tag]; ok {
-			c.logger.Logf("%s replaces an existing tag", tag)
-		}
+	for _, tc := range changes {
+		c.logTagChange(tc)
 
 		imgTag := ImageTag{
-			tag,
+			tc.Tag,
 			imageID,
 		}
 		err := c.setTag(ctx, containerID, imgTag)
-		if err != nil {
-			return err
+		if tr.add(tc.Arch, tc.Tag, err); err == nil {
+			applied = append(applied, tc)
 		}
 	}
-	return nil
+	return applied, tr.err()
 }
 
 // getTags returns a tag map for the specified containerID
@@ -164,22 +170,21 @@ func (c *Client) getTags(ctx context.Context, containerID string) (TagMap, error
 	if err != nil {
 		return nil, fmt.Errorf("error creating request to server:\n\t%v", err)
 	}
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("error making request to server:\n\t%v", err)
 	}
 	defer res.Body.Close()
+	if res.StatusCode == http.StatusUnauthorized {
+		return nil, c.unauthorizedError(res)
+	}
 	if res.StatusCode != http.StatusOK {
-		err := jsonresp.ReadError(res.Body)
-		if err != nil {
-			return nil, fmt.Errorf("creation did not succeed: %v", err)
-		}
-		return nil, fmt.Errorf("unexpected http status code: %d", res.StatusCode)
+		return nil, responseError(res, "error getting tags")
 	}
 	var tagRes TagsResponse
-	err = json.NewDecoder(res.Body).Decode(&tagRes)
+	err = json.NewDecoder(limitResponse(res.Body, c.maxResponseSize)).Decode(&tagRes)
 	if err != nil {
-		return nil, fmt.Errorf("error decoding tags: %v", err)
+		return nil, fmt.Errorf("error decoding tags: %w", err)
 	}
 	return tagRes.Data, nil
 }
@@ -196,47 +201,54 @@ func (c *Client) setTag(ctx context.Context, containerID string, t ImageTag) err
 	if err != nil {
 		return fmt.Errorf("error creating POST request:\n\t%v", err)
 	}
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("error making request to server:\n\t%v", err)
 	}
 	defer res.Body.Close()
+	if res.StatusCode == http.StatusUnauthorized {
+		return c.unauthorizedError(res)
+	}
+	if err := modificationError(res); err != nil {
+		return err
+	}
 	if res.StatusCode != http.StatusOK {
-		err := jsonresp.ReadError(res.Body)
-		if err != nil {
-			return fmt.Errorf("creation did not succeed: %v", err)
-		}
-		return fmt.Errorf("creation did not succeed: http status code: %d", res.StatusCode)
+		return responseError(res, "creation did not succeed")
 	}
 	return nil
 }
 
-// setTags applies tags to the specified container
-func (c *Client) setTagsV2(ctx context.Context, containerID, arch string, imageID string, tags []string) error {
+// setTagsV2 applies tags to the specified container for arch, and returns the changes made. If
+// replace is false and any tag refers to another image, no tags are applied.
+func (c *Client) setTagsV2(ctx context.Context, containerID, arch string, imageID string, tags []string, replace bool) ([]TagChange, error) {
 	// Get existing tags, so we know which will be replaced
 	existingTags, err := c.getTagsV2(ctx, containerID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, tag := range tags {
-		c.logger.Logf("Setting tag %s", tag)
+	changes, err := planTagChanges(arch, imageID, tags, existingTags[arch], replace)
+	if err != nil {
+		return nil, err
+	}
 
-		if _, ok := existingTags[arch][tag]; ok {
-			c.logger.Logf("%s replaces an existing tag for arch %s", tag, arch)
-		}
+	var tr tagResults
+	applied := make([]TagChange, 0, len(changes))
+
+	for _, tc := range changes {
+		c.logTagChange(tc)
 
 		imgTag := ArchImageTag{
 			Arch:    arch,
-			Tag:     tag,
+			Tag:     tc.Tag,
 			ImageID: imageID,
 		}
 		err := c.setTagV2(ctx, containerID, imgTag)
-		if err != nil {
-			return err
+		if tr.add(tc.Arch, tc.Tag, err); err == nil {
+			applied = append(applied, tc)
 		}
 	}
-	return nil
+	return applied, tr.err()
 }
 
 // getTagsV2 returns a arch->tag map for the specified containerID
@@ -247,22 +259,21 @@ func (c *Client) getTagsV2(ctx context.Context, containerID string) (ArchTagMap,
 	if err != nil {
 		return nil, fmt.Errorf("error creating request to server:\n\t%v", err)
 	}
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("error making request to server:\n\t%v", err)
 	}
 	defer res.Body.Close()
+	if res.StatusCode == http.StatusUnauthorized {
+		return nil, c.unauthorizedError(res)
+	}
 	if res.StatusCode != http.StatusOK {
-		err := jsonresp.ReadError(res.Body)
-		if err != nil {
-			return nil, fmt.Errorf("creation did not succeed: %v", err)
-		}
-		return nil, fmt.Errorf("unexpected http status code: %d", res.StatusCode)
+		return nil, responseError(res, "error getting tags")
 	}
 	var tagRes ArchTagsResponse
-	err = json.NewDecoder(res.Body).Decode(&tagRes)
+	err = json.NewDecoder(limitResponse(res.Body, c.maxResponseSize)).Decode(&tagRes)
 	if err != nil {
-		return nil, fmt.Errorf("error decoding tags: %v", err)
+		return nil, fmt.Errorf("error decoding tags: %w", err)
 	}
 	return tagRes.Data, nil
 }
@@ -279,17 +290,19 @@ func (c *Client) setTagV2(ctx context.Context, containerID string, t ArchImageTa
 	if err != nil {
 		return fmt.Errorf("error creating POST request:\n\t%v", err)
 	}
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("error making request to server:\n\t%v", err)
 	}
 	defer res.Body.Close()
+	if res.StatusCode == http.StatusUnauthorized {
+		return c.unauthorizedError(res)
+	}
+	if err := modificationError(res); err != nil {
+		return err
+	}
 	if res.StatusCode != http.StatusOK {
-		err := jsonresp.ReadError(res.Body)
-		if err != nil {
-			return fmt.Errorf("creation did not succeed: %v", err)
-		}
-		return fmt.Errorf("creation did not succeed: http status code: %d", res.StatusCode)
+		return responseError(res, "creation did not succeed")
 	}
 	return nil
 }
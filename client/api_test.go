@@ -193,7 +193,7 @@ func (m *mockService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func Test_getEntity(t *testing.T) {
+func TestGetEntity(t *testing.T) {
 	tests := []struct {
 		description  string
 		code         int
@@ -255,7 +255,7 @@ func Test_getEntity(t *testing.T) {
 				t.Errorf("Error initializing client: %v", err)
 			}
 
-			entity, err := c.getEntity(context.Background(), tt.entityRef)
+			entity, err := c.GetEntity(context.Background(), tt.entityRef)
 
 			if err != nil && !tt.expectError {
 				t.Errorf("Unexpected error: %v", err)
@@ -273,7 +273,7 @@ func Test_getEntity(t *testing.T) {
 	}
 }
 
-func Test_getCollection(t *testing.T) {
+func TestGetCollection(t *testing.T) {
 	tests := []struct {
 		description      string
 		code             int
@@ -335,7 +335,7 @@ func Test_getCollection(t *testing.T) {
 				t.Errorf("Error initializing client: %v", err)
 			}
 
-			collection, err := c.getCollection(context.Background(), tt.collectionRef)
+			collection, err := c.GetCollection(context.Background(), tt.collectionRef)
 
 			if err != nil && !tt.expectError {
 				t.Errorf("Unexpected error: %v", err)
@@ -353,7 +353,7 @@ func Test_getCollection(t *testing.T) {
 	}
 }
 
-func Test_getContainer(t *testing.T) {
+func TestGetContainer(t *testing.T) {
 	tests := []struct {
 		description     string
 		code            int
@@ -415,7 +415,7 @@ func Test_getContainer(t *testing.T) {
 				t.Errorf("Error initializing client: %v", err)
 			}
 
-			container, err := c.getContainer(context.Background(), tt.containerRef)
+			container, err := c.GetContainer(context.Background(), tt.containerRef)
 
 			if err != nil && !tt.expectError {
 				t.Errorf("Unexpected error: %v", err)
@@ -672,7 +672,7 @@ func Test_createContainer(t *testing.T) {
 				t.Errorf("Error initializing client: %v", err)
 			}
 
-			container, err := c.createContainer(context.Background(), tt.containerRef, "5cb9c34d7d960d82f5f5bc51")
+			container, err := c.createContainer(context.Background(), tt.containerRef, "5cb9c34d7d960d82f5f5bc51", false)
 
 			if err != nil && !tt.expectError {
 				t.Errorf("Unexpected error: %v", err)
@@ -734,7 +734,7 @@ func Test_createImage(t *testing.T) {
 				t.Errorf("Error initializing client: %v", err)
 			}
 
-			image, err := c.createImage(context.Background(), tt.imageRef, "5cb9c34d7d960d82f5f5bc52", "No Description")
+			image, err := c.createImage(context.Background(), tt.imageRef, "5cb9c34d7d960d82f5f5bc52", "No Description", nil)
 
 			if err != nil && !tt.expectError {
 				t.Errorf("Unexpected error: %v", err)
@@ -795,7 +795,7 @@ func Test_setTags(t *testing.T) {
 				t.Errorf("Error initializing client: %v", err)
 			}
 
-			err = c.setTags(context.Background(), tt.containerRef, tt.imageRef, tt.tags)
+			_, err = c.setTags(context.Background(), tt.containerRef, tt.imageRef, tt.tags, true)
 
 			if err != nil && !tt.expectError {
 				t.Errorf("Unexpected error: %v", err)
@@ -909,7 +909,7 @@ func Test_setTagsV2(t *testing.T) {
 				t.Errorf("Error initializing client: %v", err)
 			}
 
-			if err := c.setTagsV2(context.Background(), tt.containerRef, tt.imageRef, tt.arch, tt.tags); (err != nil) != tt.expectError {
+			if _, err := c.setTagsV2(context.Background(), tt.containerRef, tt.imageRef, tt.arch, tt.tags, true); (err != nil) != tt.expectError {
 				t.Fatalf("unexpected error: %v", err)
 				return
 			}
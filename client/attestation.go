@@ -0,0 +1,240 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Media types of attestations that may be associated with an image.
+const (
+	// MediaTypeInTotoStatement is the media type of an in-toto statement.
+	MediaTypeInTotoStatement = "application/vnd.in-toto+json"
+	// MediaTypeDSSEEnvelope is the media type of a DSSE envelope containing a signed in-toto
+	// statement.
+	MediaTypeDSSEEnvelope = "application/vnd.dsse.envelope.v1+json"
+)
+
+// PredicateTypeSLSAProvenance is the predicate type of a SLSA v1 provenance attestation.
+const PredicateTypeSLSAProvenance = "https://slsa.dev/provenance/v1"
+
+// annotationPredicateType is the annotation that records the predicate type of an attestation.
+const annotationPredicateType = "in-toto.io/predicate-type"
+
+// maxAttestationSize is the maximum size of an attestation.
+const maxAttestationSize = 16 * 1024 * 1024
+
+// errAttestationsNotSupported is returned when attestations are used without direct OCI registry
+// access.
+var errAttestationsNotSupported = errors.New("attestations require OCI registry access")
+
+// AttestationInfo describes an attestation associated with an image.
+type AttestationInfo struct {
+	// Digest is the digest of the attestation manifest, which identifies the attestation.
+	Digest digest.Digest
+	// MediaType is the media type of the attestation (ie. MediaTypeInTotoStatement).
+	MediaType string
+	// PredicateType is the predicate type of the in-toto statement (ie.
+	// PredicateTypeSLSAProvenance).
+	PredicateType string
+	// Created is the time at which the attestation was attached, if known.
+	Created time.Time
+}
+
+// Attestation is an attestation associated with an image.
+type Attestation struct {
+	AttestationInfo
+	// Content is the in-toto statement, or DSSE envelope.
+	Content []byte
+}
+
+// AttachAttestation associates the attestation read from r, of type mediaType (ie.
+// MediaTypeInTotoStatement), with the image identified by ref (ie.
+// "entity/collection/container:tag" or "entity/collection/container@sha256:..."). The attestation
+// is uploaded to the OCI registry as an artifact referring to the image, annotated with its
+// predicate type. Attestations require direct OCI registry access.
+func (c *Client) AttachAttestation(ctx context.Context, ref, mediaType string, r io.Reader) (*AttestationInfo, error) {
+	b, err := io.ReadAll(io.LimitReader(r, maxAttestationSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading attestation: %w", err)
+	}
+	if len(b) > maxAttestationSize {
+		return nil, fmt.Errorf("attestation exceeds maximum size of %d bytes", maxAttestationSize)
+	}
+
+	predicateType, err := attestationPredicateType(mediaType, b)
+	if err != nil {
+		return nil, err
+	}
+
+	reg, creds, name, err := c.attestationRegistry(ctx, ref, accessTypePull, accessTypePush)
+	if err != nil {
+		return nil, err
+	}
+	_, reference := artifactRef(ref)
+
+	subject, err := reg.describeManifest(ctx, creds, name, reference)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving image: %w", err)
+	}
+
+	created := time.Now().UTC().Truncate(time.Second)
+
+	annotations := map[string]string{
+		v1.AnnotationCreated:    created.Format(time.RFC3339),
+		annotationPredicateType: predicateType,
+	}
+
+	d, err := reg.uploadArtifact(ctx, creds, name, mediaType, subject, mediaType, b, annotations)
+	if err != nil {
+		return nil, fmt.Errorf("error uploading attestation: %w", err)
+	}
+
+	c.logger.Logf("Attached %v attestation %v to %v", predicateType, d, subject.Digest)
+
+	return &AttestationInfo{
+		Digest:        d,
+		MediaType:     mediaType,
+		PredicateType: predicateType,
+		Created:       created,
+	}, nil
+}
+
+// ListAttestations returns the attestations associated with the image identified by ref (ie.
+// "entity/collection/container:tag" or "entity/collection/container@sha256:..."). If
+// predicateType is not empty, only attestations with that predicate type are returned.
+// Attestations require direct OCI registry access.
+func (c *Client) ListAttestations(ctx context.Context, ref, predicateType string) ([]AttestationInfo, error) {
+	reg, creds, name, err := c.attestationRegistry(ctx, ref, accessTypePull)
+	if err != nil {
+		return nil, err
+	}
+	_, reference := artifactRef(ref)
+
+	subject, err := reg.resolveManifest(ctx, creds, name, reference)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving image: %w", err)
+	}
+
+	var as []AttestationInfo
+
+	for _, mediaType := range []string{MediaTypeInTotoStatement, MediaTypeDSSEEnvelope} {
+		ds, err := reg.listReferrers(ctx, creds, name, subject, mediaType)
+		if err != nil {
+			return nil, fmt.Errorf("error listing referrers: %w", err)
+		}
+
+		for _, d := range ds {
+			a := attestationInfo(d)
+			if predicateType == "" || a.PredicateType == predicateType {
+				as = append(as, a)
+			}
+		}
+	}
+	return as, nil
+}
+
+// GetAttestation returns the attestation with digest d associated with the image identified by
+// ref (ie. "entity/collection/container:tag"), as returned by ListAttestations. Attestations
+// require direct OCI registry access.
+func (c *Client) GetAttestation(ctx context.Context, ref string, d digest.Digest) (*Attestation, error) {
+	reg, creds, name, err := c.attestationRegistry(ctx, ref, accessTypePull)
+	if err != nil {
+		return nil, err
+	}
+
+	l, b, err := reg.downloadArtifact(ctx, creds, name, d, maxAttestationSize)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading attestation: %w", err)
+	}
+
+	predicateType, err := attestationPredicateType(l.MediaType, b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Attestation{
+		AttestationInfo: AttestationInfo{
+			Digest:        d,
+			MediaType:     l.MediaType,
+			PredicateType: predicateType,
+		},
+		Content: b,
+	}, nil
+}
+
+// attestationRegistry returns the OCI registry, credentials and (optionally) remapped name of the
+// container identified by ref, with the specified access.
+func (c *Client) attestationRegistry(ctx context.Context, ref string, accessTypes ...accessType) (*ociRegistry, credentials, string, error) {
+	name, _ := artifactRef(ref)
+
+	reg, creds, name, err := c.newOCIRegistry(ctx, name, accessTypes)
+	if err != nil {
+		if errors.Is(err, errOCIDownloadNotSupported) {
+			return nil, nil, "", errAttestationsNotSupported
+		}
+		return nil, nil, "", err
+	}
+	return reg, creds, name, nil
+}
+
+// attestationInfo returns an AttestationInfo describing the attestation referrer d.
+func attestationInfo(d v1.Descriptor) AttestationInfo {
+	a := AttestationInfo{
+		Digest:        d.Digest,
+		MediaType:     d.ArtifactType,
+		PredicateType: d.Annotations[annotationPredicateType],
+	}
+	if t, err := time.Parse(time.RFC3339, d.Annotations[v1.AnnotationCreated]); err == nil {
+		a.Created = t
+	}
+	return a
+}
+
+// attestationPredicateType returns the predicate type of attestation b of type mediaType.
+func attestationPredicateType(mediaType string, b []byte) (string, error) {
+	switch mediaType {
+	case MediaTypeInTotoStatement:
+	case MediaTypeDSSEEnvelope:
+		var env struct {
+			PayloadType string `json:"payloadType"`
+			Payload     string `json:"payload"`
+		}
+		if err := json.Unmarshal(b, &env); err != nil {
+			return "", fmt.Errorf("error decoding DSSE envelope: %w", err)
+		}
+		if env.PayloadType != MediaTypeInTotoStatement {
+			return "", fmt.Errorf("unsupported DSSE payload type: %v", env.PayloadType)
+		}
+
+		var err error
+		if b, err = base64.StdEncoding.DecodeString(env.Payload); err != nil {
+			return "", fmt.Errorf("error decoding DSSE payload: %w", err)
+		}
+	default:
+		return "", fmt.Errorf("unsupported attestation media type: %v", mediaType)
+	}
+
+	var st struct {
+		PredicateType string `json:"predicateType"`
+	}
+	if err := json.Unmarshal(b, &st); err != nil {
+		return "", fmt.Errorf("error decoding in-toto statement: %w", err)
+	}
+	if st.PredicateType == "" {
+		return "", errors.New("in-toto statement does not specify a predicate type")
+	}
+	return st.PredicateType, nil
+}
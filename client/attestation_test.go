@@ -0,0 +1,182 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	testSLSAStatement  = `{"_type":"https://in-toto.io/Statement/v1","predicateType":"https://slsa.dev/provenance/v1","predicate":{}}`
+	testVulnStatement  = `{"_type":"https://in-toto.io/Statement/v1","predicateType":"https://cosign.sigstore.dev/attestation/vuln/v1","predicate":{}}`
+	testPredicateVulns = "https://cosign.sigstore.dev/attestation/vuln/v1"
+)
+
+// testDSSEEnvelope returns a DSSE envelope containing statement.
+func testDSSEEnvelope(statement string) string {
+	return `{"payloadType":"application/vnd.in-toto+json","payload":"` + base64.StdEncoding.EncodeToString([]byte(statement)) + `","signatures":[]}`
+}
+
+func Test_attestationPredicateType(t *testing.T) {
+	tests := []struct {
+		name      string
+		mediaType string
+		content   string
+		want      string
+		wantErr   bool
+	}{
+		{"Statement", MediaTypeInTotoStatement, testSLSAStatement, PredicateTypeSLSAProvenance, false},
+		{"Envelope", MediaTypeDSSEEnvelope, testDSSEEnvelope(testVulnStatement), testPredicateVulns, false},
+		{"NoPredicateType", MediaTypeInTotoStatement, `{"_type":"https://in-toto.io/Statement/v1"}`, "", true},
+		{"MalformedStatement", MediaTypeInTotoStatement, `not json`, "", true},
+		{"EnvelopePayloadType", MediaTypeDSSEEnvelope, `{"payloadType":"text/plain","payload":""}`, "", true},
+		{"EnvelopePayload", MediaTypeDSSEEnvelope, `{"payloadType":"application/vnd.in-toto+json","payload":"!"}`, "", true},
+		{"UnsupportedMediaType", "application/json", testSLSAStatement, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := attestationPredicateType(tt.mediaType, []byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got predicate type %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttestations(t *testing.T) {
+	tests := []struct {
+		name    string
+		oci     bool
+		wantErr error
+	}{
+		{"OCI", true, nil},
+		{"Library", false, errAttestationsNotSupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := newMockArtifactRegistry(t)
+
+			var srv *httptest.Server
+			srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/v1/oci-redirect":
+					if !tt.oci {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+
+					response := struct {
+						Token       string `json:"token"`
+						RegistryURI string `json:"url"`
+						Name        string `json:"name"`
+					}{
+						Token:       "xxx",
+						RegistryURI: srv.URL,
+						Name:        "entity/collection/container",
+					}
+					if err := json.NewEncoder(w).Encode(&response); err != nil {
+						t.Errorf("error JSON encoding: %v", err)
+					}
+
+				case strings.HasPrefix(r.URL.Path, "/v2/"):
+					reg.ServeHTTP(w, r)
+
+				default:
+					t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			ctx := context.Background()
+			ref := "entity/collection/container:latest"
+
+			slsa, err := c.AttachAttestation(ctx, ref, MediaTypeInTotoStatement, strings.NewReader(testSLSAStatement))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if _, err := c.ListAttestations(ctx, ref, ""); !errors.Is(err, tt.wantErr) {
+					t.Errorf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if got, want := slsa.PredicateType, PredicateTypeSLSAProvenance; got != want {
+				t.Errorf("got predicate type %v, want %v", got, want)
+			}
+
+			vuln, err := c.AttachAttestation(ctx, ref, MediaTypeDSSEEnvelope, strings.NewReader(testDSSEEnvelope(testVulnStatement)))
+			if err != nil {
+				t.Fatalf("error attaching attestation: %v", err)
+			}
+
+			if _, err := c.AttachAttestation(ctx, ref, MediaTypeInTotoStatement, strings.NewReader(`{}`)); err == nil {
+				t.Error("unexpected success attaching statement without predicate type")
+			}
+
+			all, err := c.ListAttestations(ctx, ref, "")
+			if err != nil {
+				t.Fatalf("error listing attestations: %v", err)
+			}
+			if got, want := len(all), 2; got != want {
+				t.Fatalf("got %v attestations, want %v", got, want)
+			}
+
+			filtered, err := c.ListAttestations(ctx, ref, testPredicateVulns)
+			if err != nil {
+				t.Fatalf("error listing attestations: %v", err)
+			}
+			if len(filtered) != 1 || filtered[0].Digest != vuln.Digest || filtered[0].MediaType != MediaTypeDSSEEnvelope {
+				t.Errorf("got attestations %v, want %v", filtered, vuln)
+			}
+			if filtered[0].Created.IsZero() {
+				t.Error("attestation creation time not set")
+			}
+
+			// Attestations are associated with the image digest, so are also listed by digest.
+			d, err := reg.manifestDigest("latest")
+			if err != nil {
+				t.Fatal(err)
+			}
+			byDigest, err := c.ListAttestations(ctx, "entity/collection/container@"+d, PredicateTypeSLSAProvenance)
+			if err != nil {
+				t.Fatalf("error listing attestations: %v", err)
+			}
+			if len(byDigest) != 1 || byDigest[0].Digest != slsa.Digest {
+				t.Errorf("got attestations %v, want %v", byDigest, slsa)
+			}
+
+			a, err := c.GetAttestation(ctx, ref, slsa.Digest)
+			if err != nil {
+				t.Fatalf("error getting attestation: %v", err)
+			}
+			if got, want := string(a.Content), testSLSAStatement; got != want {
+				t.Errorf("got content %q, want %q", got, want)
+			}
+			if got, want := a.PredicateType, PredicateTypeSLSAProvenance; got != want {
+				t.Errorf("got predicate type %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GetAuditEvents returns the events recorded for images within scope, which identifies an entity
+// (ie. "entity") or collection (ie. "entity/collection"), that occurred at or after since, in
+// chronological order. If since is zero, all recorded events are returned. Events are retrieved
+// one page at a time, until no further events are available.
+func (c *Client) GetAuditEvents(ctx context.Context, scope string, since time.Time) ([]AuditEvent, error) {
+	scope = strings.TrimPrefix(scope, "/")
+
+	parts := strings.Split(scope, "/")
+	if len(parts) > 2 {
+		return nil, fmt.Errorf("invalid scope %q: must identify an entity or collection", scope)
+	}
+	for _, p := range parts {
+		if !IsRefPart(p) {
+			return nil, fmt.Errorf("invalid scope %q", scope)
+		}
+	}
+
+	var events []AuditEvent
+
+	seen := make(map[string]bool)
+
+	for cursor := ""; ; {
+		page, err := c.getAuditEventPage(ctx, scope, since, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("error getting audit events: %w", err)
+		}
+
+		events = append(events, page.Events...)
+
+		if page.Next == "" {
+			return events, nil
+		}
+
+		// Guard against a server that repeatedly returns the same page.
+		if seen[page.Next] {
+			return nil, fmt.Errorf("error getting audit events: cursor %q repeated", page.Next)
+		}
+		seen[page.Next] = true
+
+		cursor = page.Next
+	}
+}
+
+// getAuditEventPage returns the page of events within scope that occurred at or after since,
+// identified by cursor. If cursor is empty, the first page is returned.
+func (c *Client) getAuditEventPage(ctx context.Context, scope string, since time.Time, cursor string) (*AuditEventPage, error) {
+	v := url.Values{}
+	v.Set("scope", scope)
+	if !since.IsZero() {
+		v.Set("since", since.UTC().Format(time.RFC3339Nano))
+	}
+	if cursor != "" {
+		v.Set("cursor", cursor)
+	}
+
+	b, err := c.apiGet(ctx, "v1/events?"+v.Encode())
+	if err != nil {
+		return nil, err
+	}
+	var res AuditEventsResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("error decoding audit events: %v", err)
+	}
+	return &res.Data, nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func TestGetAuditEvents(t *testing.T) {
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	pages := map[string]AuditEventPage{
+		"": {
+			Events: []AuditEvent{
+				{ID: "1", Type: AuditEventPush, Container: "entity/collection/container", ImageID: "imageID", Arch: "amd64"},
+				{ID: "2", Type: AuditEventTag, Container: "entity/collection/container", ImageID: "imageID", Tag: "latest"},
+			},
+			Next: "page2",
+		},
+		"page2": {
+			Events: []AuditEvent{
+				{ID: "3", Type: AuditEventPull, Container: "entity/collection/container", ImageID: "imageID"},
+			},
+		},
+		"loop": {Next: "loop"},
+	}
+
+	tests := []struct {
+		name      string
+		scope     string
+		since     time.Time
+		first     string
+		wantQuery string
+		wantIDs   []string
+		expectErr bool
+	}{
+		{"Entity", "entity", time.Time{}, "", "scope=entity", []string{"1", "2", "3"}, false},
+		{"Collection", "entity/collection", since, "", "scope=entity%2Fcollection&since=2026-01-02T03%3A04%3A05Z", []string{"1", "2", "3"}, false},
+		{"Container", "entity/collection/container", time.Time{}, "", "", nil, true},
+		{"InvalidScope", "Entity", time.Time{}, "", "", nil, true},
+		{"RepeatedCursor", "entity", time.Time{}, "loop", "scope=entity", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got, want := r.URL.Path, "/v1/events"; got != want {
+					t.Errorf("got path %v, want %v", got, want)
+				}
+
+				q := r.URL.Query()
+
+				cursor := q.Get("cursor")
+				if cursor == "" {
+					if got, want := r.URL.RawQuery, tt.wantQuery; got != want {
+						t.Errorf("got query %v, want %v", got, want)
+					}
+					cursor = tt.first
+				}
+
+				if err := jsonresp.WriteResponse(w, pages[cursor], http.StatusOK); err != nil {
+					t.Errorf("error writing JSON response: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			events, err := c.GetAuditEvents(context.Background(), tt.scope, tt.since)
+			if tt.expectErr && err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+
+			var ids []string
+			for _, e := range events {
+				ids = append(ids, e.ID)
+			}
+
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("got events %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
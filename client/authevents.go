@@ -0,0 +1,102 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-log/log"
+)
+
+// AuthEventKind identifies the kind of an AuthEvent.
+type AuthEventKind string
+
+const (
+	// AuthEventRegistryToken is emitted when the library server is asked for a token granting
+	// direct OCI registry access. Err is set if a token was not issued.
+	AuthEventRegistryToken AuthEventKind = "registry-token"
+	// AuthEventAnonymousToken is emitted when a token is requested from the authorization service
+	// of an OCI registry without presenting credentials. Err is set if a token was not issued.
+	AuthEventAnonymousToken AuthEventKind = "anonymous-token"
+	// AuthEventChallenge is emitted when an OCI registry rejects a request with HTTP status 401,
+	// and credentials are negotiated according to the challenge.
+	AuthEventChallenge AuthEventKind = "challenge"
+	// AuthEventRefresh is emitted when the library server rejects a request with HTTP status 401,
+	// and the auth token is refreshed (see Config.TokenSource). Err is set if the refresh failed.
+	AuthEventRefresh AuthEventKind = "refresh"
+	// AuthEventUnauthorized is emitted when the library server rejects a request with HTTP status
+	// 401, and the request is not re-attempted.
+	AuthEventUnauthorized AuthEventKind = "unauthorized"
+	// AuthEventPermissionDenied is emitted when an OCI registry rejects a request with HTTP status
+	// 403.
+	AuthEventPermissionDenied AuthEventKind = "permission-denied"
+)
+
+// AuthEvent describes an event in the authentication of the client to the library server or an OCI
+// registry. Tokens are never included.
+type AuthEvent struct {
+	// Kind of event.
+	Kind AuthEventKind
+	// Host of the server involved (ie. "registry.example.com").
+	Host string
+	// Scopes requested or challenged, if known (ie. "repository:entity/collection/container:pull").
+	Scopes []string
+	// Expiry of the token issued, if known.
+	Expiry time.Time
+	// Err describes the failure, if any.
+	Err error
+}
+
+// AuthEventHandler is called with each AuthEvent, allowing operators to detect authentication
+// loops and misconfigured scopes (ie. by recording metrics) before a transfer ultimately fails.
+type AuthEventHandler func(AuthEvent)
+
+// emitAuthEvent logs ev using logger, and relays it to handler (if supplied).
+func emitAuthEvent(logger log.Logger, handler AuthEventHandler, ev AuthEvent) {
+	if logger == nil && handler == nil {
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Auth event: kind=[%v] host=[%v]", ev.Kind, ev.Host)
+	if len(ev.Scopes) > 0 {
+		fmt.Fprintf(&sb, " scopes=[%v]", strings.Join(ev.Scopes, " "))
+	}
+	if !ev.Expiry.IsZero() {
+		fmt.Fprintf(&sb, " expiry=[%v]", ev.Expiry.Format(time.RFC3339))
+	}
+	if ev.Err != nil {
+		fmt.Fprintf(&sb, " err=[%v]", ev.Err)
+	}
+	if logger != nil {
+		logger.Log(sb.String())
+	}
+
+	if handler != nil {
+		handler(ev)
+	}
+}
+
+// emitAuthEvent logs ev, and relays it to the auth event handler of the client.
+func (c *Client) emitAuthEvent(ev AuthEvent) {
+	emitAuthEvent(c.logger, c.authEventHandler, ev)
+}
+
+// emitAuthEvent logs ev, and relays it to the auth event handler of the registry.
+func (r *ociRegistry) emitAuthEvent(ev AuthEvent) {
+	emitAuthEvent(r.logger, r.authEventHandler, ev)
+}
+
+// tokenEvent returns an event of kind k, describing the token issued by host for scopes.
+func tokenEvent(k AuthEventKind, host string, scopes []string, token string, err error) AuthEvent {
+	ev := AuthEvent{Kind: k, Host: host, Scopes: scopes, Err: err}
+	if exp, ok := tokenExpiry(token); ok {
+		ev.Expiry = exp
+	}
+	return ev
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// authEventRecorder records auth events.
+type authEventRecorder struct {
+	mu     sync.Mutex
+	events []AuthEvent
+}
+
+func (r *authEventRecorder) handle(ev AuthEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, ev)
+}
+
+// kind returns the first event recorded of kind k.
+func (r *authEventRecorder) kind(k AuthEventKind) (AuthEvent, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, ev := range r.events {
+		if ev.Kind == k {
+			return ev, true
+		}
+	}
+	return AuthEvent{}, false
+}
+
+func Test_emitAuthEvent(t *testing.T) {
+	exp := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		ev      AuthEvent
+		wantLog string
+	}{
+		{
+			name:    "Challenge",
+			ev:      AuthEvent{Kind: AuthEventChallenge, Host: "registry.example.com"},
+			wantLog: "Auth event: kind=[challenge] host=[registry.example.com]\n",
+		},
+		{
+			name: "Token",
+			ev: AuthEvent{
+				Kind:   AuthEventRegistryToken,
+				Host:   "registry.example.com",
+				Scopes: []string{"repository:a/b/c:pull,push", "repository:d/e/f:pull"},
+				Expiry: exp,
+			},
+			wantLog: "Auth event: kind=[registry-token] host=[registry.example.com] scopes=[repository:a/b/c:pull,push repository:d/e/f:pull] expiry=[2026-10-01T00:00:00Z]\n",
+		},
+		{
+			name:    "Error",
+			ev:      AuthEvent{Kind: AuthEventRefresh, Host: "library.example.com", Err: errors.New("refresh error")},
+			wantLog: "Auth event: kind=[refresh] host=[library.example.com] err=[refresh error]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var l bufferLogger
+			var r authEventRecorder
+
+			emitAuthEvent(&l, r.handle, tt.ev)
+
+			if got, want := l.String(), tt.wantLog; got != want {
+				t.Errorf("got log %q, want %q", got, want)
+			}
+			if got, want := r.events, []AuthEvent{tt.ev}; !reflect.DeepEqual(got, want) {
+				t.Errorf("got events %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_tokenEvent(t *testing.T) {
+	exp := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		token      string
+		wantExpiry time.Time
+	}{
+		{"Expiry", jwtWithExpiry(exp), exp},
+		{"NotJWT", "token", time.Time{}},
+		{"NoToken", "", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev := tokenEvent(AuthEventAnonymousToken, "host", []string{"scope"}, tt.token, nil)
+
+			if got, want := ev.Expiry, tt.wantExpiry; !got.Equal(want) {
+				t.Errorf("got expiry %v, want %v", got, want)
+			}
+			if got, want := ev.Kind, AuthEventAnonymousToken; got != want {
+				t.Errorf("got kind %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestAuthEventHandlerRefresh(t *testing.T) {
+	tests := []struct {
+		name        string
+		tokenSource *testTokenSource
+		wantKinds   []AuthEventKind
+		wantErr     bool
+	}{
+		{
+			name:        "Refreshed",
+			tokenSource: &testTokenSource{tokens: []string{"old", "new"}},
+			wantKinds:   []AuthEventKind{AuthEventRefresh},
+		},
+		{
+			name:        "RefreshError",
+			tokenSource: &testTokenSource{tokens: []string{"old"}, refreshErr: errors.New("refresh error")},
+			wantKinds:   []AuthEventKind{AuthEventRefresh, AuthEventUnauthorized},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer new" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer srv.Close()
+
+			var r authEventRecorder
+
+			c, err := NewClient(&Config{
+				BaseURL:          srv.URL,
+				TokenSource:      tt.tokenSource,
+				AuthEventHandler: r.handle,
+				Logger:           testLogger,
+			})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			if _, err := c.doPOSTRequest(context.Background(), "v1/test", map[string]string{"name": "value"}); (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			var kinds []AuthEventKind
+			for _, ev := range r.events {
+				kinds = append(kinds, ev.Kind)
+
+				if got, want := ev.Host, c.baseURL.Host; got != want {
+					t.Errorf("got host %v, want %v", got, want)
+				}
+			}
+			if got, want := kinds, tt.wantKinds; !reflect.DeepEqual(got, want) {
+				t.Errorf("got kinds %v, want %v", got, want)
+			}
+
+			if ev, ok := r.kind(AuthEventRefresh); ok && (ev.Err != nil) != (tt.tokenSource.refreshErr != nil) {
+				t.Errorf("got refresh error %v, want %v", ev.Err, tt.tokenSource.refreshErr)
+			}
+		})
+	}
+}
+
+func TestAuthEventHandlerRegistryToken(t *testing.T) {
+	image := testPartitionSIF(t, "amd64")
+	hash := strings.Replace(digest.FromBytes(image).String(), ":", ".", 1)
+
+	srv := newTestOCIPushServer(t, nil)
+	defer srv.Close()
+
+	var r authEventRecorder
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, AuthEventHandler: r.handle, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	_, err = c.ociUploadImage(context.Background(), bytes.NewReader(image), int64(len(image)), "name", "amd64", []string{"latest"}, "", nil, hash, UploadOptions{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ev, ok := r.kind(AuthEventRegistryToken)
+	if !ok {
+		t.Fatalf("no %v event emitted", AuthEventRegistryToken)
+	}
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ev.Host, u.Host; got != want {
+		t.Errorf("got host %v, want %v", got, want)
+	}
+	if got, want := ev.Scopes, []string{"repository:name:pull,push"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got scopes %v, want %v", got, want)
+	}
+	if ev.Err != nil {
+		t.Errorf("unexpected error: %v", ev.Err)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultBenchmarkSize is the default number of bytes downloaded by each benchmark iteration.
+const defaultBenchmarkSize = 256 * 1024 * 1024
+
+// defaultBenchmarkDownloaders are the download configurations benchmarked by default.
+var defaultBenchmarkDownloaders = []Downloader{
+	{Concurrency: 1, PartSize: 5 * 1024 * 1024},
+	{Concurrency: 4, PartSize: 5 * 1024 * 1024},
+	{Concurrency: 8, PartSize: 5 * 1024 * 1024},
+	{Concurrency: 4, PartSize: 32 * 1024 * 1024},
+	{Concurrency: 8, PartSize: 32 * 1024 * 1024},
+	{Concurrency: 16, PartSize: 32 * 1024 * 1024},
+}
+
+// BenchmarkSpec defines the parameters of a download benchmark.
+type BenchmarkSpec struct {
+	// Arch is the architecture of the image to download.
+	Arch string
+	// Size is the number of bytes, from the start of the image, downloaded by each iteration. If
+	// zero, 256 MiB is used. The size is limited to the size of the image.
+	Size int64
+	// Downloaders are the download configurations to benchmark. If empty, a range of concurrency
+	// and part size values is used.
+	Downloaders []Downloader
+	// Iterations is the number of times each download configuration is benchmarked. If zero, one
+	// iteration is performed.
+	Iterations int
+}
+
+// BenchmarkResult describes the performance of a download configuration.
+type BenchmarkResult struct {
+	// Downloader is the download configuration.
+	Downloader Downloader
+	// Bytes downloaded over all iterations.
+	Bytes int64
+	// Elapsed time over all iterations.
+	Elapsed time.Duration
+}
+
+// Throughput returns the average throughput of the download configuration, in bytes per second.
+func (r *BenchmarkResult) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Bytes) / r.Elapsed.Seconds()
+}
+
+// discardWriterAt is an io.WriterAt that discards all data written to it.
+type discardWriterAt struct{}
+
+func (discardWriterAt) WriteAt(p []byte, _ int64) (int, error) {
+	return len(p), nil
+}
+
+// BenchmarkDownload repeatedly downloads a byte range of the image identified by ref (ie.
+// "library://entity/collection/container:tag") using each of the download configurations in spec,
+// and reports the throughput of each configuration. Site administrators can use this to choose
+// the Downloader settings best suited to their network. Downloaded data is discarded. If spec is
+// nil, default parameters are used.
+func (c *Client) BenchmarkDownload(ctx context.Context, ref string, spec *BenchmarkSpec) ([]BenchmarkResult, error) {
+	if spec == nil {
+		spec = &BenchmarkSpec{}
+	}
+
+	r, err := ParseAmbiguous(ref)
+	if err != nil {
+		return nil, fmt.Errorf("malformed image ref: %w", err)
+	}
+
+	name := strings.TrimPrefix(r.Path, "/")
+	tag := "latest"
+	if len(r.Tags) > 0 {
+		tag = r.Tags[0]
+	}
+
+	u, creds, size, err := c.imageBlob(ctx, spec.Arch, name, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.Size > 0 && spec.Size < size {
+		size = spec.Size
+	} else if spec.Size <= 0 && size > defaultBenchmarkSize {
+		size = defaultBenchmarkSize
+	}
+
+	downloaders := spec.Downloaders
+	if len(downloaders) == 0 {
+		downloaders = defaultBenchmarkDownloaders
+	}
+
+	iterations := spec.Iterations
+	if iterations <= 0 {
+		iterations = 1
+	}
+
+	results := make([]BenchmarkResult, 0, len(downloaders))
+
+	for _, d := range downloaders {
+		if d.Concurrency == 0 || d.PartSize <= 0 {
+			return nil, fmt.Errorf("invalid download configuration (concurrency: %v, part size: %v)", d.Concurrency, d.PartSize)
+		}
+
+		result := BenchmarkResult{Downloader: d}
+
+		for i := 0; i < iterations; i++ {
+			start := time.Now()
+
+			if err := c.multipartDownload(ctx, u, creds, discardWriterAt{}, size, &d, &NoopProgressBar{}); err != nil {
+				return nil, err
+			}
+
+			result.Bytes += size
+			result.Elapsed += time.Since(start)
+		}
+
+		c.logger.Logf("Benchmark (concurrency: %v, part size: %v): %.0f byte(s)/s", d.Concurrency, d.PartSize, result.Throughput())
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
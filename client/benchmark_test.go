@@ -0,0 +1,122 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBenchmarkResultThroughput(t *testing.T) {
+	r := BenchmarkResult{Bytes: 2048, Elapsed: 2 * time.Second}
+	if got, want := r.Throughput(), 1024.0; got != want {
+		t.Errorf("got throughput %v, want %v", got, want)
+	}
+}
+
+func TestBenchmarkDownload(t *testing.T) {
+	sampleBytes := generateSampleData(t)
+	size := int64(len(sampleBytes))
+
+	tests := []struct {
+		name        string
+		multistream bool
+		spec        *BenchmarkSpec
+		wantResults int
+		wantBytes   int64
+		expectErr   bool
+	}{
+		{
+			name:        "Default",
+			multistream: true,
+			spec:        nil,
+			wantResults: len(defaultBenchmarkDownloaders),
+			wantBytes:   size,
+		},
+		{
+			name:        "Iterations",
+			multistream: true,
+			spec: &BenchmarkSpec{
+				Downloaders: []Downloader{{Concurrency: 2, PartSize: 64 * 1024}},
+				Iterations:  3,
+			},
+			wantResults: 1,
+			wantBytes:   3 * size,
+		},
+		{
+			name:        "Size",
+			multistream: true,
+			spec: &BenchmarkSpec{
+				Size:        1,
+				Downloaders: []Downloader{{Concurrency: 2, PartSize: 64 * 1024}},
+			},
+			wantResults: 1,
+			wantBytes:   1,
+		},
+		{
+			name:        "InvalidDownloader",
+			multistream: true,
+			spec: &BenchmarkSpec{
+				Downloaders: []Downloader{{Concurrency: 0, PartSize: 64 * 1024}},
+			},
+			expectErr: true,
+		},
+		{
+			name:        "SingleStream",
+			multistream: false,
+			expectErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lib := mockLibraryServer(t, sampleBytes, tt.multistream)
+			defer lib.Close()
+
+			// Direct OCI registry access is not supported by the mock library server
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/v1/oci-redirect" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				lib.Config.Handler.ServeHTTP(w, r)
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			results, err := c.BenchmarkDownload(context.Background(), "library://entity/collection/container:tag", tt.spec)
+			if tt.expectErr && err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+
+			if got, want := len(results), tt.wantResults; got != want {
+				t.Fatalf("got %v results, want %v", got, want)
+			}
+
+			for _, r := range results {
+				if got, want := r.Bytes, tt.wantBytes; got != want {
+					t.Errorf("got %v bytes, want %v", got, want)
+				}
+				if r.Elapsed <= 0 {
+					t.Errorf("got elapsed time %v", r.Elapsed)
+				}
+			}
+		})
+	}
+}
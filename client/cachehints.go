@@ -0,0 +1,99 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// CacheHints describe how long the resolution of a pulled image (ie. the image to which a tag
+// refers) may be reused by caches layered on the client, as indicated by the server resolving the
+// image.
+type CacheHints struct {
+	// Immutable is true if the image was identified by digest (ie. "sha256.<hex>"), rather than
+	// by a mutable tag, or if the server indicated that the resolution will not change (using the
+	// "immutable" Cache-Control directive).
+	Immutable bool
+	// NoCache is true if the server indicated that the resolution must be revalidated before it
+	// is reused (using the "no-cache" Cache-Control directive).
+	NoCache bool
+	// NoStore is true if the server indicated that the resolution must not be cached (using the
+	// "no-store" Cache-Control directive).
+	NoStore bool
+	// Expires is the time after which the resolution is stale, derived from the "max-age"
+	// Cache-Control directive or the Expires header. If no expiry was indicated, Expires is the
+	// zero time.
+	Expires time.Time
+	// CacheControl is the Cache-Control header of the response resolving the image, if any.
+	CacheControl string
+}
+
+// Fresh returns true if the resolution described by h may be reused at time t without
+// revalidation.
+func (h *CacheHints) Fresh(t time.Time) bool {
+	switch {
+	case h.Immutable:
+		return true
+	case h.NoCache, h.NoStore, h.Expires.IsZero():
+		return false
+	default:
+		return t.Before(h.Expires)
+	}
+}
+
+// isDigestRef returns true if ref identifies an image by digest, either in the form used by the
+// library ("sha256.<hex>") or by OCI registries ("sha256:<hex>").
+func isDigestRef(ref string) bool {
+	return digest.Digest(strings.Replace(ref, ".", ":", 1)).Validate() == nil
+}
+
+// parseCacheHints returns the cache hints for the image identified by ref, resolved by a response
+// with headers h received at time now.
+func parseCacheHints(ref string, h http.Header, now time.Time) *CacheHints {
+	ch := &CacheHints{
+		Immutable:    isDigestRef(ref),
+		CacheControl: h.Get("Cache-Control"),
+	}
+
+	maxAge := -1
+	for _, directive := range strings.Split(ch.CacheControl, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+
+		switch strings.ToLower(name) {
+		case "immutable":
+			ch.Immutable = true
+		case "no-cache":
+			ch.NoCache = true
+		case "no-store":
+			ch.NoStore = true
+		case "max-age":
+			if n, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil && n >= 0 {
+				maxAge = n
+			}
+		}
+	}
+
+	switch {
+	case maxAge >= 0:
+		// The age of the response, if cached by an intermediary, reduces its freshness.
+		age, _ := strconv.Atoi(h.Get("Age"))
+		ch.Expires = now.Add(time.Duration(maxAge-max(age, 0)) * time.Second)
+	case h.Get("Expires") != "":
+		// An invalid Expires header (ie. "0") indicates that the response is already stale.
+		t, err := http.ParseTime(h.Get("Expires"))
+		if err != nil {
+			t = now
+		}
+		ch.Expires = t
+	}
+
+	return ch
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseCacheHints(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	hash := "sha256.e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	tests := []struct {
+		name        string
+		ref         string
+		header      http.Header
+		want        CacheHints
+		wantFresh   bool
+		freshOffset time.Duration
+	}{
+		{
+			name: "Tag",
+			ref:  "latest",
+			want: CacheHints{},
+		},
+		{
+			name:      "LibraryDigest",
+			ref:       hash,
+			want:      CacheHints{Immutable: true},
+			wantFresh: true,
+		},
+		{
+			name:      "OCIDigest",
+			ref:       "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			want:      CacheHints{Immutable: true},
+			wantFresh: true,
+		},
+		{
+			name:      "ImmutableDirective",
+			ref:       "v1",
+			header:    http.Header{"Cache-Control": {"public, immutable"}},
+			want:      CacheHints{Immutable: true, CacheControl: "public, immutable"},
+			wantFresh: true,
+		},
+		{
+			name:        "MaxAge",
+			ref:         "latest",
+			header:      http.Header{"Cache-Control": {"max-age=60"}},
+			want:        CacheHints{Expires: now.Add(time.Minute), CacheControl: "max-age=60"},
+			wantFresh:   true,
+			freshOffset: 59 * time.Second,
+		},
+		{
+			name:        "MaxAgeExpired",
+			ref:         "latest",
+			header:      http.Header{"Cache-Control": {"max-age=60"}},
+			want:        CacheHints{Expires: now.Add(time.Minute), CacheControl: "max-age=60"},
+			freshOffset: time.Minute,
+		},
+		{
+			name:   "MaxAgeWithAge",
+			ref:    "latest",
+			header: http.Header{"Cache-Control": {"Max-Age=60"}, "Age": {"45"}},
+			want:   CacheHints{Expires: now.Add(15 * time.Second), CacheControl: "Max-Age=60"},
+			// Stale after the remaining 15 seconds, rather than 60 seconds.
+			freshOffset: 30 * time.Second,
+		},
+		{
+			name:   "MaxAgeOverridesExpires",
+			ref:    "latest",
+			header: http.Header{"Cache-Control": {"max-age=10"}, "Expires": {"Fri, 02 Jan 2026 04:00:00 GMT"}},
+			want:   CacheHints{Expires: now.Add(10 * time.Second), CacheControl: "max-age=10"},
+			// Stale after 10 seconds, rather than at the (later) Expires time.
+			freshOffset: 30 * time.Second,
+		},
+		{
+			name:      "Expires",
+			ref:       "latest",
+			header:    http.Header{"Expires": {"Fri, 02 Jan 2026 04:00:00 GMT"}},
+			want:      CacheHints{Expires: time.Date(2026, 1, 2, 4, 0, 0, 0, time.UTC)},
+			wantFresh: true,
+		},
+		{
+			name:   "InvalidExpires",
+			ref:    "latest",
+			header: http.Header{"Expires": {"0"}},
+			want:   CacheHints{Expires: now},
+		},
+		{
+			name:   "InvalidMaxAge",
+			ref:    "latest",
+			header: http.Header{"Cache-Control": {"max-age=soon"}},
+			want:   CacheHints{CacheControl: "max-age=soon"},
+		},
+		{
+			name:   "NoCache",
+			ref:    "latest",
+			header: http.Header{"Cache-Control": {"no-cache, max-age=60"}},
+			want:   CacheHints{NoCache: true, Expires: now.Add(time.Minute), CacheControl: "no-cache, max-age=60"},
+		},
+		{
+			name:   "NoStore",
+			ref:    "latest",
+			header: http.Header{"Cache-Control": {"no-store"}},
+			want:   CacheHints{NoStore: true, CacheControl: "no-store"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseCacheHints(tt.ref, tt.header, now)
+			if *got != tt.want {
+				t.Errorf("got hints %+v, want %+v", *got, tt.want)
+			}
+
+			if fresh := got.Fresh(now.Add(tt.freshOffset)); fresh != tt.wantFresh {
+				t.Errorf("got fresh %v, want %v", fresh, tt.wantFresh)
+			}
+		})
+	}
+}
+
+func TestLibraryDownloadCacheHints(t *testing.T) {
+	sampleBytes := []byte("sample image content")
+	size := int64(len(sampleBytes))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/version", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"data": {"apiVersion": "1.0.0"}}`)
+	})
+	mux.HandleFunc("/v1/imagefile/", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=300")
+		w.Header().Set("Content-Length", fmt.Sprint(size))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(sampleBytes)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	ctx, stats := withTransferStats(context.Background())
+	dst := &inMemoryBuffer{buf: make([]byte, size)}
+
+	start := time.Now()
+	if err := c.libraryDownloadImage(ctx, "amd64", "entity/collection/container", "latest", dst, nil, &NoopProgressBar{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	h := stats.summary().Cache
+	if h == nil {
+		t.Fatal("no cache hints recorded")
+	}
+	if h.Immutable {
+		t.Error("tag resolution reported as immutable")
+	}
+	if got, want := h.CacheControl, "max-age=300"; got != want {
+		t.Errorf("got Cache-Control %q, want %q", got, want)
+	}
+	if !h.Fresh(start.Add(4*time.Minute)) || h.Fresh(start.Add(6*time.Minute)) {
+		t.Errorf("unexpected expiry %v", h.Expires)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// OCICatalog returns up to n repository names in the OCI registry associated with the library, in
+// lexical order, starting after last. If n is zero, the registry default page size applies. If
+// further repositories are available, next contains the value of last to use to retrieve the next
+// page; otherwise, next is empty.
+//
+// Listing repositories requires direct OCI registry access, and is typically only permitted by
+// Enterprise or self-hosted registries.
+func (c *Client) OCICatalog(ctx context.Context, n int, last string) (repos []string, next string, err error) {
+	if n < 0 {
+		return nil, "", fmt.Errorf("invalid page size %v", n)
+	}
+
+	reg, creds, _, err := c.newOCIRegistry(ctx, "", []accessType{accessTypePull})
+	if err != nil {
+		if errors.Is(err, errOCIDownloadNotSupported) {
+			return nil, "", errors.New("listing repositories requires OCI registry access")
+		}
+		return nil, "", err
+	}
+
+	if repos, next, err = reg.catalog(ctx, creds, n, last); err != nil {
+		return nil, "", fmt.Errorf("error listing repositories: %w", err)
+	}
+	return repos, next, nil
+}
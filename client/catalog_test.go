@@ -0,0 +1,120 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func TestOCICatalog(t *testing.T) {
+	repos := []string{"entity/a/x", "entity/b/y", "entity/c/z"}
+
+	tests := []struct {
+		name      string
+		n         int
+		last      string
+		wantRepos []string
+		wantNext  string
+		wantErr   bool
+	}{
+		{"All", 0, "", repos, "", false},
+		{"FirstPage", 2, "", repos[:2], "entity/b/y", false},
+		{"LastPage", 2, "entity/b/y", repos[2:], "", false},
+		{"InvalidPageSize", -1, "", nil, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var srv *httptest.Server
+
+			srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v1/oci-redirect":
+					response := struct {
+						Token       string `json:"token"`
+						RegistryURI string `json:"url"`
+					}{
+						Token:       "xxx",
+						RegistryURI: srv.URL,
+					}
+					if err := json.NewEncoder(w).Encode(&response); err != nil {
+						t.Errorf("error JSON encoding: %v", err)
+					}
+
+				case "/v2/_catalog":
+					start := 0
+					if last := r.URL.Query().Get("last"); last != "" {
+						start = sort.SearchStrings(repos, last) + 1
+					}
+
+					end := len(repos)
+					if v := r.URL.Query().Get("n"); v != "" {
+						n, err := strconv.Atoi(v)
+						if err != nil {
+							t.Errorf("invalid n: %v", err)
+						}
+						if start+n < end {
+							end = start + n
+
+							q := url.Values{}
+							q.Set("n", v)
+							q.Set("last", repos[end-1])
+							w.Header().Set("Link", fmt.Sprintf(`</v2/_catalog?%v>; rel="next"`, q.Encode()))
+						}
+					}
+
+					if err := json.NewEncoder(w).Encode(map[string][]string{"repositories": repos[start:end]}); err != nil {
+						t.Errorf("error JSON encoding: %v", err)
+					}
+
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			got, next, err := c.OCICatalog(context.Background(), tt.n, tt.last)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.wantRepos) {
+				t.Errorf("got repositories %v, want %v", got, tt.wantRepos)
+			}
+			if next != tt.wantNext {
+				t.Errorf("got next %q, want %q", next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestOCICatalogNotSupported(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	if _, _, err := c.OCICatalog(context.Background(), 0, ""); err == nil {
+		t.Error("unexpected success")
+	}
+}
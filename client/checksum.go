@@ -0,0 +1,319 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// ChecksumAlgorithm identifies an algorithm used to checksum uploaded content.
+type ChecksumAlgorithm string
+
+const (
+	// ChecksumMD5 identifies the MD5 checksum algorithm.
+	ChecksumMD5 ChecksumAlgorithm = "md5"
+	// ChecksumSHA256 identifies the SHA256 checksum algorithm.
+	ChecksumSHA256 ChecksumAlgorithm = "sha256"
+	// ChecksumSHA512 identifies the SHA512 checksum algorithm.
+	ChecksumSHA512 ChecksumAlgorithm = "sha512"
+	// ChecksumCRC32C identifies the CRC32 (Castagnoli) checksum algorithm.
+	ChecksumCRC32C ChecksumAlgorithm = "crc32c"
+
+	// OptionChecksumAlgorithms is a comma-separated list of checksum algorithms accepted by the
+	// backend library server for each part of a multipart upload.
+	OptionChecksumAlgorithms = "checksumAlgorithms"
+)
+
+// defaultChecksumAlgorithms are computed over an image prior to upload, unless configured
+// otherwise.
+var defaultChecksumAlgorithms = []ChecksumAlgorithm{ChecksumMD5, ChecksumSHA256}
+
+// uploadChecksumAlgorithms returns the checksum algorithms computed over an image prior to upload
+// when algs are requested. The SHA256 checksum is always computed, as it identifies the image.
+func uploadChecksumAlgorithms(algs []ChecksumAlgorithm) ([]ChecksumAlgorithm, error) {
+	res := []ChecksumAlgorithm{ChecksumSHA256}
+	for _, alg := range algs {
+		if _, err := newHash(alg); err != nil {
+			return nil, err
+		}
+		if !hasChecksumAlgorithm(res, alg) {
+			res = append(res, alg)
+		}
+	}
+	return res, nil
+}
+
+// newHash returns a hash.Hash for algorithm alg.
+func newHash(alg ChecksumAlgorithm) (hash.Hash, error) {
+	switch alg {
+	case ChecksumMD5:
+		return md5.New(), nil
+	case ChecksumSHA256:
+		return sha256.New(), nil
+	case ChecksumSHA512:
+		return sha512.New(), nil
+	case ChecksumCRC32C:
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli)), nil
+	}
+	return nil, fmt.Errorf("unsupported checksum algorithm %q", alg)
+}
+
+// encodeChecksum encodes sum according to alg. CRC32C checksums are base64 encoded, as expected
+// by the "X-Amz-Checksum-Crc32c" header. All other checksums are hex encoded.
+func encodeChecksum(alg ChecksumAlgorithm, sum []byte) string {
+	if alg == ChecksumCRC32C {
+		return base64.StdEncoding.EncodeToString(sum)
+	}
+	return hex.EncodeToString(sum)
+}
+
+// checksums maps a checksum algorithm to an encoded checksum.
+type checksums map[ChecksumAlgorithm]string
+
+// computeChecksums computes checksums of r concurrently, using each of the algorithms in algs. The
+// number of bytes read from r is returned.
+func computeChecksums(r io.Reader, algs []ChecksumAlgorithm) (checksums, int64, error) {
+	hs := make([]hash.Hash, 0, len(algs))
+	for _, alg := range algs {
+		h, err := newHash(alg)
+		if err != nil {
+			return nil, 0, err
+		}
+		hs = append(hs, h)
+	}
+
+	var g errgroup.Group
+
+	pws := make([]*io.PipeWriter, 0, len(algs))
+	ws := make([]io.Writer, 0, len(algs))
+
+	for i, alg := range algs {
+		pr, pw := io.Pipe()
+		pws = append(pws, pw)
+		ws = append(ws, pw)
+
+		h, alg := hs[i], alg
+		g.Go(func() error {
+			if _, err := io.Copy(h, pr); err != nil {
+				return fmt.Errorf("error calculating %v checksum: %v", alg, err)
+			}
+			return nil
+		})
+	}
+
+	n, err := io.Copy(io.MultiWriter(ws...), r)
+	for _, pw := range pws {
+		// The pipe writers must be closed so checksum computation gets EOF and will complete.
+		pw.CloseWithError(err)
+	}
+
+	if gerr := g.Wait(); err == nil {
+		err = gerr
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sums := make(checksums, len(algs))
+	for i, alg := range algs {
+		sums[alg] = encodeChecksum(alg, hs[i].Sum(nil))
+	}
+	return sums, n, nil
+}
+
+// computeChecksumsReadAhead computes checksums of r, using each of the algorithms in algs, reading
+// up to readAhead buffers of bufSize bytes (or defaultHashBufferSize, if zero) ahead of checksum
+// computation. Each checksum is computed by a separate goroutine, which consumes buffers
+// independently of the others, so reading and the computation of each checksum overlap. If
+// readAhead is zero, computeChecksums is used. The number of bytes read from r is returned.
+func computeChecksumsReadAhead(r io.Reader, algs []ChecksumAlgorithm, readAhead int, bufSize int64) (checksums, int64, error) {
+	if readAhead <= 0 {
+		return computeChecksums(r, algs)
+	}
+	if bufSize <= 0 {
+		bufSize = defaultHashBufferSize
+	}
+
+	hs := make([]hash.Hash, 0, len(algs))
+	for _, alg := range algs {
+		h, err := newHash(alg)
+		if err != nil {
+			return nil, 0, err
+		}
+		hs = append(hs, h)
+	}
+
+	// Buffers are recycled once each checksum has consumed them, bounding memory use.
+	free := make(chan []byte, readAhead)
+	for i := 0; i < readAhead; i++ {
+		free <- make([]byte, bufSize)
+	}
+
+	var wg sync.WaitGroup
+
+	chs := make([]chan *hashBuffer, 0, len(algs))
+	for _, h := range hs {
+		ch := make(chan *hashBuffer, readAhead)
+		chs = append(chs, ch)
+
+		wg.Add(1)
+		go func(h hash.Hash) {
+			defer wg.Done()
+
+			for hb := range ch {
+				h.Write(hb.b)
+				hb.release(free)
+			}
+		}(h)
+	}
+
+	var n int64
+	var err error
+	for {
+		b := <-free
+
+		var m int
+		m, err = io.ReadFull(r, b)
+		if m > 0 {
+			n += int64(m)
+
+			hb := &hashBuffer{b: b[:m]}
+			hb.refs.Store(int32(len(chs)))
+			for _, ch := range chs {
+				ch <- hb
+			}
+		} else {
+			free <- b
+		}
+
+		if err != nil {
+			break
+		}
+	}
+
+	for _, ch := range chs {
+		close(ch)
+	}
+	wg.Wait()
+
+	if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, 0, fmt.Errorf("error reading content: %w", err)
+	}
+
+	sums := make(checksums, len(algs))
+	for i, alg := range algs {
+		sums[alg] = encodeChecksum(alg, hs[i].Sum(nil))
+	}
+	return sums, n, nil
+}
+
+// hashBuffer is a buffer of content consumed by several checksum goroutines.
+type hashBuffer struct {
+	b    []byte
+	refs atomic.Int32
+}
+
+// release records that a goroutine has consumed hb. Once consumed by all goroutines, the buffer is
+// returned to free.
+func (hb *hashBuffer) release(free chan<- []byte) {
+	if hb.refs.Add(-1) == 0 {
+		free <- hb.b[:cap(hb.b)]
+	}
+}
+
+// parseChecksumAlgorithms parses a comma-separated list of checksum algorithms, as supplied by the
+// backend library server. Unsupported algorithms are ignored.
+func parseChecksumAlgorithms(val string) []ChecksumAlgorithm {
+	var algs []ChecksumAlgorithm
+
+	for _, s := range strings.Split(val, ",") {
+		alg := ChecksumAlgorithm(strings.ToLower(strings.TrimSpace(s)))
+		if _, err := newHash(alg); err == nil {
+			algs = append(algs, alg)
+		}
+	}
+	return algs
+}
+
+// hasChecksumAlgorithm returns true if alg is present in algs.
+func hasChecksumAlgorithm(algs []ChecksumAlgorithm, alg ChecksumAlgorithm) bool {
+	for _, a := range algs {
+		if a == alg {
+			return true
+		}
+	}
+	return false
+}
+
+// ChecksumMismatchError is returned when upload verification is enabled, and the checksum of the
+// content stored by the object store does not match the checksum of the content that was sent. It
+// is also returned when downloaded content does not match the digest supplied to a
+// DownloadVerifier.
+type ChecksumMismatchError struct {
+	// PartNumber identifies the part of a multipart upload, or is zero when the checksum applies
+	// to the complete object.
+	PartNumber int
+	// Algorithm is the checksum algorithm (ie. "md5" or "sha256").
+	Algorithm string
+	// Got is the checksum reported by the server.
+	Got string
+	// Want is the checksum computed locally.
+	Want string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	if e.PartNumber > 0 {
+		return fmt.Sprintf("%v checksum mismatch for part %d: got %v, want %v", e.Algorithm, e.PartNumber, e.Got, e.Want)
+	}
+	return fmt.Sprintf("%v checksum mismatch: got %v, want %v", e.Algorithm, e.Got, e.Want)
+}
+
+// md5ETagRegexp matches an ETag that consists of an MD5 checksum. ETags of objects encrypted
+// using SSE-KMS or assembled using multipart upload are not MD5 checksums of the content.
+var md5ETagRegexp = regexp.MustCompile(`^[a-f0-9]{32}$`)
+
+// verifyETag compares the (possibly quoted) etag returned by the object store with the locally
+// computed MD5 checksum md5sum. If etag is not an MD5 checksum, verification is skipped and
+// verified is false.
+func verifyETag(partNumber int, etag, md5sum string) (verified bool, err error) {
+	tag := strings.ToLower(strings.Trim(etag, `"`))
+	if !md5ETagRegexp.MatchString(tag) {
+		return false, nil
+	}
+
+	if tag != md5sum {
+		return false, &ChecksumMismatchError{PartNumber: partNumber, Algorithm: "md5", Got: tag, Want: md5sum}
+	}
+	return true, nil
+}
+
+// verifyUploadComplete compares the SHA256 checksum of the stored object, if reported by the
+// server in the upload completion response, with the locally computed sha256sum.
+func verifyUploadComplete(res *UploadImageComplete, sha256sum string) error {
+	if res == nil || res.SHA256Checksum == "" || sha256sum == "" {
+		return nil
+	}
+
+	if got := strings.TrimPrefix(res.SHA256Checksum, "sha256."); got != sha256sum {
+		return &ChecksumMismatchError{Algorithm: "sha256", Got: got, Want: sha256sum}
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func Test_computeChecksums(t *testing.T) {
+	const src = "0123456789"
+
+	tests := []struct {
+		name    string
+		algs    []ChecksumAlgorithm
+		want    checksums
+		wantErr bool
+	}{
+		{
+			name: "Default",
+			algs: defaultChecksumAlgorithms,
+			want: checksums{
+				ChecksumMD5:    "781e5e245d69b566979b86e28d23f2c7",
+				ChecksumSHA256: "84d89877f0d4041efb6bf91a16f0248f2fd573e6af05c19f96bedb9f882f7882",
+			},
+		},
+		{
+			name: "All",
+			algs: []ChecksumAlgorithm{ChecksumMD5, ChecksumSHA256, ChecksumSHA512, ChecksumCRC32C},
+			want: checksums{
+				ChecksumMD5:    "781e5e245d69b566979b86e28d23f2c7",
+				ChecksumSHA256: "84d89877f0d4041efb6bf91a16f0248f2fd573e6af05c19f96bedb9f882f7882",
+				ChecksumSHA512: "bb96c2fc40d2d54617d6f276febe571f623a8dadf0b734855299b0e107fda32cf6b69f2da32b36445d73690b93cbd0f7bfc20e0f7f28553d2a4428f23b716e90",
+				ChecksumCRC32C: "KAwGng==",
+			},
+		},
+		{
+			name: "None",
+			algs: nil,
+			want: checksums{},
+		},
+		{
+			name:    "Unsupported",
+			algs:    []ChecksumAlgorithm{"sha1"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			sums, n, err := computeChecksums(strings.NewReader(src), tt.algs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got err %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got, want := n, int64(len(src)); got != want {
+				t.Errorf("got %v bytes, want %v", got, want)
+			}
+			if got, want := sums, tt.want; !reflect.DeepEqual(got, want) {
+				t.Errorf("got checksums %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_computeChecksumsReadAhead(t *testing.T) {
+	src := strings.Repeat("0123456789", 1000)
+
+	want, _, err := computeChecksums(strings.NewReader(src), []ChecksumAlgorithm{ChecksumMD5, ChecksumSHA256, ChecksumCRC32C})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		readAhead int
+		bufSize   int64
+	}{
+		{"None", 0, 0},
+		{"DefaultBufferSize", 2, 0},
+		{"SingleBuffer", 1, 64},
+		{"SeveralBuffers", 4, 333},
+		{"UnalignedBuffer", 3, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sums, n, err := computeChecksumsReadAhead(strings.NewReader(src), []ChecksumAlgorithm{ChecksumMD5, ChecksumSHA256, ChecksumCRC32C}, tt.readAhead, tt.bufSize)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got, want := n, int64(len(src)); got != want {
+				t.Errorf("got %v bytes, want %v", got, want)
+			}
+			if !reflect.DeepEqual(sums, want) {
+				t.Errorf("got checksums %v, want %v", sums, want)
+			}
+		})
+	}
+
+	t.Run("ReadError", func(t *testing.T) {
+		errRead := errors.New("read error")
+
+		r := io.MultiReader(strings.NewReader(src), iotest.ErrReader(errRead))
+
+		if _, _, err := computeChecksumsReadAhead(r, defaultChecksumAlgorithms, 2, 64); !errors.Is(err, errRead) {
+			t.Errorf("got error %v, want %v", err, errRead)
+		}
+	})
+}
+
+func Test_parseChecksumAlgorithms(t *testing.T) {
+	tests := []struct {
+		name string
+		val  string
+		want []ChecksumAlgorithm
+	}{
+		{"Empty", "", nil},
+		{"Single", "sha256", []ChecksumAlgorithm{ChecksumSHA256}},
+		{"Multiple", "SHA256, crc32c", []ChecksumAlgorithm{ChecksumSHA256, ChecksumCRC32C}},
+		{"Unsupported", "sha1,sha512", []ChecksumAlgorithm{ChecksumSHA512}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := parseChecksumAlgorithms(tt.val), tt.want; !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_verifyETag(t *testing.T) {
+	const md5sum = "781e5e245d69b566979b86e28d23f2c7"
+
+	tests := []struct {
+		name         string
+		etag         string
+		wantVerified bool
+		wantErr      bool
+	}{
+		{"Match", md5sum, true, false},
+		{"QuotedMatch", `"` + md5sum + `"`, true, false},
+		{"UppercaseMatch", `"781E5E245D69B566979B86E28D23F2C7"`, true, false},
+		{"Mismatch", `"00000000000000000000000000000000"`, false, true},
+		{"MultipartETag", `"781e5e245d69b566979b86e28d23f2c7-2"`, false, false},
+		{"Empty", "", false, false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			verified, err := verifyETag(1, tt.etag, md5sum)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got err %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got, want := verified, tt.wantVerified; got != want {
+				t.Errorf("got verified %v, want %v", got, want)
+			}
+
+			var cme *ChecksumMismatchError
+			if err != nil && !errors.As(err, &cme) {
+				t.Errorf("got error type %T, want %T", err, cme)
+			}
+		})
+	}
+}
+
+func Test_verifyUploadComplete(t *testing.T) {
+	const sha256sum = "d7d356079af905c04e5ae10711ecf3f5b34385e9b143c5d9ddbf740665ce2fb7"
+
+	tests := []struct {
+		name    string
+		res     *UploadImageComplete
+		wantErr bool
+	}{
+		{"NilResponse", nil, false},
+		{"NotReported", &UploadImageComplete{}, false},
+		{"Match", &UploadImageComplete{SHA256Checksum: sha256sum}, false},
+		{"PrefixedMatch", &UploadImageComplete{SHA256Checksum: "sha256." + sha256sum}, false},
+		{"Mismatch", &UploadImageComplete{SHA256Checksum: "0000"}, true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifyUploadComplete(tt.res, sha256sum); (err != nil) != tt.wantErr {
+				t.Fatalf("got err %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
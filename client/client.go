@@ -9,25 +9,158 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-log/log"
+	"golang.org/x/sync/singleflight"
 )
 
 // Config contains the client configuration.
 type Config struct {
-	// Base URL of the service.
+	// Base URL of the service. A library server listening on a Unix domain socket is specified
+	// using the "unix" scheme and the path of the socket (ie. "unix:///run/library.sock").
 	BaseURL string
-	// Auth token to include in the Authorization header of each request (if supplied).
+	// Auth token to include in the Authorization header of each request (if supplied). Ignored
+	// if TokenSource is supplied.
 	AuthToken string
+	// TokenSource supplies the auth token included in the Authorization header of each request
+	// (if supplied). When the library server rejects a request with HTTP status 401, the token is
+	// refreshed and the request re-attempted once, so that tokens may be rotated during
+	// long-running operations.
+	TokenSource TokenSource
 	// User agent to include in each request (if supplied).
 	UserAgent string
-	// HTTPClient to use to make HTTP requests (if supplied).
+	// NameResolver maps short image names to fully-qualified names prior to direct OCI registry
+	// access (if supplied). If nil, names are mapped by the library server. Supply a
+	// StaticNameResolver to map names in disconnected deployments.
+	NameResolver NameResolver
+	// HTTPClient to use to make HTTP requests (if supplied). If nil, a client using a transport
+	// tuned for concurrent transfers is constructed.
 	HTTPClient *http.Client
+	// Transport overrides parameters of the transport constructed when HTTPClient is nil.
+	Transport *TransportConfig
 	// Logger to be used when output is generated
 	Logger log.Logger
+	// Number of times a failed multipart upload part is retried before the upload is aborted. If
+	// zero, a default of 3 is used. Set to a negative value to disable retries. Ignored if
+	// RetryPolicy is supplied.
+	UploadPartRetries int
+	// RetryPolicy determines whether, and after what delay, a failed multipart upload part is
+	// retried (if supplied). If nil, parts are retried UploadPartRetries times with exponential
+	// backoff, starting with a delay of one second.
+	RetryPolicy RetryPolicy
+	// Sleeper is used to wait between attempts of a failed operation (if supplied). If nil, the
+	// client waits in real time. Tests may substitute a Sleeper to observe retry behaviour without
+	// real delays.
+	Sleeper Sleeper
+	// VerifyUploadChecksums enables verification of the checksums reported by the object store
+	// (and library server) against the checksums of the uploaded content.
+	VerifyUploadChecksums bool
+	// ChecksumAlgorithms computed over an image prior to upload. If nil, MD5 and SHA256 checksums
+	// are computed. The SHA256 checksum is always computed, as it identifies the image; omit MD5
+	// to skip the MD5 computation when the server only consumes SHA256 checksums.
+	ChecksumAlgorithms []ChecksumAlgorithm
+	// Debug enables logging of all HTTP requests and responses made by the client, including
+	// headers and timings. Credentials and presigned URL signatures are redacted. Debug logging
+	// may also be enabled by setting the environment variable named by DebugEnvVar.
+	Debug bool
+	// WarningHandler is called with warning and deprecation notices returned by the library
+	// server (if supplied). Notices are also logged using Logger.
+	WarningHandler WarningHandler
+	// AuthEventHandler is called with each event in the authentication of the client to the
+	// library server or an OCI registry (if supplied). Events are also logged using Logger.
+	AuthEventHandler AuthEventHandler
+	// Upload specifies options applied to image uploads. If nil, the defaults described by
+	// UploadOptions are used.
+	Upload *UploadOptions
+	// Downloader defines the default transfer parameters used when a nil *Downloader is passed
+	// to DownloadImage. If nil, a concurrency of 1 and part size of 5 MiB is used.
+	Downloader *Downloader
+	// MaxRequests limits the number of concurrent in-flight HTTP requests made by the client,
+	// including download workers, multipart uploads and metadata requests. If zero, the number of
+	// requests is not limited.
+	MaxRequests int
+	// MaxRequestsPerHost limits the number of concurrent in-flight HTTP requests made by the
+	// client to a single host. If zero, the number of requests per host is not limited.
+	MaxRequestsPerHost int
+	// DisableCompression disables negotiation of compressed (gzip) responses from the library
+	// server, including JSON metadata such as search results and tag maps.
+	DisableCompression bool
+	// ContentDecoders adds support for additional HTTP content codings (such as "zstd") when
+	// negotiating compressed responses, keyed by content coding. The "gzip" content coding is
+	// always supported.
+	ContentDecoders map[string]ContentDecoder
+	// PublishChecksums enables publication of the checksums computed over an image when it is
+	// uploaded, so that they may be retrieved using GetImageChecksums.
+	PublishChecksums bool
+	// CompressedImageDownloads enables negotiation of compressed image content when images are
+	// served directly by the library server. Compression is not negotiated for concurrent
+	// (ranged) downloads. Ignored if DisableCompression is set.
+	CompressedImageDownloads bool
+	// RegistryCredentials contains static credentials used to access OCI registries directly,
+	// keyed by registry host (ie. "registry.example.com" or "registry.example.com:5000"). If
+	// present for a registry, they are used in place of the token issued by the library server.
+	RegistryCredentials map[string]RegistryCredentials
+	// AnonymousRegistryURL is the URL of an OCI registry from which images are pulled anonymously
+	// (if the registry permits) when the library server does not grant direct registry access.
+	// If the anonymous pull fails, the image is downloaded from the library server.
+	AnonymousRegistryURL string
+	// AllowedRegistryHosts restricts the OCI registries to which the library server may redirect
+	// the client for direct registry access. If non-empty, only registries whose host matches an
+	// entry are accepted. Entries are host names (ie. "registry.example.com"), which match any
+	// port, host names with a port (ie. "registry.example.com:5000"), or wildcards matching any
+	// subdomain (ie. "*.example.com"). A registry that is refused is not sent the token issued by
+	// the library server; ErrRegistryHostRefused is reported, and the fallback described by
+	// StrictRegistryAccess applies.
+	AllowedRegistryHosts []string
+	// DeniedRegistryHosts lists OCI registries to which the client refuses redirection, in the
+	// format described by AllowedRegistryHosts. Denied hosts take precedence over allowed hosts.
+	DeniedRegistryHosts []string
+	// RequireRegistryHTTPS refuses redirection to OCI registries not accessed using HTTPS.
+	RequireRegistryHTTPS bool
+	// AllowInsecureRegistries lists hosts, in the format described by AllowedRegistryHosts, that
+	// may be accessed using plain HTTP when the library server directs the client to them, either
+	// as the OCI registry for direct access, or as a presigned URL for image content. Access to
+	// other hosts using plain HTTP is refused with ErrInsecureEndpoint, except for loopback
+	// addresses and the host of the library server itself. Each permitted insecure host is logged
+	// as a warning when first accessed.
+	AllowInsecureRegistries []string
+	// LenientManifestContentType relaxes the check of the Content-Type of manifests downloaded
+	// from OCI registries. Parameters (such as charset) are ignored, and if the Content-Type does
+	// not match (ie. "application/json" is returned), the manifest media type is determined from
+	// its content instead. By default, the Content-Type must match exactly.
+	LenientManifestContentType bool
+	// MaxResponseSize limits the size (in bytes) of API responses read by the client, such as
+	// search results and tag maps, to guard against a misbehaving server exhausting memory. If
+	// zero, a default of 64 MiB is used. Set to a negative value to disable the limit. Image
+	// content is not subject to this limit.
+	MaxResponseSize int64
+	// StrictRegistryAccess disables the fallback to the library server when direct OCI registry
+	// access fails due to a transport or authorization error. The fallback still occurs when the
+	// library server does not support direct OCI registry access.
+	StrictRegistryAccess bool
+	// ValidateUploads enables validation of images prior to upload. The image must be a
+	// well-formed SIF image, containing a primary system partition of the architecture specified
+	// to UploadImage. If not, ErrInvalidImage is returned before any content is uploaded.
+	ValidateUploads bool
+	// DescribeUploads enables population of the description and labels of uploaded images from
+	// the SIF definition file and labels, when no description is specified to UploadImage.
+	DescribeUploads bool
+	// ShareDownloads enables deduplication of concurrent downloads of the same image, whether
+	// from the OCI registry or the library. The image is downloaded once, and copied to each
+	// destination. Only content downloaded to a seekable destination is shared. Downloads are not
+	// shared with clients returned by Clone.
+	ShareDownloads bool
+	// Timeouts limits the duration of metadata requests, transfers of image parts, and image
+	// uploads and downloads in their entirety (if supplied). These limits are independent of any
+	// Timeout of HTTPClient, which applies to all requests alike.
+	Timeouts *Timeouts
 }
 
 // DefaultConfig is a configuration that uses default values.
@@ -35,14 +168,136 @@ var DefaultConfig = &Config{}
 
 // Client describes the client details.
 type Client struct {
-	baseURL    *url.URL
-	authToken  string
-	userAgent  string
-	httpClient *http.Client
-	logger     log.Logger
+	baseURL            *url.URL
+	authToken          string
+	tokenSource        TokenSource
+	nameResolver       NameResolver
+	userAgent          string
+	httpClient         *http.Client
+	logger             log.Logger
+	retryPolicy        RetryPolicy
+	sleeper            Sleeper
+	verifyChecksums    bool
+	publishChecksums   bool
+	checksumAlgorithms []ChecksumAlgorithm
+	warningHandler     WarningHandler
+	authEventHandler   AuthEventHandler
+	downloader         Downloader
+	upload             UploadOptions
+	warnings           sync.Map // warnings relayed, to prevent repetition
+	contentDecoders    map[string]ContentDecoder
+	acceptEncoding     string
+	compressedImages   bool
+	registryCreds      map[string]RegistryCredentials
+	anonymousRegistry  *url.URL
+	registryHosts      registryHostPolicy
+	insecureRegistries []string
+	insecureWarnings   sync.Map // insecure hosts warned of, to prevent repetition
+	lenientManifests   bool
+	maxResponseSize    int64
+	strictRegistry     bool
+	validateUploads    bool
+	describeUploads    bool
+	sharedDownloads    *sharedDownloads
+	partChecksums      *partChecksumCache
+	multipartThreshold *multipartThresholds
+	inflightRequests   singleflight.Group
+	timeouts           Timeouts
 }
 
-const defaultBaseURL = "https://library.sylabs.io"
+const (
+	defaultBaseURL           = "https://library.sylabs.io"
+	defaultUploadPartRetries = 3
+	defaultPartRetryDelay    = time.Second
+
+	defaultDownloadConcurrency = 1
+	defaultDownloadPartSize    = 5 * 1024 * 1024
+
+	// cleanupTimeout bounds the time spent releasing server-side state (such as upload sessions)
+	// after an operation fails or is cancelled.
+	cleanupTimeout = 30 * time.Second
+)
+
+// cleanupContext returns a context, derived from ctx, that is not cancelled when ctx is
+// cancelled. It is used to release server-side state after an operation fails, which is
+// necessary even (and especially) when the operation failed due to cancellation.
+func cleanupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
+}
+
+// parseBaseURL parses and validates the base URL of the library server s. The path of the returned
+// URL is terminated with a single separator, to prevent url.ResolveReference from stripping the
+// final component of the path when constructing request URLs.
+func parseBaseURL(s string) (*url.URL, error) {
+	// Detect malformed IPv6 literals up front, since url.Parse reports them as invalid ports or
+	// escapes, which is misleading.
+	if host := rawHost(s); strings.Count(host, ":") > 1 && !strings.HasPrefix(host, "[") {
+		return nil, fmt.Errorf("invalid base URL %q: IPv6 address must be enclosed in square brackets", s)
+	} else if i := strings.Index(host, "%"); strings.HasPrefix(host, "[") && i >= 0 && !strings.HasPrefix(host[i:], "%25") {
+		return nil, fmt.Errorf("invalid base URL %q: IPv6 zone identifier must be escaped as \"%%25\"", s)
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q: %w", s, err)
+	}
+
+	if u.Scheme == "" {
+		return nil, fmt.Errorf("invalid base URL %q: missing protocol scheme", s)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported protocol scheme %q", u.Scheme)
+	}
+	if u.Opaque != "" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid base URL %q: missing host", s)
+	}
+	if u.User != nil {
+		return nil, fmt.Errorf("invalid base URL %q: user info not supported (use an auth token)", s)
+	}
+	if u.RawQuery != "" || u.ForceQuery {
+		return nil, fmt.Errorf("invalid base URL %q: query not supported", s)
+	}
+	if u.Fragment != "" {
+		return nil, fmt.Errorf("invalid base URL %q: fragment not supported", s)
+	}
+
+	if strings.HasPrefix(u.Host, "[") {
+		ip, _, _ := strings.Cut(u.Hostname(), "%")
+		if net.ParseIP(ip) == nil || !strings.Contains(ip, ":") {
+			return nil, fmt.Errorf("invalid base URL %q: invalid IPv6 address %q", s, ip)
+		}
+	}
+
+	if p := u.Port(); p != "" {
+		if n, err := strconv.Atoi(p); err != nil || n < 1 || n > 65535 {
+			return nil, fmt.Errorf("invalid base URL %q: invalid port %q", s, p)
+		}
+	} else if strings.HasSuffix(u.Host, ":") {
+		// An empty port is permitted by RFC 3986, but is equivalent to no port.
+		u.Host = strings.TrimSuffix(u.Host, ":")
+	}
+
+	u.Path = strings.TrimRight(u.Path, "/") + "/"
+	if u.RawPath != "" {
+		u.RawPath = strings.TrimRight(u.RawPath, "/") + "/"
+	}
+	return u, nil
+}
+
+// rawHost returns the host (and port) component of the URL s, without parsing it.
+func rawHost(s string) string {
+	_, rest, ok := strings.Cut(s, "://")
+	if !ok {
+		return ""
+	}
+	if i := strings.IndexAny(rest, "/?#"); i >= 0 {
+		rest = rest[:i]
+	}
+	if i := strings.LastIndex(rest, "@"); i >= 0 {
+		rest = rest[i+1:]
+	}
+	return rest
+}
 
 // NewClient sets up a new Cloud-Library Service client with the specified base URL and auth token.
 func NewClient(cfg *Config) (*Client, error) {
@@ -56,32 +311,127 @@ func NewClient(cfg *Config) (*Client, error) {
 		bu = cfg.BaseURL
 	}
 
-	// If baseURL has a path component, ensure it is terminated with a separator, to prevent
-	// url.ResolveReference from stripping the final component of the path when constructing
-	// request URL.
-	if !strings.HasSuffix(bu, "/") {
-		bu += "/"
+	// A library server listening on a Unix domain socket is addressed using a placeholder host,
+	// which the transport resolves to the socket.
+	var socket string
+	if u, err := url.Parse(bu); err == nil && u.Scheme == unixSocketScheme {
+		if cfg.HTTPClient != nil {
+			return nil, fmt.Errorf("unix socket URL %q cannot be used with a supplied HTTP client", bu)
+		}
+		if socket, err = unixSocketPath(u); err != nil {
+			return nil, err
+		}
+		bu = "http://" + unixSocketHost
 	}
 
-	baseURL, err := url.Parse(bu)
+	baseURL, err := parseBaseURL(bu)
 	if err != nil {
 		return nil, err
 	}
-	if baseURL.Scheme != "http" && baseURL.Scheme != "https" {
-		return nil, fmt.Errorf("unsupported protocol scheme %q", baseURL.Scheme)
-	}
 
 	c := &Client{
-		baseURL:   baseURL,
-		authToken: cfg.AuthToken,
-		userAgent: cfg.UserAgent,
+		baseURL:            baseURL,
+		authToken:          cfg.AuthToken,
+		tokenSource:        cfg.TokenSource,
+		nameResolver:       cfg.NameResolver,
+		userAgent:          cfg.UserAgent,
+		verifyChecksums:    cfg.VerifyUploadChecksums,
+		publishChecksums:   cfg.PublishChecksums,
+		registryCreds:      cfg.RegistryCredentials,
+		insecureRegistries: cfg.AllowInsecureRegistries,
+		lenientManifests:   cfg.LenientManifestContentType,
+		strictRegistry:     cfg.StrictRegistryAccess,
+		validateUploads:    cfg.ValidateUploads,
+		describeUploads:    cfg.DescribeUploads,
+		warningHandler:     cfg.WarningHandler,
+		authEventHandler:   cfg.AuthEventHandler,
+		downloader:         Downloader{Concurrency: defaultDownloadConcurrency, PartSize: defaultDownloadPartSize},
+		partChecksums:      newPartChecksumCache(),
+		multipartThreshold: newMultipartThresholds(),
+		registryHosts: registryHostPolicy{
+			allowed:      cfg.AllowedRegistryHosts,
+			denied:       cfg.DeniedRegistryHosts,
+			requireHTTPS: cfg.RequireRegistryHTTPS,
+		},
+	}
+
+	c.checksumAlgorithms = defaultChecksumAlgorithms
+	if cfg.ChecksumAlgorithms != nil {
+		if c.checksumAlgorithms, err = uploadChecksumAlgorithms(cfg.ChecksumAlgorithms); err != nil {
+			return nil, err
+		}
+	}
+
+	if !cfg.DisableCompression {
+		c.contentDecoders = newContentDecoders(cfg.ContentDecoders)
+		c.acceptEncoding = acceptEncoding(c.contentDecoders)
+		c.compressedImages = cfg.CompressedImageDownloads
+	}
+
+	if v := cfg.AnonymousRegistryURL; v != "" {
+		u, err := url.Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("unsupported registry protocol scheme %q", u.Scheme)
+		}
+		c.anonymousRegistry = u
+	}
+
+	c.maxResponseSize = defaultMaxResponseSize
+	if cfg.MaxResponseSize < 0 {
+		c.maxResponseSize = 0
+	} else if cfg.MaxResponseSize > 0 {
+		c.maxResponseSize = cfg.MaxResponseSize
+	}
+
+	if t := cfg.Timeouts; t != nil {
+		c.timeouts = *t
+	}
+
+	if cfg.RetryPolicy != nil {
+		c.retryPolicy = cfg.RetryPolicy
+	} else {
+		retries := defaultUploadPartRetries
+		if cfg.UploadPartRetries < 0 {
+			retries = 0
+		} else if cfg.UploadPartRetries > 0 {
+			retries = cfg.UploadPartRetries
+		}
+		c.retryPolicy = ExponentialBackoff{Retries: retries, Delay: defaultPartRetryDelay}
+	}
+
+	c.sleeper = timerSleeper{}
+	if cfg.Sleeper != nil {
+		c.sleeper = cfg.Sleeper
+	}
+
+	if u := cfg.Upload; u != nil {
+		c.upload = *u
+		if _, err := c.uploadOptions(nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if d := cfg.Downloader; d != nil {
+		if d.Concurrency > 0 {
+			c.downloader.Concurrency = d.Concurrency
+		}
+		if d.PartSize > 0 {
+			c.downloader.PartSize = d.PartSize
+		}
 	}
 
 	// Set HTTP client
 	if cfg.HTTPClient != nil {
 		c.httpClient = cfg.HTTPClient
 	} else {
-		c.httpClient = http.DefaultClient
+		t := newTransport(c.downloader.Concurrency, cfg.Transport)
+		if socket != "" {
+			t.DialContext = unixSocketDialer(socket, t.DialContext)
+		}
+		c.httpClient = &http.Client{Transport: t}
 	}
 
 	if cfg.Logger != nil {
@@ -90,6 +440,18 @@ func NewClient(cfg *Config) (*Client, error) {
 		c.logger = log.DefaultLogger
 	}
 
+	if cfg.ShareDownloads {
+		c.sharedDownloads = newSharedDownloads(c.logger)
+	}
+
+	if cfg.Debug || debugEnabled() {
+		c.httpClient = newDebugHTTPClient(c.httpClient, c.logger)
+	}
+
+	if l := newRequestLimiter(cfg.MaxRequests, cfg.MaxRequestsPerHost); l != nil {
+		c.httpClient = newLimitedHTTPClient(c.httpClient, l)
+	}
+
 	return c, nil
 }
 
@@ -105,7 +467,11 @@ func (c *Client) newRequest(ctx context.Context, method, path, rawQuery string,
 		return nil, err
 	}
 
-	if v := c.authToken; v != "" {
+	v, err := c.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if v != "" {
 		if err := (bearerTokenCredentials{authToken: v}).ModifyRequest(r); err != nil {
 			return nil, err
 		}
@@ -115,5 +481,11 @@ func (c *Client) newRequest(ctx context.Context, method, path, rawQuery string,
 		r.Header.Set("User-Agent", v)
 	}
 
+	if v := c.acceptEncoding; v != "" {
+		r.Header.Set("Accept-Encoding", v)
+	}
+
+	setRequestID(r)
+
 	return r, nil
 }
@@ -0,0 +1,110 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"errors"
+
+	"github.com/go-log/log"
+)
+
+// Option overrides a setting of a client returned by Clone.
+type Option func(*Client) error
+
+// OptBaseURL specifies the base URL of the service. Unix domain socket URLs are not supported,
+// since the transport of the original client is used.
+func OptBaseURL(s string) Option {
+	return func(c *Client) error {
+		u, err := parseBaseURL(s)
+		if err != nil {
+			return err
+		}
+		c.baseURL = u
+		return nil
+	}
+}
+
+// OptAuthToken specifies the auth token included in the Authorization header of each request, in
+// place of any Config.TokenSource. An empty token disables authentication.
+func OptAuthToken(token string) Option {
+	return func(c *Client) error {
+		c.authToken = token
+		c.tokenSource = nil
+		return nil
+	}
+}
+
+// OptUserAgent specifies the user agent included in each request.
+func OptUserAgent(ua string) Option {
+	return func(c *Client) error {
+		c.userAgent = ua
+		return nil
+	}
+}
+
+// OptLogger specifies the logger used when output is generated. HTTP requests logged when debug
+// logging is enabled continue to use the logger of the original client.
+func OptLogger(l log.Logger) Option {
+	return func(c *Client) error {
+		if l == nil {
+			return errors.New("logger is required")
+		}
+		c.logger = l
+		return nil
+	}
+}
+
+// Clone returns a client with the configuration of c, overridden by opts. The returned client
+// shares the HTTP client (and so the transport and its pool of idle connections) of c, so that
+// services acting on behalf of many users, or against many endpoints, do not multiply idle
+// connections. Requests and downloads are not shared across clients, since they may be made with
+// different credentials. c is not modified.
+func (c *Client) Clone(opts ...Option) (*Client, error) {
+	nc := &Client{
+		baseURL:            c.baseURL,
+		authToken:          c.authToken,
+		tokenSource:        c.tokenSource,
+		nameResolver:       c.nameResolver,
+		userAgent:          c.userAgent,
+		httpClient:         c.httpClient,
+		logger:             c.logger,
+		retryPolicy:        c.retryPolicy,
+		sleeper:            c.sleeper,
+		verifyChecksums:    c.verifyChecksums,
+		publishChecksums:   c.publishChecksums,
+		checksumAlgorithms: c.checksumAlgorithms,
+		warningHandler:     c.warningHandler,
+		authEventHandler:   c.authEventHandler,
+		downloader:         c.downloader,
+		upload:             c.upload,
+		contentDecoders:    c.contentDecoders,
+		acceptEncoding:     c.acceptEncoding,
+		compressedImages:   c.compressedImages,
+		registryCreds:      c.registryCreds,
+		anonymousRegistry:  c.anonymousRegistry,
+		registryHosts:      c.registryHosts,
+		insecureRegistries: c.insecureRegistries,
+		lenientManifests:   c.lenientManifests,
+		maxResponseSize:    c.maxResponseSize,
+		strictRegistry:     c.strictRegistry,
+		validateUploads:    c.validateUploads,
+		describeUploads:    c.describeUploads,
+		partChecksums:      c.partChecksums,
+		multipartThreshold: c.multipartThreshold,
+		timeouts:           c.timeouts,
+	}
+
+	for _, opt := range opts {
+		if err := opt(nc); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.sharedDownloads != nil {
+		nc.sharedDownloads = newSharedDownloads(nc.logger)
+	}
+	return nc, nil
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func TestClone(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		conns int
+		auths []string
+	)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		auths = append(auths, r.Header.Get("Authorization"))
+		mu.Unlock()
+
+		if err := jsonresp.WriteResponse(w, VersionInfo{Version: "1.0.0"}, http.StatusOK); err != nil {
+			t.Errorf("error writing JSON response: %v", err)
+		}
+	}))
+	srv.Config.ConnState = func(_ net.Conn, s http.ConnState) {
+		if s == http.StateNew {
+			mu.Lock()
+			conns++
+			mu.Unlock()
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, AuthToken: "token1", Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	cc, err := c.Clone(OptAuthToken("token2"), OptUserAgent("agent"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, c := range []*Client{c, cc, c} {
+		if _, err := c.GetVersion(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if got, want := auths, []string{"Bearer token1", "Bearer token2", "Bearer token1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got authorization %v, want %v", got, want)
+	}
+	if got, want := conns, 1; got != want {
+		t.Errorf("got %v connection(s), want %v", got, want)
+	}
+
+	if got, want := c.userAgent, ""; got != want {
+		t.Errorf("got original user agent %q, want %q", got, want)
+	}
+}
+
+func TestCloneOptions(t *testing.T) {
+	c, err := NewClient(&Config{Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		expectErr bool
+	}{
+		{"None", nil, false},
+		{"BaseURL", []Option{OptBaseURL("https://library.example.com/api")}, false},
+		{"BadBaseURL", []Option{OptBaseURL("ftp://library.example.com")}, true},
+		{"UnixSocket", []Option{OptBaseURL("unix:///run/library.sock")}, true},
+		{"Logger", []Option{OptLogger(testLogger)}, false},
+		{"NilLogger", []Option{OptLogger(nil)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc, err := c.Clone(tt.opts...)
+			if tt.expectErr && err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+
+			if cc.httpClient != c.httpClient {
+				t.Error("HTTP client not shared")
+			}
+		})
+	}
+}
+
+// TestCloneFields ensures that Clone copies each field of Client, so that fields added to Client
+// are not overlooked.
+func TestCloneFields(t *testing.T) {
+	c, err := NewClient(&Config{
+		AuthToken:                  "token",
+		TokenSource:                &testTokenSource{tokens: []string{"token"}},
+		NameResolver:               StaticNameResolver{"alpine": "library/default/alpine"},
+		UserAgent:                  "agent",
+		Logger:                     testLogger,
+		Sleeper:                    noSleep,
+		WarningHandler:             func(ServerWarning) {},
+		AuthEventHandler:           func(AuthEvent) {},
+		VerifyUploadChecksums:      true,
+		PublishChecksums:           true,
+		RegistryCredentials:        map[string]RegistryCredentials{"registry.example.com": {}},
+		AnonymousRegistryURL:       "https://registry.example.com",
+		AllowedRegistryHosts:       []string{"registry.example.com"},
+		AllowInsecureRegistries:    []string{"registry.example.com"},
+		LenientManifestContentType: true,
+		StrictRegistryAccess:       true,
+		ValidateUploads:            true,
+		DescribeUploads:            true,
+		ShareDownloads:             true,
+		CompressedImageDownloads:   true,
+		Timeouts:                   &Timeouts{Operation: 1},
+		Upload:                     &UploadOptions{Private: true},
+	})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	cc, err := c.Clone()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Fields that hold per-client state, and are not copied.
+	perClient := map[string]bool{"warnings": true, "insecureWarnings": true, "inflightRequests": true, "sharedDownloads": true}
+
+	if cc.sharedDownloads == nil || cc.sharedDownloads == c.sharedDownloads {
+		t.Errorf("got shared downloads %p, want new instance", cc.sharedDownloads)
+	}
+
+	v, cv := reflect.ValueOf(c).Elem(), reflect.ValueOf(cc).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Name
+		if perClient[name] {
+			continue
+		}
+
+		f, cf := v.Field(i), cv.Field(i)
+		if f.IsZero() {
+			t.Errorf("field %v not populated by test", name)
+			continue
+		}
+
+		// Fields are unexported, so are compared by their formatted values (pointers by address).
+		if got, want := fmt.Sprint(cf), fmt.Sprint(f); got != want {
+			t.Errorf("field %v not copied: got %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// coalesce calls fn to perform the idempotent request identified by key (ie. method and URL),
+// unless an identical request is already in progress, in which case its result is shared. If g is
+// nil, fn is called directly.
+//
+// The result of a shared request must be treated as read-only. If a shared request is interrupted
+// because the context of the caller that issued it is done, fn is called again on behalf of
+// callers whose context is not done.
+func coalesce(ctx context.Context, g *singleflight.Group, key string, fn func() (interface{}, error)) (interface{}, error) {
+	if g == nil {
+		return fn()
+	}
+
+	select {
+	case res := <-g.DoChan(key, fn):
+		if res.Err != nil && res.Shared && ctx.Err() == nil && isContextError(res.Err) {
+			return fn()
+		}
+		return res.Val, res.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// isContextError returns true if err results from a context being cancelled or timing out.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	jsonresp "github.com/sylabs/json-resp"
+	"golang.org/x/sync/singleflight"
+)
+
+func TestCoalescedRequests(t *testing.T) {
+	const n = 8
+
+	var requests atomic.Int32
+	var ready sync.WaitGroup
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		// Hold the response until all callers have issued their request.
+		ready.Wait()
+		time.Sleep(50 * time.Millisecond)
+
+		switch r.URL.Path {
+		case "/version":
+			if err := jsonresp.WriteResponse(w, VersionInfo{Version: "1.0.0", APIVersion: "2.0.0"}, http.StatusOK); err != nil {
+				t.Errorf("error writing JSON response: %v", err)
+			}
+		case "/v1/images/entity/collection/container:latest":
+			if err := jsonresp.WriteResponse(w, testImage, http.StatusOK); err != nil {
+				t.Errorf("error writing JSON response: %v", err)
+			}
+		default:
+			t.Errorf("unexpected request to %v", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		fn   func(context.Context, *Client) (interface{}, error)
+		want interface{}
+	}{
+		{
+			name: "GetVersion",
+			fn: func(ctx context.Context, c *Client) (interface{}, error) {
+				return c.GetVersion(ctx)
+			},
+			want: VersionInfo{Version: "1.0.0", APIVersion: "2.0.0"},
+		},
+		{
+			name: "GetImage",
+			fn: func(ctx context.Context, c *Client) (interface{}, error) {
+				return c.GetImage(ctx, "", "entity/collection/container:latest")
+			},
+			want: &testImage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+			ready.Add(n)
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			var wg sync.WaitGroup
+			for i := 0; i < n; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					ready.Done()
+
+					got, err := tt.fn(context.Background(), c)
+					if err != nil {
+						t.Errorf("unexpected error: %v", err)
+					}
+					if !reflect.DeepEqual(got, tt.want) {
+						t.Errorf("got %v, want %v", got, tt.want)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if got := requests.Load(); got != 1 {
+				t.Errorf("got %v requests, want 1", got)
+			}
+		})
+	}
+}
+
+func Test_coalesce(t *testing.T) {
+	var g singleflight.Group
+
+	t.Run("LeaderCancelled", func(t *testing.T) {
+		leaderCtx, cancel := context.WithCancel(context.Background())
+
+		started := make(chan struct{})
+		leaderErr := make(chan error, 1)
+		go func() {
+			_, err := coalesce(leaderCtx, &g, "key", func() (interface{}, error) {
+				close(started)
+				<-leaderCtx.Done()
+				return nil, leaderCtx.Err()
+			})
+			leaderErr <- err
+		}()
+		<-started
+
+		followerRes := make(chan interface{}, 1)
+		go func() {
+			v, err := coalesce(context.Background(), &g, "key", func() (interface{}, error) {
+				return "retried", nil
+			})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			followerRes <- v
+		}()
+
+		// Allow the follower to join the request in progress.
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+
+		if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+			t.Errorf("got error %v, want %v", err, context.Canceled)
+		}
+		if got := <-followerRes; got != "retried" {
+			t.Errorf("got %v, want retried", got)
+		}
+	})
+
+	t.Run("NilGroup", func(t *testing.T) {
+		v, err := coalesce(context.Background(), nil, "key", func() (interface{}, error) {
+			return "value", nil
+		})
+		if err != nil || v != "value" {
+			t.Errorf("got %v, %v, want value", v, err)
+		}
+	})
+}
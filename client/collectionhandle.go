@@ -0,0 +1,108 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CollectionHandle provides access to the containers of a collection, identified by references
+// relative to the collection (ie. "container:tag"), rather than the full path of each container.
+// It is a convenience layer over the methods of Client.
+type CollectionHandle struct {
+	c   *Client
+	ref string // "entity/collection"
+}
+
+// Collection returns a handle to the collection identified by ref (ie. "entity/collection"). The
+// existence of the collection is not checked.
+func (c *Client) Collection(ref string) (*CollectionHandle, error) {
+	ref = strings.TrimPrefix(ref, "/")
+
+	entity, collection, ok := strings.Cut(ref, "/")
+	if !ok || !IsRefPart(entity) || !IsRefPart(collection) {
+		return nil, fmt.Errorf("malformed collection ref: %s", ref)
+	}
+	return &CollectionHandle{c: c, ref: ref}, nil
+}
+
+// Ref returns the reference of the collection (ie. "entity/collection").
+func (h *CollectionHandle) Ref() string {
+	return h.ref
+}
+
+// path returns the path of the container identified by the relative reference ref (ie.
+// "container:tag"), and its tags. If no tags are specified, "latest" is returned.
+func (h *CollectionHandle) path(ref string) (string, []string, error) {
+	name, tags, err := parsePath(ref)
+	if err != nil {
+		return "", nil, fmt.Errorf("malformed container ref %q: %w", ref, err)
+	}
+	if !IsRefPart(name) {
+		return "", nil, fmt.Errorf("malformed container ref: %s", ref)
+	}
+
+	if len(tags) == 0 {
+		tags = []string{"latest"}
+	}
+	return h.ref + "/" + name, tags, nil
+}
+
+// Push uploads the image read from r to the container identified by ref (ie. "container:tag" or
+// "container:tag1,tag2"), applying the specified tags. If no tags are specified, the image is
+// tagged "latest". See UploadImage.
+func (h *CollectionHandle) Push(ctx context.Context, ref string, r io.ReadSeeker, arch, description string, opts ...UploadOption) (*UploadImageComplete, error) {
+	path, tags, err := h.path(ref)
+	if err != nil {
+		return nil, err
+	}
+	return h.c.UploadImage(ctx, r, path, arch, tags, description, nil, opts...)
+}
+
+// Pull downloads the image identified by ref (ie. "container:tag") to dst, as specified by opts.
+// If no tag is specified, the image tagged "latest" is downloaded. The Tag of opts is ignored. See
+// PullImage.
+func (h *CollectionHandle) Pull(ctx context.Context, dst io.WriterAt, ref string, opts *DownloadOptions) (*TransferSummary, error) {
+	path, tags, err := h.path(ref)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) > 1 {
+		return nil, fmt.Errorf("malformed container ref %q: multiple tags", ref)
+	}
+
+	var o DownloadOptions
+	if opts != nil {
+		o = *opts
+	}
+	o.Tag = tags[0]
+
+	return h.c.PullImage(ctx, dst, path, &o)
+}
+
+// Tags returns the sorted tags of the container with the specified name. See ListTags.
+func (h *CollectionHandle) Tags(ctx context.Context, container string) ([]string, error) {
+	if !IsRefPart(container) {
+		return nil, fmt.Errorf("malformed container name: %s", container)
+	}
+	return h.c.ListTags(ctx, h.ref+"/"+container)
+}
+
+// Delete deletes the image identified by ref (ie. "container:tag") for the specified
+// architecture. If no tag is specified, the image tagged "latest" is deleted. See DeleteImage.
+func (h *CollectionHandle) Delete(ctx context.Context, ref, arch string) error {
+	path, tags, err := h.path(ref)
+	if err != nil {
+		return err
+	}
+	if len(tags) > 1 {
+		return fmt.Errorf("malformed container ref %q: multiple tags", ref)
+	}
+	return h.c.DeleteImage(ctx, path+":"+tags[0], arch)
+}
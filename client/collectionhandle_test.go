@@ -0,0 +1,157 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func TestClientCollection(t *testing.T) {
+	tests := []struct {
+		name      string
+		ref       string
+		want      string
+		expectErr bool
+	}{
+		{"OK", "entity/collection", "entity/collection", false},
+		{"LeadingSlash", "/entity/collection", "entity/collection", false},
+		{"Entity", "entity", "", true},
+		{"Container", "entity/collection/container", "", true},
+		{"Uppercase", "Entity/collection", "", true},
+	}
+
+	c, err := NewClient(&Config{Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := c.Collection(tt.ref)
+			if tt.expectErr && err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+
+			if got := h.Ref(); got != tt.want {
+				t.Errorf("got ref %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectionHandle_path(t *testing.T) {
+	tests := []struct {
+		name      string
+		ref       string
+		wantPath  string
+		wantTags  []string
+		expectErr bool
+	}{
+		{"NoTag", "container", "entity/collection/container", []string{"latest"}, false},
+		{"Tag", "container:v1", "entity/collection/container", []string{"v1"}, false},
+		{"Tags", "container:v1,v2", "entity/collection/container", []string{"v1", "v2"}, false},
+		{"Empty", "", "", nil, true},
+		{"EmptyTag", "container:", "", nil, true},
+		{"Path", "other/container:v1", "", nil, true},
+		{"Colons", "container:v1:v2", "", nil, true},
+	}
+
+	h := &CollectionHandle{ref: "entity/collection"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, tags, err := h.path(tt.ref)
+			if tt.expectErr && err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if path != tt.wantPath {
+				t.Errorf("got path %v, want %v", path, tt.wantPath)
+			}
+			if !reflect.DeepEqual(tags, tt.wantTags) {
+				t.Errorf("got tags %v, want %v", tags, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestCollectionHandle(t *testing.T) {
+	var deleted []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body interface{}
+
+		switch {
+		case r.URL.Path == "/v1/oci-redirect":
+			// Direct OCI registry access is not supported.
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case r.URL.Path == "/v1/containers/entity/collection/container":
+			body = Container{ID: "containerID"}
+		case r.URL.Path == "/v2/tags/containerID":
+			body = ArchTagMap{"amd64": {"v1": "imageID", "latest": "imageID"}}
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path+"?"+r.URL.RawQuery)
+		default:
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if err := jsonresp.WriteResponse(w, body, http.StatusOK); err != nil {
+			t.Errorf("error writing JSON response: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	h, err := c.Collection("entity/collection")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tags, err := h.Tags(context.Background(), "container")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := tags, []string{"latest", "v1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got tags %v, want %v", got, want)
+	}
+
+	if _, err := h.Tags(context.Background(), "other/container"); err == nil {
+		t.Error("unexpected success")
+	}
+
+	if err := h.Delete(context.Background(), "container:v1", "amd64"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := h.Delete(context.Background(), "container:v1,v2", "amd64"); err == nil {
+		t.Error("unexpected success")
+	}
+
+	if got, want := deleted, []string{"/v1/images/entity/collection/container:v1?arch=amd64"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got deleted %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ContentDecoder returns a reader that decodes content read from r, which has been encoded using
+// a HTTP content coding (such as "zstd").
+type ContentDecoder func(r io.Reader) (io.ReadCloser, error)
+
+// gzipDecoder decodes content encoded using the "gzip" content coding.
+func gzipDecoder(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// newContentDecoders returns the content decoders supported by the client, consisting of the
+// built-in "gzip" decoder, and the decoders in extra. Encodings are case-insensitive.
+func newContentDecoders(extra map[string]ContentDecoder) map[string]ContentDecoder {
+	decoders := map[string]ContentDecoder{
+		"gzip": gzipDecoder,
+	}
+	for enc, dec := range extra {
+		if dec != nil {
+			decoders[strings.ToLower(enc)] = dec
+		}
+	}
+	return decoders
+}
+
+// acceptEncoding returns the value of the Accept-Encoding header advertising decoders.
+func acceptEncoding(decoders map[string]ContentDecoder) string {
+	encodings := make([]string, 0, len(decoders))
+	for enc := range decoders {
+		encodings = append(encodings, enc)
+	}
+	sort.Strings(encodings)
+
+	return strings.Join(encodings, ", ")
+}
+
+// decodedBody reads decoded content from the body of a response, closing both the decoder and
+// the underlying body when closed. The encoded bytes read from the body are counted by wire.
+type decodedBody struct {
+	io.ReadCloser
+	body io.Closer
+	wire *networkCounter
+}
+
+func (b *decodedBody) Close() error {
+	err := b.ReadCloser.Close()
+	if berr := b.body.Close(); err == nil {
+		err = berr
+	}
+	return err
+}
+
+// decodeResponse replaces the body of res with the decoded content, if the response content has
+// been encoded in accordance with the Accept-Encoding header of the request. As with responses
+// decoded by net/http, the Content-Encoding and Content-Length headers are removed, and
+// res.Uncompressed is set.
+func (c *Client) decodeResponse(res *http.Response) error {
+	if res.Request == nil || res.Request.Method == http.MethodHead {
+		return nil
+	}
+	if res.StatusCode == http.StatusNoContent || res.StatusCode == http.StatusNotModified {
+		return nil
+	}
+
+	// Only decode content if an encoding was negotiated by the client.
+	if ae := res.Request.Header.Get("Accept-Encoding"); ae == "" || ae == "identity" {
+		return nil
+	}
+
+	enc := strings.ToLower(strings.TrimSpace(res.Header.Get("Content-Encoding")))
+	if enc == "" || enc == "identity" {
+		return nil
+	}
+
+	dec, ok := c.contentDecoders[enc]
+	if !ok {
+		res.Body.Close()
+		return fmt.Errorf("unsupported Content-Encoding %q", enc)
+	}
+
+	wire := &networkCounter{r: res.Body}
+
+	r, err := dec(wire)
+	if err != nil {
+		res.Body.Close()
+		return fmt.Errorf("decoding %v content: %w", enc, err)
+	}
+
+	res.Body = &decodedBody{ReadCloser: r, body: res.Body, wire: wire}
+	res.Header.Del("Content-Encoding")
+	res.Header.Del("Content-Length")
+	res.ContentLength = -1
+	res.Uncompressed = true
+
+	return nil
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func gzipData(t *testing.T, b []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// reverse returns a byte-reversed copy of b.
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return r
+}
+
+// reverseDecoder is a ContentDecoder for the (fictional) "x-reverse" content coding, in which
+// content is byte-reversed.
+func reverseDecoder(r io.Reader) (io.ReadCloser, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(reverse(b))), nil
+}
+
+func TestCompressedMetadata(t *testing.T) {
+	const body = `{"data":{"name":"entity"}}`
+
+	tests := []struct {
+		name               string
+		disableCompression bool
+		decoders           map[string]ContentDecoder
+		encoding           string
+		wantAcceptEncoding string
+	}{
+		{"Gzip", false, nil, "gzip", "gzip"},
+		{"Identity", false, nil, "", "gzip"},
+		{"Extra", false, map[string]ContentDecoder{"X-Reverse": reverseDecoder}, "x-reverse", "gzip, x-reverse"},
+		{"Disabled", true, map[string]ContentDecoder{"x-reverse": reverseDecoder}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ae := r.Header.Get("Accept-Encoding")
+				if tt.wantAcceptEncoding != "" && ae != tt.wantAcceptEncoding {
+					t.Errorf("got Accept-Encoding %q, want %q", ae, tt.wantAcceptEncoding)
+				}
+				if tt.wantAcceptEncoding == "" && strings.Contains(ae, "x-reverse") {
+					t.Errorf("unexpected Accept-Encoding %q", ae)
+				}
+
+				b := []byte(body)
+
+				switch tt.encoding {
+				case "gzip":
+					b = gzipData(t, b)
+				case "x-reverse":
+					b = reverse(b)
+				}
+
+				if tt.encoding != "" {
+					w.Header().Set("Content-Encoding", tt.encoding)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write(b)
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{
+				BaseURL:            srv.URL,
+				Logger:             testLogger,
+				DisableCompression: tt.disableCompression,
+				ContentDecoders:    tt.decoders,
+			})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			e, err := c.GetEntity(context.Background(), "entity")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got, want := e.Name, "entity"; got != want {
+				t.Errorf("got name %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestCompressedImageDownload(t *testing.T) {
+	sampleBytes := bytes.Repeat([]byte("0123456789"), 10*1024)
+	size := int64(len(sampleBytes))
+	compressedBytes := gzipData(t, sampleBytes)
+
+	tests := []struct {
+		name        string
+		compressed  bool
+		wantNetwork int64
+	}{
+		{"Compressed", true, int64(len(compressedBytes))},
+		{"Uncompressed", false, size},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/version", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = io.WriteString(w, `{"data": {"apiVersion": "1.0.0"}}`)
+			})
+			mux.HandleFunc("/v1/images/", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = io.WriteString(w, fmt.Sprintf(`{"data": {"size": %v}}`, size))
+			})
+			mux.HandleFunc("/v1/imagefile/", func(w http.ResponseWriter, r *http.Request) {
+				if ae := r.Header.Get("Accept-Encoding"); ae == "identity" {
+					writeBlob(t, sampleBytes, 0, size-1, http.StatusOK, w)
+					return
+				}
+
+				w.Header().Set("Content-Encoding", "gzip")
+				w.Header().Set("Content-Length", fmt.Sprint(len(compressedBytes)))
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write(compressedBytes)
+			})
+
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger, CompressedImageDownloads: tt.compressed})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			dst := &inMemoryBuffer{buf: make([]byte, size)}
+			pb := &proxyCountingProgressBar{}
+
+			ctx, stats := withTransferStats(context.Background())
+
+			err = c.libraryDownloadImage(ctx, "amd64", "entity/collection/container", "tag", dst, nil, pb)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !bytes.Equal(dst.Bytes(), sampleBytes) {
+				t.Error("unexpected image content")
+			}
+
+			if got, want := pb.size, size; got != want {
+				t.Errorf("got progress size %v, want %v", got, want)
+			}
+			if got, want := pb.n, size; got != want {
+				t.Errorf("got progress %v, want %v", got, want)
+			}
+			if got, want := pb.network, tt.wantNetwork; got != want {
+				t.Errorf("got network progress %v, want %v", got, want)
+			}
+
+			s := stats.summary()
+			if got, want := s.Bytes, size; got != want {
+				t.Errorf("got %v bytes, want %v", got, want)
+			}
+			if got, want := s.NetworkBytes, tt.wantNetwork; got != want {
+				t.Errorf("got %v network bytes, want %v", got, want)
+			}
+		})
+	}
+}
+
+// proxyCountingProgressBar records the size it is initialised with, the number of bytes read
+// through its proxy reader, and the number of bytes received over the network.
+type proxyCountingProgressBar struct {
+	NoopProgressBar
+
+	size    int64
+	n       int64
+	network int64
+}
+
+func (pb *proxyCountingProgressBar) Init(size int64) { pb.size = size }
+
+func (pb *proxyCountingProgressBar) IncrNetworkBy(n int) { pb.network += int64(n) }
+
+func (pb *proxyCountingProgressBar) ProxyReader(r io.Reader) io.ReadCloser {
+	return io.NopCloser(&countingReader{r: r, n: &pb.n})
+}
+
+type countingReader struct {
+	r io.Reader
+	n *int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	*cr.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// configFile is the on-disk representation of a client configuration.
+type configFile struct {
+	// BaseURL of the service.
+	BaseURL string `json:"baseURL,omitempty"`
+	// AuthTokenFile is the path of a file containing the auth token. Relative paths are resolved
+	// relative to the directory containing the configuration file.
+	AuthTokenFile string `json:"authTokenFile,omitempty"`
+	// AuthTokenEnv is the name of an environment variable containing the auth token. It takes
+	// precedence over AuthTokenFile when the environment variable is set.
+	AuthTokenEnv string `json:"authTokenEnv,omitempty"`
+	// UserAgent to include in each request.
+	UserAgent string `json:"userAgent,omitempty"`
+	// UploadPartRetries is the number of times a failed multipart upload part is retried.
+	UploadPartRetries int `json:"uploadPartRetries,omitempty"`
+	// VerifyUploadChecksums enables verification of upload checksums.
+	VerifyUploadChecksums bool `json:"verifyUploadChecksums,omitempty"`
+	// ChecksumAlgorithms computed over an image prior to upload.
+	ChecksumAlgorithms []ChecksumAlgorithm `json:"checksumAlgorithms,omitempty"`
+	// Debug enables logging of HTTP traffic.
+	Debug bool `json:"debug,omitempty"`
+	// MaxRequests limits the number of concurrent in-flight HTTP requests.
+	MaxRequests int `json:"maxRequests,omitempty"`
+	// MaxRequestsPerHost limits the number of concurrent in-flight HTTP requests to a single host.
+	MaxRequestsPerHost int `json:"maxRequestsPerHost,omitempty"`
+	// PublishChecksums enables publication of image checksums on upload.
+	PublishChecksums bool `json:"publishChecksums,omitempty"`
+	// DisableCompression disables negotiation of compressed responses.
+	DisableCompression bool `json:"disableCompression,omitempty"`
+	// CompressedImageDownloads enables negotiation of compressed image content.
+	CompressedImageDownloads bool `json:"compressedImageDownloads,omitempty"`
+	// LenientManifestContentType relaxes the Content-Type check of OCI manifests.
+	LenientManifestContentType bool `json:"lenientManifestContentType,omitempty"`
+	// StrictRegistryAccess disables the fallback to the library on OCI registry access errors.
+	StrictRegistryAccess bool `json:"strictRegistryAccess,omitempty"`
+	// AllowedRegistryHosts restricts the OCI registries to which the client may be redirected.
+	AllowedRegistryHosts []string `json:"allowedRegistryHosts,omitempty"`
+	// DeniedRegistryHosts lists OCI registries to which the client refuses redirection.
+	DeniedRegistryHosts []string `json:"deniedRegistryHosts,omitempty"`
+	// RequireRegistryHTTPS refuses redirection to OCI registries not accessed using HTTPS.
+	RequireRegistryHTTPS bool `json:"requireRegistryHTTPS,omitempty"`
+	// AllowInsecureRegistries lists hosts that may be accessed using plain HTTP.
+	AllowInsecureRegistries []string `json:"allowInsecureRegistries,omitempty"`
+	// ValidateUploads enables validation of images prior to upload.
+	ValidateUploads bool `json:"validateUploads,omitempty"`
+	// DescribeUploads enables population of image descriptions and labels from SIF metadata.
+	DescribeUploads bool `json:"describeUploads,omitempty"`
+	// ShareDownloads enables deduplication of concurrent downloads of the same image.
+	ShareDownloads bool `json:"shareDownloads,omitempty"`
+	// NameMappings maps short image names to fully-qualified names, without consulting the
+	// library server.
+	NameMappings map[string]string `json:"nameMappings,omitempty"`
+	// Upload contains options applied to image uploads.
+	Upload *uploadConfig `json:"upload,omitempty"`
+	// Download contains default download transfer parameters.
+	Download *downloadConfig `json:"download,omitempty"`
+	// Timeouts contains limits on the duration of operations.
+	Timeouts *timeoutsConfig `json:"timeouts,omitempty"`
+}
+
+// uploadConfig is the on-disk representation of upload options. Options that are omitted take
+// their default value.
+type uploadConfig struct {
+	CreateMissing       *bool  `json:"createMissing,omitempty"`
+	Private             bool   `json:"private,omitempty"`
+	DescriptionTemplate string `json:"descriptionTemplate,omitempty"`
+	SkipDedup           bool   `json:"skipDedup,omitempty"`
+	RequireMultipart    bool   `json:"requireMultipart,omitempty"`
+	VerifyBlobs         bool   `json:"verifyBlobs,omitempty"`
+	HashReadAhead       int    `json:"hashReadAhead,omitempty"`
+	HashBufferSize      int64  `json:"hashBufferSize,omitempty"`
+}
+
+// downloadConfig is the on-disk representation of default download transfer parameters.
+type downloadConfig struct {
+	Concurrency uint  `json:"concurrency,omitempty"`
+	PartSize    int64 `json:"partSize,omitempty"`
+}
+
+// timeoutsConfig is the on-disk representation of operation timeouts. Durations are expressed in
+// the format accepted by time.ParseDuration (ie. "30s" or "2h").
+type timeoutsConfig struct {
+	Metadata  string `json:"metadata,omitempty"`
+	Part      string `json:"part,omitempty"`
+	Operation string `json:"operation,omitempty"`
+}
+
+// timeouts returns the Timeouts represented by tc.
+func (tc *timeoutsConfig) timeouts() (*Timeouts, error) {
+	var t Timeouts
+
+	for _, d := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"metadata", tc.Metadata, &t.Metadata},
+		{"part", tc.Part, &t.Part},
+		{"operation", tc.Operation, &t.Operation},
+	} {
+		if d.value == "" {
+			continue
+		}
+
+		v, err := time.ParseDuration(d.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v timeout: %w", d.name, err)
+		}
+		*d.dst = v
+	}
+
+	return &t, nil
+}
+
+// LoadConfig reads a client configuration from the file at path, allowing a standard transfer
+// policy to be distributed to all nodes. The file must be in JSON format; YAML is not supported,
+// other than YAML documents that are also valid JSON. Credentials are not stored in the file
+// directly; the file instead references the file or environment variable from which the auth
+// token is read. For example:
+//
+//	{
+//	  "baseURL": "https://library.example.com",
+//	  "authTokenFile": "token",
+//	  "uploadPartRetries": 5,
+//	  "upload": {"createMissing": false},
+//	  "download": {"concurrency": 8, "partSize": 16777216},
+//	  "timeouts": {"metadata": "30s", "part": "10m", "operation": "6h"}
+//	}
+//
+// Fields of the returned Config that are not supported by the file format (such as Logger) may be
+// set by the caller prior to calling NewClient.
+func LoadConfig(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config: %w", err)
+	}
+
+	var cf configFile
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&cf); err != nil {
+		return nil, fmt.Errorf("error parsing config %v: %w", path, err)
+	}
+
+	cfg := &Config{
+		BaseURL:                    cf.BaseURL,
+		UserAgent:                  cf.UserAgent,
+		UploadPartRetries:          cf.UploadPartRetries,
+		VerifyUploadChecksums:      cf.VerifyUploadChecksums,
+		ChecksumAlgorithms:         cf.ChecksumAlgorithms,
+		Debug:                      cf.Debug,
+		MaxRequests:                cf.MaxRequests,
+		MaxRequestsPerHost:         cf.MaxRequestsPerHost,
+		PublishChecksums:           cf.PublishChecksums,
+		DisableCompression:         cf.DisableCompression,
+		CompressedImageDownloads:   cf.CompressedImageDownloads,
+		LenientManifestContentType: cf.LenientManifestContentType,
+		StrictRegistryAccess:       cf.StrictRegistryAccess,
+		AllowedRegistryHosts:       cf.AllowedRegistryHosts,
+		DeniedRegistryHosts:        cf.DeniedRegistryHosts,
+		RequireRegistryHTTPS:       cf.RequireRegistryHTTPS,
+		AllowInsecureRegistries:    cf.AllowInsecureRegistries,
+		ValidateUploads:            cf.ValidateUploads,
+		DescribeUploads:            cf.DescribeUploads,
+		ShareDownloads:             cf.ShareDownloads,
+	}
+
+	if len(cf.NameMappings) > 0 {
+		cfg.NameResolver = StaticNameResolver(cf.NameMappings)
+	}
+
+	if cfg.AuthToken, err = cf.authToken(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+
+	if u := cf.Upload; u != nil {
+		cfg.Upload = &UploadOptions{
+			DisableCreateMissing: u.CreateMissing != nil && !*u.CreateMissing,
+			Private:              u.Private,
+			DescriptionTemplate:  u.DescriptionTemplate,
+			SkipDedup:            u.SkipDedup,
+			RequireMultipart:     u.RequireMultipart,
+			VerifyBlobs:          u.VerifyBlobs,
+			HashReadAhead:        u.HashReadAhead,
+			HashBufferSize:       u.HashBufferSize,
+		}
+	}
+
+	if d := cf.Download; d != nil {
+		cfg.Downloader = &Downloader{
+			Concurrency: d.Concurrency,
+			PartSize:    d.PartSize,
+		}
+	}
+
+	if tc := cf.Timeouts; tc != nil {
+		if cfg.Timeouts, err = tc.timeouts(); err != nil {
+			return nil, fmt.Errorf("error parsing config %v: %w", path, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// authToken returns the auth token referenced by cf. Relative token file paths are resolved
+// relative to dir.
+func (cf *configFile) authToken(dir string) (string, error) {
+	if cf.AuthTokenEnv != "" {
+		if v, ok := os.LookupEnv(cf.AuthTokenEnv); ok {
+			return strings.TrimSpace(v), nil
+		}
+	}
+
+	if cf.AuthTokenFile == "" {
+		return "", nil
+	}
+
+	path := cf.AuthTokenFile
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading auth token: %w", err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
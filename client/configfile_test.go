@@ -0,0 +1,173 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "token"), []byte("file-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("TEST_LIBRARY_TOKEN", "env-token")
+
+	tests := []struct {
+		name      string
+		content   string
+		want      *Config
+		expectErr bool
+	}{
+		{
+			name:    "Empty",
+			content: `{}`,
+			want:    &Config{},
+		},
+		{
+			name: "Full",
+			content: `{
+  "baseURL": "https://library.example.com",
+  "authTokenFile": "token",
+  "userAgent": "fleet/1.0",
+  "uploadPartRetries": 5,
+  "verifyUploadChecksums": true,
+  "checksumAlgorithms": ["sha256", "crc32c"],
+  "debug": true,
+  "maxRequests": 16,
+  "maxRequestsPerHost": 4,
+  "upload": {"createMissing": false, "private": true, "descriptionTemplate": "{{.Description}}"},
+  "download": {"concurrency": 8, "partSize": 16777216},
+  "timeouts": {"metadata": "30s", "part": "10m", "operation": "6h"}
+}`,
+			want: &Config{
+				BaseURL:               "https://library.example.com",
+				AuthToken:             "file-token",
+				UserAgent:             "fleet/1.0",
+				UploadPartRetries:     5,
+				VerifyUploadChecksums: true,
+				ChecksumAlgorithms:    []ChecksumAlgorithm{ChecksumSHA256, ChecksumCRC32C},
+				Debug:                 true,
+				MaxRequests:           16,
+				MaxRequestsPerHost:    4,
+				Upload:                &UploadOptions{DisableCreateMissing: true, Private: true, DescriptionTemplate: "{{.Description}}"},
+				Downloader:            &Downloader{Concurrency: 8, PartSize: 16777216},
+				Timeouts:              &Timeouts{Metadata: 30 * time.Second, Part: 10 * time.Minute, Operation: 6 * time.Hour},
+			},
+		},
+		{
+			name:    "NameMappings",
+			content: `{"nameMappings": {"alpine": "library/default/alpine"}}`,
+			want:    &Config{NameResolver: StaticNameResolver{"alpine": "library/default/alpine"}},
+		},
+		{
+			name:    "RegistryHosts",
+			content: `{"allowedRegistryHosts": ["*.example.com"], "deniedRegistryHosts": ["bad.example.com"], "requireRegistryHTTPS": true}`,
+			want: &Config{
+				AllowedRegistryHosts: []string{"*.example.com"},
+				DeniedRegistryHosts:  []string{"bad.example.com"},
+				RequireRegistryHTTPS: true,
+			},
+		},
+		{
+			name:    "InsecureRegistries",
+			content: `{"allowInsecureRegistries": ["registry.example.com"]}`,
+			want:    &Config{AllowInsecureRegistries: []string{"registry.example.com"}},
+		},
+		{
+			name:    "UploadDefaults",
+			content: `{"upload": {}}`,
+			want:    &Config{Upload: &UploadOptions{}},
+		},
+		{
+			name:    "TokenEnv",
+			content: `{"authTokenFile": "token", "authTokenEnv": "TEST_LIBRARY_TOKEN"}`,
+			want:    &Config{AuthToken: "env-token"},
+		},
+		{
+			name:    "TokenEnvNotSet",
+			content: `{"authTokenFile": "token", "authTokenEnv": "TEST_LIBRARY_TOKEN_NOT_SET"}`,
+			want:    &Config{AuthToken: "file-token"},
+		},
+		{
+			name:      "TokenFileMissing",
+			content:   `{"authTokenFile": "missing"}`,
+			expectErr: true,
+		},
+		{
+			name:      "InvalidTimeout",
+			content:   `{"timeouts": {"part": "10"}}`,
+			expectErr: true,
+		},
+		{
+			name:      "UnknownField",
+			content:   `{"endpoint": "https://library.example.com"}`,
+			expectErr: true,
+		},
+		{
+			name:      "Malformed",
+			content:   `{`,
+			expectErr: true,
+		},
+		{
+			name:      "YAML",
+			content:   "baseURL: https://library.example.com\n",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := LoadConfig(path)
+			if tt.expectErr && err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(cfg, tt.want) {
+				t.Errorf("got config %+v, want %+v", cfg, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClientDownloader(t *testing.T) {
+	tests := []struct {
+		name       string
+		downloader *Downloader
+		want       Downloader
+	}{
+		{"Default", nil, Downloader{Concurrency: 1, PartSize: 5 * 1024 * 1024}},
+		{"Concurrency", &Downloader{Concurrency: 4}, Downloader{Concurrency: 4, PartSize: 5 * 1024 * 1024}},
+		{"PartSize", &Downloader{PartSize: 1024}, Downloader{Concurrency: 1, PartSize: 1024}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(&Config{Downloader: tt.downloader})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			if got := c.downloader; got != tt.want {
+				t.Errorf("got downloader %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
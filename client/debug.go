@@ -0,0 +1,135 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-log/log"
+)
+
+// DebugEnvVar is the environment variable that, when set to a true value (as understood by
+// strconv.ParseBool), enables debug logging of HTTP traffic. See Config.Debug.
+const DebugEnvVar = "SCS_LIBRARY_CLIENT_DEBUG"
+
+// redacted replaces sensitive values in debug output.
+const redacted = "REDACTED"
+
+// sensitiveHeaders are HTTP headers whose values are redacted in debug output.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":        true,
+	"Proxy-Authorization":  true,
+	"Cookie":               true,
+	"Set-Cookie":           true,
+	"X-Amz-Security-Token": true,
+}
+
+// sensitiveQueryParams are (lower case) query parameters whose values are redacted in debug
+// output, such as the signatures and credentials embedded in presigned URLs.
+var sensitiveQueryParams = map[string]bool{
+	"x-amz-signature":      true,
+	"x-amz-credential":     true,
+	"x-amz-security-token": true,
+	"x-goog-signature":     true,
+	"x-goog-credential":    true,
+	"sig":                  true,
+	"signature":            true,
+	"token":                true,
+	"access_token":         true,
+}
+
+// debugEnabled returns true if debug logging is enabled by the environment.
+func debugEnabled() bool {
+	v, err := strconv.ParseBool(os.Getenv(DebugEnvVar))
+	return err == nil && v
+}
+
+// debugTransport is a http.RoundTripper that logs requests and responses, with credentials
+// redacted, before passing them to next.
+type debugTransport struct {
+	next   http.RoundTripper
+	logger log.Logger
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.logger.Logf("--> %v %v", req.Method, redactURL(req.URL))
+	t.logHeaders("-->", req.Header)
+
+	start := time.Now()
+
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.logger.Logf("<-- %v %v: error after %v: %v", req.Method, redactURL(req.URL), time.Since(start), err)
+		return nil, err
+	}
+
+	t.logger.Logf("<-- %v %v: %v (%v)", req.Method, redactURL(req.URL), res.Status, time.Since(start))
+	t.logHeaders("<--", res.Header)
+
+	return res, nil
+}
+
+// logHeaders logs h in a deterministic order, redacting sensitive values.
+func (t *debugTransport) logHeaders(prefix string, h http.Header) {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := strings.Join(h[k], ", ")
+		if sensitiveHeaders[http.CanonicalHeaderKey(k)] {
+			v = redacted
+		} else if k == "Location" {
+			if u, err := url.Parse(v); err == nil {
+				v = redactURL(u)
+			}
+		}
+		t.logger.Logf("%v %v: %v", prefix, k, v)
+	}
+}
+
+// redactURL returns u as a string, with user information and sensitive query parameters
+// redacted.
+func redactURL(u *url.URL) string {
+	ru := *u
+
+	if ru.User != nil {
+		ru.User = url.User(redacted)
+	}
+
+	if ru.RawQuery != "" {
+		q := ru.Query()
+		for k := range q {
+			if sensitiveQueryParams[strings.ToLower(k)] {
+				q.Set(k, redacted)
+			}
+		}
+		ru.RawQuery = q.Encode()
+	}
+
+	return ru.String()
+}
+
+// newDebugHTTPClient returns a copy of hc that logs HTTP traffic using logger.
+func newDebugHTTPClient(hc *http.Client, logger log.Logger) *http.Client {
+	next := hc.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	dc := *hc
+	dc.Transport = &debugTransport{next: next, logger: logger}
+	return &dc
+}
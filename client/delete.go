@@ -3,15 +3,183 @@ package client
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
-// DeleteImage deletes requested imageRef.
+// DeleteImage deletes requested imageRef. If supported, the image manifest of arch is deleted from
+// the OCI registry directly, and removed from any image index that refers to it; otherwise, the
+// library images endpoint is used. Images identified by library hash (ie.
+// "entity/collection/container:sha256.<hex>") are always deleted using the library images
+// endpoint.
 func (c *Client) DeleteImage(ctx context.Context, imageRef, arch string) error {
 	if imageRef == "" || arch == "" {
 		return errors.New("imageRef and arch are required")
 	}
 
+	if _, tag, _ := strings.Cut(imageRef, ":"); !isDigestRef(tag) {
+		err := c.ociDeleteImage(ctx, imageRef, arch)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, errOCIDownloadNotSupported) {
+			return err
+		}
+	}
+
+	c.logger.Log("Fallback to (legacy) library delete")
+
 	_, err := c.doDeleteRequest(ctx, "v1/images/"+imageRef+"?arch="+url.QueryEscape(arch))
 	return err
 }
+
+// DeleteTag deletes the tag specified by tagRef (ie. "entity/collection/container:tag") from the
+// OCI registry. The manifest (or image index) it refers to is retained. If the registry does not
+// support the deletion of tags, the manifest is deleted instead, provided no other tag refers to
+// it. Deleting tags requires direct OCI registry access.
+func (c *Client) DeleteTag(ctx context.Context, tagRef string) error {
+	name, tag, ok := strings.Cut(strings.TrimPrefix(tagRef, "/"), ":")
+	if !ok || name == "" || tag == "" {
+		return fmt.Errorf("malformed tag ref: %s", tagRef)
+	}
+
+	reg, creds, name, err := c.newOCIRegistry(ctx, name, []accessType{accessTypePull, accessTypeDelete})
+	if err != nil {
+		if errors.Is(err, errOCIDownloadNotSupported) {
+			return errors.New("deleting tags requires OCI registry access")
+		}
+		return err
+	}
+
+	d, err := reg.resolveManifest(ctx, creds, name, tag)
+	if err != nil {
+		return fmt.Errorf("error resolving tag: %w", err)
+	}
+
+	c.logger.Logf("Deleting tag %v (manifest %v)", tag, d)
+
+	err = reg.deleteTag(ctx, creds, name, tag)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, errTagDeleteUnsupported) {
+		return fmt.Errorf("error deleting tag: %w", err)
+	}
+
+	// Deleting the manifest deletes every tag that refers to it, so is only safe if no other tag
+	// does.
+	tags, err := reg.tagsReferencing(ctx, creds, name, d)
+	if err != nil {
+		return fmt.Errorf("error determining tags of manifest %v: %w", d, err)
+	}
+	if others := slices.DeleteFunc(tags, func(t string) bool { return t == tag }); len(others) > 0 {
+		return fmt.Errorf("%w, and manifest %v is also referred to by tags %v", errTagDeleteUnsupported, d, others)
+	}
+
+	c.logger.Logf("Tag deletion not supported by registry; deleting manifest %v", d)
+
+	if err := reg.deleteManifest(ctx, creds, name, d); err != nil {
+		return fmt.Errorf("error deleting manifest: %w", err)
+	}
+	return nil
+}
+
+// ociDeleteImage deletes the manifest of the image specified by imageRef (ie.
+// "entity/collection/container:tag") and arch from the OCI registry. If the tag refers to an image
+// index, the manifest is first removed from the index, so that no index refers to a deleted
+// manifest. Every tag referring to the index is updated, and if no other manifests remain, the
+// index itself is deleted.
+func (c *Client) ociDeleteImage(ctx context.Context, imageRef, arch string) error {
+	name, tag, _ := strings.Cut(strings.TrimPrefix(imageRef, "/"), ":")
+	if tag == "" {
+		tag = "latest"
+	}
+
+	reg, creds, name, err := c.newOCIRegistry(ctx, name, []accessType{accessTypePull, accessTypePush, accessTypeDelete})
+	if err != nil {
+		return err
+	}
+
+	id, idx, err := reg.DownloadV1Index(ctx, creds, name, tag)
+	if err != nil {
+		if !isNotIndex(err) {
+			return fmt.Errorf("error getting image index: %w", err)
+		}
+
+		// Not an image index; the tag refers to the image manifest directly.
+		d, _, err := reg.downloadV1Manifest(ctx, creds, name, tag)
+		if err != nil {
+			return fmt.Errorf("error getting image manifest: %w", err)
+		}
+		return c.ociDeleteManifest(ctx, reg, creds, name, d)
+	}
+
+	d, err := reg.getManifestFromIndex(idx, arch)
+	if err != nil {
+		return fmt.Errorf("error getting image manifest: %w", err)
+	}
+
+	if err := c.ociRemoveFromIndex(ctx, reg, creds, name, id, idx, d); err != nil {
+		return err
+	}
+	return c.ociDeleteManifest(ctx, reg, creds, name, d)
+}
+
+// isNotIndex returns true if err, returned by DownloadV1Index, indicates that the manifest is not
+// an image index (as reported by its content type, or by the registry being unable to serve it as
+// one), rather than that it could not be retrieved.
+func isNotIndex(err error) bool {
+	var (
+		ce *unexpectedContentTypeError
+		re *RegistryError
+	)
+	return errors.As(err, &ce) || (errors.As(err, &re) && re.StatusCode == http.StatusNotFound)
+}
+
+// ociRemoveFromIndex removes the manifest with digest d from image index idx (with digest id) in
+// name. Each tag referring to the index is updated to refer to the rewritten index, and the
+// original index is deleted. If no manifests remain, the index (and the tags referring to it) is
+// deleted.
+func (c *Client) ociRemoveFromIndex(ctx context.Context, reg *ociRegistry, creds credentials, name string, id digest.Digest, idx v1.Index, d digest.Digest) error {
+	idx.Manifests = slices.DeleteFunc(slices.Clone(idx.Manifests), func(desc v1.Descriptor) bool {
+		return desc.Digest == d
+	})
+
+	if len(idx.Manifests) > 0 {
+		tags, err := reg.tagsReferencing(ctx, creds, name, id)
+		if err != nil {
+			return fmt.Errorf("error determining tags of image index %v: %w", id, err)
+		}
+
+		for _, tag := range tags {
+			c.logger.Logf("Removing manifest %v from image index (tag %v)", d, tag)
+
+			if _, err := reg.UploadV1Index(ctx, creds, name, tag, idx); err != nil {
+				return fmt.Errorf("error updating image index: %w", err)
+			}
+		}
+	}
+
+	c.logger.Logf("Deleting image index %v", id)
+
+	if err := reg.deleteManifest(ctx, creds, name, id); err != nil {
+		return fmt.Errorf("error deleting image index: %w", err)
+	}
+	return nil
+}
+
+// ociDeleteManifest deletes the manifest with digest d from name.
+func (c *Client) ociDeleteManifest(ctx context.Context, reg *ociRegistry, creds credentials, name string, d digest.Digest) error {
+	c.logger.Logf("Deleting manifest %v", d)
+
+	if err := reg.deleteManifest(ctx, creds, name, d); err != nil {
+		return fmt.Errorf("error deleting manifest: %w", err)
+	}
+	return nil
+}
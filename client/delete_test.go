@@ -2,9 +2,21 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	jsonresp "github.com/sylabs/json-resp"
 )
 
 func Test_DeleteImage(t *testing.T) {
@@ -70,3 +82,400 @@ func Test_DeleteImage(t *testing.T) {
 		})
 	}
 }
+
+// mockOCIDeleteRegistry is a minimal in-memory OCI registry supporting manifest upload and
+// deletion, that grants direct OCI registry access.
+type mockOCIDeleteRegistry struct {
+	t           *testing.T
+	mu          sync.Mutex
+	manifests   map[digest.Digest][]byte
+	types       map[digest.Digest]string
+	tags        map[string]digest.Digest
+	tagDelete   bool     // support tag deletion
+	getStatus   int      // if set, status of manifest GET requests
+	deleted     []string // refs deleted
+	libraryURLs []string // library delete requests
+	srv         *httptest.Server
+}
+
+// newMockOCIDeleteRegistry returns a registry serving a multi-arch image index tagged "v1" and
+// "v2", containing image manifests for each of archs.
+func newMockOCIDeleteRegistry(t *testing.T, tagDelete bool, archs ...string) *mockOCIDeleteRegistry {
+	t.Helper()
+
+	m := &mockOCIDeleteRegistry{
+		t:         t,
+		manifests: make(map[digest.Digest][]byte),
+		types:     make(map[digest.Digest]string),
+		tags:      make(map[string]digest.Digest),
+		tagDelete: tagDelete,
+	}
+
+	idx := v1.Index{MediaType: v1.MediaTypeImageIndex}
+	for _, arch := range archs {
+		d := m.put(v1.MediaTypeImageManifest, v1.Manifest{MediaType: v1.MediaTypeImageManifest, Annotations: map[string]string{"arch": arch}})
+		idx.Manifests = append(idx.Manifests, v1.Descriptor{
+			MediaType: v1.MediaTypeImageManifest,
+			Digest:    d,
+			Platform:  &v1.Platform{Architecture: arch, OS: "linux"},
+		})
+	}
+	id := m.put(v1.MediaTypeImageIndex, idx)
+	m.tags["v1"], m.tags["v2"] = id, id
+
+	m.srv = httptest.NewServer(m)
+	t.Cleanup(m.srv.Close)
+
+	return m
+}
+
+// put stores v as a manifest of media type mt, returning its digest.
+func (m *mockOCIDeleteRegistry) put(mt string, v interface{}) digest.Digest {
+	b, err := json.Marshal(v)
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	d := digest.FromBytes(b)
+	m.manifests[d], m.types[d] = b, mt
+	return d
+}
+
+// index returns the image index referred to by tag, if any.
+func (m *mockOCIDeleteRegistry) index(tag string) (v1.Index, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	d, ok := m.tags[tag]
+	if !ok {
+		return v1.Index{}, false
+	}
+	var idx v1.Index
+	if err := json.Unmarshal(m.manifests[d], &idx); err != nil {
+		m.t.Fatal(err)
+	}
+	return idx, true
+}
+
+func (m *mockOCIDeleteRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	const prefix = "/v2/entity/collection/container/"
+
+	path := strings.TrimPrefix(r.URL.Path, prefix)
+
+	switch {
+	case r.URL.Path == "/v1/oci-redirect":
+		if err := json.NewEncoder(w).Encode(map[string]string{"token": "xxx", "url": m.srv.URL}); err != nil {
+			m.t.Errorf("error JSON encoding: %v", err)
+		}
+
+	case strings.HasPrefix(r.URL.Path, "/v1/images/"):
+		m.libraryURLs = append(m.libraryURLs, r.URL.Path)
+		if err := jsonresp.WriteResponse(w, "", http.StatusOK); err != nil {
+			m.t.Errorf("error writing response: %v", err)
+		}
+
+	case path == "tags/list":
+		tl := struct {
+			Tags []string `json:"tags"`
+		}{}
+		for tag := range m.tags {
+			tl.Tags = append(tl.Tags, tag)
+		}
+		sort.Strings(tl.Tags)
+		if err := json.NewEncoder(w).Encode(tl); err != nil {
+			m.t.Errorf("error JSON encoding: %v", err)
+		}
+
+	case strings.HasPrefix(path, "manifests/"):
+		ref := strings.TrimPrefix(path, "manifests/")
+
+		d := digest.Digest(ref)
+		if td, ok := m.tags[ref]; ok {
+			d = td
+		}
+
+		switch r.Method {
+		case http.MethodPut:
+			b, err := io.ReadAll(r.Body)
+			if err != nil {
+				m.t.Errorf("error reading request body: %v", err)
+			}
+			d := digest.FromBytes(b)
+			m.manifests[d], m.types[d] = b, r.Header.Get("Content-Type")
+			if d.String() != ref {
+				m.tags[ref] = d
+			}
+			w.WriteHeader(http.StatusCreated)
+
+		case http.MethodDelete:
+			if _, ok := m.manifests[d]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if d.String() != ref {
+				if !m.tagDelete {
+					w.WriteHeader(http.StatusMethodNotAllowed)
+					return
+				}
+				delete(m.tags, ref)
+			} else {
+				delete(m.manifests, d)
+				for tag, td := range m.tags {
+					if td == d {
+						delete(m.tags, tag)
+					}
+				}
+			}
+			m.deleted = append(m.deleted, ref)
+			w.WriteHeader(http.StatusAccepted)
+
+		default:
+			if m.getStatus != 0 {
+				w.WriteHeader(m.getStatus)
+				return
+			}
+			b, ok := m.manifests[d]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", m.types[d])
+			w.Header().Set("Docker-Content-Digest", d.String())
+			_, _ = w.Write(b)
+		}
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func Test_DeleteImageOCI(t *testing.T) {
+	tests := []struct {
+		name      string
+		archs     []string
+		imageRef  string
+		wantArchs []string // archs remaining in index, or nil if index deleted
+		wantLib   bool     // library images endpoint used
+	}{
+		{
+			name:      "RemoveFromIndex",
+			archs:     []string{archIntel, "arm64"},
+			imageRef:  "entity/collection/container:v1",
+			wantArchs: []string{"arm64"},
+		},
+		{
+			name:     "DeleteIndex",
+			archs:    []string{archIntel},
+			imageRef: "entity/collection/container:v1",
+		},
+		{
+			name:      "LibraryHash",
+			archs:     []string{archIntel},
+			imageRef:  "entity/collection/container:sha256." + strings.Repeat("0", 64),
+			wantArchs: []string{archIntel},
+			wantLib:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMockOCIDeleteRegistry(t, true, tt.archs...)
+
+			c, err := NewClient(&Config{BaseURL: m.srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			if err := c.DeleteImage(context.Background(), tt.imageRef, archIntel); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := len(m.libraryURLs) > 0; got != tt.wantLib {
+				t.Errorf("got library delete %v, want %v", got, tt.wantLib)
+			}
+
+			// Each tag must refer to the same index, containing only the remaining archs.
+			for _, tag := range []string{"v1", "v2"} {
+				idx, ok := m.index(tag)
+				if tt.wantArchs == nil {
+					if ok {
+						t.Errorf("tag %v not deleted", tag)
+					}
+					continue
+				}
+				if !ok {
+					t.Fatalf("tag %v deleted", tag)
+				}
+
+				var archs []string
+				for _, desc := range idx.Manifests {
+					archs = append(archs, desc.Platform.Architecture)
+					if _, ok := m.manifests[desc.Digest]; !ok {
+						t.Errorf("tag %v refers to deleted manifest %v", tag, desc.Digest)
+					}
+				}
+				if !reflect.DeepEqual(archs, tt.wantArchs) {
+					t.Errorf("tag %v: got archs %v, want %v", tag, archs, tt.wantArchs)
+				}
+			}
+		})
+	}
+}
+
+func Test_DeleteImageOCIManifest(t *testing.T) {
+	m := newMockOCIDeleteRegistry(t, true, archIntel)
+
+	// Tag an image manifest directly, rather than an image index.
+	d := m.put(v1.MediaTypeImageManifest, v1.Manifest{MediaType: v1.MediaTypeImageManifest})
+	m.tags["plain"] = d
+
+	c, err := NewClient(&Config{BaseURL: m.srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	if err := c.DeleteImage(context.Background(), "entity/collection/container:plain", archIntel); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := m.manifests[d]; ok {
+		t.Errorf("manifest %v not deleted", d)
+	}
+	if _, ok := m.index("v1"); !ok {
+		t.Error("index unexpectedly deleted")
+	}
+}
+
+func Test_DeleteImageOCIIndexError(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		wantStatus int
+	}{
+		{"ServerError", http.StatusInternalServerError, http.StatusInternalServerError},
+		{"Forbidden", http.StatusForbidden, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMockOCIDeleteRegistry(t, true, archIntel)
+			m.getStatus = tt.status
+
+			c, err := NewClient(&Config{BaseURL: m.srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			err = c.DeleteImage(context.Background(), "entity/collection/container:v1", archIntel)
+
+			var re *RegistryError
+			if !errors.As(err, &re) || re.StatusCode != tt.wantStatus {
+				t.Fatalf("got error %v, want registry error %v", err, tt.wantStatus)
+			}
+			if !strings.Contains(err.Error(), "error getting image index") {
+				t.Errorf("got error %v, want index error", err)
+			}
+
+			if len(m.deleted) > 0 {
+				t.Errorf("got deleted refs %v, want none", m.deleted)
+			}
+		})
+	}
+}
+
+func Test_DeleteTag(t *testing.T) {
+	tests := []struct {
+		name        string
+		tagDelete   bool
+		tags        []string // tags to add, referring to the same index
+		tagRef      string
+		expectError bool
+		wantTags    []string
+		wantDeleted int
+	}{
+		{
+			name:        "OK",
+			tagDelete:   true,
+			tagRef:      "entity/collection/container:v2",
+			wantTags:    []string{"v1"},
+			wantDeleted: 1,
+		},
+		{
+			name:        "UnsupportedSiblingTag",
+			tagRef:      "entity/collection/container:v2",
+			expectError: true,
+			wantTags:    []string{"v1", "v2"},
+		},
+		{
+			name:        "UnsupportedOnlyTag",
+			tagRef:      "entity/collection/container:v3",
+			tags:        []string{"v3"},
+			wantTags:    []string{"v1", "v2"},
+			wantDeleted: 1,
+		},
+		{
+			name:        "MissingTag",
+			tagDelete:   true,
+			tagRef:      "entity/collection/container",
+			expectError: true,
+			wantTags:    []string{"v1", "v2"},
+		},
+		{
+			name:        "NotFound",
+			tagDelete:   true,
+			tagRef:      "entity/collection/container:v3",
+			expectError: true,
+			wantTags:    []string{"v1", "v2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMockOCIDeleteRegistry(t, tt.tagDelete, archIntel)
+
+			// Additional tags refer to a distinct index.
+			for _, tag := range tt.tags {
+				m.tags[tag] = m.put(v1.MediaTypeImageIndex, v1.Index{MediaType: v1.MediaTypeImageIndex, Annotations: map[string]string{"tag": tag}})
+			}
+
+			c, err := NewClient(&Config{BaseURL: m.srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			err = c.DeleteTag(context.Background(), tt.tagRef)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("got error %v, want error %v", err, tt.expectError)
+			}
+
+			var tags []string
+			for tag := range m.tags {
+				tags = append(tags, tag)
+			}
+			sort.Strings(tags)
+
+			if !reflect.DeepEqual(tags, tt.wantTags) {
+				t.Errorf("got tags %v, want %v", tags, tt.wantTags)
+			}
+			if got, want := len(m.deleted), tt.wantDeleted; got != want {
+				t.Errorf("got %v deletions (%v), want %v", got, m.deleted, want)
+			}
+		})
+	}
+}
+
+func Test_DeleteTagNotSupported(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	if err := c.DeleteTag(context.Background(), "entity/collection/container:v1"); err == nil {
+		t.Error("unexpected success")
+	}
+}
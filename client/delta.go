@@ -0,0 +1,244 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// GetImageBlockManifest returns the block manifest of the image identified by imageRef (ie.
+// "entity/collection/container:tag") and arch. Returns ErrNotFound if the image is not found, or
+// the server does not support block manifests.
+func (c *Client) GetImageBlockManifest(ctx context.Context, arch string, imageRef string) (*BlockManifest, error) {
+	q := url.Values{}
+	q.Add("arch", arch)
+	apiURL := &url.URL{
+		Path:     "v1/imageblocks/" + imageRef,
+		RawQuery: q.Encode(),
+	}
+
+	b, err := c.apiGet(ctx, apiURL.String())
+	if err != nil {
+		return nil, err
+	}
+	var res BlockManifestResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("error decoding block manifest: %v", err)
+	}
+	return &res.Data, nil
+}
+
+// algorithm returns the checksum algorithm used by m.
+func (m *BlockManifest) algorithm() ChecksumAlgorithm {
+	if m.Algorithm == "" {
+		return ChecksumSHA256
+	}
+	return ChecksumAlgorithm(strings.ToLower(m.Algorithm))
+}
+
+// validate returns an error if m is not internally consistent.
+func (m *BlockManifest) validate() error {
+	if m.Size < 0 || m.BlockSize <= 0 {
+		return fmt.Errorf("invalid block manifest (size: %v, block size: %v)", m.Size, m.BlockSize)
+	}
+	if got, want := int64(len(m.Blocks)), (m.Size+m.BlockSize-1)/m.BlockSize; got != want {
+		return fmt.Errorf("invalid block manifest: got %v block(s), want %v", got, want)
+	}
+	if _, err := newHash(m.algorithm()); err != nil {
+		return fmt.Errorf("invalid block manifest: %w", err)
+	}
+	return nil
+}
+
+// blockBounds returns the first and last byte offsets of block i.
+func (m *BlockManifest) blockBounds(i int) (start, end int64) {
+	start = int64(i) * m.BlockSize
+	return start, minInt64(start+m.BlockSize, m.Size) - 1
+}
+
+// blockChecksum returns the checksum of the n bytes of r at offset off, computed using alg.
+func blockChecksum(r io.ReaderAt, off, n int64, alg ChecksumAlgorithm) (string, error) {
+	h, err := newHash(alg)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(h, io.NewSectionReader(r, off, n)); err != nil {
+		return "", err
+	}
+	return encodeChecksum(alg, h.Sum(nil)), nil
+}
+
+// indexBlocks returns a map of block checksum to offset for the blocks of r, which is size bytes
+// in length, using the block size and checksum algorithm of m.
+func indexBlocks(r io.ReaderAt, size int64, m *BlockManifest) (map[string]int64, error) {
+	index := make(map[string]int64)
+
+	for off := int64(0); off < size; off += m.BlockSize {
+		sum, err := blockChecksum(r, off, minInt64(m.BlockSize, size-off), m.algorithm())
+		if err != nil {
+			return nil, fmt.Errorf("error indexing base image: %w", err)
+		}
+		if _, ok := index[sum]; !ok {
+			index[sum] = off
+		}
+	}
+	return index, nil
+}
+
+// DeltaSummary describes the outcome of a delta download.
+type DeltaSummary struct {
+	// ReusedBytes is the number of bytes copied from the base image.
+	ReusedBytes int64
+	// DownloadedBytes is the number of bytes downloaded.
+	DownloadedBytes int64
+}
+
+// DownloadImageDelta downloads the image specified by path, tag and arch to dst, reusing content
+// from base, a previous version of the image that is baseSize bytes in length. The block manifest
+// of the image is compared against the block-aligned content of base, and only blocks not present
+// in base are downloaded; downloaded blocks are verified against the manifest. This requires
+// server support for block manifests; if the server does not support block manifests, ErrNotFound
+// is returned, and the caller should fall back to DownloadImage.
+//
+// If spec is nil, the client default download parameters are used. If pb is nil,
+// NoopProgressBar is used.
+func (c *Client) DownloadImageDelta(ctx context.Context, dst *os.File, base io.ReaderAt, baseSize int64, arch, path, tag string, spec *Downloader, pb ProgressBar) (*DeltaSummary, error) {
+	if pb == nil {
+		pb = &NoopProgressBar{}
+	}
+
+	if spec == nil {
+		d := c.downloader
+		spec = &d
+	}
+
+	if strings.Contains(path, ":") {
+		return nil, fmt.Errorf("malformed image path: %s", path)
+	}
+
+	name := strings.TrimPrefix(path, "/")
+	if tag == "" {
+		tag = "latest"
+	}
+
+	m, err := c.GetImageBlockManifest(ctx, arch, fmt.Sprintf("%v:%v", name, tag))
+	if err != nil {
+		return nil, err
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+
+	index, err := indexBlocks(base, baseSize, m)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dst.Truncate(m.Size); err != nil {
+		return nil, err
+	}
+
+	pb.Init(m.Size)
+	defer pb.Wait()
+
+	summary, parts, err := c.copyBaseBlocks(ctx, dst, base, index, m, spec.PartSize, pb)
+	if err == nil && len(parts) > 0 {
+		err = c.downloadDeltaParts(ctx, dst, arch, name, tag, m, parts, spec, pb)
+	}
+	if err != nil {
+		pb.Abort(true)
+
+		return nil, err
+	}
+
+	for _, ps := range parts {
+		summary.DownloadedBytes += ps.end - ps.start + 1
+	}
+
+	c.logger.Logf("Delta download: reused %v byte(s), downloaded %v byte(s)", summary.ReusedBytes, summary.DownloadedBytes)
+
+	return summary, nil
+}
+
+// copyBaseBlocks copies the blocks described by m that are present in base (according to index)
+// to dst. The remaining blocks are returned as contiguous parts of at most partSize bytes (or a
+// single block, if larger).
+func (c *Client) copyBaseBlocks(ctx context.Context, dst io.WriterAt, base io.ReaderAt, index map[string]int64, m *BlockManifest, partSize int64, pb ProgressBar) (*DeltaSummary, []filePartDescriptor, error) {
+	summary := &DeltaSummary{}
+
+	var parts []filePartDescriptor
+
+	for i, sum := range m.Blocks {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		start, end := m.blockBounds(i)
+
+		if off, ok := index[sum]; ok {
+			ps := &filePartDescriptor{start: start, end: end, w: dst}
+
+			n, err := io.Copy(ps, io.NewSectionReader(base, off, end-start+1))
+			if err != nil {
+				return nil, nil, fmt.Errorf("error copying block %d from base image: %w", i, err)
+			}
+
+			summary.ReusedBytes += n
+			pb.IncrBy(int(n))
+
+			continue
+		}
+
+		// Extend the previous part if contiguous, and the part size permits.
+		if n := len(parts); n > 0 && parts[n-1].end+1 == start && end-parts[n-1].start+1 <= partSize {
+			parts[n-1].end = end
+			continue
+		}
+
+		parts = append(parts, filePartDescriptor{part: len(parts) + 1, start: start, end: end, w: dst})
+	}
+
+	return summary, parts, nil
+}
+
+// downloadDeltaParts downloads parts of the image specified by name, tag and arch concurrently,
+// and verifies the downloaded blocks, read back from r, against m.
+func (c *Client) downloadDeltaParts(ctx context.Context, r io.ReaderAt, arch, name, tag string, m *BlockManifest, parts []filePartDescriptor, spec *Downloader, pb ProgressBar) error {
+	u, creds, size, err := c.imageBlob(ctx, arch, name, tag)
+	if err != nil {
+		return err
+	}
+	if size != m.Size {
+		return fmt.Errorf("image size (%v) does not match block manifest size (%v)", size, m.Size)
+	}
+
+	if err := c.downloadParts(ctx, u, creds, parts, spec.Concurrency, pb); err != nil {
+		return err
+	}
+
+	// Verify downloaded blocks.
+	for _, ps := range parts {
+		for i := int(ps.start / m.BlockSize); i <= int(ps.end/m.BlockSize); i++ {
+			start, end := m.blockBounds(i)
+
+			sum, err := blockChecksum(r, start, end-start+1, m.algorithm())
+			if err != nil {
+				return err
+			}
+			if sum != m.Blocks[i] {
+				return fmt.Errorf("%v checksum mismatch for block %d: got %v, want %v", m.algorithm(), i, sum, m.Blocks[i])
+			}
+		}
+	}
+
+	return nil
+}
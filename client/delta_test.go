@@ -0,0 +1,182 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+// blockManifest returns the SHA256 block manifest of b.
+func blockManifest(b []byte, blockSize int64) BlockManifest {
+	m := BlockManifest{Size: int64(len(b)), BlockSize: blockSize}
+
+	for off := int64(0); off < int64(len(b)); off += blockSize {
+		sum := sha256.Sum256(b[off:minInt64(off+blockSize, int64(len(b)))])
+		m.Blocks = append(m.Blocks, hex.EncodeToString(sum[:]))
+	}
+	return m
+}
+
+func TestDownloadImageDelta(t *testing.T) {
+	const blockSize = 4
+
+	image := []byte("aaaabbbbccccddddeeeeffffgg")
+
+	tests := []struct {
+		name           string
+		base           []byte
+		manifest       *BlockManifest
+		wantReused     int64
+		wantDownloaded int64
+		wantErr        error
+	}{
+		{
+			name:           "Identical",
+			base:           image,
+			wantReused:     26,
+			wantDownloaded: 0,
+		},
+		{
+			name:           "Changed",
+			base:           []byte("aaaaBBBBccccDDDDEEEEffff"),
+			wantReused:     12,
+			wantDownloaded: 14,
+		},
+		{
+			name:           "Moved",
+			base:           []byte("ffffeeeeddddccccbbbbaaaa"),
+			wantReused:     24,
+			wantDownloaded: 2,
+		},
+		{
+			name:           "Empty",
+			base:           nil,
+			wantReused:     0,
+			wantDownloaded: 26,
+		},
+		{
+			name:     "Corrupt",
+			base:     nil,
+			manifest: &BlockManifest{Size: 26, BlockSize: blockSize, Blocks: make([]string, 7)},
+			wantErr:  errors.New("checksum mismatch"),
+		},
+		{
+			name:     "Invalid",
+			base:     nil,
+			manifest: &BlockManifest{Size: 26, BlockSize: blockSize},
+			wantErr:  errors.New("invalid block manifest"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := blockManifest(image, blockSize)
+			if tt.manifest != nil {
+				m = *tt.manifest
+			}
+
+			var downloaded atomic.Int64
+
+			lib := mockLibraryServer(t, image, true)
+			defer lib.Close()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/v1/oci-redirect":
+					w.WriteHeader(http.StatusNotFound)
+				case r.URL.Path == "/v1/imageblocks/entity/collection/container:tag":
+					if err := jsonresp.WriteResponse(w, &m, http.StatusOK); err != nil {
+						t.Errorf("error writing JSON response: %v", err)
+					}
+				case strings.HasPrefix(r.URL.Path, "/v1/imagepart/"):
+					start, end := parseRangeHeader(t, r.Header.Get("Range"))
+					downloaded.Add(end - start + 1)
+
+					lib.Config.Handler.ServeHTTP(w, r)
+				default:
+					lib.Config.Handler.ServeHTTP(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			dst, err := os.Create(filepath.Join(t.TempDir(), "image.sif"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer dst.Close()
+
+			base := bytes.NewReader(tt.base)
+
+			summary, err := c.DownloadImageDelta(context.Background(), dst, base, base.Size(), "amd64", "entity/collection/container", "tag", &Downloader{Concurrency: 2, PartSize: 8}, nil)
+			if tt.wantErr != nil {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr.Error()) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got, want := summary.ReusedBytes, tt.wantReused; got != want {
+				t.Errorf("got %v reused byte(s), want %v", got, want)
+			}
+			if got, want := summary.DownloadedBytes, tt.wantDownloaded; got != want {
+				t.Errorf("got %v downloaded byte(s), want %v", got, want)
+			}
+			if got, want := downloaded.Load(), tt.wantDownloaded; got != want {
+				t.Errorf("got %v byte(s) requested, want %v", got, want)
+			}
+
+			got, err := os.ReadFile(dst.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, image) {
+				t.Errorf("got image %q, want %q", got, image)
+			}
+		})
+	}
+}
+
+func TestDownloadImageDeltaNotSupported(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	dst, err := os.Create(filepath.Join(t.TempDir(), "image.sif"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+
+	_, err = c.DownloadImageDelta(context.Background(), dst, bytes.NewReader(nil), 0, "amd64", "entity/collection/container", "tag", nil, nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, ErrNotFound)
+	}
+}
@@ -13,16 +13,146 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 
+	"github.com/opencontainers/go-digest"
 	"golang.org/x/sync/errgroup"
 )
 
+// errPresignedURLExpired is returned when the object store rejects a request made using a
+// presigned URL (HTTP status 403), reporting that the URL has expired.
+var errPresignedURLExpired = errors.New("presigned URL expired")
+
+var (
+	// errRangeIgnored is returned when a server responds to a range request with content other
+	// than the requested range, typically the full object.
+	errRangeIgnored = errors.New("server did not honour range request")
+
+	// errPartMismatch is returned when the content of a range response does not match the
+	// requested range.
+	errPartMismatch = errors.New("response does not match requested range")
+)
+
+// maxPresignedURLRenewals is the number of times a fresh presigned URL is requested for a single
+// part before the transfer is failed.
+const maxPresignedURLRenewals = 1
+
+// blobURL holds the URL used to download a blob. If renew is non-nil, it is called to obtain a
+// fresh URL when the object store reports the current URL has expired.
+type blobURL struct {
+	mu       sync.Mutex
+	u        string
+	renew    func(context.Context) (string, error)
+	digest   digest.Digest // digest of the blob, if known
+	registry bool          // URL refers to an OCI registry
+}
+
+// get returns the current URL.
+func (b *blobURL) get() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.u
+}
+
+// renewIfStale obtains a fresh URL if the current URL is stale. Concurrent workers that observe
+// the same stale URL will only trigger a single renewal.
+func (b *blobURL) renewIfStale(ctx context.Context, stale string) error {
+	if b.renew == nil {
+		return errPresignedURLExpired
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.u != stale {
+		// URL already renewed by another worker
+		return nil
+	}
+
+	u, err := b.renew(ctx)
+	if err != nil {
+		return fmt.Errorf("error renewing presigned URL: %w", err)
+	}
+	b.u = u
+
+	return nil
+}
+
+// PartError describes the failure to transfer one part of a multipart download or upload. When
+// several parts fail, the PartErrors are joined using errors.Join.
+type PartError struct {
+	// PartNumber identifies the part, starting at 1.
+	PartNumber int
+	// Start and End are the (inclusive) byte offsets of the part.
+	Start, End int64
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *PartError) Error() string {
+	return fmt.Sprintf("part %d (bytes %d-%d): %v", e.PartNumber, e.Start, e.End, e.Err)
+}
+
+func (e *PartError) Unwrap() error { return e.Err }
+
+// SizeMismatchError is returned when the size of an image reported by the server hosting the image
+// content differs from that recorded in the image metadata, such as when the metadata is stale.
+type SizeMismatchError struct {
+	// Got is the size of the image reported by the server.
+	Got int64
+	// Want is the size of the image recorded in the image metadata.
+	Want int64
+}
+
+func (e *SizeMismatchError) Error() string {
+	return fmt.Sprintf("image size mismatch: got %d byte(s), want %d byte(s)", e.Got, e.Want)
+}
+
+// partErrors collects the failures of parts that are transferred concurrently.
+type partErrors struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+// add records the failure of a part.
+func (pe *partErrors) add(err *PartError) {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+
+	pe.errs = append(pe.errs, err)
+}
+
+// err returns the recorded failures joined into a single error, or nil if no failures were
+// recorded. Parts that failed with context.Canceled are omitted if other parts failed for another
+// reason, since they were most likely cancelled as a result of that failure.
+func (pe *partErrors) err() error {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+
+	var errs []error
+	for _, err := range pe.errs {
+		if !errors.Is(err, context.Canceled) {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		errs = pe.errs
+	}
+	return errors.Join(errs...)
+}
+
 // filePartDescriptor defines one part of multipart download.
 type filePartDescriptor struct {
+	part  int
 	start int64
 	end   int64
 	cur   int64
 
+	// size is the total size of the content of which the part is a range, or zero if unknown.
+	size int64
+
 	w io.WriterAt
 }
 
@@ -44,11 +174,24 @@ func minInt64(a, b int64) int64 {
 }
 
 // Download performs download of contents at url by writing 'size' bytes to 'dst' using credentials 'c'.
-func (c *Client) multipartDownload(ctx context.Context, u string, creds credentials, w io.WriterAt, size int64, spec *Downloader, pb ProgressBar) error {
+func (c *Client) multipartDownload(ctx context.Context, u *blobURL, creds credentials, w io.WriterAt, size int64, spec *Downloader, pb ProgressBar) error {
 	if size <= 0 {
 		return fmt.Errorf("invalid image size (%v)", size)
 	}
 
+	downloadVerificationFromContext(ctx).setSize(size)
+
+	if c.sharedDownloads != nil && u.digest != "" && !sharedDownloadsDisabled(ctx) {
+		return c.sharedDownloads.do(ctx, u.digest, w, size, pb, func() error {
+			return c.concurrentDownload(ctx, u, creds, w, size, spec, pb)
+		})
+	}
+	return c.concurrentDownload(ctx, u, creds, w, size, spec, pb)
+}
+
+// concurrentDownload downloads size bytes from u to w, using concurrent range requests as
+// specified by spec.
+func (c *Client) concurrentDownload(ctx context.Context, u *blobURL, creds credentials, w io.WriterAt, size int64, spec *Downloader, pb ProgressBar) error {
 	// Initialize the progress bar using passed size
 	pb.Init(size)
 
@@ -60,50 +203,252 @@ func (c *Client) multipartDownload(ctx context.Context, u string, creds credenti
 
 	c.logger.Logf("size: %d, parts: %d, streams: %d, partsize: %d", size, parts, spec.Concurrency, spec.PartSize)
 
-	g, ctx := errgroup.WithContext(ctx)
+	resumeTrackerFromContext(ctx).begin(u, creds, size, spec.PartSize)
+
+	g, gctx := errgroup.WithContext(ctx)
+
+	var errs partErrors
 
-	// Allocate channel for file part requests
-	ch := make(chan filePartDescriptor, parts)
+	// Number of bytes reported to the progress bar
+	var reported atomic.Int64
+
+	// Allocate channel for file part requests. The channel is unbuffered, so that parts are only
+	// dispatched to workers while the download is proceeding.
+	ch := make(chan filePartDescriptor)
 
 	// Create download part workers
 	for n := uint(0); n < spec.Concurrency; n++ {
-		g.Go(c.downloadWorker(ctx, u, creds, ch, pb))
+		g.Go(c.downloadWorker(gctx, u, creds, ch, pb, &reported, &errs))
 	}
 
 	// Add part download requests
+	fps := make([]filePartDescriptor, 0, parts)
 	for n := uint(0); n < parts; n++ {
 		partSize := minInt64(spec.PartSize, size-int64(n)*spec.PartSize)
 
-		ch <- filePartDescriptor{start: int64(n) * spec.PartSize, end: int64(n)*spec.PartSize + partSize - 1, w: w}
+		fps = append(fps, filePartDescriptor{part: int(n) + 1, start: int64(n) * spec.PartSize, end: int64(n)*spec.PartSize + partSize - 1, size: size, w: w})
 	}
-
-	// Close worker queue after submitting all requests
-	close(ch)
+	g.Go(func() error { return enqueueParts(gctx, ch, fps) })
 
 	// Wait for workers to complete
-	return g.Wait()
+	if err := g.Wait(); err != nil {
+		if perr := errs.err(); perr != nil {
+			err = perr
+		}
+
+		// If the server does not honour range requests, abandon the concurrent download in favour
+		// of a single stream.
+		if errors.Is(err, errRangeIgnored) {
+			c.logger.Logf("Server does not support range requests (%v); reverting to single stream", err)
+
+			transferStatsFromContext(ctx).setSingleStreamFallback()
+			downloadVerificationFromContext(ctx).reset()
+			resumeTrackerFromContext(ctx).abandon()
+
+			err = c.singleStreamDownload(ctx, u, creds, w, size, pb, reported.Load())
+		}
+
+		if err != nil {
+			// Cleanly abort progress bar on error
+			pb.Abort(true)
+
+			return err
+		}
+	}
+	return nil
 }
 
-func (c *Client) downloadWorker(ctx context.Context, u string, creds credentials, ch chan filePartDescriptor, pb ProgressBar) func() error {
+// downloadParts downloads parts of the content at u concurrently, using the specified number of
+// workers (or one, if concurrency is zero).
+func (c *Client) downloadParts(ctx context.Context, u *blobURL, creds credentials, parts []filePartDescriptor, concurrency uint, pb ProgressBar) error {
+	g, gctx := errgroup.WithContext(ctx)
+
+	var errs partErrors
+
+	var reported atomic.Int64
+
+	ch := make(chan filePartDescriptor)
+
+	if concurrency == 0 {
+		concurrency = 1
+	}
+
+	for n := uint(0); n < concurrency; n++ {
+		g.Go(c.downloadWorker(gctx, u, creds, ch, pb, &reported, &errs))
+	}
+
+	g.Go(func() error { return enqueueParts(gctx, ch, parts) })
+
+	if err := g.Wait(); err != nil {
+		if perr := errs.err(); perr != nil {
+			return perr
+		}
+		return err
+	}
+	return nil
+}
+
+// enqueueParts sends parts to ch as workers become available to receive them, and then closes
+// ch. If ctx is done before all parts are sent, the remaining parts are abandoned, and the context
+// error is returned.
+func enqueueParts(ctx context.Context, ch chan<- filePartDescriptor, parts []filePartDescriptor) error {
+	defer close(ch)
+
+	for _, ps := range parts {
+		select {
+		case ch <- ps:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (c *Client) downloadWorker(ctx context.Context, u *blobURL, creds credentials, ch chan filePartDescriptor, pb ProgressBar, reported *atomic.Int64, errs *partErrors) func() error {
 	return func() error {
 		// Iterate on channel 'ch' to handle download part requests
 		for ps := range ch {
-			written, err := c.downloadBlobPart(ctx, creds, u, &ps)
+			// Abandon queued parts promptly once the download is cancelled.
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			// Bound the content buffered by an ordered (streaming) destination.
+			if ow, ok := ps.w.(*orderedWriter); ok {
+				if err := ow.reserve(ctx, ps.start, ps.end); err != nil {
+					return err
+				}
+			}
+
+			written, err := c.downloadPartWithRenewal(ctx, creds, u, &ps)
 			if err != nil {
-				// Cleanly abort progress bar on error
-				pb.Abort(true)
+				perr := &PartError{PartNumber: ps.part, Start: ps.start, End: ps.end, Err: err}
+				errs.add(perr)
 
-				return err
+				return perr
 			}
 
+			transferStatsFromContext(ctx).addPart(written)
+			downloadVerificationFromContext(ctx).partDone(ps.part, ps.start, ps.end)
+			resumeTrackerFromContext(ctx).partDone(ps.part)
+
 			// Increase progress bar by number of bytes downloaded/written
 			pb.IncrBy(int(written))
+			incrNetwork(pb, written)
+			reported.Add(written)
 		}
 		return nil
 	}
 }
 
-func (c *Client) downloadBlobPart(ctx context.Context, creds credentials, u string, ps *filePartDescriptor) (int64, error) {
+// singleStreamDownload downloads the content at u by writing 'size' bytes to w using a single
+// request without a Range header. The first 'reported' bytes are not reported to pb, since they
+// have already been reported during an abandoned concurrent download.
+func (c *Client) singleStreamDownload(ctx context.Context, u *blobURL, creds credentials, w io.WriterAt, size int64, pb ProgressBar, reported int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.get(), nil)
+	if err != nil {
+		return err
+	}
+
+	if creds != nil {
+		if err := creds.ModifyRequest(req); err != nil {
+			return err
+		}
+	}
+
+	setRequestID(req)
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return withResponseRequestID(fmt.Errorf("unexpected HTTP status %d", res.StatusCode), res)
+	}
+
+	if res.ContentLength >= 0 && res.ContentLength != size {
+		return withResponseRequestID(&SizeMismatchError{Got: res.ContentLength, Want: size}, res)
+	}
+
+	pw := &progressWriter{
+		w:       &filePartDescriptor{part: 1, start: 0, end: size - 1, w: w},
+		pb:      pb,
+		skip:    reported,
+		network: true,
+	}
+
+	written, err := io.CopyN(pw, limitReader(ctx, res.Body), size)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return withResponseRequestID(fmt.Errorf("body truncated after %d of %d byte(s)", written, size), res)
+	}
+	if err != nil {
+		return err
+	}
+
+	transferStatsFromContext(ctx).addPart(written)
+
+	return nil
+}
+
+// progressWriter writes to w, reporting the number of bytes written to pb. The first skip bytes
+// written are not reported. If network is set, the bytes written were received over the network,
+// and are also reported as such.
+type progressWriter struct {
+	w       io.Writer
+	pb      ProgressBar
+	skip    int64
+	network bool
+}
+
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	n, err := pw.w.Write(p)
+
+	if m := int64(n) - pw.skip; m > 0 {
+		pw.pb.IncrBy(int(m))
+		if pw.network {
+			incrNetwork(pw.pb, m)
+		}
+		pw.skip = 0
+	} else {
+		pw.skip -= int64(n)
+	}
+
+	return n, err
+}
+
+// downloadPartWithRenewal downloads the part described by ps. If the object store rejects the
+// presigned URL, a fresh URL is requested and the part download is re-attempted.
+func (c *Client) downloadPartWithRenewal(ctx context.Context, creds credentials, u *blobURL, ps *filePartDescriptor) (int64, error) {
+	for attempt := 0; ; attempt++ {
+		cur := u.get()
+
+		written, err := c.downloadBlobPart(ctx, creds, cur, u.registry, ps)
+		if !errors.Is(err, errPresignedURLExpired) || attempt >= maxPresignedURLRenewals {
+			return written, err
+		}
+
+		c.logger.Logf("Presigned URL rejected downloading bytes %d-%d; requesting a new one", ps.start, ps.end)
+
+		transferStatsFromContext(ctx).addRetry()
+
+		if err := u.renewIfStale(ctx, cur); err != nil {
+			return 0, err
+		}
+
+		// Discard anything written by the failed attempt
+		ps.cur = 0
+	}
+}
+
+// downloadBlobPart downloads the part described by ps from u. If registry is set, u refers to an
+// OCI registry, and error responses from the registry are reported as described by
+// registryResponseError.
+func (c *Client) downloadBlobPart(ctx context.Context, creds credentials, u string, registry bool, ps *filePartDescriptor) (int64, error) {
+	ctx, cancel := c.partContext(ctx)
+	defer cancel()
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
 	if err != nil {
 		return 0, err
@@ -117,20 +462,133 @@ func (c *Client) downloadBlobPart(ctx context.Context, creds credentials, u stri
 
 	req.Header.Add("Range", fmt.Sprintf("bytes=%d-%d", ps.start, ps.end))
 
+	setRequestID(req)
+
 	res, err := c.httpClient.Do(req)
 	if err != nil {
 		return 0, err
 	}
 	defer res.Body.Close()
 
-	return io.Copy(ps, res.Body)
+	if res.StatusCode/100 != 2 {
+		return 0, withResponseRequestID(partResponseError(req, res, registry), res)
+	}
+
+	if err := checkPartResponse(res, ps); err != nil {
+		return 0, withResponseRequestID(err, res)
+	}
+
+	want := ps.end - ps.start + 1
+
+	written, err := io.CopyN(ps, limitReader(ctx, res.Body), want)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = fmt.Errorf("%w: body truncated after %d of %d byte(s)", errPartMismatch, written, want)
+
+		return written, withResponseRequestID(err, res)
+	}
+	return written, err
+}
+
+// partResponseError returns an error describing the error response res to part download request
+// req. Responses from an OCI registry (rather than an object store to which it redirected) are
+// described by registryResponseError. A 403 response from an object store that reports the
+// presigned URL has expired wraps errPresignedURLExpired. The response body is consumed.
+func partResponseError(req *http.Request, res *http.Response, registry bool) error {
+	if registry && (res.Request == nil || res.Request.URL.Host == req.URL.Host) {
+		return registryResponseError(res)
+	}
+
+	if res.StatusCode == http.StatusForbidden {
+		err := objectStoreErrorFromResponse(res)
+		if err.expired() {
+			return fmt.Errorf("%w: %w", errPresignedURLExpired, err)
+		}
+		return err
+	}
+	return fmt.Errorf("unexpected HTTP status %d", res.StatusCode)
+}
+
+// checkPartResponse verifies that the headers of res are consistent with the byte range described
+// by ps having been returned.
+func checkPartResponse(res *http.Response, ps *filePartDescriptor) error {
+	want := ps.end - ps.start + 1
+
+	if res.StatusCode != http.StatusPartialContent {
+		// A response containing the full object is acceptable only when the requested range
+		// covers the full object.
+		if ps.start == 0 && res.ContentLength == want {
+			return nil
+		}
+		return fmt.Errorf("%w: HTTP status %d, Content-Length %d, requested bytes %d-%d",
+			errRangeIgnored, res.StatusCode, res.ContentLength, ps.start, ps.end)
+	}
+
+	if val := res.Header.Get("Content-Range"); val != "" {
+		start, end, size, err := parseContentRangeBounds(val)
+		if err != nil {
+			return fmt.Errorf("parsing Content-Range header %q: %w", val, err)
+		}
+
+		if ps.size > 0 && size >= 0 && size != ps.size {
+			return &SizeMismatchError{Got: size, Want: ps.size}
+		}
+
+		if start != ps.start || end != ps.end {
+			return fmt.Errorf("%w: got bytes %d-%d, requested bytes %d-%d", errPartMismatch, start, end, ps.start, ps.end)
+		}
+	}
+
+	if res.ContentLength >= 0 && res.ContentLength != want {
+		return fmt.Errorf("%w: Content-Length %d, requested %d byte(s)", errPartMismatch, res.ContentLength, want)
+	}
+
+	return nil
+}
+
+// parseContentRangeBounds parses "Content-Range" header (eg. "Content-Range: bytes 0-999/2000")
+// and returns the first and last byte positions of the range, and the complete length. If the
+// complete length is unknown ("*"), size is -1.
+func parseContentRangeBounds(val string) (start, end, size int64, err error) {
+	unit, rng, ok := strings.Cut(val, " ")
+	if !ok || !strings.EqualFold(unit, "bytes") {
+		return 0, 0, 0, errors.New("unexpected/malformed value")
+	}
+
+	rng, sizeStr, ok := strings.Cut(rng, "/")
+	if !ok {
+		return 0, 0, 0, errors.New("unexpected/malformed value")
+	}
+
+	startStr, endStr, ok := strings.Cut(rng, "-")
+	if !ok {
+		return 0, 0, 0, errors.New("unexpected/malformed value")
+	}
+
+	if start, err = strconv.ParseInt(startStr, 10, 64); err != nil {
+		return 0, 0, 0, err
+	}
+	if end, err = strconv.ParseInt(endStr, 10, 64); err != nil {
+		return 0, 0, 0, err
+	}
+	if end < start {
+		return 0, 0, 0, errors.New("unexpected/malformed value")
+	}
+
+	size = -1
+	if sizeStr != "*" {
+		if size, err = strconv.ParseInt(sizeStr, 10, 64); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+
+	return start, end, size, nil
 }
 
 // parseContentRange parses "Content-Range" header (eg. "Content-Range: bytes 0-1000/2000") and returns size
 func parseContentRange(val string) (int64, error) {
 	e := strings.Split(val, " ")
 
-	if !strings.EqualFold(e[0], "bytes") {
+	if len(e) != 2 || !strings.EqualFold(e[0], "bytes") {
 		return 0, errors.New("unexpected/malformed value")
 	}
 
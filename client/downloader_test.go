@@ -8,6 +8,7 @@ package client
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -42,6 +43,20 @@ func (f *inMemoryBuffer) WriteAt(p []byte, ofs int64) (n int, err error) {
 	return
 }
 
+func (f *inMemoryBuffer) ReadAt(p []byte, ofs int64) (n int, err error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if ofs >= int64(len(f.buf)) {
+		return 0, io.EOF
+	}
+	n = copy(p, f.buf[ofs:])
+	if n < len(p) {
+		err = io.EOF
+	}
+	return
+}
+
 func (f *inMemoryBuffer) Bytes() []byte {
 	f.m.Lock()
 	defer f.m.Unlock()
@@ -72,6 +87,37 @@ func TestParseContentRange(t *testing.T) {
 	}
 }
 
+func Test_parseContentRangeBounds(t *testing.T) {
+	tests := []struct {
+		name      string
+		val       string
+		wantStart int64
+		wantEnd   int64
+		wantSize  int64
+		wantErr   bool
+	}{
+		{"Range", "bytes 0-999/2000", 0, 999, 2000, false},
+		{"UnknownSize", "bytes 10-19/*", 10, 19, -1, false},
+		{"Unit", "BYTES 0-0/1", 0, 0, 1, false},
+		{"BadUnit", "items 0-999/2000", 0, 0, 0, true},
+		{"Unsatisfied", "bytes */2000", 0, 0, 0, true},
+		{"Reversed", "bytes 999-0/2000", 0, 0, 0, true},
+		{"Malformed", "bytes 0-999", 0, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, size, err := parseContentRangeBounds(tt.val)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if start != tt.wantStart || end != tt.wantEnd || size != tt.wantSize {
+				t.Errorf("got %v-%v/%v, want %v-%v/%v", start, end, size, tt.wantStart, tt.wantEnd, tt.wantSize)
+			}
+		})
+	}
+}
+
 func parseRangeHeader(t *testing.T, val string) (int64, int64) {
 	t.Helper()
 
@@ -130,7 +176,7 @@ func TestMultistreamDownloader(t *testing.T) {
 					}
 				}
 
-				w.Header().Set("Content-Range", fmt.Sprintf("bytes %v-%v/%v", start, end+1, size))
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes %v-%v/%v", start, end, size))
 				w.Header().Set("Content-Length", fmt.Sprintf("%v", end-start+1))
 
 				w.WriteHeader(http.StatusPartialContent)
@@ -150,7 +196,7 @@ func TestMultistreamDownloader(t *testing.T) {
 			dst := &inMemoryBuffer{buf: make([]byte, size)}
 
 			// Start download
-			err = c.multipartDownload(context.Background(), srv.URL, creds, dst, tt.size, tt.spec, &NoopProgressBar{})
+			err = c.multipartDownload(context.Background(), &blobURL{u: srv.URL}, creds, dst, tt.size, tt.spec, &NoopProgressBar{})
 			if tt.expectErr && err == nil {
 				t.Fatal("unexpected success")
 			}
@@ -168,3 +214,514 @@ func TestMultistreamDownloader(t *testing.T) {
 		})
 	}
 }
+
+func TestMultistreamDownloaderPartError(t *testing.T) {
+	const src = "123456789012345678901234567890"
+	size := int64(len(src))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, end := parseRangeHeader(t, r.Header.Get("Range"))
+
+		if start == 6 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Length", fmt.Sprintf("%v", end-start+1))
+		w.WriteHeader(http.StatusPartialContent)
+
+		if _, err := io.Copy(w, strings.NewReader(src[start:end+1])); err != nil {
+			t.Fatalf("unexpected error writing http response: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	dst := &inMemoryBuffer{buf: make([]byte, size)}
+
+	err = c.multipartDownload(context.Background(), &blobURL{u: srv.URL}, nil, dst, size, &Downloader{Concurrency: 1, PartSize: 3}, &NoopProgressBar{})
+	if err == nil {
+		t.Fatal("unexpected success")
+	}
+
+	var pe *PartError
+	if !errors.As(err, &pe) {
+		t.Fatalf("got error %v, want PartError", err)
+	}
+
+	if got, want := *pe, (PartError{PartNumber: 3, Start: 6, End: 8, Err: pe.Err}); got != want {
+		t.Errorf("got part error %+v, want %+v", got, want)
+	}
+}
+
+func Test_partErrors(t *testing.T) {
+	errPart := &PartError{PartNumber: 1, Start: 0, End: 9, Err: errors.New("failed")}
+	errCanceled := &PartError{PartNumber: 2, Start: 10, End: 19, Err: context.Canceled}
+	errOther := &PartError{PartNumber: 3, Start: 20, End: 29, Err: errors.New("failed")}
+
+	tests := []struct {
+		name      string
+		errs      []*PartError
+		wantNil   bool
+		wantIs    []error
+		wantNotIs []error
+	}{
+		{"None", nil, true, nil, nil},
+		{"Single", []*PartError{errPart}, false, []error{errPart}, nil},
+		{"CanceledOnly", []*PartError{errCanceled}, false, []error{context.Canceled}, nil},
+		{"CanceledOmitted", []*PartError{errCanceled, errPart, errOther}, false, []error{errPart, errOther}, []error{context.Canceled}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pe partErrors
+			for _, err := range tt.errs {
+				pe.add(err)
+			}
+
+			err := pe.err()
+			if got := err == nil; got != tt.wantNil {
+				t.Fatalf("got error %v, want nil %v", err, tt.wantNil)
+			}
+
+			for _, target := range tt.wantIs {
+				if !errors.Is(err, target) {
+					t.Errorf("error %v does not match %v", err, target)
+				}
+			}
+			for _, target := range tt.wantNotIs {
+				if errors.Is(err, target) {
+					t.Errorf("error %v unexpectedly matches %v", err, target)
+				}
+			}
+		})
+	}
+}
+
+// expiredURLBody is the body of an S3 response to a request made using an expired presigned URL.
+const expiredURLBody = `<?xml version="1.0" encoding="UTF-8"?><Error><Code>AccessDenied</Code><Message>Request has expired</Message></Error>`
+
+func TestMultistreamDownloaderRenewURL(t *testing.T) {
+	const src = "123456789012345678901234567890"
+	size := int64(len(src))
+
+	tests := []struct {
+		name      string
+		renew     bool
+		expired   bool // object store reports expiry, rather than denying access
+		expectErr bool
+	}{
+		{"Renewed", true, true, false},
+		{"NotRenewable", false, true, true},
+		{"NotExpired", true, false, true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("signature") != "fresh" {
+					w.WriteHeader(http.StatusForbidden)
+					if tt.expired {
+						_, _ = io.WriteString(w, expiredURLBody)
+					}
+					return
+				}
+
+				start, end := parseRangeHeader(t, r.Header.Get("Range"))
+
+				w.Header().Set("Content-Length", fmt.Sprintf("%v", end-start+1))
+				w.WriteHeader(http.StatusPartialContent)
+
+				if _, err := io.Copy(w, strings.NewReader(src[start:end+1])); err != nil {
+					t.Fatalf("unexpected error writing http response: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			var renewals int
+
+			u := &blobURL{u: srv.URL + "?signature=expired"}
+			if tt.renew {
+				u.renew = func(context.Context) (string, error) {
+					renewals++
+					return srv.URL + "?signature=fresh", nil
+				}
+			}
+
+			dst := &inMemoryBuffer{buf: make([]byte, size)}
+
+			ctx, stats := withTransferStats(context.Background())
+
+			err = c.multipartDownload(ctx, u, nil, dst, size, &Downloader{Concurrency: 4, PartSize: 3}, &NoopProgressBar{})
+			if tt.expectErr && err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.expired && renewals != 0 {
+				t.Errorf("got %v renewals, want 0", renewals)
+			}
+			if err != nil {
+				return
+			}
+
+			if got, want := renewals, 1; got != want {
+				t.Errorf("got %v renewals, want %v", got, want)
+			}
+
+			if got, want := string(dst.Bytes()), src; got != want {
+				t.Fatalf("unexpected data: got %v, want %v", got, want)
+			}
+
+			summary := stats.summary()
+			if got, want := summary.Bytes, size; got != want {
+				t.Errorf("got %v bytes in summary, want %v", got, want)
+			}
+			if got, want := summary.Parts, 10; got != want {
+				t.Errorf("got %v parts in summary, want %v", got, want)
+			}
+			if summary.Retries < 1 {
+				t.Errorf("got %v retries in summary, want at least 1", summary.Retries)
+			}
+		})
+	}
+}
+
+type abortRecordingProgressBar struct {
+	NoopProgressBar
+
+	m       sync.Mutex
+	aborted bool
+}
+
+func (pb *abortRecordingProgressBar) Abort(bool) {
+	pb.m.Lock()
+	defer pb.m.Unlock()
+
+	pb.aborted = true
+}
+
+func TestMultistreamDownloaderCancel(t *testing.T) {
+	const src = "123456789012345678901234567890"
+	size := int64(len(src))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Cancel the operation, and wait for the client to disconnect.
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	dst := &inMemoryBuffer{buf: make([]byte, size)}
+	pb := &abortRecordingProgressBar{}
+
+	err = c.multipartDownload(ctx, &blobURL{u: srv.URL}, nil, dst, size, &Downloader{Concurrency: 2, PartSize: 3}, pb)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+
+	if !pb.aborted {
+		t.Error("progress bar not aborted")
+	}
+}
+
+func Test_enqueueParts(t *testing.T) {
+	parts := make([]filePartDescriptor, 1000)
+	for i := range parts {
+		parts[i].part = i + 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan filePartDescriptor)
+	received := make(chan int)
+
+	go func() {
+		n := 0
+		for range ch {
+			// Cancel once a few parts have been received.
+			if n++; n == 3 {
+				cancel()
+			}
+		}
+		received <- n
+	}()
+
+	if err := enqueueParts(ctx, ch, parts); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+
+	// The channel is closed once the parts are abandoned.
+	if n := <-received; n == len(parts) {
+		t.Errorf("got %v parts, want parts abandoned", n)
+	}
+}
+
+func TestMultistreamDownloaderPartMismatch(t *testing.T) {
+	const src = "123456789012345678901234567890"
+	size := int64(len(src))
+
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter, start, end int64)
+		wantErr error
+	}{
+		{
+			name: "WrongRange",
+			handler: func(w http.ResponseWriter, start, end int64) {
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes %v-%v/%v", start+1, end+1, size))
+				w.WriteHeader(http.StatusPartialContent)
+				_, _ = io.WriteString(w, src[start+1:end+2])
+			},
+			wantErr: errPartMismatch,
+		},
+		{
+			name: "WrongLength",
+			handler: func(w http.ResponseWriter, start, end int64) {
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes %v-%v/%v", start, end, size))
+				w.Header().Set("Content-Length", fmt.Sprintf("%v", end-start))
+				w.WriteHeader(http.StatusPartialContent)
+				_, _ = io.WriteString(w, src[start:end])
+			},
+			wantErr: errPartMismatch,
+		},
+		{
+			name: "Truncated",
+			handler: func(w http.ResponseWriter, start, end int64) {
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes %v-%v/%v", start, end, size))
+				w.Header().Set("Content-Length", fmt.Sprintf("%v", end-start+1))
+				w.WriteHeader(http.StatusPartialContent)
+				_, _ = io.WriteString(w, src[start:end])
+			},
+			wantErr: errPartMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				start, end := parseRangeHeader(t, r.Header.Get("Range"))
+				tt.handler(w, start, end)
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			dst := &inMemoryBuffer{buf: make([]byte, size)}
+
+			err = c.multipartDownload(context.Background(), &blobURL{u: srv.URL}, nil, dst, size, &Downloader{Concurrency: 1, PartSize: 10}, &NoopProgressBar{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMultistreamDownloaderSizeMismatch(t *testing.T) {
+	const src = "1234567890123456789012345678901234567890"
+
+	// The image metadata records a smaller size than that of the content.
+	const size = 30
+
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter, r *http.Request)
+	}{
+		{
+			name: "ContentRange",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				start, end := parseRangeHeader(t, r.Header.Get("Range"))
+
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes %v-%v/%v", start, end, len(src)))
+				w.WriteHeader(http.StatusPartialContent)
+				_, _ = io.WriteString(w, src[start:end+1])
+			},
+		},
+		{
+			name: "ContentLength",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				// Range ignored, so the download reverts to a single stream.
+				_, _ = io.WriteString(w, src)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(tt.handler))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			dst := &inMemoryBuffer{buf: make([]byte, size)}
+
+			err = c.multipartDownload(context.Background(), &blobURL{u: srv.URL}, nil, dst, size, &Downloader{Concurrency: 1, PartSize: 10}, &NoopProgressBar{})
+
+			var sme *SizeMismatchError
+			if !errors.As(err, &sme) {
+				t.Fatalf("got error %v, want SizeMismatchError", err)
+			}
+
+			if got, want := *sme, (SizeMismatchError{Got: int64(len(src)), Want: size}); got != want {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+type countingProgressBar struct {
+	NoopProgressBar
+
+	m sync.Mutex
+	n int
+}
+
+func (pb *countingProgressBar) IncrBy(n int) {
+	pb.m.Lock()
+	defer pb.m.Unlock()
+
+	pb.n += n
+}
+
+func TestMultistreamDownloaderRangeIgnored(t *testing.T) {
+	const src = "123456789012345678901234567890"
+	size := int64(len(src))
+
+	tests := []struct {
+		name     string
+		honoured int // number of range requests honoured before server ignores Range header
+	}{
+		{"Ignored", 0},
+		{"PartiallyHonoured", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m sync.Mutex
+			var requests int
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				m.Lock()
+				requests++
+				honour := requests <= tt.honoured
+				m.Unlock()
+
+				if rng := r.Header.Get("Range"); honour && rng != "" {
+					start, end := parseRangeHeader(t, rng)
+
+					w.Header().Set("Content-Range", fmt.Sprintf("bytes %v-%v/%v", start, end, size))
+					w.WriteHeader(http.StatusPartialContent)
+					_, _ = io.WriteString(w, src[start:end+1])
+					return
+				}
+
+				w.Header().Set("Content-Length", fmt.Sprintf("%v", size))
+				w.WriteHeader(http.StatusOK)
+				_, _ = io.WriteString(w, src)
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			dst := &inMemoryBuffer{buf: make([]byte, size)}
+			pb := &countingProgressBar{}
+
+			ctx, stats := withTransferStats(context.Background())
+
+			err = c.multipartDownload(ctx, &blobURL{u: srv.URL}, nil, dst, size, &Downloader{Concurrency: 1, PartSize: 3}, pb)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			summary := stats.summary()
+			if !summary.SingleStreamFallback {
+				t.Error("single stream fallback not recorded")
+			}
+			if got, want := summary.Retries, 0; got != want {
+				t.Errorf("got %v retries, want %v", got, want)
+			}
+
+			if got, want := string(dst.Bytes()), src; got != want {
+				t.Errorf("got data %v, want %v", got, want)
+			}
+
+			if got, want := pb.n, len(src); got != want {
+				t.Errorf("got %v bytes of progress, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_downloadBlobPartForbidden(t *testing.T) {
+	tests := []struct {
+		name       string
+		registry   bool
+		body       string
+		wantErr    error
+		wantNotErr error
+	}{
+		{"Registry", true, `{"errors":[{"code":"DENIED","message":"requested access to the resource is denied"}]}`, ErrPermissionDenied, errPresignedURLExpired},
+		{"ObjectStoreExpired", false, expiredURLBody, errPresignedURLExpired, ErrPermissionDenied},
+		{"ObjectStoreDenied", false, "", nil, errPresignedURLExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = io.WriteString(w, tt.body)
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			dst := &inMemoryBuffer{buf: make([]byte, 3)}
+
+			_, err = c.downloadBlobPart(context.Background(), nil, srv.URL+"/v2/name/blobs/sha256:abc", tt.registry, &filePartDescriptor{part: 1, end: 2, w: dst})
+			if err == nil {
+				t.Fatal("unexpected success")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+			if errors.Is(err, tt.wantNotErr) {
+				t.Errorf("got error %v, want not %v", err, tt.wantNotErr)
+			}
+		})
+	}
+}
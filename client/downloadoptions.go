@@ -0,0 +1,79 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DownloadOptions specifies the image to download, and how it is downloaded, for PullImage.
+type DownloadOptions struct {
+	// Arch is the architecture of the image.
+	Arch string
+	// Tag of the image. If empty, "latest" is used.
+	Tag string
+	// Backend from which the image is downloaded (ie. TransferBackendOCI or
+	// TransferBackendLibrary). If empty, the image is downloaded from the OCI registry if
+	// supported, and the library otherwise.
+	Backend string
+	// Downloader specifies transfer parameters and verification of the downloaded image. If nil,
+	// the parameters specified by Config.Downloader are used.
+	Downloader *Downloader
+	// ProgressBar reports the progress of the download (if supplied).
+	ProgressBar ProgressBar
+	// NoShare prevents content from being shared with concurrent downloads of the same image,
+	// when enabled by Config.ShareDownloads.
+	NoShare bool
+	// MaxBytesPerSecond limits the rate at which image content is downloaded. If zero, the rate
+	// is not limited.
+	MaxBytesPerSecond int64
+}
+
+// validate returns an error if o is not valid.
+func (o *DownloadOptions) validate() error {
+	switch o.Backend {
+	case "", TransferBackendOCI, TransferBackendLibrary:
+	default:
+		return fmt.Errorf("invalid download options: unsupported backend %q", o.Backend)
+	}
+
+	if o.MaxBytesPerSecond < 0 {
+		return errors.New("invalid download options: negative rate limit")
+	}
+	return nil
+}
+
+// PullImage downloads the image specified by path to dst, as specified by opts. If opts is nil,
+// the image tagged "latest" is downloaded using the transfer parameters specified by
+// Config.Downloader. A summary of the completed transfer is returned.
+func (c *Client) PullImage(ctx context.Context, dst io.WriterAt, path string, opts *DownloadOptions) (*TransferSummary, error) {
+	ctx, id := ensureRequestID(ctx)
+
+	c.logger.Logf("Downloading image (request ID: %v)", id)
+
+	ctx, stats := withTransferStats(ctx)
+
+	if err := c.downloadImage(ctx, dst, path, opts); err != nil {
+		return nil, err
+	}
+	return stats.summary(), nil
+}
+
+type noShareKey struct{}
+
+// withoutSharedDownloads returns a context that prevents downloads from being shared.
+func withoutSharedDownloads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noShareKey{}, true)
+}
+
+// sharedDownloadsDisabled reports whether ctx prevents downloads from being shared.
+func sharedDownloadsDisabled(ctx context.Context) bool {
+	b, _ := ctx.Value(noShareKey{}).(bool)
+	return b
+}
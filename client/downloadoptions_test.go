@@ -0,0 +1,101 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPullImage(t *testing.T) {
+	sampleBytes := bytes.Repeat([]byte("0123456789abcdef"), 8*1024)
+	size := int64(len(sampleBytes))
+
+	tests := []struct {
+		name        string
+		opts        *DownloadOptions
+		expectErr   bool
+		wantBackend string
+		wantSleeps  bool
+	}{
+		{"Defaults", nil, false, TransferBackendLibrary, false},
+		{"Auto", &DownloadOptions{Arch: "amd64", Tag: "tag"}, false, TransferBackendLibrary, false},
+		{"Library", &DownloadOptions{Backend: TransferBackendLibrary}, false, TransferBackendLibrary, false},
+		{"OCI", &DownloadOptions{Backend: TransferBackendOCI}, true, "", false},
+		{"BadBackend", &DownloadOptions{Backend: "ftp"}, true, "", false},
+		{"NoShare", &DownloadOptions{NoShare: true}, false, TransferBackendLibrary, false},
+		{"RateLimit", &DownloadOptions{MaxBytesPerSecond: 16 * 1024}, false, TransferBackendLibrary, true},
+		{"BadRateLimit", &DownloadOptions{MaxBytesPerSecond: -1}, true, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lib := mockLibraryServer(t, sampleBytes, true)
+			defer lib.Close()
+
+			// Direct OCI registry access is not supported by the mock library server
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/v1/oci-redirect" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				lib.Config.Handler.ServeHTTP(w, r)
+			}))
+			defer srv.Close()
+
+			s := &recordingSleeper{}
+
+			c, err := NewClient(&Config{
+				BaseURL:        srv.URL,
+				Logger:         testLogger,
+				Downloader:     &Downloader{Concurrency: 4, PartSize: 64 * 1024},
+				ShareDownloads: true,
+				Sleeper:        s,
+			})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			dst := &inMemoryBuffer{buf: make([]byte, size)}
+
+			summary, err := c.PullImage(context.Background(), dst, "entity/collection/container", tt.opts)
+			if tt.expectErr && err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+
+			if got, want := summary.Backend, tt.wantBackend; got != want {
+				t.Errorf("got backend %v, want %v", got, want)
+			}
+
+			if !bytes.Equal(dst.Bytes(), sampleBytes) {
+				t.Error("downloaded content does not match")
+			}
+
+			if got, want := len(s.delays) > 0, tt.wantSleeps; got != want {
+				t.Errorf("got sleeps %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSharedDownloadsDisabled(t *testing.T) {
+	if sharedDownloadsDisabled(context.Background()) {
+		t.Error("unexpected shared downloads disabled")
+	}
+
+	if !sharedDownloadsDisabled(withoutSharedDownloads(context.Background())) {
+		t.Error("expected shared downloads disabled")
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// DownloadVerifier specifies verification of image content as it is downloaded. As parts of a
+// concurrent download arrive, they are read back from the destination and fed to the hash in
+// order, so that the sum is available as soon as the final part is written.
+type DownloadVerifier struct {
+	// NewHash returns the hash.Hash that is fed the downloaded content. If nil, the hash is
+	// determined by the algorithm of Digest.
+	NewHash func() hash.Hash
+	// Digest is the expected digest of the content (ie. "sha256:..."). If set, the download fails
+	// with a *ChecksumMismatchError if the content does not match.
+	Digest digest.Digest
+	// PartSums enables computation of a sum of each part, in addition to the sum of the content.
+	PartSums bool
+}
+
+// VerificationResult describes the verification of downloaded content.
+type VerificationResult struct {
+	// Sum is the hex-encoded sum of the content.
+	Sum string
+	// Verified is true if Sum was compared to, and matched, the expected digest.
+	Verified bool
+	// PartSums are the hex-encoded sums of each part, in part order, if requested. A single
+	// stream download consists of one part.
+	PartSums []string
+}
+
+// downloadVerification feeds downloaded content to the hash specified by a DownloadVerifier.
+// Methods may be called concurrently, and are no-ops on a nil *downloadVerification.
+type downloadVerification struct {
+	mu       sync.Mutex
+	v        *DownloadVerifier
+	newHash  func() hash.Hash
+	r        io.ReaderAt
+	h        hash.Hash
+	next     int64           // offset of next byte to feed to h
+	pending  map[int64]int64 // start -> end of parts written but not yet fed to h
+	size     int64
+	partSums map[int]string
+	err      error
+}
+
+// newDownloadVerification returns a *downloadVerification that verifies content written to dst
+// according to v.
+func newDownloadVerification(v *DownloadVerifier, dst io.ReaderAt) (*downloadVerification, error) {
+	newHash := v.NewHash
+	if newHash == nil {
+		if v.Digest == "" {
+			return nil, errors.New("download verifier requires a hash or digest")
+		}
+		if err := v.Digest.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid digest: %w", err)
+		}
+		newHash = v.Digest.Algorithm().Hash
+	} else if v.Digest != "" {
+		if err := v.Digest.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid digest: %w", err)
+		}
+	}
+
+	return &downloadVerification{
+		v:        v,
+		newHash:  newHash,
+		r:        dst,
+		h:        newHash(),
+		pending:  make(map[int64]int64),
+		partSums: make(map[int]string),
+	}, nil
+}
+
+type downloadVerificationKey struct{}
+
+// withDownloadVerification returns a context carrying dv.
+func withDownloadVerification(ctx context.Context, dv *downloadVerification) context.Context {
+	return context.WithValue(ctx, downloadVerificationKey{}, dv)
+}
+
+// downloadVerificationFromContext returns the *downloadVerification carried by ctx, or nil if not
+// present.
+func downloadVerificationFromContext(ctx context.Context) *downloadVerification {
+	dv, _ := ctx.Value(downloadVerificationKey{}).(*downloadVerification)
+	return dv
+}
+
+// setSize records the size of the content being downloaded.
+func (dv *downloadVerification) setSize(size int64) {
+	if dv == nil {
+		return
+	}
+	dv.mu.Lock()
+	defer dv.mu.Unlock()
+
+	dv.size = size
+}
+
+// sum returns the hex-encoded sum of n bytes of the destination starting at off, using a new hash.
+func (dv *downloadVerification) sum(off, n int64) (string, error) {
+	h := dv.newHash()
+	if _, err := io.Copy(h, io.NewSectionReader(dv.r, off, n)); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// feed feeds the contiguous parts that have been written, starting at the next offset, to the
+// hash. The caller must hold dv.mu.
+func (dv *downloadVerification) feed() {
+	for dv.err == nil {
+		end, ok := dv.pending[dv.next]
+		if !ok {
+			return
+		}
+		delete(dv.pending, dv.next)
+
+		if _, err := io.Copy(dv.h, io.NewSectionReader(dv.r, dv.next, end-dv.next+1)); err != nil {
+			dv.err = fmt.Errorf("error reading downloaded content: %w", err)
+			return
+		}
+		dv.next = end + 1
+	}
+}
+
+// partDone records that part, consisting of the bytes from start to end (inclusive), has been
+// written to the destination.
+func (dv *downloadVerification) partDone(part int, start, end int64) {
+	if dv == nil {
+		return
+	}
+	dv.mu.Lock()
+	defer dv.mu.Unlock()
+
+	if dv.err != nil {
+		return
+	}
+
+	if dv.v.PartSums {
+		s, err := dv.sum(start, end-start+1)
+		if err != nil {
+			dv.err = fmt.Errorf("error reading downloaded content: %w", err)
+			return
+		}
+		dv.partSums[part] = s
+	}
+
+	dv.pending[start] = end
+	dv.feed()
+}
+
+// reset discards the parts recorded so far, such as when a concurrent download is abandoned in
+// favour of a single stream.
+func (dv *downloadVerification) reset() {
+	if dv == nil {
+		return
+	}
+	dv.mu.Lock()
+	defer dv.mu.Unlock()
+
+	dv.h = dv.newHash()
+	dv.next = 0
+	dv.pending = make(map[int64]int64)
+	dv.partSums = make(map[int]string)
+}
+
+// complete feeds any content not yet fed to the hash, and verifies the sum against the expected
+// digest (if specified).
+func (dv *downloadVerification) complete() (*VerificationResult, error) {
+	dv.mu.Lock()
+	defer dv.mu.Unlock()
+
+	if dv.err != nil {
+		return nil, dv.err
+	}
+
+	// Content written without being recorded as parts (ie. by a single stream download).
+	if n := dv.size - dv.next; n > 0 {
+		if len(dv.partSums) == 0 && dv.v.PartSums {
+			s, err := dv.sum(dv.next, n)
+			if err != nil {
+				return nil, fmt.Errorf("error reading downloaded content: %w", err)
+			}
+			dv.partSums[1] = s
+		}
+
+		dv.pending[dv.next] = dv.size - 1
+		dv.feed()
+
+		if dv.err != nil {
+			return nil, dv.err
+		}
+	}
+
+	res := &VerificationResult{Sum: hex.EncodeToString(dv.h.Sum(nil))}
+
+	if len(dv.partSums) > 0 {
+		parts := make([]int, 0, len(dv.partSums))
+		for part := range dv.partSums {
+			parts = append(parts, part)
+		}
+		sort.Ints(parts)
+
+		for _, part := range parts {
+			res.PartSums = append(res.PartSums, dv.partSums[part])
+		}
+	}
+
+	if d := dv.v.Digest; d != "" {
+		if res.Sum != d.Encoded() {
+			return nil, &ChecksumMismatchError{Algorithm: d.Algorithm().String(), Got: res.Sum, Want: d.Encoded()}
+		}
+		res.Verified = true
+	}
+
+	return res, nil
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+)
+
+func TestDownloadVerification(t *testing.T) {
+	const src = "123456789012345678901234567890"
+	size := int64(len(src))
+
+	sha256Sum := func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+
+	md5Sum := md5.Sum([]byte(src))
+
+	tests := []struct {
+		name         string
+		v            DownloadVerifier
+		ignoreRange  bool
+		want         *VerificationResult
+		wantErr      bool
+		wantMismatch bool
+	}{
+		{
+			name: "Digest",
+			v:    DownloadVerifier{Digest: digest.FromString(src)},
+			want: &VerificationResult{Sum: sha256Sum(src), Verified: true},
+		},
+		{
+			name:         "DigestMismatch",
+			v:            DownloadVerifier{Digest: digest.FromString("other")},
+			wantErr:      true,
+			wantMismatch: true,
+		},
+		{
+			name: "NewHash",
+			v:    DownloadVerifier{NewHash: md5.New},
+			want: &VerificationResult{Sum: hex.EncodeToString(md5Sum[:])},
+		},
+		{
+			name: "PartSums",
+			v:    DownloadVerifier{NewHash: sha256.New, PartSums: true},
+			want: &VerificationResult{
+				Sum: sha256Sum(src),
+				PartSums: []string{
+					sha256Sum(src[0:7]),
+					sha256Sum(src[7:14]),
+					sha256Sum(src[14:21]),
+					sha256Sum(src[21:28]),
+					sha256Sum(src[28:30]),
+				},
+			},
+		},
+		{
+			name:        "RangeIgnored",
+			v:           DownloadVerifier{Digest: digest.FromString(src), PartSums: true},
+			ignoreRange: true,
+			want:        &VerificationResult{Sum: sha256Sum(src), Verified: true, PartSums: []string{sha256Sum(src)}},
+		},
+		{
+			name:    "NoHash",
+			v:       DownloadVerifier{},
+			wantErr: true,
+		},
+		{
+			name:    "InvalidDigest",
+			v:       DownloadVerifier{Digest: "sha256:bad"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.ignoreRange {
+					w.Header().Set("Content-Length", fmt.Sprint(size))
+					if _, err := io.WriteString(w, src); err != nil {
+						t.Errorf("unexpected error writing http response: %v", err)
+					}
+					return
+				}
+
+				start, end := parseRangeHeader(t, r.Header.Get("Range"))
+
+				w.Header().Set("Content-Length", fmt.Sprint(end-start+1))
+				w.WriteHeader(http.StatusPartialContent)
+
+				if _, err := io.Copy(w, strings.NewReader(src[start:end+1])); err != nil {
+					t.Errorf("unexpected error writing http response: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			dst := &inMemoryBuffer{buf: make([]byte, size)}
+
+			dv, err := newDownloadVerification(&tt.v, dst)
+			if err == nil {
+				ctx := withDownloadVerification(context.Background(), dv)
+
+				err = c.multipartDownload(ctx, &blobURL{u: srv.URL}, nil, dst, size, &Downloader{Concurrency: 3, PartSize: 7}, &NoopProgressBar{})
+				if err != nil {
+					t.Fatalf("unexpected download error: %v", err)
+				}
+			}
+
+			var res *VerificationResult
+			if err == nil {
+				res, err = dv.complete()
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			var cme *ChecksumMismatchError
+			if got, want := errors.As(err, &cme), tt.wantMismatch; got != want {
+				t.Errorf("got checksum mismatch %v, want %v", got, want)
+			}
+
+			if !reflect.DeepEqual(res, tt.want) {
+				t.Errorf("got result %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownloadVerificationSingleStream(t *testing.T) {
+	const src = "123456789012345678901234567890"
+
+	dst := &inMemoryBuffer{buf: make([]byte, len(src))}
+
+	var h hash.Hash
+	dv, err := newDownloadVerification(&DownloadVerifier{NewHash: func() hash.Hash {
+		h = sha256.New()
+		return h
+	}}, dst)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c, err := NewClient(&Config{Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	ctx := withDownloadVerification(context.Background(), dv)
+
+	if err := c.download(ctx, dst, strings.NewReader(src), int64(len(src)), &NoopProgressBar{}); err != nil {
+		t.Fatalf("unexpected download error: %v", err)
+	}
+
+	res, err := dv.complete()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := res.Sum, digest.FromString(src).Encoded(); got != want {
+		t.Errorf("got sum %v, want %v", got, want)
+	}
+	if res.Verified {
+		t.Error("got verified, want not verified")
+	}
+	if h == nil {
+		t.Error("hash factory not called")
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+)
+
+const (
+	// ConfigEnvVar is the environment variable naming a configuration file (see LoadConfig) used
+	// by ConfigFromEnvironment.
+	ConfigEnvVar = "SCS_LIBRARY_CLIENT_CONFIG"
+	// BaseURLEnvVar is the environment variable that, when set, overrides the base URL of the
+	// service in the configuration returned by ConfigFromEnvironment.
+	BaseURLEnvVar = "SCS_LIBRARY_CLIENT_URL"
+	// AuthTokenEnvVar is the environment variable that, when set, overrides the auth token in the
+	// configuration returned by ConfigFromEnvironment.
+	AuthTokenEnvVar = "SCS_LIBRARY_CLIENT_TOKEN"
+)
+
+// ConfigFromEnvironment returns a configuration loaded from the file named by ConfigEnvVar (if
+// set), with the base URL and auth token overridden by BaseURLEnvVar and AuthTokenEnvVar (if set).
+func ConfigFromEnvironment() (*Config, error) {
+	cfg := &Config{}
+
+	if path := os.Getenv(ConfigEnvVar); path != "" {
+		var err error
+		if cfg, err = LoadConfig(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if v, ok := os.LookupEnv(BaseURLEnvVar); ok {
+		cfg.BaseURL = v
+	}
+	if v, ok := os.LookupEnv(AuthTokenEnvVar); ok {
+		cfg.AuthToken = strings.TrimSpace(v)
+	}
+	return cfg, nil
+}
+
+// PushOptions specifies how an image is uploaded by Push.
+type PushOptions struct {
+	// Arch is the architecture of the image. If empty, the architecture of the running program
+	// (runtime.GOARCH) is used.
+	Arch string
+	// Description of the image.
+	Description string
+	// Callback is notified of the progress of the upload (if supplied).
+	Callback UploadCallback
+	// Upload overrides the upload options specified by the configuration.
+	Upload []UploadOption
+}
+
+// facadeClient returns a client configured from the environment to access the image identified
+// by rawRef (ie. "library://entity/collection/container:tag"), along with the path and tags of
+// the image. If rawRef specifies a host other than that of the configured base URL, the library at
+// that host is accessed using HTTPS, without the configured auth token, so that it is never
+// disclosed to a host named by the ref.
+func facadeClient(rawRef string) (*Client, string, []string, error) {
+	ref, err := ParseAmbiguous(rawRef)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("error parsing ref %q: %w", rawRef, err)
+	}
+
+	if strings.Count(ref.Path, "/") != 2 {
+		return nil, "", nil, fmt.Errorf("ref %q must be of the form entity/collection/container", rawRef)
+	}
+
+	cfg, err := ConfigFromEnvironment()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	if ref.Host != "" {
+		bu := cfg.BaseURL
+		if bu == "" {
+			bu = defaultBaseURL
+		}
+		if u, err := url.Parse(bu); err != nil || !strings.EqualFold(u.Host, ref.Host) {
+			cfg.BaseURL = "https://" + ref.Host
+			cfg.AuthToken, cfg.TokenSource = "", nil
+		}
+	}
+
+	c, err := NewClient(cfg)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	tags := ref.Tags
+	if len(tags) == 0 {
+		tags = []string{"latest"}
+	}
+	return c, ref.Path, tags, nil
+}
+
+// Pull downloads the image identified by rawRef (ie. "library://entity/collection/container:tag")
+// to dst, using a client configured by ConfigFromEnvironment. It is intended for one-off scripts
+// and examples; applications should construct a Client using NewClient.
+//
+// If rawRef does not specify a tag, the image tagged "latest" is downloaded. The Tag of opts is
+// ignored. If opts does not specify an architecture, the architecture of the running program is
+// used.
+func Pull(ctx context.Context, dst io.WriterAt, rawRef string, opts *DownloadOptions) (*TransferSummary, error) {
+	c, path, tags, err := facadeClient(rawRef)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) > 1 {
+		return nil, errors.New("only one tag may be specified when pulling an image")
+	}
+
+	var o DownloadOptions
+	if opts != nil {
+		o = *opts
+	}
+	o.Tag = tags[0]
+	if o.Arch == "" {
+		o.Arch = runtime.GOARCH
+	}
+
+	return c.PullImage(ctx, dst, path, &o)
+}
+
+// Push uploads the image read from src to rawRef (ie.
+// "library://entity/collection/container:tag1,tag2"), using a client configured by
+// ConfigFromEnvironment. It is intended for one-off scripts and examples; applications should
+// construct a Client using NewClient.
+//
+// If rawRef does not specify a tag, the image is tagged "latest".
+func Push(ctx context.Context, src io.ReadSeeker, rawRef string, opts *PushOptions) (*UploadImageComplete, error) {
+	c, path, tags, err := facadeClient(rawRef)
+	if err != nil {
+		return nil, err
+	}
+
+	var o PushOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Arch == "" {
+		o.Arch = runtime.GOARCH
+	}
+
+	return c.UploadImage(ctx, src, path, o.Arch, tags, o.Description, o.Callback, o.Upload...)
+}
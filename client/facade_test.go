@@ -0,0 +1,197 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigFromEnvironment(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"baseURL": "https://library.example.com", "authTokenFile": "token"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "token"), []byte("filetoken\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		env       map[string]string
+		wantURL   string
+		wantToken string
+		expectErr bool
+	}{
+		{"Empty", nil, "", "", false},
+		{"Config", map[string]string{ConfigEnvVar: path}, "https://library.example.com", "filetoken", false},
+		{"Overrides", map[string]string{ConfigEnvVar: path, BaseURLEnvVar: "https://other.example.com", AuthTokenEnvVar: "envtoken"}, "https://other.example.com", "envtoken", false},
+		{"NoConfig", map[string]string{BaseURLEnvVar: "https://other.example.com"}, "https://other.example.com", "", false},
+		{"MissingConfig", map[string]string{ConfigEnvVar: filepath.Join(dir, "missing.json")}, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, k := range []string{ConfigEnvVar, BaseURLEnvVar, AuthTokenEnvVar} {
+				t.Setenv(k, "")
+				os.Unsetenv(k)
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			cfg, err := ConfigFromEnvironment()
+			if tt.expectErr && err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+
+			if got, want := cfg.BaseURL, tt.wantURL; got != want {
+				t.Errorf("got base URL %q, want %q", got, want)
+			}
+			if got, want := cfg.AuthToken, tt.wantToken; got != want {
+				t.Errorf("got auth token %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func Test_facadeClient(t *testing.T) {
+	t.Setenv(ConfigEnvVar, "")
+	t.Setenv(BaseURLEnvVar, "https://library.example.com")
+	t.Setenv(AuthTokenEnvVar, "token")
+
+	tests := []struct {
+		name      string
+		ref       string
+		wantHost  string
+		wantToken string
+		wantPath  string
+		wantTags  []string
+		expectErr bool
+	}{
+		{"Hostless", "library://entity/collection/container", "library.example.com", "token", "entity/collection/container", []string{"latest"}, false},
+		{"Tags", "library://entity/collection/container:v1,v2", "library.example.com", "token", "entity/collection/container", []string{"v1", "v2"}, false},
+		{"SameHost", "library://Library.Example.com/entity/collection/container:v1", "library.example.com", "token", "entity/collection/container", []string{"v1"}, false},
+		{"OtherHost", "library://other.example.com/entity/collection/container:v1", "other.example.com", "", "entity/collection/container", []string{"v1"}, false},
+		{"ShortPath", "library://collection/container", "", "", "", nil, true},
+		{"BadScheme", "docker://entity/collection/container", "", "", "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, path, tags, err := facadeClient(tt.ref)
+			if tt.expectErr && err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+
+			if got, want := c.baseURL.Host, tt.wantHost; got != want {
+				t.Errorf("got host %v, want %v", got, want)
+			}
+			if got, want := c.authToken, tt.wantToken; got != want {
+				t.Errorf("got auth token %q, want %q", got, want)
+			}
+			if got, want := path, tt.wantPath; got != want {
+				t.Errorf("got path %v, want %v", got, want)
+			}
+			if got, want := tags, tt.wantTags; !reflect.DeepEqual(got, want) {
+				t.Errorf("got tags %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_facadeClientBaseURL(t *testing.T) {
+	t.Setenv(ConfigEnvVar, "")
+	t.Setenv(BaseURLEnvVar, "http://lib.local:8080/prefix")
+	t.Setenv(AuthTokenEnvVar, "token")
+
+	tests := []struct {
+		name      string
+		ref       string
+		wantURL   string
+		wantToken string
+	}{
+		{"Hostless", "library://entity/collection/container", "http://lib.local:8080/prefix", "token"},
+		{"SameHost", "library://lib.local:8080/entity/collection/container", "http://lib.local:8080/prefix", "token"},
+		{"OtherPort", "library://lib.local/entity/collection/container", "https://lib.local", ""},
+		{"OtherHost", "library://other.example.com/entity/collection/container", "https://other.example.com", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, _, err := facadeClient(tt.ref)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got, want := strings.TrimSuffix(c.baseURL.String(), "/"), tt.wantURL; got != want {
+				t.Errorf("got base URL %v, want %v", got, want)
+			}
+			if got, want := c.authToken, tt.wantToken; got != want {
+				t.Errorf("got auth token %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestPull(t *testing.T) {
+	sampleBytes := generateSampleData(t)
+
+	lib := mockLibraryServer(t, sampleBytes, true)
+	defer lib.Close()
+
+	// Direct OCI registry access is not supported by the mock library server
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/oci-redirect" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		lib.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	t.Setenv(ConfigEnvVar, "")
+	t.Setenv(BaseURLEnvVar, srv.URL)
+
+	dst := &inMemoryBuffer{buf: make([]byte, len(sampleBytes))}
+
+	summary, err := Pull(context.Background(), dst, "library://entity/collection/container:v1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !bytes.Equal(dst.Bytes(), sampleBytes) {
+		t.Error("downloaded content does not match")
+	}
+	if got, want := summary.Bytes, int64(len(sampleBytes)); got != want {
+		t.Errorf("got %v byte(s), want %v", got, want)
+	}
+
+	if _, err := Pull(context.Background(), dst, "library://entity/collection/container:v1,v2", nil); err == nil {
+		t.Error("unexpected success")
+	}
+}
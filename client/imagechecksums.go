@@ -0,0 +1,65 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// imageChecksumsURL returns the API URL of the checksums of the image identified by imageRef (ie.
+// "entity/collection/container:tag") and arch.
+func imageChecksumsURL(arch, imageRef string) string {
+	q := url.Values{}
+	q.Add("arch", arch)
+	apiURL := &url.URL{
+		Path:     "v1/imagechecksums/" + imageRef,
+		RawQuery: q.Encode(),
+	}
+	return apiURL.String()
+}
+
+// GetImageChecksums returns the checksums of the image identified by imageRef (ie.
+// "entity/collection/container:tag") and arch; returns ErrNotFound if the image is not found, or
+// no checksums have been published for the image.
+func (c *Client) GetImageChecksums(ctx context.Context, arch string, imageRef string) (*ImageChecksums, error) {
+	b, err := c.apiGet(ctx, imageChecksumsURL(arch, imageRef))
+	if err != nil {
+		return nil, err
+	}
+	var res ImageChecksumsResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("error decoding image checksums: %v", err)
+	}
+	return &res.Data, nil
+}
+
+// PublishImageChecksums publishes the checksums of the image identified by imageRef (ie.
+// "entity/collection/container:sha256.<hash>") and arch, so that the image may be verified by
+// mirrors and offline consumers without re-computing checksums against the API.
+func (c *Client) PublishImageChecksums(ctx context.Context, arch string, imageRef string, sums *ImageChecksums) error {
+	if _, err := c.apiUpdate(ctx, imageChecksumsURL(arch, imageRef), sums); err != nil {
+		return fmt.Errorf("error publishing image checksums: %w", err)
+	}
+	return nil
+}
+
+// Sidecar returns the content of a checksum sidecar file (ie. "image.sif.sha256") for the image
+// stored as filename, using algorithm alg. The format is compatible with the "--check" option of
+// sha256sum and related utilities. Only hex encoded checksums are supported.
+func (s *ImageChecksums) Sidecar(alg ChecksumAlgorithm, filename string) (string, error) {
+	if alg == ChecksumCRC32C {
+		return "", fmt.Errorf("checksum algorithm %q not supported in sidecar files", alg)
+	}
+
+	sum, ok := s.Checksums[alg]
+	if !ok {
+		return "", fmt.Errorf("%v checksum not present", alg)
+	}
+	return fmt.Sprintf("%v  %v\n", sum, filename), nil
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+const testImageSHA256 = "ea7eb1b4d1cf2bd7b6ba2ad47a9bd35e9fba0cc09d7e8b1c9a0b3fd1f0d3a4c2"
+
+func TestGetImageChecksums(t *testing.T) {
+	want := ImageChecksums{
+		Size:      1024,
+		Checksums: map[ChecksumAlgorithm]string{ChecksumSHA256: testImageSHA256},
+	}
+
+	tests := []struct {
+		name    string
+		code    int
+		wantErr error
+	}{
+		{"OK", http.StatusOK, nil},
+		{"NotFound", http.StatusNotFound, ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got, want := r.URL.Path, "/v1/imagechecksums/entity/collection/container:tag"; got != want {
+					t.Errorf("got path %v, want %v", got, want)
+				}
+				if got, want := r.URL.Query().Get("arch"), "amd64"; got != want {
+					t.Errorf("got arch %v, want %v", got, want)
+				}
+
+				if tt.code != http.StatusOK {
+					w.WriteHeader(tt.code)
+					return
+				}
+				if err := jsonresp.WriteResponse(w, &want, http.StatusOK); err != nil {
+					t.Errorf("error writing JSON response: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			got, err := c.GetImageChecksums(context.Background(), "amd64", "entity/collection/container:tag")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(*got, want) {
+				t.Errorf("got checksums %+v, want %+v", *got, want)
+			}
+		})
+	}
+}
+
+func Test_publishUploadChecksums(t *testing.T) {
+	sums := checksums{
+		ChecksumSHA256: testImageSHA256,
+		ChecksumMD5:    "781e5e245d69b566979b86e28d23f2c7",
+	}
+
+	tests := []struct {
+		name        string
+		publish     bool
+		wantPublish bool
+	}{
+		{"Enabled", true, true},
+		{"Disabled", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *ImageChecksums
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got, want := r.Method, http.MethodPut; got != want {
+					t.Errorf("got method %v, want %v", got, want)
+				}
+				if got, want := r.URL.Path, "/v1/imagechecksums/entity/collection/container:sha256."+testImageSHA256; got != want {
+					t.Errorf("got path %v, want %v", got, want)
+				}
+
+				got = &ImageChecksums{}
+				if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+					t.Errorf("error decoding request: %v", err)
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger, PublishChecksums: tt.publish})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			if err := c.publishUploadChecksums(context.Background(), "amd64", "entity/collection/container", 10, sums); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if (got != nil) != tt.wantPublish {
+				t.Fatalf("got published %v, want %v", got != nil, tt.wantPublish)
+			}
+
+			if want := (&ImageChecksums{Size: 10, Checksums: sums}); got != nil && !reflect.DeepEqual(got, want) {
+				t.Errorf("got checksums %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestImageChecksumsSidecar(t *testing.T) {
+	s := &ImageChecksums{
+		Size: 1024,
+		Checksums: map[ChecksumAlgorithm]string{
+			ChecksumSHA256: testImageSHA256,
+			ChecksumCRC32C: "yZRlqg==",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		alg     ChecksumAlgorithm
+		want    string
+		wantErr bool
+	}{
+		{"SHA256", ChecksumSHA256, testImageSHA256 + "  image.sif\n", false},
+		{"Missing", ChecksumSHA512, "", true},
+		{"CRC32C", ChecksumCRC32C, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Sidecar(tt.alg, "image.sif")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got sidecar %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ImageConfigMismatchError is returned when a property of a downloaded image does not match the
+// image config recorded in the OCI registry, such as when the config is stale or has been
+// tampered with.
+type ImageConfigMismatchError struct {
+	// Property is the mismatched property ("architecture", "signed", "encrypted" or "rootfs").
+	Property string
+	// Got is the value of the property in the downloaded image.
+	Got string
+	// Want is the value of the property recorded in the image config.
+	Want string
+}
+
+func (e *ImageConfigMismatchError) Error() string {
+	return fmt.Sprintf("image config %v mismatch: got %v, want %v", e.Property, e.Got, e.Want)
+}
+
+// verifyImageConfig verifies that the properties recorded in image config ic match the SIF image
+// read from r. If ic is nil (ie. the image has no SIF image config), verification is skipped.
+func (c *Client) verifyImageConfig(ic *imageConfig, r *io.SectionReader) error {
+	if ic == nil {
+		c.logger.Log("Image config not present, skipping image config verification")
+		return nil
+	}
+
+	var header bytes.Buffer
+
+	f, err := readSIFHeader(io.NewSectionReader(r, 0, r.Size()), &header)
+	if err != nil {
+		return fmt.Errorf("error verifying image config: %w", err)
+	}
+	defer func() {
+		if err := f.UnloadContainer(); err != nil {
+			c.logger.Logf("Failed to unload container: %v", err)
+		}
+	}()
+
+	if got, want := f.PrimaryArch(), ic.Architecture; got != want {
+		return &ImageConfigMismatchError{Property: "architecture", Got: got, Want: want}
+	}
+
+	signed, err := getSigned(f)
+	if err != nil {
+		return fmt.Errorf("error verifying image config: %w", err)
+	}
+	if got, want := signed, ic.Signed; got != want {
+		return &ImageConfigMismatchError{Property: "signed", Got: strconv.FormatBool(got), Want: strconv.FormatBool(want)}
+	}
+
+	encrypted, err := getEncrypted(f)
+	if err != nil {
+		return fmt.Errorf("error verifying image config: %w", err)
+	}
+	if got, want := encrypted, ic.Encrypted; got != want {
+		return &ImageConfigMismatchError{Property: "encrypted", Got: strconv.FormatBool(got), Want: strconv.FormatBool(want)}
+	}
+
+	if err := ic.RootFS.Validate(); err != nil {
+		return fmt.Errorf("error verifying image config: %w", err)
+	}
+	got, err := ic.RootFS.Algorithm().FromReader(io.NewSectionReader(r, 0, r.Size()))
+	if err != nil {
+		return fmt.Errorf("error verifying image config: %w", err)
+	}
+	if want := ic.RootFS; got != want {
+		return &ImageConfigMismatchError{Property: "rootfs", Got: got.String(), Want: want.String()}
+	}
+
+	c.logger.Logf("Verified image config (arch: %v, signed: %v, encrypted: %v)", ic.Architecture, ic.Signed, ic.Encrypted)
+	return nil
+}
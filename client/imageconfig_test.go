@@ -0,0 +1,73 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+)
+
+func Test_verifyImageConfig(t *testing.T) {
+	image := testPartitionSIF(t, "amd64")
+
+	valid := imageConfig{
+		Architecture: "amd64",
+		OS:           "linux",
+		RootFS:       digest.FromBytes(image),
+	}
+
+	tests := []struct {
+		name         string
+		image        []byte
+		modify       func(*imageConfig)
+		noConfig     bool
+		wantProperty string
+		wantErr      bool
+	}{
+		{name: "Valid", image: image},
+		{name: "NoConfig", image: []byte("not a SIF image"), noConfig: true},
+		{name: "Architecture", image: image, modify: func(ic *imageConfig) { ic.Architecture = "arm64" }, wantProperty: "architecture", wantErr: true},
+		{name: "Signed", image: image, modify: func(ic *imageConfig) { ic.Signed = true }, wantProperty: "signed", wantErr: true},
+		{name: "Encrypted", image: image, modify: func(ic *imageConfig) { ic.Encrypted = true }, wantProperty: "encrypted", wantErr: true},
+		{name: "RootFS", image: image, modify: func(ic *imageConfig) { ic.RootFS = digest.FromString("stale") }, wantProperty: "rootfs", wantErr: true},
+		{name: "NotSIF", image: []byte("not a SIF image"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(&Config{Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			var ic *imageConfig
+			if !tt.noConfig {
+				cfg := valid
+				if tt.modify != nil {
+					tt.modify(&cfg)
+				}
+				ic = &cfg
+			}
+
+			err = c.verifyImageConfig(ic, io.NewSectionReader(bytes.NewReader(tt.image), 0, int64(len(tt.image))))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			var me *ImageConfigMismatchError
+			if got := errors.As(err, &me); got != (tt.wantProperty != "") {
+				t.Fatalf("got error %v, want mismatch of %q", err, tt.wantProperty)
+			}
+			if me != nil && me.Property != tt.wantProperty {
+				t.Errorf("got mismatched property %v, want %v", me.Property, tt.wantProperty)
+			}
+		})
+	}
+}
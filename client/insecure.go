@@ -0,0 +1,75 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ErrInsecureEndpoint is returned when the library server directs the client to an endpoint that
+// is accessed using plain HTTP, and the endpoint is not permitted by
+// Config.AllowInsecureRegistries.
+var ErrInsecureEndpoint = errors.New("insecure endpoint refused")
+
+// InsecureEndpointError records the refusal of an endpoint accessed using plain HTTP.
+type InsecureEndpointError struct {
+	URL string // URL of the endpoint, with credentials redacted
+}
+
+func (e *InsecureEndpointError) Error() string {
+	return fmt.Sprintf("%v: %v: plain HTTP not permitted", ErrInsecureEndpoint, e.URL)
+}
+
+// Is returns true if target is ErrInsecureEndpoint.
+func (e *InsecureEndpointError) Is(target error) bool {
+	return target == ErrInsecureEndpoint
+}
+
+// isLoopbackHost returns true if host is "localhost", or a loopback IP address.
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// checkInsecure returns an error if u is accessed using plain HTTP, and is not a loopback host or
+// permitted by the insecure registries of the client. Access to a permitted host is logged, once
+// per host.
+func (c *Client) checkInsecure(u *url.URL) error {
+	if !strings.EqualFold(u.Scheme, "http") || isLoopbackHost(u.Hostname()) {
+		return nil
+	}
+
+	for _, pattern := range c.insecureRegistries {
+		if matchHost(pattern, u) {
+			if _, loaded := c.insecureWarnings.LoadOrStore(strings.ToLower(u.Host), struct{}{}); !loaded {
+				c.logger.Logf("WARNING: accessing %v using insecure plain HTTP; content and credentials are not protected in transit", u.Host)
+			}
+			return nil
+		}
+	}
+	return &InsecureEndpointError{URL: redactURL(u)}
+}
+
+// checkPresignedURL returns an error if the presigned URL rawURL returned by the library server
+// is accessed using plain HTTP, and is not permitted (see checkInsecure). URLs of the same host as
+// the library server are always permitted.
+func (c *Client) checkPresignedURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("error parsing presigned URL: %w", err)
+	}
+	if samehost(c.baseURL, u) {
+		return nil
+	}
+	return c.checkInsecure(u)
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_checkPresignedURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		insecure []string
+		url      string
+		wantErr  error
+		wantWarn bool
+	}{
+		{"HTTPS", nil, "https://bucket.example.com/object?X-Amz-Signature=abc", nil, false},
+		{"LibraryHost", nil, "http://library.example.com/object", nil, false},
+		{"Localhost", nil, "http://localhost:9000/object", nil, false},
+		{"Loopback", nil, "http://127.0.0.1:9000/object", nil, false},
+		{"LoopbackIPv6", nil, "http://[::1]:9000/object", nil, false},
+		{"Refused", nil, "http://bucket.example.com/object?X-Amz-Signature=abc", ErrInsecureEndpoint, false},
+		{"Allowed", []string{"bucket.example.com"}, "http://bucket.example.com/object", nil, true},
+		{"AllowedWildcard", []string{"*.example.com"}, "http://bucket.example.com/object", nil, true},
+		{"NotAllowed", []string{"other.example.com"}, "http://bucket.example.com/object", ErrInsecureEndpoint, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var l bufferLogger
+
+			c, err := NewClient(&Config{
+				BaseURL:                 "http://library.example.com",
+				AllowInsecureRegistries: tt.insecure,
+				Logger:                  &l,
+			})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			// Warnings are logged once per host.
+			for i := 0; i < 2; i++ {
+				if err := c.checkPresignedURL(tt.url); !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+			}
+
+			wantWarnings := 0
+			if tt.wantWarn {
+				wantWarnings = 1
+			}
+			if got, want := strings.Count(l.String(), "WARNING"), wantWarnings; got != want {
+				t.Errorf("got %v warnings, want %v", got, want)
+			}
+
+			if strings.Contains(l.String(), "abc") {
+				t.Errorf("signature logged: %v", l.String())
+			}
+		})
+	}
+}
+
+func TestInsecureEndpointErrorRedacted(t *testing.T) {
+	c, err := NewClient(&Config{BaseURL: "https://library.example.com"})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	err = c.checkPresignedURL("http://bucket.example.com/object?X-Amz-Signature=abc")
+	if !errors.Is(err, ErrInsecureEndpoint) {
+		t.Fatalf("got error %v, want %v", err, ErrInsecureEndpoint)
+	}
+	if strings.Contains(err.Error(), "abc") {
+		t.Errorf("signature included in error: %v", err)
+	}
+}
+
+func TestInsecureRegistryRefused(t *testing.T) {
+	lib := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if err := json.NewEncoder(w).Encode(map[string]string{"token": "token", "url": "http://registry.example.com"}); err != nil {
+			t.Errorf("error JSON encoding: %v", err)
+		}
+	}))
+	defer lib.Close()
+
+	tests := []struct {
+		name     string
+		insecure []string
+		wantErr  error
+	}{
+		{"Refused", nil, ErrInsecureEndpoint},
+		{"Allowed", []string{"registry.example.com"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(&Config{
+				BaseURL:                 lib.URL,
+				AllowInsecureRegistries: tt.insecure,
+				StrictRegistryAccess:    true,
+				Logger:                  testLogger,
+			})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			_, _, _, err = c.newOCIRegistry(context.Background(), "entity/collection/container", []accessType{accessTypePull})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
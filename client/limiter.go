@@ -0,0 +1,150 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// requestLimiter limits the number of in-flight HTTP requests, overall and per host. A request is
+// in-flight until its response body is closed.
+type requestLimiter struct {
+	// total limits requests to all hosts (if non-nil).
+	total *semaphore.Weighted
+
+	// perHost is the maximum number of requests to a single host, or zero if unlimited.
+	perHost int64
+
+	mu    sync.Mutex
+	hosts map[string]*semaphore.Weighted
+}
+
+// newRequestLimiter returns a requestLimiter that permits maxTotal requests overall, and maxPerHost
+// requests per host. A value of zero indicates no limit. If both values are zero, nil is returned.
+func newRequestLimiter(maxTotal, maxPerHost int) *requestLimiter {
+	if maxTotal <= 0 && maxPerHost <= 0 {
+		return nil
+	}
+
+	l := &requestLimiter{
+		perHost: int64(maxPerHost),
+		hosts:   make(map[string]*semaphore.Weighted),
+	}
+	if maxTotal > 0 {
+		l.total = semaphore.NewWeighted(int64(maxTotal))
+	}
+	return l
+}
+
+// host returns the semaphore limiting requests to host, or nil if requests are not limited per
+// host.
+func (l *requestLimiter) host(host string) *semaphore.Weighted {
+	if l.perHost <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	host = strings.ToLower(host)
+
+	s, ok := l.hosts[host]
+	if !ok {
+		s = semaphore.NewWeighted(l.perHost)
+		l.hosts[host] = s
+	}
+	return s
+}
+
+// acquire blocks until req may be sent, or the request context is done. On success, the returned
+// function must be called to release the resources acquired.
+func (l *requestLimiter) acquire(req *http.Request) (func(), error) {
+	ctx := req.Context()
+
+	if l.total != nil {
+		if err := l.total.Acquire(ctx, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	hs := l.host(req.URL.Host)
+	if hs != nil {
+		if err := hs.Acquire(ctx, 1); err != nil {
+			if l.total != nil {
+				l.total.Release(1)
+			}
+			return nil, err
+		}
+	}
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			if hs != nil {
+				hs.Release(1)
+			}
+			if l.total != nil {
+				l.total.Release(1)
+			}
+		})
+	}, nil
+}
+
+// limitedTransport is a http.RoundTripper that limits the number of in-flight requests passed to
+// next.
+type limitedTransport struct {
+	next    http.RoundTripper
+	limiter *requestLimiter
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	release, err := t.limiter.acquire(req)
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	res.Body = &releaseOnClose{ReadCloser: res.Body, release: release}
+	return res, nil
+}
+
+// releaseOnClose calls release when the wrapped io.ReadCloser is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	defer r.release()
+	return r.ReadCloser.Close()
+}
+
+// newLimitedHTTPClient returns a copy of hc that limits in-flight requests using l.
+func newLimitedHTTPClient(hc *http.Client, l *requestLimiter) *http.Client {
+	next := hc.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	lc := *hc
+	lc.Transport = &limitedTransport{next: next, limiter: l}
+	return &lc
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_newRequestLimiter(t *testing.T) {
+	if l := newRequestLimiter(0, 0); l != nil {
+		t.Errorf("got limiter %v, want nil", l)
+	}
+	if l := newRequestLimiter(1, 0); l == nil {
+		t.Error("got nil limiter")
+	}
+	if l := newRequestLimiter(0, 1); l == nil {
+		t.Error("got nil limiter")
+	}
+}
+
+func TestRequestLimiterAcquireCanceled(t *testing.T) {
+	l := newRequestLimiter(0, 1)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release, err := l.acquire(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := l.acquire(req.WithContext(ctx)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// Requests to other hosts are not limited.
+	other, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.org", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	releaseOther, err := l.acquire(other)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	releaseOther()
+}
+
+func TestMultistreamDownloaderRequestLimit(t *testing.T) {
+	const src = "123456789012345678901234567890"
+	size := int64(len(src))
+
+	tests := []struct {
+		name               string
+		maxRequests        int
+		maxRequestsPerHost int
+		want               int
+	}{
+		{"MaxRequests", 2, 0, 2},
+		{"MaxRequestsPerHost", 0, 3, 3},
+		{"Both", 3, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var inFlight, maxInFlight int
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				inFlight++
+				if inFlight > maxInFlight {
+					maxInFlight = inFlight
+				}
+				mu.Unlock()
+
+				defer func() {
+					mu.Lock()
+					inFlight--
+					mu.Unlock()
+				}()
+
+				// Give other workers the opportunity to exceed the limit.
+				time.Sleep(5 * time.Millisecond)
+
+				start, end := parseRangeHeader(t, r.Header.Get("Range"))
+
+				w.Header().Set("Content-Length", fmt.Sprintf("%v", end-start+1))
+				w.WriteHeader(http.StatusPartialContent)
+
+				if _, err := io.Copy(w, strings.NewReader(src[start:end+1])); err != nil {
+					t.Errorf("unexpected error writing http response: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{
+				Logger:             testLogger,
+				MaxRequests:        tt.maxRequests,
+				MaxRequestsPerHost: tt.maxRequestsPerHost,
+			})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			dst := &inMemoryBuffer{buf: make([]byte, size)}
+
+			err = c.multipartDownload(context.Background(), &blobURL{u: srv.URL}, nil, dst, size, &Downloader{Concurrency: 10, PartSize: 3}, &NoopProgressBar{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got, want := string(dst.Bytes()), src; got != want {
+				t.Fatalf("unexpected data: got %v, want %v", got, want)
+			}
+
+			if maxInFlight > tt.want {
+				t.Errorf("got %v requests in flight, want at most %v", maxInFlight, tt.want)
+			}
+		})
+	}
+}
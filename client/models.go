@@ -14,7 +14,7 @@ import (
 // LibraryModels lists names of valid models in the database
 var LibraryModels = []string{"Entity", "Collection", "Container", "Image", "Blob"}
 
-// ModelManager - Generic interface for models which must have a bson ObjectID
+// ModelManager - Generic interface for models which must have an ID
 type ModelManager interface {
 	GetID() string
 }
@@ -97,6 +97,7 @@ type Collection struct {
 	Containers  []string `json:"containers"`
 	Size        int64    `json:"size"`
 	Private     bool     `json:"private"`
+	ReadOnly    bool     `json:"readOnly"`
 	// CustomData can hold a user-provided string for integration purposes
 	// not used by the library itself.
 	CustomData string `json:"customData"`
@@ -178,6 +179,8 @@ type Image struct {
 	Architecture *string  `json:"arch,omitempty"`
 	Fingerprints []string `json:"fingerprints,omitempty"`
 	Encrypted    *bool    `json:"encrypted,omitempty"`
+	// Labels are the labels of the image, as recorded in its SIF metadata.
+	Labels map[string]string `json:"labels,omitempty"`
 	// CustomData can hold a user-provided string for integration purposes
 	// not used by the library itself.
 	CustomData string `json:"customData"`
@@ -191,6 +194,9 @@ type Image struct {
 	ContainerDescription string   `json:"containerDescription,omitempty"`
 	ContainerStars       int      `json:"containerStars"`
 	ContainerDownloads   int64    `json:"containerDownloads"`
+	// ContainerPath is the path of the container (ie. "entity/collection/container"), populated
+	// in search results.
+	ContainerPath string `json:"containerPath,omitempty"`
 }
 
 // GetID - Convenience method to get model ID if working with an interface
@@ -198,6 +204,23 @@ func (img Image) GetID() string {
 	return img.ID
 }
 
+// containerPath returns the path of the container holding the image.
+func (img Image) containerPath() string {
+	if img.ContainerPath != "" {
+		return img.ContainerPath
+	}
+	return img.EntityName + "/" + img.CollectionName + "/" + img.ContainerName
+}
+
+// LibraryURIs - library:// URIs to the image, one per tag pointing at the image
+func (img Image) LibraryURIs() []string {
+	uris := make([]string, 0, len(img.Tags))
+	for _, tag := range img.Tags {
+		uris = append(uris, "library://"+img.containerPath()+":"+tag)
+	}
+	return uris
+}
+
 // Blob - Binary data object (e.g. container image file) stored in a Backend
 // Uses object store bucket/key semantics
 type Blob struct {
@@ -215,18 +238,18 @@ func (b Blob) GetID() string {
 	return b.ID
 }
 
-// ImageTag - A single mapping from a string to bson ID. Not stored in the DB
+// ImageTag - A single mapping from a string to an image ID. Not stored in the DB
 // but used by API calls setting tags
 type ImageTag struct {
 	Tag     string
 	ImageID string
 }
 
-// TagMap is a mapping of a string tag, to an ObjectID that refers to an Image
+// TagMap is a mapping of a string tag, to an ID that refers to an Image
 // e.g. { "latest": 507f1f77bcf86cd799439011 }
 type TagMap map[string]string
 
-// ArchImageTag - A simple mapping from a architecture and tag string to bson
+// ArchImageTag - A simple mapping from a architecture and tag string to an image
 // ID. Not stored in the DB but used by API calls setting tags
 type ArchImageTag struct {
 	Arch    string
@@ -242,3 +265,175 @@ type ArchImageTag struct {
 //				"ppc64le":  { "latest": 507f1f77bcf86cd799439012 },
 //			}
 type ArchTagMap map[string]TagMap
+
+// BlockManifest describes the checksums of fixed-size blocks of an image, used to perform delta
+// downloads. Not stored in the DB but returned by API calls.
+type BlockManifest struct {
+	// Size of the image, in bytes.
+	Size int64 `json:"size"`
+	// BlockSize is the size of each block, in bytes. The final block may be shorter.
+	BlockSize int64 `json:"blockSize"`
+	// Algorithm used to compute block checksums (ie. "sha256"). If empty, "sha256" is assumed.
+	Algorithm string `json:"algorithm,omitempty"`
+	// Blocks contains the encoded checksum of each block, in order.
+	Blocks []string `json:"blocks"`
+}
+
+// ImageChecksums describes the checksums of an image, allowing the image to be verified without
+// re-computing checksums against the API. Not stored in the DB but used by API calls.
+type ImageChecksums struct {
+	// Size of the image, in bytes.
+	Size int64 `json:"size"`
+	// Checksums maps a checksum algorithm to the encoded checksum of the image. CRC32C checksums
+	// are base64 encoded; all other checksums are hex encoded.
+	Checksums map[ChecksumAlgorithm]string `json:"checksums"`
+}
+
+// SBOM is a software bill of materials document associated with an image. Not stored in the DB
+// but used by API calls.
+type SBOM struct {
+	// MediaType is the media type of the document (ie. "application/spdx+json").
+	MediaType string `json:"mediaType"`
+	// Content is the document.
+	Content []byte `json:"content"`
+}
+
+// UsageSummary describes the storage consumed by a set of images. Not stored in the DB but
+// returned by API calls.
+type UsageSummary struct {
+	// Size of the images, in bytes.
+	Size int64 `json:"size"`
+	// ImageCount is the number of images.
+	ImageCount int64 `json:"imageCount"`
+	// LastAccessed is the time at which any of the images was last downloaded. Zero if the images
+	// have never been downloaded.
+	LastAccessed time.Time `json:"lastAccessed"`
+}
+
+// ContainerUsage describes the storage consumed by the images of a container. Not stored in the
+// DB but returned by API calls.
+type ContainerUsage struct {
+	UsageSummary
+	// Name of the container.
+	Name string `json:"name"`
+	// Arches maps an architecture to the storage consumed by images of that architecture.
+	Arches map[string]UsageSummary `json:"arches"`
+}
+
+// CollectionUsage describes the storage consumed by the images of a collection. Not stored in the
+// DB but returned by API calls.
+type CollectionUsage struct {
+	UsageSummary
+	// Containers describes the storage consumed by each container within the collection.
+	Containers []ContainerUsage `json:"containers"`
+}
+
+// AccessLevel describes the access granted to a principal by an ACLEntry.
+type AccessLevel string
+
+const (
+	// AccessRead permits pulling images.
+	AccessRead AccessLevel = "read"
+	// AccessWrite permits pushing images, in addition to AccessRead.
+	AccessWrite AccessLevel = "write"
+)
+
+// PrincipalType describes the type of principal to which an ACLEntry applies.
+type PrincipalType string
+
+const (
+	// PrincipalUser is an individual user, identified by username.
+	PrincipalUser PrincipalType = "user"
+	// PrincipalTeam is a team, identified by name.
+	PrincipalTeam PrincipalType = "team"
+)
+
+// ACLEntry grants access to a container or collection to a user or team.
+type ACLEntry struct {
+	PrincipalType PrincipalType `json:"principalType"`
+	Principal     string        `json:"principal"`
+	Access        AccessLevel   `json:"access"`
+}
+
+// AccessToken describes an access token. Not stored in the DB but returned by API calls.
+type AccessToken struct {
+	ID          string    `json:"id"`
+	Description string    `json:"description,omitempty"`
+	Scopes      []string  `json:"scopes,omitempty"`
+	Created     time.Time `json:"created"`
+	Expiry      time.Time `json:"expiry"`
+	// Token is the token value, used to authenticate requests. Only returned when the token is
+	// created.
+	Token string `json:"token,omitempty"`
+}
+
+// AuditEventType describes the operation recorded by an AuditEvent.
+type AuditEventType string
+
+const (
+	// AuditEventPush records the push of an image.
+	AuditEventPush AuditEventType = "push"
+	// AuditEventPull records the pull of an image.
+	AuditEventPull AuditEventType = "pull"
+	// AuditEventDelete records the deletion of an image or tag.
+	AuditEventDelete AuditEventType = "delete"
+	// AuditEventTag records the application of a tag to an image.
+	AuditEventTag AuditEventType = "tag"
+)
+
+// AuditEvent records an operation on an image. Not stored in the DB but returned by API calls.
+type AuditEvent struct {
+	ID   string         `json:"id"`
+	Type AuditEventType `json:"type"`
+	Time time.Time      `json:"time"`
+	// User is the name of the user that performed the operation.
+	User string `json:"user"`
+	// Container is the ref of the container (ie. "entity/collection/container").
+	Container string `json:"container"`
+	ImageID   string `json:"imageID,omitempty"`
+	Arch      string `json:"arch,omitempty"`
+	Tag       string `json:"tag,omitempty"`
+}
+
+// AuditEventPage is a page of audit events.
+type AuditEventPage struct {
+	Events []AuditEvent `json:"events"`
+	// Next is the cursor used to retrieve the next page, or empty if there are no further events.
+	Next string `json:"next,omitempty"`
+}
+
+// WebhookEvent describes a collection event of which a webhook is notified.
+type WebhookEvent string
+
+const (
+	// WebhookEventTag is the application of a tag to an image.
+	WebhookEventTag WebhookEvent = "tag"
+	// WebhookEventImage is the push of a new image.
+	WebhookEventImage WebhookEvent = "image"
+	// WebhookEventDelete is the deletion of an image or tag.
+	WebhookEventDelete WebhookEvent = "delete"
+)
+
+// Webhook describes a webhook notified of collection events. Not stored in the DB but returned by
+// API calls.
+type Webhook struct {
+	ID      string         `json:"id"`
+	URL     string         `json:"url"`
+	Events  []WebhookEvent `json:"events"`
+	Created time.Time      `json:"created"`
+	// Secret is used to sign the payloads delivered to the webhook. Only returned when the webhook
+	// is created.
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookPayload is delivered to a webhook when a collection event occurs.
+type WebhookPayload struct {
+	ID    string       `json:"id"`
+	Event WebhookEvent `json:"event"`
+	Time  time.Time    `json:"time"`
+	// Container is the ref of the container (ie. "entity/collection/container").
+	Container string `json:"container"`
+	ImageID   string `json:"imageID,omitempty"`
+	Arch      string `json:"arch,omitempty"`
+	Tag       string `json:"tag,omitempty"`
+}
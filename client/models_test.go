@@ -0,0 +1,55 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestModelTimestamps(t *testing.T) {
+	const body = `{
+		"createdAt": "2026-01-02T03:04:05Z",
+		"updatedAt": "2026-02-03T04:05:06.5+01:00",
+		"deletedAt": null
+	}`
+
+	wantCreated := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	wantUpdated := time.Date(2026, 2, 3, 3, 5, 6, 500000000, time.UTC)
+
+	tests := []struct {
+		name string
+		m    interface {
+			GetCreated() (string, time.Time)
+			GetUpdated() (string, time.Time)
+			GetDeleted() (string, time.Time)
+		}
+	}{
+		{"Entity", &Entity{}},
+		{"Collection", &Collection{}},
+		{"Container", &Container{}},
+		{"Image", &Image{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(body), tt.m); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if _, got := tt.m.GetCreated(); !got.Equal(wantCreated) {
+				t.Errorf("got created %v, want %v", got, wantCreated)
+			}
+			if _, got := tt.m.GetUpdated(); !got.Equal(wantUpdated) {
+				t.Errorf("got updated %v, want %v", got, wantUpdated)
+			}
+			if _, got := tt.m.GetDeleted(); !got.IsZero() {
+				t.Errorf("got deleted %v, want zero", got)
+			}
+		})
+	}
+}
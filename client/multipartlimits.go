@@ -0,0 +1,102 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+const (
+	// OptionMinimumPartSize is the minimum size, in bytes, of each part (other than the last) of
+	// a multipart upload accepted by the object store used by the backend library server.
+	OptionMinimumPartSize = "minPartSize"
+
+	// OptionMaximumParts is the maximum number of parts of a multipart upload accepted by the
+	// object store used by the backend library server.
+	OptionMaximumParts = "maxParts"
+)
+
+// multipartLimits describes the limits placed on multipart uploads by the backend library server.
+// A zero value indicates the corresponding limit was not advertised.
+type multipartLimits struct {
+	minPartSize int64
+	maxParts    int
+}
+
+// multipartLimitsFromOptions returns the multipartLimits advertised in the options returned by
+// the backend library server when a multipart upload is started. Malformed values are ignored.
+func multipartLimitsFromOptions(opts map[string]string) multipartLimits {
+	var l multipartLimits
+	if n, err := strconv.ParseInt(opts[OptionMinimumPartSize], 10, 64); err == nil && n > 0 {
+		l.minPartSize = n
+	}
+	if n, err := strconv.Atoi(opts[OptionMaximumParts]); err == nil && n > 0 {
+		l.maxParts = n
+	}
+	return l
+}
+
+// check returns an error if the plan of the multipart upload of fileSize bytes returned by the
+// backend library server is inconsistent with l, or cannot upload the entire file.
+func (l multipartLimits) check(mu MultipartUpload, fileSize int64) error {
+	if mu.TotalParts < 1 || mu.PartSize < 1 {
+		return fmt.Errorf("invalid multipart upload: %d parts of %d bytes", mu.TotalParts, mu.PartSize)
+	}
+	if l.maxParts > 0 && mu.TotalParts > l.maxParts {
+		return fmt.Errorf("invalid multipart upload: %d parts exceeds maximum of %d", mu.TotalParts, l.maxParts)
+	}
+	if l.minPartSize > 0 && mu.TotalParts > 1 && mu.PartSize < l.minPartSize {
+		return fmt.Errorf("invalid multipart upload: part size %d less than minimum of %d", mu.PartSize, l.minPartSize)
+	}
+	if int64(mu.TotalParts)*mu.PartSize < fileSize {
+		return fmt.Errorf("invalid multipart upload: %d parts of %d bytes cannot hold %d bytes", mu.TotalParts, mu.PartSize, fileSize)
+	}
+	return nil
+}
+
+// multipartThresholds records the minimum part size advertised by each backend library server, so
+// that the decision to attempt a multipart upload tracks the object store in use, rather than
+// minimumPartSize. Methods may be called concurrently, and a nil *multipartThresholds always
+// returns minimumPartSize.
+type multipartThresholds struct {
+	mu      sync.Mutex
+	servers map[string]int64 // base URL -> minimum part size
+}
+
+// newMultipartThresholds returns a multipartThresholds with no servers recorded.
+func newMultipartThresholds() *multipartThresholds {
+	return &multipartThresholds{servers: make(map[string]int64)}
+}
+
+// get returns the size above which a multipart upload is attempted against server.
+func (mt *multipartThresholds) get(server string) int64 {
+	if mt == nil {
+		return minimumPartSize
+	}
+
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+
+	if n, ok := mt.servers[server]; ok {
+		return n
+	}
+	return minimumPartSize
+}
+
+// put records the limits advertised by server. If a minimum part size is not advertised, the
+// threshold of server is unchanged.
+func (mt *multipartThresholds) put(server string, l multipartLimits) {
+	if mt == nil || l.minPartSize == 0 {
+		return
+	}
+
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+
+	mt.servers[server] = l.minPartSize
+}
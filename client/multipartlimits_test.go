@@ -0,0 +1,158 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func Test_multipartLimitsFromOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts map[string]string
+		want multipartLimits
+	}{
+		{"Nil", nil, multipartLimits{}},
+		{"MinPartSize", map[string]string{OptionMinimumPartSize: "5242880"}, multipartLimits{minPartSize: 5242880}},
+		{"MaxParts", map[string]string{OptionMaximumParts: "10000"}, multipartLimits{maxParts: 10000}},
+		{"Both", map[string]string{OptionMinimumPartSize: "1024", OptionMaximumParts: "2"}, multipartLimits{minPartSize: 1024, maxParts: 2}},
+		{"Malformed", map[string]string{OptionMinimumPartSize: "5MiB", OptionMaximumParts: "many"}, multipartLimits{}},
+		{"Negative", map[string]string{OptionMinimumPartSize: "-1", OptionMaximumParts: "-1"}, multipartLimits{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := multipartLimitsFromOptions(tt.opts); got != tt.want {
+				t.Errorf("got limits %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_multipartLimitsCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		limits   multipartLimits
+		upload   MultipartUpload
+		fileSize int64
+		wantErr  bool
+	}{
+		{"NoLimits", multipartLimits{}, MultipartUpload{TotalParts: 3, PartSize: 10}, 25, false},
+		{"NoParts", multipartLimits{}, MultipartUpload{TotalParts: 0, PartSize: 10}, 25, true},
+		{"NoPartSize", multipartLimits{}, MultipartUpload{TotalParts: 3, PartSize: 0}, 25, true},
+		{"TooSmall", multipartLimits{}, MultipartUpload{TotalParts: 2, PartSize: 10}, 25, true},
+		{"MaxParts", multipartLimits{maxParts: 3}, MultipartUpload{TotalParts: 3, PartSize: 10}, 25, false},
+		{"MaxPartsExceeded", multipartLimits{maxParts: 2}, MultipartUpload{TotalParts: 3, PartSize: 10}, 25, true},
+		{"MinPartSize", multipartLimits{minPartSize: 10}, MultipartUpload{TotalParts: 3, PartSize: 10}, 25, false},
+		{"MinPartSizeNotMet", multipartLimits{minPartSize: 20}, MultipartUpload{TotalParts: 3, PartSize: 10}, 25, true},
+		{"MinPartSizeSinglePart", multipartLimits{minPartSize: 20}, MultipartUpload{TotalParts: 1, PartSize: 10}, 10, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.limits.check(tt.upload, tt.fileSize); (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_multipartThresholds(t *testing.T) {
+	var nilThresholds *multipartThresholds
+	nilThresholds.put("https://a.example/", multipartLimits{minPartSize: 1024})
+	if got, want := nilThresholds.get("https://a.example/"), int64(minimumPartSize); got != want {
+		t.Errorf("got threshold %v, want %v", got, want)
+	}
+
+	mt := newMultipartThresholds()
+	mt.put("https://a.example/", multipartLimits{minPartSize: 1024})
+	mt.put("https://b.example/", multipartLimits{maxParts: 2})
+
+	tests := []struct {
+		server string
+		want   int64
+	}{
+		{"https://a.example/", 1024},
+		{"https://b.example/", minimumPartSize},
+		{"https://c.example/", minimumPartSize},
+	}
+
+	for _, tt := range tests {
+		if got := mt.get(tt.server); got != tt.want {
+			t.Errorf("%v: got threshold %v, want %v", tt.server, got, tt.want)
+		}
+	}
+}
+
+func Test_postFileV2MultipartLimits(t *testing.T) {
+	const imageID = "5cb9c34d7d960d82f5f5bc55"
+
+	var starts, aborts int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/imagefile/" + imageID + "/_multipart":
+			starts++
+			if starts > 1 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			// Advertise limits that the returned plan does not satisfy.
+			response := MultipartUpload{
+				UploadID:   "id",
+				TotalParts: 3,
+				PartSize:   minimumPartSize,
+				Options:    map[string]string{OptionMinimumPartSize: "1024", OptionMaximumParts: "2"},
+			}
+			if err := jsonresp.WriteResponse(w, &response, http.StatusOK); err != nil {
+				t.Errorf("error writing JSON response: %v", err)
+			}
+		case "/v2/imagefile/" + imageID + "/_multipart_abort":
+			aborts++
+		case "/v2/imagefile/" + imageID:
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	upload := func(size int64) error {
+		r := io.NewSectionReader(zeroReaderAt{}, 0, size)
+		_, err := c.postFileV2(context.Background(), r, size, imageID, &defaultUploadCallback{r: r}, map[string]string{}, false)
+		return err
+	}
+
+	// The plan returned by the server exceeds the maximum number of parts, so the upload is
+	// aborted.
+	if err := upload(minimumPartSize + 1); err == nil {
+		t.Fatal("unexpected success")
+	}
+	if got, want := aborts, 1; got != want {
+		t.Errorf("got %v aborts, want %v", got, want)
+	}
+
+	// The minimum part size advertised by the server is used to decide whether to attempt a
+	// multipart upload.
+	if err := upload(2048); err == nil {
+		t.Fatal("unexpected success")
+	}
+	if got, want := starts, 2; got != want {
+		t.Errorf("got %v multipart starts, want %v", got, want)
+	}
+}
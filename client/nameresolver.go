@@ -0,0 +1,72 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// NameResolver maps short image names (ie. "alpine") to fully-qualified names (ie.
+// "library/default/alpine"). Names that are already fully-qualified, or are not known to the
+// resolver, are returned unchanged.
+type NameResolver interface {
+	ResolveName(ctx context.Context, name string) (string, error)
+}
+
+// StaticNameResolver is a NameResolver that maps names using a fixed table, keyed by short name.
+// It allows names to be resolved without consulting the library server, as required by
+// disconnected deployments.
+type StaticNameResolver map[string]string
+
+// ResolveName implements NameResolver.
+func (m StaticNameResolver) ResolveName(_ context.Context, name string) (string, error) {
+	if v, ok := m[name]; ok {
+		return v, nil
+	}
+	return name, nil
+}
+
+// ResolveName returns the fully-qualified name of the image name (ie. "alpine", or
+// "library://alpine"), without a tag. If Config.NameResolver is supplied, it is used to resolve
+// name. Otherwise, the name is resolved by the library server, which requires direct OCI registry
+// access; if the library server does not support direct OCI registry access, an error wrapping
+// ErrNotFound is returned.
+func (c *Client) ResolveName(ctx context.Context, name string) (string, error) {
+	name = strings.TrimPrefix(name, "library://")
+	if name == "" || strings.Contains(name, ":") {
+		return "", fmt.Errorf("invalid name %q", name)
+	}
+
+	if c.nameResolver != nil {
+		return c.resolveName(ctx, name)
+	}
+
+	_, _, resolved, err := c.ociRegistryAuth(ctx, name, []accessType{accessTypePull})
+	if err != nil {
+		return "", fmt.Errorf("error resolving name %q: %w", name, err)
+	}
+	return resolved, nil
+}
+
+// resolveName resolves name using the NameResolver of the client, if supplied. Otherwise, name is
+// returned unchanged.
+func (c *Client) resolveName(ctx context.Context, name string) (string, error) {
+	if c.nameResolver == nil {
+		return name, nil
+	}
+
+	resolved, err := c.nameResolver.ResolveName(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("error resolving name %q: %w", name, err)
+	}
+
+	if resolved != name {
+		c.logger.Logf("Name \"%v\" resolved to \"%v\"", name, resolved)
+	}
+	return resolved, nil
+}
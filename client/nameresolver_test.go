@@ -0,0 +1,162 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStaticNameResolver(t *testing.T) {
+	r := StaticNameResolver{"alpine": "library/default/alpine"}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"alpine", "library/default/alpine"},
+		{"busybox", "busybox"},
+		{"entity/collection/alpine", "entity/collection/alpine"},
+	}
+
+	for _, tt := range tests {
+		got, err := r.ResolveName(context.Background(), tt.name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("%v: got name %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResolveName(t *testing.T) {
+	tests := []struct {
+		name         string
+		resolver     NameResolver
+		redirectCode int
+		ref          string
+		want         string
+		wantErr      error
+		wantRequest  bool
+	}{
+		{"Server", nil, http.StatusOK, "alpine", "library/default/alpine", nil, true},
+		{"ServerScheme", nil, http.StatusOK, "library://alpine", "library/default/alpine", nil, true},
+		{"ServerNotSupported", nil, http.StatusNotFound, "alpine", "", ErrNotFound, true},
+		{"Static", StaticNameResolver{"alpine": "entity/collection/alpine"}, http.StatusOK, "alpine", "entity/collection/alpine", nil, false},
+		{"StaticUnknown", StaticNameResolver{}, http.StatusOK, "alpine", "alpine", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested bool
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/oci-redirect" {
+					t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+				}
+				requested = true
+
+				if r.URL.Query().Get("mapped") != "1" {
+					t.Errorf("name mapping not requested")
+				}
+
+				if tt.redirectCode != http.StatusOK {
+					w.WriteHeader(tt.redirectCode)
+					return
+				}
+				if err := json.NewEncoder(w).Encode(map[string]string{"url": "https://registry", "name": "library/default/" + r.URL.Query().Get("namespace")}); err != nil {
+					t.Errorf("error encoding response: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, NameResolver: tt.resolver, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			got, err := c.ResolveName(context.Background(), tt.ref)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got name %v, want %v", got, tt.want)
+			}
+			if requested != tt.wantRequest {
+				t.Errorf("got request %v, want %v", requested, tt.wantRequest)
+			}
+		})
+	}
+}
+
+func TestResolveNameInvalid(t *testing.T) {
+	c, err := NewClient(&Config{BaseURL: "https://library.example.com", Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	for _, ref := range []string{"", "library://", "alpine:latest"} {
+		if _, err := c.ResolveName(context.Background(), ref); err == nil {
+			t.Errorf("%q: unexpected success", ref)
+		}
+	}
+}
+
+func Test_newOCIRegistryNameResolver(t *testing.T) {
+	errResolve := errors.New("resolve error")
+
+	tests := []struct {
+		name          string
+		resolver      NameResolver
+		wantNamespace string
+		wantErr       error
+	}{
+		{"Static", StaticNameResolver{"alpine": "entity/collection/alpine"}, "entity/collection/alpine", nil},
+		{"Error", nameResolverFunc(func(context.Context, string) (string, error) { return "", errResolve }), "", errResolve},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var namespace string
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				namespace = r.URL.Query().Get("namespace")
+				if err := json.NewEncoder(w).Encode(map[string]string{"url": "https://registry"}); err != nil {
+					t.Errorf("error encoding response: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, NameResolver: tt.resolver, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			_, _, name, err := c.newOCIRegistry(context.Background(), "alpine", []accessType{accessTypePull})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if got, want := namespace, tt.wantNamespace; got != want {
+				t.Errorf("got namespace %q, want %q", got, want)
+			}
+			if err == nil && name != tt.wantNamespace {
+				t.Errorf("got name %q, want %q", name, tt.wantNamespace)
+			}
+		})
+	}
+}
+
+// nameResolverFunc is an adapter to allow the use of an ordinary function as a NameResolver.
+type nameResolverFunc func(ctx context.Context, name string) (string, error)
+
+func (f nameResolverFunc) ResolveName(ctx context.Context, name string) (string, error) {
+	return f(ctx, name)
+}
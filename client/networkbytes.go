@@ -0,0 +1,59 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import "io"
+
+// NetworkProgressBar is a ProgressBar that also reports the bytes received over the network.
+// When image content is transferred compressed, the bytes received over the network differ from
+// the (decompressed) bytes of image data reported by IncrBy and ProxyReader, so implementations
+// may use the network bytes to display a truthful throughput. Content that is not received over
+// the network (such as content copied from a shared download) is not reported.
+type NetworkProgressBar interface {
+	ProgressBar
+
+	// IncrNetworkBy increments the number of bytes received over the network.
+	IncrNetworkBy(int)
+}
+
+// incrNetwork reports n bytes received over the network to pb, if pb is a NetworkProgressBar.
+func incrNetwork(pb ProgressBar, n int64) {
+	if npb, ok := pb.(NetworkProgressBar); ok && n > 0 {
+		npb.IncrNetworkBy(int(n))
+	}
+}
+
+// networkCounter counts the bytes read from r, which is read directly from the network. If pb is
+// non-nil, the bytes read are reported to it.
+type networkCounter struct {
+	r  io.Reader
+	n  int64
+	pb ProgressBar
+}
+
+func (nc *networkCounter) Read(p []byte) (int, error) {
+	n, err := nc.r.Read(p)
+	nc.n += int64(n)
+	if nc.pb != nil {
+		incrNetwork(nc.pb, int64(n))
+	}
+	return n, err
+}
+
+// countNetwork returns a reader that reads content from r, and a counter of the bytes of r
+// received over the network, which are reported to pb. If r reads decoded content from a
+// response body (see decodeResponse), the encoded bytes of the response body are counted.
+func countNetwork(r io.Reader, pb ProgressBar) (io.Reader, *networkCounter) {
+	if db, ok := r.(*decodedBody); ok {
+		// The decoder may already have read from the body (ie. to read a header).
+		incrNetwork(pb, db.wire.n)
+		db.wire.pb = pb
+		return r, db.wire
+	}
+
+	nc := &networkCounter{r: r, pb: pb}
+	return nc, nc
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// OptionObjectStore identifies the type of object store used by backend library server. If
+	// not present, an S3 compatible object store is assumed.
+	OptionObjectStore = "objectStore"
+
+	// ObjectStoreS3 identifies an S3 compatible object store.
+	ObjectStoreS3 = "s3"
+	// ObjectStoreAzure identifies an Azure Blob Storage object store, accessed using SAS URLs.
+	ObjectStoreAzure = "azure"
+	// ObjectStoreGCS identifies a Google Cloud Storage object store, accessed using V4 signed URLs.
+	ObjectStoreGCS = "gcs"
+)
+
+// objectStore adapts upload requests made against presigned (or SAS) URLs to the requirements
+// of the object store used by the backend library server.
+type objectStore struct {
+	// kind is one of ObjectStoreS3, ObjectStoreAzure or ObjectStoreGCS.
+	kind string
+
+	// s3Compliant indicates a 100% S3 compatible object store, in which case the
+	// "X-Amz-Content-Sha256" header is included in requests.
+	s3Compliant bool
+}
+
+// objectStoreFromOptions returns the objectStore described by the options returned by the
+// backend library server when a multipart upload is started.
+func objectStoreFromOptions(opts map[string]string) objectStore {
+	switch kind := opts[OptionObjectStore]; kind {
+	case ObjectStoreAzure, ObjectStoreGCS:
+		return objectStore{kind: kind}
+	}
+
+	// Enable S3 compliance mode by default
+	val := opts[OptionS3Compliant]
+	return objectStore{kind: ObjectStoreS3, s3Compliant: val == "" || val == "true"}
+}
+
+// objectStoreFromURL returns the objectStore inferred from the query parameters of presigned URL
+// u. This is used when the backend library server does not supply upload options.
+func objectStoreFromURL(u *url.URL) objectStore {
+	q := u.Query()
+
+	switch {
+	case q.Has("X-Goog-Signature"):
+		return objectStore{kind: ObjectStoreGCS}
+	case q.Has("sig") && q.Has("sv"):
+		return objectStore{kind: ObjectStoreAzure}
+	}
+
+	// parse presigned URL to determine if we need to send sha256 checksum
+	return objectStore{kind: ObjectStoreS3, s3Compliant: remoteSHA256ChecksumSupport(u)}
+}
+
+// requiresSHA256 returns true if the SHA256 checksum of the payload must be supplied as part of
+// the upload request.
+func (s objectStore) requiresSHA256() bool {
+	return s.kind == ObjectStoreS3 && s.s3Compliant
+}
+
+// modifyRequest sets object store specific headers on req. sums contains checksums of the
+// payload; the SHA256 checksum is ignored unless requiresSHA256 returns true. multipart indicates
+// req uploads a single part of a multipart upload.
+func (s objectStore) modifyRequest(req *http.Request, sums checksums, multipart bool) {
+	if !multipart {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+
+	switch s.kind {
+	case ObjectStoreS3:
+		if v := sums[ChecksumSHA256]; s.s3Compliant && v != "" {
+			req.Header.Set("x-amz-content-sha256", v)
+		}
+		if v := sums[ChecksumCRC32C]; v != "" {
+			req.Header.Set("x-amz-checksum-crc32c", v)
+		}
+	case ObjectStoreAzure:
+		// "Put Blob" requires the blob type, whereas "Put Block" (used for parts) does not.
+		if !multipart {
+			req.Header.Set("x-ms-blob-type", "BlockBlob")
+		}
+	}
+}
+
+// partToken returns the token identifying an uploaded part, to be included in the multipart
+// upload completion request.
+func (s objectStore) partToken(res *http.Response) string {
+	// Azure "Put Block" responses do not include an ETag; the block is identified by the block ID
+	// embedded in the SAS URL.
+	if s.kind == ObjectStoreAzure && res.Request != nil {
+		if id := res.Request.URL.Query().Get("blockid"); id != "" {
+			return id
+		}
+	}
+	return res.Header.Get("ETag")
+}
+
+// maxObjectStoreErrorSize is the maximum size of an object store error response body that is read.
+const maxObjectStoreErrorSize = 64 * 1024
+
+// ObjectStoreError describes an error response returned by an object store. Code and Message are
+// populated from the XML error document returned by S3 compatible, Azure Blob Storage and Google
+// Cloud Storage object stores, if present.
+type ObjectStoreError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Code is the object store specific error code (ie. "SignatureDoesNotMatch").
+	Code string
+	// Message is the human readable error message.
+	Message string
+}
+
+func (e *ObjectStoreError) Error() string {
+	switch {
+	case e.Code != "" && e.Message != "":
+		return fmt.Sprintf("object store returned an error: %d: %v: %v", e.StatusCode, e.Code, e.Message)
+	case e.Code != "":
+		return fmt.Sprintf("object store returned an error: %d: %v", e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("object store returned an error: %d", e.StatusCode)
+}
+
+// expired returns true if e reports that the presigned URL used to make the request has expired,
+// rather than that the request is otherwise not authorized.
+func (e *ObjectStoreError) expired() bool {
+	if e.StatusCode != http.StatusForbidden {
+		return false
+	}
+	if e.Code == "ExpiredToken" {
+		return true
+	}
+
+	// S3 and GCS report "Request has expired", and Azure reports that the signature is "not valid
+	// in the specified time frame".
+	msg := strings.ToLower(e.Message)
+	return strings.Contains(msg, "request has expired") || strings.Contains(msg, "not valid in the specified time frame")
+}
+
+// objectStoreErrorFromResponse returns an ObjectStoreError describing error response res. The
+// response body is consumed.
+func objectStoreErrorFromResponse(res *http.Response) *ObjectStoreError {
+	e := &ObjectStoreError{StatusCode: res.StatusCode}
+
+	var doc struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if err := xml.NewDecoder(io.LimitReader(res.Body, maxObjectStoreErrorSize)).Decode(&doc); err == nil {
+		e.Code = doc.Code
+		e.Message = doc.Message
+	}
+	return e
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func Test_objectStoreFromOptions(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           map[string]string
+		wantKind       string
+		requiresSHA256 bool
+	}{
+		{"Default", nil, ObjectStoreS3, true},
+		{"S3Compliant", map[string]string{OptionS3Compliant: "true"}, ObjectStoreS3, true},
+		{"S3NotCompliant", map[string]string{OptionS3Compliant: "false"}, ObjectStoreS3, false},
+		{"Azure", map[string]string{OptionObjectStore: ObjectStoreAzure}, ObjectStoreAzure, false},
+		{"GCS", map[string]string{OptionObjectStore: ObjectStoreGCS}, ObjectStoreGCS, false},
+		{"Unknown", map[string]string{OptionObjectStore: "other"}, ObjectStoreS3, true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s := objectStoreFromOptions(tt.opts)
+
+			if got, want := s.kind, tt.wantKind; got != want {
+				t.Errorf("got kind %v, want %v", got, want)
+			}
+			if got, want := s.requiresSHA256(), tt.requiresSHA256; got != want {
+				t.Errorf("got requiresSHA256 %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_objectStoreFromURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		rawURL   string
+		wantKind string
+	}{
+		{"S3", "https://bucket.s3.amazonaws.com/key?X-Amz-SignedHeaders=host%3Bx-amz-content-sha256", ObjectStoreS3},
+		{"Azure", "https://account.blob.core.windows.net/container/blob?sv=2021-08-06&sig=abc", ObjectStoreAzure},
+		{"GCS", "https://storage.googleapis.com/bucket/key?X-Goog-Signature=abc", ObjectStoreGCS},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.rawURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := objectStoreFromURL(u).kind, tt.wantKind; got != want {
+				t.Errorf("got kind %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_objectStoreModifyRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		store      objectStore
+		multipart  bool
+		wantHeader http.Header
+	}{
+		{
+			name:      "S3Part",
+			store:     objectStore{kind: ObjectStoreS3, s3Compliant: true},
+			multipart: true,
+			wantHeader: http.Header{
+				"X-Amz-Content-Sha256": {"sha256"},
+			},
+		},
+		{
+			name:  "S3NotCompliant",
+			store: objectStore{kind: ObjectStoreS3},
+			wantHeader: http.Header{
+				"Content-Type": {"application/octet-stream"},
+			},
+		},
+		{
+			name:  "AzureBlob",
+			store: objectStore{kind: ObjectStoreAzure},
+			wantHeader: http.Header{
+				"Content-Type":   {"application/octet-stream"},
+				"X-Ms-Blob-Type": {"BlockBlob"},
+			},
+		},
+		{
+			name:       "AzureBlock",
+			store:      objectStore{kind: ObjectStoreAzure},
+			multipart:  true,
+			wantHeader: http.Header{},
+		},
+		{
+			name:       "GCSPart",
+			store:      objectStore{kind: ObjectStoreGCS},
+			multipart:  true,
+			wantHeader: http.Header{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPut, "http://127.0.0.1/", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tt.store.modifyRequest(req, checksums{ChecksumSHA256: "sha256"}, tt.multipart)
+
+			if got, want := len(req.Header), len(tt.wantHeader); got != want {
+				t.Fatalf("got %v headers, want %v", got, want)
+			}
+			for k := range tt.wantHeader {
+				if got, want := req.Header.Get(k), tt.wantHeader.Get(k); got != want {
+					t.Errorf("got header %v value %v, want %v", k, got, want)
+				}
+			}
+		})
+	}
+}
+
+func Test_objectStorePartToken(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPut, "http://127.0.0.1/blob?comp=block&blockid=YmxvY2s%3D", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := &http.Response{
+		Header:  http.Header{"Etag": {"etag"}},
+		Request: req,
+	}
+
+	if got, want := (objectStore{kind: ObjectStoreAzure}).partToken(res), "YmxvY2s="; got != want {
+		t.Errorf("got Azure part token %v, want %v", got, want)
+	}
+	if got, want := (objectStore{kind: ObjectStoreS3}).partToken(res), "etag"; got != want {
+		t.Errorf("got S3 part token %v, want %v", got, want)
+	}
+}
+
+func Test_objectStoreErrorFromResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		want        ObjectStoreError
+		wantMessage string
+	}{
+		{
+			name:        "S3",
+			body:        `<?xml version="1.0" encoding="UTF-8"?><Error><Code>SignatureDoesNotMatch</Code><Message>The request signature we calculated does not match the signature you provided.</Message><RequestId>4442587FB7D0A2F9</RequestId></Error>`,
+			want:        ObjectStoreError{StatusCode: http.StatusForbidden, Code: "SignatureDoesNotMatch", Message: "The request signature we calculated does not match the signature you provided."},
+			wantMessage: "object store returned an error: 403: SignatureDoesNotMatch: The request signature we calculated does not match the signature you provided.",
+		},
+		{
+			name:        "Azure",
+			body:        "\ufeff<?xml version=\"1.0\" encoding=\"utf-8\"?><Error><Code>AuthenticationFailed</Code><Message>Signature did not match.</Message></Error>",
+			want:        ObjectStoreError{StatusCode: http.StatusForbidden, Code: "AuthenticationFailed", Message: "Signature did not match."},
+			wantMessage: "object store returned an error: 403: AuthenticationFailed: Signature did not match.",
+		},
+		{
+			name:        "CodeOnly",
+			body:        `<Error><Code>EntityTooSmall</Code></Error>`,
+			want:        ObjectStoreError{StatusCode: http.StatusForbidden, Code: "EntityTooSmall"},
+			wantMessage: "object store returned an error: 403: EntityTooSmall",
+		},
+		{
+			name:        "NotXML",
+			body:        "Forbidden",
+			want:        ObjectStoreError{StatusCode: http.StatusForbidden},
+			wantMessage: "object store returned an error: 403",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &http.Response{StatusCode: http.StatusForbidden, Body: io.NopCloser(strings.NewReader(tt.body))}
+
+			got := objectStoreErrorFromResponse(res)
+			if *got != tt.want {
+				t.Errorf("got error %+v, want %+v", *got, tt.want)
+			}
+			if got, want := got.Error(), tt.wantMessage; got != want {
+				t.Errorf("got message %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestObjectStoreErrorExpired(t *testing.T) {
+	tests := []struct {
+		name string
+		err  ObjectStoreError
+		want bool
+	}{
+		{"S3", ObjectStoreError{StatusCode: http.StatusForbidden, Code: "AccessDenied", Message: "Request has expired"}, true},
+		{"ExpiredToken", ObjectStoreError{StatusCode: http.StatusForbidden, Code: "ExpiredToken"}, true},
+		{"Azure", ObjectStoreError{StatusCode: http.StatusForbidden, Code: "AuthenticationFailed", Message: "Signature not valid in the specified time frame: Start [...] - Expiry [...]"}, true},
+		{"SignatureMismatch", ObjectStoreError{StatusCode: http.StatusForbidden, Code: "SignatureDoesNotMatch"}, false},
+		{"NoBody", ObjectStoreError{StatusCode: http.StatusForbidden}, false},
+		{"NotForbidden", ObjectStoreError{StatusCode: http.StatusBadRequest, Code: "ExpiredToken"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := tt.err.expired(), tt.want; got != want {
+				t.Errorf("got expired %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -12,10 +12,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-log/log"
@@ -23,6 +25,7 @@ import (
 	"github.com/opencontainers/image-spec/specs-go"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/sylabs/sif/v2/pkg/sif"
+	"golang.org/x/sync/singleflight"
 )
 
 const mediaTypeSIFLayer = "application/vnd.sylabs.sif.layer.v1.sif"
@@ -35,7 +38,14 @@ const mediaTypeSIFLayer = "application/vnd.sylabs.sif.layer.v1.sif"
 // The mapped name can be the same value as 'name' or mapped to a fully-qualified name
 // (ie. from "alpine" to "library/default/alpine") if supported by cloud library server.
 // It will never be an empty string ("")
-func (c *Client) ociRegistryAuth(ctx context.Context, name string, accessTypes []accessType) (*url.URL, *bearerTokenCredentials, string, error) {
+//
+// The token requested additionally grants the access specified by others, so that operations
+// spanning namespaces (ie. pulling from one repository, and pushing to another) are authorized by
+// a single token. The namespaces of others are not mapped.
+func (c *Client) ociRegistryAuth(ctx context.Context, name string, accessTypes []accessType, others ...accessOptions) (*url.URL, *bearerTokenCredentials, string, error) {
+	ctx, cancel := c.metadataContext(ctx)
+	defer cancel()
+
 	// Build raw query string to get token for specified namespace and access
 	v := url.Values{}
 	v.Set("namespace", name)
@@ -51,6 +61,12 @@ func (c *Client) ociRegistryAuth(ctx context.Context, name string, accessTypes [
 
 	v.Set("accessTypes", strings.Join(ats, ","))
 
+	scopes := []string{accessOptions{namespace: name, accessTypes: accessTypes}.scope()}
+	for _, o := range others {
+		v.Add("scope", o.scope())
+		scopes = append(scopes, o.scope())
+	}
+
 	req, err := c.newRequest(ctx, http.MethodGet, "v1/oci-redirect", v.Encode(), nil)
 	if err != nil {
 		return nil, nil, "", err
@@ -60,14 +76,19 @@ func (c *Client) ociRegistryAuth(ctx context.Context, name string, accessTypes [
 		req.Header.Set("User-Agent", c.userAgent)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, nil, "", fmt.Errorf("error determining direct OCI registry access: %w", err)
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil, "", fmt.Errorf("error determining direct OCI registry access: %w", ErrNotFound)
+	}
 	if res.StatusCode != http.StatusOK {
-		return nil, nil, "", fmt.Errorf("error determining direct OCI registry access: %w", err)
+		err := responseError(res, "error determining direct OCI registry access")
+		c.emitAuthEvent(tokenEvent(AuthEventRegistryToken, c.baseURL.Host, scopes, "", err))
+		return nil, nil, "", err
 	}
 
 	type ociDownloadRedirectResponse struct {
@@ -90,20 +111,47 @@ func (c *Client) ociRegistryAuth(ctx context.Context, name string, accessTypes [
 	if err != nil {
 		return nil, nil, "", fmt.Errorf("malformed OCI registry URI %v: %v", ociArtifactSpec.RegistryURI, err)
 	}
+
+	err = c.registryHosts.check(endpoint)
+	if err == nil {
+		err = c.checkInsecure(endpoint)
+	}
+	if err != nil {
+		c.emitAuthEvent(AuthEvent{Kind: AuthEventRegistryToken, Host: endpoint.Host, Scopes: scopes, Err: err})
+		return nil, nil, "", err
+	}
+
+	if ociArtifactSpec.Token != "" {
+		c.emitAuthEvent(tokenEvent(AuthEventRegistryToken, endpoint.Host, scopes, ociArtifactSpec.Token, nil))
+	}
 	return endpoint, &bearerTokenCredentials{authToken: ociArtifactSpec.Token}, name, nil
 }
 
 const (
 	mediaTypeSIFConfig = "application/vnd.sylabs.sif.config.v1+json"
+
+	// artifactTypeSIF is the artifact type of a SIF image. Prior to OCI 1.1, the artifact type is
+	// conveyed by the config media type.
+	artifactTypeSIF = mediaTypeSIFConfig
 )
 
+// manifestArtifactType returns the artifact type of m. If the "artifactType" field is not set, the
+// config media type is returned, as described by the OCI image specification.
+func manifestArtifactType(m v1.Manifest) string {
+	if m.ArtifactType != "" {
+		return m.ArtifactType
+	}
+	return m.Config.MediaType
+}
+
 type imageConfig struct {
-	Architecture string        `json:"architecture"`
-	OS           string        `json:"os"`
-	RootFS       digest.Digest `json:"rootfs"`
-	Description  string        `json:"description,omitempty"`
-	Signed       bool          `json:"signed"`
-	Encrypted    bool          `json:"encrypted"`
+	Architecture string            `json:"architecture"`
+	OS           string            `json:"os"`
+	RootFS       digest.Digest     `json:"rootfs"`
+	Description  string            `json:"description,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Signed       bool              `json:"signed"`
+	Encrypted    bool              `json:"encrypted"`
 }
 
 type credentials interface {
@@ -131,23 +179,77 @@ func (c bearerTokenCredentials) ModifyRequest(r *http.Request, _ ...modifyReques
 	return nil
 }
 
+// RegistryCredentials are static credentials used to access an OCI registry directly. If Token
+// is set, it is used as a bearer token; otherwise, Username and Password are used for HTTP basic
+// authentication.
+type RegistryCredentials struct {
+	Username string
+	Password string
+	Token    string
+}
+
+// credentials returns credentials that apply rc to a request.
+func (rc RegistryCredentials) credentials() credentials {
+	if rc.Token != "" {
+		return &bearerTokenCredentials{authToken: rc.Token}
+	}
+	return &basicCredentials{username: rc.Username, password: rc.Password}
+}
+
 type accessType string
 
 const (
-	accessTypePull accessType = "pull"
-	accessTypePush accessType = "push"
+	accessTypePull   accessType = "pull"
+	accessTypePush   accessType = "push"
+	accessTypeDelete accessType = "delete"
 )
 
+// isPullOnly returns true if accessTypes only grants pull access.
+func isPullOnly(accessTypes []accessType) bool {
+	for _, at := range accessTypes {
+		if at != accessTypePull {
+			return false
+		}
+	}
+	return true
+}
+
 type accessOptions struct {
 	namespace   string
 	accessTypes []accessType
 }
 
+// scope returns the token scope granting the access specified by o (ie.
+// "repository:entity/collection/container:pull,push").
+func (o accessOptions) scope() string {
+	ats := make([]string, 0, len(o.accessTypes))
+	for _, at := range o.accessTypes {
+		ats = append(ats, string(at))
+	}
+	return fmt.Sprintf("repository:%v:%v", o.namespace, strings.Join(ats, ","))
+}
+
+// isPullOnlyScopes returns true if each of scopes only grants pull access.
+func isPullOnlyScopes(scopes []accessOptions) bool {
+	for _, o := range scopes {
+		if !isPullOnly(o.accessTypes) {
+			return false
+		}
+	}
+	return true
+}
+
 type ociRegistry struct {
-	baseURL    *url.URL
-	httpClient *http.Client
-	userAgent  string
-	logger     log.Logger
+	baseURL            *url.URL
+	httpClient         *http.Client
+	userAgent          string
+	logger             log.Logger
+	lenientContentType bool  // sniff manifest media type if Content-Type does not match
+	maxResponseSize    int64 // limit on size of tag list, catalog and referrers responses (if positive)
+	inflightRequests   *singleflight.Group
+	metadataTimeout    time.Duration   // limit on manifest and image config requests (if positive)
+	additionalScopes   []accessOptions // access granted in addition to that of each request
+	authEventHandler   AuthEventHandler
 }
 
 var errArchNotSpecified = errors.New("architecture not specified")
@@ -193,33 +295,41 @@ func (r *ociRegistry) getImageManifest(ctx context.Context, creds credentials, n
 	return r.downloadV1Manifest(ctx, creds, name, tag)
 }
 
-func (r *ociRegistry) getImageDetails(ctx context.Context, creds credentials, name, tag, arch string) (v1.Descriptor, error) {
+// getImageDetails returns the descriptor of the image blob associated with name/tag and arch, and
+// the image config. If the image has no SIF image config, a nil config is returned.
+func (r *ociRegistry) getImageDetails(ctx context.Context, creds credentials, name, tag, arch string) (v1.Descriptor, *imageConfig, error) {
 	_, m, err := r.getImageManifest(ctx, creds, name, tag, arch)
 	if err != nil {
-		return v1.Descriptor{}, err
+		return v1.Descriptor{}, nil, err
 	}
 
-	if got, want := m.Config.MediaType, mediaTypeSIFConfig; got != want {
-		return v1.Descriptor{}, fmt.Errorf("unexpected media type error (got %v, want %v)", got, want)
+	if got, want := manifestArtifactType(m), artifactTypeSIF; got != want {
+		return v1.Descriptor{}, nil, fmt.Errorf("unexpected media type error (got %v, want %v)", got, want)
 	}
 
 	// There should always be exactly one layer (the image blob).
 	if n := len(m.Layers); n != 1 {
-		return v1.Descriptor{}, fmt.Errorf("unexpected # of layers: %v", n)
+		return v1.Descriptor{}, nil, fmt.Errorf("unexpected # of layers: %v", n)
+	}
+
+	// Artifacts pushed by OCI 1.1 tooling may not include a SIF image config (ie. the empty
+	// config is used). In that case, the architecture is only verified by the image index.
+	if m.Config.MediaType != mediaTypeSIFConfig {
+		return m.Layers[0], nil, nil
 	}
 
 	// If architecture was supplied, ensure the image config matches.
 	ic, err := r.getImageConfig(ctx, creds, name, m.Config.Digest)
 	if err != nil {
-		return v1.Descriptor{}, err
+		return v1.Descriptor{}, nil, err
 	}
 
 	// Ensure architecture matches, if supplied.
 	if got, want := ic.Architecture, arch; want != "" && got != want {
-		return v1.Descriptor{}, &unexpectedArchitectureError{got, want}
+		return v1.Descriptor{}, nil, &unexpectedArchitectureError{got, want}
 	}
 
-	return m.Layers[0], nil
+	return m.Layers[0], &ic, nil
 }
 
 func (r *ociRegistry) DownloadV1Index(ctx context.Context, creds credentials, name, tag string) (digest.Digest, v1.Index, error) {
@@ -235,7 +345,14 @@ func (r *ociRegistry) downloadV1Manifest(ctx context.Context, creds credentials,
 }
 
 func (r *ociRegistry) newRequest(ctx context.Context, method string, u *url.URL, body io.Reader) (*http.Request, error) {
-	return http.NewRequestWithContext(ctx, method, r.baseURL.ResolveReference(u).String(), body)
+	req, err := http.NewRequestWithContext(ctx, method, r.baseURL.ResolveReference(u).String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	setRequestID(req)
+
+	return req, nil
 }
 
 type modifyRequestOptions struct {
@@ -243,6 +360,8 @@ type modifyRequestOptions struct {
 	userAgent          string
 	authenticateHeader authHeader // Parsed "Www-Authenticate" header
 	accessOptions      *accessOptions
+	additionalScopes   []accessOptions
+	emitAuthEvent      func(AuthEvent)
 }
 
 type modifyRequestOption func(*modifyRequestOptions) error
@@ -373,6 +492,16 @@ func getAuthType(raw string) (authType, error) {
 	}
 }
 
+// challengeScopes returns the scopes challenged by WWW-Authenticate header value authenticateHeader,
+// if any.
+func challengeScopes(authenticateHeader string) []string {
+	ah, err := parseAuthHeader(authenticateHeader)
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(ah.scope)
+}
+
 func parseAuthHeader(authenticateHeader string) (authHeader, error) {
 	parts := strings.SplitN(authenticateHeader, " ", 2)
 
@@ -414,6 +543,112 @@ func none() *noneCreds {
 	return &noneCreds{}
 }
 
+var errAnonymousAccessDenied = errors.New("registry does not permit anonymous access")
+
+// anonymousCredentials obtains a bearer token without presenting credentials, according to the
+// "WWW-Authenticate" header of a challenge response, for registries that permit anonymous pulls.
+// The token is cached, and applied to subsequent requests.
+type anonymousCredentials struct {
+	mu    sync.Mutex
+	token string
+}
+
+func (c *anonymousCredentials) ModifyRequest(r *http.Request, opts ...modifyRequestOption) error {
+	o := modifyRequestOptions{httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// If challenged, obtain a (new) token from the authorization service.
+	if ah := o.authenticateHeader; ah.at != authTypeUnknown {
+		if ah.at != authTypeBearer || ah.realm == "" {
+			return errAnonymousAccessDenied
+		}
+
+		token, err := anonymousToken(r.Context(), ah, &o)
+		if err != nil {
+			return err
+		}
+		c.token = token
+	}
+
+	if c.token != "" {
+		r.Header.Set("Authorization", fmt.Sprintf("Bearer %v", c.token))
+	}
+	return nil
+}
+
+// anonymousToken requests a bearer token from the authorization service described by ah, without
+// presenting credentials.
+func anonymousToken(ctx context.Context, ah authHeader, o *modifyRequestOptions) (token string, err error) {
+	u, err := url.Parse(ah.realm)
+	if err != nil {
+		return "", fmt.Errorf("malformed realm %v: %w", ah.realm, err)
+	}
+
+	q := u.Query()
+	if ah.service != "" {
+		q.Set("service", ah.service)
+	}
+	if scope := ah.scope; scope != "" {
+		q.Set("scope", scope)
+	} else if ao := o.accessOptions; ao != nil {
+		q.Set("scope", ao.scope())
+	}
+	for _, ao := range o.additionalScopes {
+		if scope := ao.scope(); scope != q.Get("scope") {
+			q.Add("scope", scope)
+		}
+	}
+	u.RawQuery = q.Encode()
+
+	if o.emitAuthEvent != nil {
+		defer func() {
+			o.emitAuthEvent(tokenEvent(AuthEventAnonymousToken, u.Host, q["scope"], token, err))
+		}()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if o.userAgent != "" {
+		req.Header.Set("User-Agent", o.userAgent)
+	}
+
+	res, err := o.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: %w", errAnonymousAccessDenied, registryErrorFromResponse(res))
+	}
+
+	var tr struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&tr); err != nil {
+		return "", fmt.Errorf("error decoding token response: %w", err)
+	}
+
+	if tr.Token != "" {
+		return tr.Token, nil
+	}
+	if tr.AccessToken != "" {
+		return tr.AccessToken, nil
+	}
+	return "", fmt.Errorf("%w: no token issued", errAnonymousAccessDenied)
+}
+
 func withUserAgent(s string) modifyRequestOption {
 	return func(o *modifyRequestOptions) error {
 		o.userAgent = s
@@ -434,6 +669,8 @@ func (r *ociRegistry) doRequestWithCredentials(req *http.Request, creds credenti
 	opts = append(opts,
 		withUserAgent(r.userAgent),
 		withHTTPClient(r.httpClient),
+		withAdditionalScopes(r.additionalScopes),
+		withAuthEventHandler(r.emitAuthEvent),
 	)
 
 	// Modify request to include credentials.
@@ -449,7 +686,7 @@ func (r *ociRegistry) doRequestWithCredentials(req *http.Request, creds credenti
 	if code := res.StatusCode; code/100 != 2 {
 		defer res.Body.Close()
 
-		return nil, fmt.Errorf("unexpected HTTP status %v", res.StatusCode)
+		return nil, withResponseRequestID(r.responseError(res), res)
 	}
 
 	return res, nil
@@ -479,22 +716,28 @@ func (r *ociRegistry) doRequest(req *http.Request, creds credentials, opts ...mo
 	}
 
 	if code := res.StatusCode; code/100 != 2 {
-		defer res.Body.Close()
-
 		// If authorization required, re-attempt request using credentials (if supplied) according
 		// to the contents of the "WWW-Authenticate" header (if present).
 		if code == http.StatusUnauthorized {
+			// Release the response before the request is re-attempted
+			res.Body.Close()
+
 			if creds == nil {
 				// Unauthenticated requests to certain Harbor APIs require an Authorization header,
 				// even if it's set to "none". 🤦
 				creds = none()
 			}
 
-			opts = append(opts, withAuthenticateHeader(res.Header.Get("WWW-Authenticate")))
+			ah := res.Header.Get("WWW-Authenticate")
+			r.emitAuthEvent(AuthEvent{Kind: AuthEventChallenge, Host: req.URL.Host, Scopes: challengeScopes(ah)})
+
+			opts = append(opts, withAuthenticateHeader(ah))
 			return r.retryRequestWithCredentials(req, creds, opts...)
 		}
 
-		return nil, fmt.Errorf("unexpected http status %v", code)
+		defer res.Body.Close()
+
+		return nil, withResponseRequestID(r.responseError(res), res)
 	}
 
 	return res, nil
@@ -513,6 +756,149 @@ func withNamespaceAccess(name string, accessTypes ...accessType) modifyRequestOp
 	}
 }
 
+// withAdditionalScopes specifies that credentials must be procured that additionally grant the
+// access specified by scopes.
+func withAdditionalScopes(scopes []accessOptions) modifyRequestOption {
+	return func(opts *modifyRequestOptions) error {
+		opts.additionalScopes = scopes
+
+		return nil
+	}
+}
+
+// withAuthEventHandler specifies the function called to emit auth events.
+func withAuthEventHandler(f func(AuthEvent)) modifyRequestOption {
+	return func(opts *modifyRequestOptions) error {
+		opts.emitAuthEvent = f
+
+		return nil
+	}
+}
+
+// maxRegistryErrorSize is the maximum size of an OCI registry error response body that is read.
+const maxRegistryErrorSize = 64 * 1024
+
+// RegistryError describes an error response returned by an OCI registry. Code and Message are
+// populated from the first error in the error document returned by the registry, if present.
+type RegistryError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Code is the registry error code (ie. "MANIFEST_UNKNOWN").
+	Code string
+	// Message is the human readable error message.
+	Message string
+}
+
+func (e *RegistryError) Error() string {
+	switch {
+	case e.Code != "" && e.Message != "":
+		return fmt.Sprintf("registry returned an error: %d: %v: %v", e.StatusCode, e.Code, e.Message)
+	case e.Code != "":
+		return fmt.Sprintf("registry returned an error: %d: %v", e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("registry returned an error: %d", e.StatusCode)
+}
+
+// registryErrorFromResponse returns a RegistryError describing error response res. The response
+// body is consumed.
+func registryErrorFromResponse(res *http.Response) *RegistryError {
+	e := &RegistryError{StatusCode: res.StatusCode}
+
+	var doc struct {
+		Errors []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxRegistryErrorSize)).Decode(&doc); err == nil && len(doc.Errors) > 0 {
+		e.Code = doc.Errors[0].Code
+		e.Message = doc.Errors[0].Message
+	}
+	return e
+}
+
+// ErrPermissionDenied is returned when an OCI registry rejects a request with HTTP status 403,
+// indicating the credentials are valid, but do not grant the access requested.
+var ErrPermissionDenied = errors.New("permission denied")
+
+// PermissionDeniedError is returned when an OCI registry rejects a request with HTTP status 403.
+// Unlike HTTP status 401, credentials are not re-negotiated, since the registry has recognized
+// them. PermissionDeniedError matches ErrPermissionDenied when used with errors.Is.
+type PermissionDeniedError struct {
+	// Namespace to which access was denied (ie. "entity/collection/container"), if known.
+	Namespace string
+	// Err describes the error response returned by the registry.
+	Err *RegistryError
+}
+
+func (e *PermissionDeniedError) Error() string {
+	if e.Namespace != "" {
+		return fmt.Sprintf("permission denied on namespace %v: %v", e.Namespace, e.Err)
+	}
+	return fmt.Sprintf("permission denied: %v", e.Err)
+}
+
+func (e *PermissionDeniedError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrPermissionDenied.
+func (e *PermissionDeniedError) Is(target error) bool {
+	return target == ErrPermissionDenied
+}
+
+// registryResponseError returns an error describing error response res. A *PermissionDeniedError
+// is returned for HTTP status 403; otherwise, a *RegistryError is returned. The response body is
+// consumed.
+func registryResponseError(res *http.Response) error {
+	re := registryErrorFromResponse(res)
+	if res.StatusCode != http.StatusForbidden {
+		return re
+	}
+
+	var namespace string
+	if res.Request != nil {
+		namespace = registryNamespace(res.Request.URL.Path)
+	}
+	return &PermissionDeniedError{Namespace: namespace, Err: re}
+}
+
+// responseError returns an error describing error response res (see registryResponseError),
+// emitting an AuthEventPermissionDenied event if access was denied. The response body is consumed.
+func (r *ociRegistry) responseError(res *http.Response) error {
+	err := registryResponseError(res)
+
+	var pde *PermissionDeniedError
+	if errors.As(err, &pde) {
+		ev := AuthEvent{Kind: AuthEventPermissionDenied, Err: err}
+		if res.Request != nil {
+			ev.Host = res.Request.URL.Host
+		}
+		if pde.Namespace != "" {
+			ev.Scopes = []string{"repository:" + pde.Namespace}
+		}
+		r.emitAuthEvent(ev)
+	}
+	return err
+}
+
+// registryNamespace returns the namespace (repository name) referenced by the OCI distribution API
+// path p (ie. "/v2/entity/collection/container/manifests/latest"), or an empty string if p does not
+// reference a namespace.
+func registryNamespace(p string) string {
+	p, ok := strings.CutPrefix(p, "/v2/")
+	if !ok {
+		return ""
+	}
+
+	for _, s := range []string{"/manifests/", "/blobs/", "/tags/", "/referrers/"} {
+		if i := strings.LastIndex(p, s); i > 0 {
+			return p[:i]
+		}
+	}
+	return ""
+}
+
 type unexpectedContentTypeError struct {
 	got  string
 	want string
@@ -525,33 +911,261 @@ func (e *unexpectedContentTypeError) Error() string {
 // downloadManifest downloads the manifest of type contentType associated with name/ref in the
 // registry, and unmarshals it to v.
 func (r *ociRegistry) downloadManifest(ctx context.Context, creds credentials, name, tag string, v interface{}, contentType string) (digest.Digest, error) {
-	req, err := r.newRequest(ctx, http.MethodGet, &url.URL{Path: fmt.Sprintf("v2/%v/manifests/%v", name, tag)}, nil)
+	u := &url.URL{Path: fmt.Sprintf("v2/%v/manifests/%v", name, tag)}
+
+	// Identical concurrent requests are coalesced. The response depends on the accepted content
+	// type, so it forms part of the key.
+	key := http.MethodGet + " " + r.baseURL.ResolveReference(u).String() + " " + contentType
+
+	res, err := coalesce(ctx, r.inflightRequests, key, func() (interface{}, error) {
+		return r.fetchManifest(ctx, creds, name, tag, u, contentType)
+	})
 	if err != nil {
 		return "", err
 	}
+	m := res.(fetchedManifest)
+
+	if err := json.Unmarshal(m.b, &v); err != nil {
+		return "", err
+	}
+	return m.d, nil
+}
+
+// fetchedManifest is the content and digest of a manifest fetched from a registry.
+type fetchedManifest struct {
+	b []byte
+	d digest.Digest
+}
+
+// fetchManifest fetches the manifest of type contentType associated with name/tag, located at
+// relative URL u.
+func (r *ociRegistry) fetchManifest(ctx context.Context, creds credentials, name, tag string, u *url.URL, contentType string) (fetchedManifest, error) {
+	ctx, cancel := withTimeout(ctx, r.metadataTimeout)
+	defer cancel()
+
+	req, err := r.newRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fetchedManifest{}, err
+	}
 	req.Header.Set("Accept", contentType)
 
 	res, err := r.doRequest(req, creds, withNamespaceAccess(name, accessTypePull))
 	if err != nil {
-		return "", err
+		return fetchedManifest{}, err
 	}
 	defer res.Body.Close()
 
+	b, err := io.ReadAll(limitResponse(res.Body, maxManifestSize))
+	if errors.Is(err, ErrResponseTooLarge) {
+		return fetchedManifest{}, fmt.Errorf("manifest exceeds maximum size of %d bytes: %w", maxManifestSize, err)
+	}
+	if err != nil {
+		return fetchedManifest{}, err
+	}
+
 	// Although we've set the "Accept" header, some registries will return other content types.
-	if got, want := res.Header.Get("Content-Type"), contentType; got != want {
-		return "", &unexpectedContentTypeError{got, want}
+	if err := r.checkManifestContentType(res, b, contentType); err != nil {
+		return fetchedManifest{}, err
+	}
+
+	d, err := manifestDigest(res, b, tag)
+	if err != nil {
+		return fetchedManifest{}, err
 	}
 
-	d := digest.Digest(res.Header.Get("Docker-Content-Digest"))
+	transferStatsFromContext(ctx).setCacheHints(parseCacheHints(tag, res.Header, time.Now()))
+
+	return fetchedManifest{b: b, d: d}, nil
+}
+
+// maxManifestSize is the maximum size of a manifest downloaded from a registry.
+const maxManifestSize = 4 * 1024 * 1024
+
+// maxImageConfigSize is the maximum size of an image config downloaded from a registry.
+const maxImageConfigSize = 4 * 1024 * 1024
+
+// checkManifestContentType returns an error if the "Content-Type" header of res does not match
+// contentType. In lenient mode, parameters (such as charset) are ignored, and if the header does
+// not match, the media type is sniffed from the content of the manifest b instead.
+func (r *ociRegistry) checkManifestContentType(res *http.Response, b []byte, contentType string) error {
+	got := res.Header.Get("Content-Type")
+
+	if !r.lenientContentType {
+		if got != contentType {
+			return &unexpectedContentTypeError{got, contentType}
+		}
+		return nil
+	}
 
+	if mt, _, err := mime.ParseMediaType(got); err != nil || mt != contentType {
+		sniffed, err := sniffManifestMediaType(b)
+		if err != nil || sniffed != contentType {
+			return &unexpectedContentTypeError{got, contentType}
+		}
+
+		r.logger.Logf("Manifest content type %q does not match, sniffed %q", got, sniffed)
+	}
+	return nil
+}
+
+// manifestDigest returns the digest of the manifest b in the body of res. If ref is a digest, the
+// content of the manifest is verified against it. Otherwise, the digest reported by the
+// "Docker-Content-Digest" header is returned, or if the header is not present (as is the case
+// with some registries), the digest is computed from the content.
+func manifestDigest(res *http.Response, b []byte, ref string) (digest.Digest, error) {
+	if want, err := digest.Parse(ref); err == nil {
+		if got := want.Algorithm().FromBytes(b); got != want {
+			return "", fmt.Errorf("%w: manifest digest %v does not match reference %v", errDigestNotVerified, got, want)
+		}
+		return want, nil
+	}
+
+	v := res.Header.Get("Docker-Content-Digest")
+	if v == "" {
+		return digest.FromBytes(b), nil
+	}
+
+	d := digest.Digest(v)
 	if err := d.Validate(); err != nil {
 		return "", err
 	}
+	return d, nil
+}
+
+// sniffManifestMediaType returns the media type of the manifest b, as specified by the
+// "mediaType" field if present, or determined by the structure of the manifest otherwise.
+func sniffManifestMediaType(b []byte) (string, error) {
+	var m struct {
+		SchemaVersion int             `json:"schemaVersion"`
+		MediaType     string          `json:"mediaType"`
+		Config        json.RawMessage `json:"config"`
+		Manifests     json.RawMessage `json:"manifests"`
+	}
 
-	if err := json.NewDecoder(res.Body).Decode(&v); err != nil {
+	if err := json.Unmarshal(b, &m); err != nil {
 		return "", err
 	}
-	return d, nil
+
+	if m.SchemaVersion != 2 {
+		return "", fmt.Errorf("unsupported manifest schema version %v", m.SchemaVersion)
+	}
+
+	switch {
+	case m.MediaType != "":
+		return m.MediaType, nil
+	case m.Manifests != nil:
+		return v1.MediaTypeImageIndex, nil
+	case m.Config != nil:
+		return v1.MediaTypeImageManifest, nil
+	default:
+		return "", errors.New("unable to determine manifest media type")
+	}
+}
+
+// listTags returns the tags associated with name in the registry. If the registry paginates the
+// response, subsequent pages are retrieved by following the "next" link in the "Link" header.
+func (r *ociRegistry) listTags(ctx context.Context, creds credentials, name string) ([]string, error) {
+	var tags []string
+
+	u := &url.URL{Path: fmt.Sprintf("v2/%v/tags/list", name)}
+
+	for u != nil {
+		req, err := r.newRequest(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := r.doRequest(req, creds, withNamespaceAccess(name, accessTypePull))
+		if err != nil {
+			return nil, err
+		}
+
+		var tl struct {
+			Name string   `json:"name"`
+			Tags []string `json:"tags"`
+		}
+
+		err = json.NewDecoder(limitResponse(res.Body, r.maxResponseSize)).Decode(&tl)
+		res.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding tag list: %w", err)
+		}
+
+		tags = append(tags, tl.Tags...)
+
+		if u, err = nextLink(res); err != nil {
+			return nil, err
+		}
+	}
+
+	return tags, nil
+}
+
+// catalog returns up to n repository names in the registry, in lexical order, starting after
+// last. If n is zero, the registry default page size applies. If further repositories are
+// available, the value of last to use to retrieve the next page is returned as next.
+func (r *ociRegistry) catalog(ctx context.Context, creds credentials, n int, last string) (repos []string, next string, err error) {
+	q := url.Values{}
+	if n > 0 {
+		q.Set("n", strconv.Itoa(n))
+	}
+	if last != "" {
+		q.Set("last", last)
+	}
+
+	req, err := r.newRequest(ctx, http.MethodGet, &url.URL{Path: "v2/_catalog", RawQuery: q.Encode()}, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, err := r.doRequest(req, creds)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+
+	var cat struct {
+		Repositories []string `json:"repositories"`
+	}
+
+	if err := json.NewDecoder(limitResponse(res.Body, r.maxResponseSize)).Decode(&cat); err != nil {
+		return nil, "", fmt.Errorf("error decoding catalog: %w", err)
+	}
+
+	u, err := nextLink(res)
+	if err != nil {
+		return nil, "", err
+	}
+	if u != nil {
+		next = u.Query().Get("last")
+	}
+
+	return cat.Repositories, next, nil
+}
+
+// nextLink returns the URL of the "next" link in the "Link" header of res, relative to the URL of
+// the request, or nil if no such link is present.
+func nextLink(res *http.Response) (*url.URL, error) {
+	for _, v := range res.Header.Values("Link") {
+		for _, link := range strings.Split(v, ",") {
+			target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+			if !ok || !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			// Link parameters are semicolon-separated.
+			if pairs := parsePairs(strings.ReplaceAll(params, ";", ",")); pairs["rel"] != "next" {
+				continue
+			}
+
+			u, err := url.Parse(strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">"))
+			if err != nil {
+				return nil, fmt.Errorf("malformed link %q: %w", target, err)
+			}
+			return res.Request.URL.ResolveReference(u), nil
+		}
+	}
+	return nil, nil
 }
 
 func (r *ociRegistry) downloadBlob(ctx context.Context, creds credentials, name string, d digest.Digest, rangeValue string, w io.Writer) (int64, error) {
@@ -579,6 +1193,19 @@ func (r *ociRegistry) downloadBlob(ctx context.Context, creds credentials, name
 	return io.Copy(w, res.Body)
 }
 
+// downloadBlobBytes downloads the blob d, returning an error wrapping ErrResponseTooLarge if it
+// exceeds maxSize bytes. It is intended for small objects that are held in memory.
+func (r *ociRegistry) downloadBlobBytes(ctx context.Context, creds credentials, name string, d digest.Digest, maxSize int64) ([]byte, error) {
+	var b bytes.Buffer
+	if _, err := r.downloadBlob(ctx, creds, name, d, "", &limitedWriter{w: &b, n: maxSize}); err != nil {
+		if errors.Is(err, ErrResponseTooLarge) {
+			return nil, fmt.Errorf("blob %v exceeds maximum size of %d bytes: %w", d, maxSize, err)
+		}
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
 var errArchitectureNotPresent = errors.New("architecture not present")
 
 // validateImageConfig validates ic, and returns an error when ic is invalid.
@@ -611,17 +1238,20 @@ func (e *unexpectedArchitectureError) Is(target error) bool {
 var errDigestNotVerified = errors.New("digest not verified")
 
 func (r *ociRegistry) getImageConfig(ctx context.Context, creds credentials, name string, d digest.Digest) (imageConfig, error) {
-	var b bytes.Buffer
-	if _, err := r.downloadBlob(ctx, creds, name, d, "", &b); err != nil {
+	ctx, cancel := withTimeout(ctx, r.metadataTimeout)
+	defer cancel()
+
+	b, err := r.downloadBlobBytes(ctx, creds, name, d, maxImageConfigSize)
+	if err != nil {
 		return imageConfig{}, err
 	}
 
-	if digest.FromBytes(b.Bytes()) != d {
+	if digest.FromBytes(b) != d {
 		return imageConfig{}, errDigestNotVerified
 	}
 
 	var ic imageConfig
-	if err := json.Unmarshal(b.Bytes(), &ic); err != nil {
+	if err := json.Unmarshal(b, &ic); err != nil {
 		return imageConfig{}, err
 	}
 
@@ -634,41 +1264,123 @@ func (r *ociRegistry) getImageConfig(ctx context.Context, creds credentials, nam
 
 var errOCIDownloadNotSupported = errors.New("not supported")
 
-// newOCIRegistry returns *ociRegistry, credentials for that registry, and the (optionally) remapped image name
-func (c *Client) newOCIRegistry(ctx context.Context, name string, accessTypes []accessType) (*ociRegistry, *bearerTokenCredentials, string, error) {
+// ociNotSupportedError returns an error that signals a fallback to the library, recording reason.
+func ociNotSupportedError(reason error) error {
+	return fmt.Errorf("direct OCI registry access %w: %w", errOCIDownloadNotSupported, reason)
+}
+
+// newOCIRegistry returns *ociRegistry, credentials for that registry, and the (optionally) remapped image name.
+// If static credentials are configured for the registry host, they are used in place of the
+// token issued by the library.
+//
+// If the library server does not support direct OCI registry access, an error wrapping
+// errOCIDownloadNotSupported is returned. Other errors (such as transport or authorization
+// failures) are also reported this way, unless strict registry access is configured, in which
+// case they are returned directly.
+//
+// The credentials returned additionally grant the access specified by others (see
+// ociRegistryAuth).
+func (c *Client) newOCIRegistry(ctx context.Context, name string, accessTypes []accessType, others ...accessOptions) (*ociRegistry, credentials, string, error) {
+	name, err := c.resolveName(ctx, name)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
 	// Attempt to obtain (direct) OCI registry auth token
 	originalName := name
 
-	registryURI, creds, name, err := c.ociRegistryAuth(ctx, name, accessTypes)
-	if err != nil {
-		return nil, nil, "", errOCIDownloadNotSupported
+	var creds credentials
+
+	registryURI, token, name, err := c.ociRegistryAuth(ctx, name, accessTypes, others...)
+	switch {
+	case err == nil && token.authToken != "":
+		creds = token
+	case err == nil:
+		// No token issued; proceed anonymously.
+		creds = &anonymousCredentials{}
+	case c.strictRegistry && !errors.Is(err, ErrNotFound):
+		return nil, nil, "", err
+	case c.anonymousRegistry != nil && isPullOnly(accessTypes) && isPullOnlyScopes(others):
+		c.logger.Logf("Direct OCI registry access not granted, attempting anonymous pull: %v", err)
+
+		registryURI, creds, name = c.anonymousRegistry, &anonymousCredentials{}, originalName
+	case errors.Is(err, ErrNotFound):
+		c.logger.Log("Direct OCI registry access not supported by library server")
+
+		return nil, nil, "", ociNotSupportedError(err)
+	default:
+		c.logger.Logf("Direct OCI registry access failed: %v", err)
+
+		return nil, nil, "", ociNotSupportedError(err)
 	}
 
 	// Download directly from OCI registry
 	c.logger.Logf("Using OCI registry endpoint %v", registryURI)
 
+	if rc, ok := c.registryCreds[registryURI.Host]; ok {
+		c.logger.Logf("Using static credentials for OCI registry %v", registryURI.Host)
+
+		creds = rc.credentials()
+	}
+
 	if name != "" && originalName != name {
 		c.logger.Logf("OCI artifact name \"%v\" mapped to \"%v\"", originalName, name)
 	}
 
-	return &ociRegistry{baseURL: registryURI, httpClient: c.httpClient, logger: c.logger}, creds, name, nil
+	reg := &ociRegistry{
+		baseURL:            registryURI,
+		httpClient:         c.httpClient,
+		logger:             c.logger,
+		lenientContentType: c.lenientManifests,
+		maxResponseSize:    c.maxResponseSize,
+		inflightRequests:   &c.inflightRequests,
+		metadataTimeout:    c.registryMetadataTimeout(),
+		additionalScopes:   others,
+		authEventHandler:   c.authEventHandler,
+	}
+	return reg, creds, name, nil
 }
 
 func (c *Client) ociDownloadImage(ctx context.Context, arch, name, tag string, w io.WriterAt, spec *Downloader, pb ProgressBar) error {
-	reg, creds, name, err := c.newOCIRegistry(ctx, name, []accessType{accessTypePull})
+	u, creds, size, ic, err := c.ociImageBlob(ctx, arch, name, tag)
 	if err != nil {
 		return err
 	}
 
+	if err := c.multipartDownload(ctx, u, creds, w, size, spec, pb); err != nil {
+		return err
+	}
+
+	if spec.VerifyImageConfig {
+		// The destination is known to be seekable, as checked by downloadImage.
+		return c.verifyImageConfig(ic, io.NewSectionReader(w.(io.ReaderAt), 0, size))
+	}
+	return nil
+}
+
+// ociImageBlob returns the URL, credentials and size of the blob containing the image with the
+// specified name, tag and architecture in the OCI registry.
+func (c *Client) ociImageBlob(ctx context.Context, arch, name, tag string) (*blobURL, credentials, int64, *imageConfig, error) {
+	reg, creds, name, err := c.newOCIRegistry(ctx, name, []accessType{accessTypePull})
+	if err != nil {
+		return nil, nil, 0, nil, err
+	}
+
 	// Fetch image manifest to get image details
-	id, err := reg.getImageDetails(ctx, creds, name, tag, arch)
+	id, ic, err := reg.getImageDetails(ctx, creds, name, tag, arch)
 	if err != nil {
-		return fmt.Errorf("error getting image details: %w", err)
+		// If anonymous access was attempted, fall back to the library.
+		if _, ok := creds.(*anonymousCredentials); ok {
+			c.logger.Logf("Anonymous OCI registry access failed: %v", err)
+
+			return nil, nil, 0, nil, ociNotSupportedError(err)
+		}
+		return nil, nil, 0, nil, fmt.Errorf("error getting image details: %w", err)
 	}
 
 	imageURI := reg.baseURL.ResolveReference(&url.URL{Path: fmt.Sprintf("v2/%v/blobs/%v", name, id.Digest)}).String()
 
-	return c.multipartDownload(ctx, imageURI, creds, w, id.Size, spec, pb)
+	return &blobURL{u: imageURI, digest: id.Digest, registry: true}, creds, id.Size, ic, nil
 }
 
 const sifHeaderSize = 32768
@@ -682,12 +1394,14 @@ func (e *unexpectedImageDigest) Error() string {
 	return fmt.Sprintf("unexpected image digest: %v != %v", e.got, e.want)
 }
 
+// ociUploadImage uploads the image read from r to the OCI registry, and applies tags. The artifacts
+// published are returned on success, and when only some tags could not be applied.
 func (c *Client) ociUploadImage(ctx context.Context, r io.Reader, size int64, name, _ string, tags []string,
-	description, hash string, callback UploadCallback,
-) error {
+	description string, labels map[string]string, hash string, o UploadOptions, callback UploadCallback,
+) (*OCIPushResult, error) {
 	reg, creds, name, err := c.newOCIRegistry(ctx, name, []accessType{accessTypePull, accessTypePush})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	sifHeader := bytes.NewBuffer(make([]byte, 0, sifHeaderSize))
@@ -695,11 +1409,14 @@ func (c *Client) ociUploadImage(ctx context.Context, r io.Reader, size int64, na
 	// Convert SIF hash to OCI digest.
 	imageDigest := digest.Digest(strings.ReplaceAll(hash, ".", ":"))
 	if err := imageDigest.Validate(); err != nil {
-		return fmt.Errorf("invalid image hash '%v': %w", hash, err)
+		return nil, fmt.Errorf("invalid image hash '%v': %w", hash, err)
 	}
 
 	// Check if image exists, 'ok' is set correctly if this returns an error.
-	ok, _ := reg.existingImageBlob(ctx, creds, name, imageDigest)
+	var ok bool
+	if !o.SkipDedup {
+		ok, _ = reg.existingImageBlob(ctx, creds, name, imageDigest)
+	}
 
 	var id digest.Digest
 
@@ -716,6 +1433,8 @@ func (c *Client) ociUploadImage(ctx context.Context, r io.Reader, size int64, na
 			r = callback.GetReader()
 		}
 
+		onPhase(callback, PhaseBlob, "")
+
 		var err error
 		id, _, err = reg.uploadImageBlob(ctx, creds, name, size, r)
 		if err != nil {
@@ -723,7 +1442,7 @@ func (c *Client) ociUploadImage(ctx context.Context, r io.Reader, size int64, na
 				callback.Terminate()
 			}
 
-			return fmt.Errorf("upload image blob failed: %w", err)
+			return nil, fmt.Errorf("upload image blob failed: %w", err)
 		}
 
 		if callback != nil {
@@ -732,7 +1451,7 @@ func (c *Client) ociUploadImage(ctx context.Context, r io.Reader, size int64, na
 
 		// Verify image blob matches had expected digest.
 		if got, want := id, imageDigest; got != want {
-			return &unexpectedImageDigest{got, want}
+			return nil, &unexpectedImageDigest{got, want}
 		}
 
 	} else {
@@ -741,24 +1460,50 @@ func (c *Client) ociUploadImage(ctx context.Context, r io.Reader, size int64, na
 		id = imageDigest
 
 		if _, err := io.Copy(sifHeader, io.LimitReader(r, sifHeaderSize)); err != nil {
-			return fmt.Errorf("error reading local SIF file header: %v", err)
+			return nil, fmt.Errorf("error reading local SIF file header: %v", err)
 		}
 	}
 
 	// Populate image configuration.
-	ic, err := reg.processImageHeader(id, description, sifHeader.Bytes())
+	ic, err := reg.processImageHeader(id, description, labels, sifHeader.Bytes())
 	if err != nil {
-		return fmt.Errorf("process image failed: %w", err)
+		return nil, fmt.Errorf("process image failed: %w", err)
 	}
 
+	onPhase(callback, PhaseConfig, "")
+
 	cs, cd, err := reg.uploadimageConfig(ctx, creds, name, ic)
 	if err != nil {
-		return fmt.Errorf("upload image config failed: %w", err)
+		return nil, fmt.Errorf("upload image config failed: %w", err)
+	}
+
+	res := &OCIPushResult{Name: name, Image: id, Config: cd}
+
+	// Blobs are only referenced once the manifest is pushed, so a registry that garbage collects
+	// untagged blobs may have removed them during a long upload.
+	if o.VerifyBlobs {
+		if err := reg.verifyBlobs(ctx, creds, name, id, cd); err != nil {
+			return nil, err
+		}
 	}
 
+	onPhase(callback, PhaseManifest, "")
+
 	md, err := reg.uploadImageManifest(ctx, creds, name, hash, cd, id, cs, size)
 	if err != nil {
-		return fmt.Errorf("upload image manifest failed: %w", err)
+		return nil, fmt.Errorf("upload image manifest failed: %w", err)
+	}
+
+	res.Manifest = md.Digest
+
+	if s := detachedSignerFromContext(ctx); s != nil {
+		onPhase(callback, PhaseSignature, "")
+
+		sd, err := reg.uploadSignature(ctx, creds, name, md, id, s)
+		if err != nil {
+			return nil, fmt.Errorf("upload signature failed: %w", err)
+		}
+		res.Signature = sd
 	}
 
 	idx := v1.Index{
@@ -766,30 +1511,48 @@ func (c *Client) ociUploadImage(ctx context.Context, r io.Reader, size int64, na
 	}
 
 	idx.Manifests = append(idx.Manifests, v1.Descriptor{
-		MediaType: v1.MediaTypeImageManifest,
-		Digest:    md,
+		MediaType:    v1.MediaTypeImageManifest,
+		ArtifactType: artifactTypeSIF,
+		Digest:       md.Digest,
 		Platform: &v1.Platform{
 			Architecture: ic.Architecture,
 			OS:           ic.OS,
 		},
 	})
 
-	// Add tags
+	// Add tags. Each tag is applied individually, so a failure to apply one tag does not prevent
+	// the others from being applied.
+	res.Indexes = make(map[string]digest.Digest, len(tags))
+
+	var tr tagResults
 	for _, ref := range tags {
 		c.logger.Logf("Tag: %v", ref)
 
-		if _, err := reg.uploadManifest(ctx, creds, name, ref, idx, v1.MediaTypeImageIndex); err != nil {
-			return fmt.Errorf("error uploading index")
+		onPhase(callback, PhaseIndex, ref)
+
+		if o.VerifyBlobs {
+			if err := reg.verifyBlobs(ctx, creds, name, id, cd); err != nil {
+				tr.add("", ref, err)
+				continue
+			}
 		}
+
+		d, err := reg.uploadManifest(ctx, creds, name, ref, idx, v1.MediaTypeImageIndex)
+		if err != nil {
+			err = fmt.Errorf("error uploading index: %w", err)
+		} else {
+			res.Indexes[ref] = d.Digest
+		}
+		tr.add("", ref, err)
 	}
 
-	return nil
+	return res, tr.err()
 }
 
 func (r *ociRegistry) existingImageBlob(ctx context.Context, creds credentials, name string, d digest.Digest) (bool, error) {
-	u := r.baseURL.ResolveReference(&url.URL{Path: fmt.Sprintf("v2/%v/blobs/%v", name, d.String())})
+	u := &url.URL{Path: fmt.Sprintf("v2/%v/blobs/%v", name, d.String())}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.String(), nil)
+	req, err := r.newRequest(ctx, http.MethodHead, u, nil)
 	if err != nil {
 		return false, fmt.Errorf("error checking for existing layer: %v", err)
 	}
@@ -804,6 +1567,42 @@ func (r *ociRegistry) existingImageBlob(ctx context.Context, creds credentials,
 	return res.StatusCode == http.StatusOK && d.String() == res.Header.Get("Docker-Content-Digest"), nil
 }
 
+// MissingBlobError is returned when a blob uploaded to an OCI registry is no longer present, as
+// may occur when the registry garbage collects untagged blobs during an upload (see
+// UploadOptions.VerifyBlobs).
+type MissingBlobError struct {
+	// Digest of the missing blob.
+	Digest digest.Digest
+}
+
+func (e *MissingBlobError) Error() string {
+	return fmt.Sprintf("blob %v missing from OCI registry", e.Digest)
+}
+
+// Is reports whether target is ErrNotFound.
+func (e *MissingBlobError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// verifyBlobs returns a *MissingBlobError if any of the blobs identified by ds are not present in
+// the repository name.
+func (r *ociRegistry) verifyBlobs(ctx context.Context, creds credentials, name string, ds ...digest.Digest) error {
+	for _, d := range ds {
+		ok, err := r.existingImageBlob(ctx, creds, name, d)
+		if err != nil {
+			var re *RegistryError
+			if errors.As(err, &re) && re.StatusCode == http.StatusNotFound {
+				return &MissingBlobError{Digest: d}
+			}
+			return fmt.Errorf("error verifying blob %v: %w", d, err)
+		}
+		if !ok {
+			return &MissingBlobError{Digest: d}
+		}
+	}
+	return nil
+}
+
 // uploadimageConfig uploads ic into namespace name of the registry, using credentials c.
 //
 // On success, the config size and digest are returned.
@@ -830,15 +1629,16 @@ func (r *ociRegistry) uploadimageConfig(ctx context.Context, creds credentials,
 // corresponding config blob has digest configDigest of size configSize. The corresponding image
 // blob has digest imageDigest of size imageSize.
 //
-// On success, the manifest digest is returned.
-func (r *ociRegistry) uploadImageManifest(ctx context.Context, creds credentials, name, ref string, configDigest, imageDigest digest.Digest, configSize, imageSize int64) (d digest.Digest, err error) {
+// On success, the manifest descriptor is returned.
+func (r *ociRegistry) uploadImageManifest(ctx context.Context, creds credentials, name, ref string, configDigest, imageDigest digest.Digest, configSize, imageSize int64) (d v1.Descriptor, err error) {
 	r.logger.Logf("Starting image manifest upload: name=[%v], ref=[%v]", name, ref)
 	defer func(t time.Time) {
-		r.logger.Logf("Finished image manifest upload: took=[%v] digest=[%v], err=[%v]", time.Since(t), d.String(), err)
+		r.logger.Logf("Finished image manifest upload: took=[%v] digest=[%v], err=[%v]", time.Since(t), d.Digest.String(), err)
 	}(time.Now())
 
 	m := v1.Manifest{
-		Versioned: specs.Versioned{SchemaVersion: 2},
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		ArtifactType: artifactTypeSIF,
 		Config: v1.Descriptor{
 			MediaType: mediaTypeSIFConfig,
 			Digest:    configDigest,
@@ -852,7 +1652,7 @@ func (r *ociRegistry) uploadImageManifest(ctx context.Context, creds credentials
 			},
 		},
 	}
-	return r.uploadV1Manifest(ctx, creds, name, ref, m)
+	return r.uploadManifest(ctx, creds, name, ref, m, v1.MediaTypeImageManifest)
 }
 
 func (r *ociRegistry) uploadImageBlob(ctx context.Context, creds credentials, name string, size int64, rd io.Reader) (digest.Digest, int64, error) {
@@ -880,23 +1680,27 @@ func (r *ociRegistry) uploadBlob(ctx context.Context, creds credentials, name st
 			chunkSize = size - offset // last chunk
 		}
 
-		if u, err = r.uploadBlobPart(ctx, creds, u, tee, chunkSize, offset); err != nil {
-			return "", 0, err
+		next, err := r.uploadBlobPart(ctx, creds, u, tee, chunkSize, offset)
+		if err != nil {
+			return "", 0, r.cancelUploadBlobSessionOnError(ctx, creds, u, err)
 		}
+		u = next
 
 		totalBytesUploaded += chunkSize
+
+		transferStatsFromContext(ctx).addPart(chunkSize)
 	}
 
 	d := digest.NewDigest(digest.Canonical, h)
 
 	if err := r.closeUploadBlobSession(ctx, creds, u, d); err != nil {
-		return "", 0, err
+		return "", 0, r.cancelUploadBlobSessionOnError(ctx, creds, u, err)
 	}
 
 	return d, totalBytesUploaded, nil
 }
 
-func (r *ociRegistry) openUploadBlobSession(ctx context.Context, creds credentials, name string) (*url.URL, *bearerTokenCredentials, error) {
+func (r *ociRegistry) openUploadBlobSession(ctx context.Context, creds credentials, name string) (*url.URL, credentials, error) {
 	u := &url.URL{Path: fmt.Sprintf("v2/%v/blobs/uploads/", name)}
 
 	req, err := r.newRequest(ctx, http.MethodPost, u, nil)
@@ -920,6 +1724,11 @@ func (r *ociRegistry) openUploadBlobSession(ctx context.Context, creds credentia
 		return nil, nil, fmt.Errorf("malformed Authorization header (%v)", req.Header.Get("Authorization"))
 	}
 
+	// Static (non-bearer) credentials are used as-is for the remainder of the session.
+	if !strings.EqualFold(parts[0], "Bearer") {
+		return u, creds, nil
+	}
+
 	return u, &bearerTokenCredentials{authToken: parts[1]}, nil
 }
 
@@ -943,6 +1752,37 @@ func (r *ociRegistry) closeUploadBlobSession(ctx context.Context, creds credenti
 	return nil
 }
 
+// cancelUploadBlobSessionOnError cancels the blob upload session using relative URL u following
+// error err, which is returned along with any error encountered cancelling the session. The
+// session is cancelled even if ctx has been cancelled, so that the session is not left dangling.
+func (r *ociRegistry) cancelUploadBlobSessionOnError(ctx context.Context, creds credentials, u *url.URL, err error) error {
+	ctx, cancel := cleanupContext(ctx)
+	defer cancel()
+
+	if cancelErr := r.cancelUploadBlobSession(ctx, creds, u); cancelErr != nil {
+		r.logger.Logf("Error cancelling blob upload session: %v", cancelErr)
+
+		return errors.Join(err, fmt.Errorf("error cancelling blob upload session: %w", cancelErr))
+	}
+	return err
+}
+
+// cancelUploadBlobSession cancels a blob upload session using relative URL u.
+func (r *ociRegistry) cancelUploadBlobSession(ctx context.Context, creds credentials, u *url.URL) error {
+	req, err := r.newRequest(ctx, http.MethodDelete, u, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.doRequestWithCredentials(req, creds)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	return nil
+}
+
 // uploadBlobPart uploads a chunk of a blob read from rd using relative URL u. The chunk is located
 // at offset and is of size chunkSize.
 func (r *ociRegistry) uploadBlobPart(ctx context.Context, creds credentials, u *url.URL, rd io.Reader, chunkSize, offset int64) (*url.URL, error) {
@@ -999,9 +1839,9 @@ func getEncrypted(f *sif.FileImage) (bool, error) {
 	return (t == sif.FsEncryptedSquashfs), nil
 }
 
-// processImageHeader creates an imageConfig using the supplied hash, description, and SIF header
-// contained in b.
-func (r *ociRegistry) processImageHeader(rootFS digest.Digest, description string, b []byte) (imageConfig, error) {
+// processImageHeader creates an imageConfig using the supplied hash, description, labels, and SIF
+// header contained in b.
+func (r *ociRegistry) processImageHeader(rootFS digest.Digest, description string, labels map[string]string, b []byte) (imageConfig, error) {
 	f, err := sif.LoadContainer(sif.NewBuffer(b))
 	if err != nil {
 		return imageConfig{}, err
@@ -1027,6 +1867,7 @@ func (r *ociRegistry) processImageHeader(rootFS digest.Digest, description strin
 		OS:           "linux",
 		RootFS:       rootFS,
 		Description:  description,
+		Labels:       labels,
 		Signed:       signed,
 		Encrypted:    encrypted,
 	}
@@ -1042,11 +1883,12 @@ func manifestURL(name, ref string) *url.URL {
 }
 
 // uploadManifest uploads manifest v of type contentType to the registry, and associates it with
-// name/ref. If ref is empty, the manifest digest is used.
-func (r *ociRegistry) uploadManifest(ctx context.Context, creds credentials, name, ref string, v interface{}, contentType string) (digest.Digest, error) {
+// name/ref. If ref is empty, the manifest digest is used. On success, the manifest descriptor is
+// returned.
+func (r *ociRegistry) uploadManifest(ctx context.Context, creds credentials, name, ref string, v interface{}, contentType string) (v1.Descriptor, error) {
 	b, err := json.Marshal(v)
 	if err != nil {
-		return "", err
+		return v1.Descriptor{}, err
 	}
 
 	d := digest.FromBytes(b)
@@ -1057,27 +1899,178 @@ func (r *ociRegistry) uploadManifest(ctx context.Context, creds credentials, nam
 
 	req, err := r.newRequest(ctx, http.MethodPut, manifestURL(name, ref), bytes.NewReader(b))
 	if err != nil {
-		return "", err
+		return v1.Descriptor{}, err
 	}
 	req.Header.Set("Content-Type", contentType)
 
 	res, err := r.doRequest(req, creds, withNamespaceAccess(name, accessTypePush))
 	if err != nil {
-		return "", err
+		return v1.Descriptor{}, err
 	}
 	defer res.Body.Close()
 
-	return d, nil
+	return v1.Descriptor{MediaType: contentType, Digest: d, Size: int64(len(b))}, nil
 }
 
 // UploadV1Index uploads image index idx to the registry, and associates it with name/ref. If ref
 // is empty, the image index digest is used.
 func (r *ociRegistry) UploadV1Index(ctx context.Context, creds credentials, name, ref string, idx v1.Index) (digest.Digest, error) {
-	return r.uploadManifest(ctx, creds, name, ref, idx, v1.MediaTypeImageIndex)
+	d, err := r.uploadManifest(ctx, creds, name, ref, idx, v1.MediaTypeImageIndex)
+	return d.Digest, err
 }
 
 // uploadV1Manifest uploads manifest m to the registry, and associates it with name/ref. If ref is
 // empty, the manifest digest is used.
 func (r *ociRegistry) uploadV1Manifest(ctx context.Context, creds credentials, name, ref string, m v1.Manifest) (digest.Digest, error) {
-	return r.uploadManifest(ctx, creds, name, ref, m, v1.MediaTypeImageManifest)
+	d, err := r.uploadManifest(ctx, creds, name, ref, m, v1.MediaTypeImageManifest)
+	return d.Digest, err
+}
+
+// resolveManifest returns the digest of the manifest (or image index) associated with name/ref in
+// the registry.
+func (r *ociRegistry) resolveManifest(ctx context.Context, creds credentials, name, ref string) (digest.Digest, error) {
+	d, err := r.describeManifest(ctx, creds, name, ref)
+	if err != nil {
+		return "", err
+	}
+	return d.Digest, nil
+}
+
+// describeManifest returns a descriptor of the manifest (or image index) associated with name/ref
+// in the registry. If the registry does not report the digest of the manifest in response to a
+// HEAD request, the manifest is retrieved, and its digest computed from the content.
+func (r *ociRegistry) describeManifest(ctx context.Context, creds credentials, name, ref string) (v1.Descriptor, error) {
+	req, err := r.newRequest(ctx, http.MethodHead, manifestURL(name, ref), nil)
+	if err != nil {
+		return v1.Descriptor{}, err
+	}
+	req.Header.Set("Accept", manifestAccept)
+
+	res, err := r.doRequest(req, creds, withNamespaceAccess(name, accessTypePull))
+	if err != nil {
+		return v1.Descriptor{}, err
+	}
+	defer res.Body.Close()
+
+	mt, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+
+	v := res.Header.Get("Docker-Content-Digest")
+	if v == "" {
+		if d, err := digest.Parse(ref); err == nil {
+			return v1.Descriptor{MediaType: mt, Digest: d, Size: res.ContentLength}, nil
+		}
+		return r.getManifestDescriptor(ctx, creds, name, ref)
+	}
+
+	d := digest.Digest(v)
+	if err := d.Validate(); err != nil {
+		return v1.Descriptor{}, err
+	}
+
+	return v1.Descriptor{MediaType: mt, Digest: d, Size: res.ContentLength}, nil
+}
+
+// manifestAccept is the value of the "Accept" header of requests to describe manifests.
+var manifestAccept = strings.Join([]string{v1.MediaTypeImageIndex, v1.MediaTypeImageManifest}, ", ")
+
+// getManifestDescriptor retrieves the manifest (or image index) associated with name/ref in the
+// registry, and returns a descriptor of it, computing the digest from the content where the
+// registry does not report it (see manifestDigest).
+func (r *ociRegistry) getManifestDescriptor(ctx context.Context, creds credentials, name, ref string) (v1.Descriptor, error) {
+	req, err := r.newRequest(ctx, http.MethodGet, manifestURL(name, ref), nil)
+	if err != nil {
+		return v1.Descriptor{}, err
+	}
+	req.Header.Set("Accept", manifestAccept)
+
+	res, err := r.doRequest(req, creds, withNamespaceAccess(name, accessTypePull))
+	if err != nil {
+		return v1.Descriptor{}, err
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(limitResponse(res.Body, maxManifestSize))
+	if errors.Is(err, ErrResponseTooLarge) {
+		return v1.Descriptor{}, fmt.Errorf("manifest exceeds maximum size of %d bytes: %w", maxManifestSize, err)
+	}
+	if err != nil {
+		return v1.Descriptor{}, err
+	}
+
+	d, err := manifestDigest(res, b, ref)
+	if err != nil {
+		return v1.Descriptor{}, err
+	}
+
+	mt, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+
+	return v1.Descriptor{MediaType: mt, Digest: d, Size: int64(len(b))}, nil
+}
+
+// deleteManifest deletes the manifest (or image index) with digest d from name in the registry,
+// along with any tags associated with it.
+func (r *ociRegistry) deleteManifest(ctx context.Context, creds credentials, name string, d digest.Digest) error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+
+	req, err := r.newRequest(ctx, http.MethodDelete, manifestURL(name, d.String()), nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.doRequest(req, creds, withNamespaceAccess(name, accessTypeDelete))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	return nil
+}
+
+// errTagDeleteUnsupported is returned when the registry does not support the deletion of tags.
+var errTagDeleteUnsupported = errors.New("tag deletion not supported by registry")
+
+// deleteTag deletes tag from name in the registry, without deleting the manifest (or image index)
+// it refers to. If the registry does not support the deletion of tags, errTagDeleteUnsupported is
+// returned.
+func (r *ociRegistry) deleteTag(ctx context.Context, creds credentials, name, tag string) error {
+	req, err := r.newRequest(ctx, http.MethodDelete, manifestURL(name, tag), nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.doRequest(req, creds, withNamespaceAccess(name, accessTypeDelete))
+	if err != nil {
+		// Registries that do not support tag deletion respond with 400 or 405.
+		var re *RegistryError
+		if errors.As(err, &re) && (re.StatusCode == http.StatusBadRequest || re.StatusCode == http.StatusMethodNotAllowed) {
+			return fmt.Errorf("%w: %w", errTagDeleteUnsupported, err)
+		}
+		return err
+	}
+	defer res.Body.Close()
+
+	return nil
+}
+
+// tagsReferencing returns the tags of name in the registry that refer to the manifest (or image
+// index) with digest d.
+func (r *ociRegistry) tagsReferencing(ctx context.Context, creds credentials, name string, d digest.Digest) ([]string, error) {
+	tags, err := r.listTags(ctx, creds, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []string
+	for _, tag := range tags {
+		td, err := r.resolveManifest(ctx, creds, name, tag)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving tag %v: %w", tag, err)
+		}
+		if td == d {
+			refs = append(refs, tag)
+		}
+	}
+	return refs, nil
 }
@@ -6,15 +6,23 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/opencontainers/go-digest"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	jsonresp "github.com/sylabs/json-resp"
 )
 
 func TestOciRegistryAuth(t *testing.T) {
@@ -105,6 +113,83 @@ func TestOciRegistryAuth(t *testing.T) {
 	}
 }
 
+func Test_accessOptionsScope(t *testing.T) {
+	tests := []struct {
+		name string
+		o    accessOptions
+		want string
+	}{
+		{"Pull", accessOptions{"entity/collection/container", []accessType{accessTypePull}}, "repository:entity/collection/container:pull"},
+		{"PullPush", accessOptions{"name", []accessType{accessTypePull, accessTypePush}}, "repository:name:pull,push"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.o.scope(); got != tt.want {
+				t.Errorf("got scope %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newOCIRegistryAdditionalScopes(t *testing.T) {
+	other := accessOptions{namespace: "other/collection/container", accessTypes: []accessType{accessTypePull, accessTypePush}}
+
+	var libScopes, tokenScopes []string
+
+	var reg *httptest.Server
+
+	reg = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokenScopes = r.URL.Query()["scope"]
+			if err := json.NewEncoder(w).Encode(map[string]string{"token": "anon"}); err != nil {
+				t.Errorf("error JSON encoding: %v", err)
+			}
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer anon" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%v/token",service="registry"`, reg.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if err := json.NewEncoder(w).Encode(map[string][]string{"tags": {"latest"}}); err != nil {
+			t.Errorf("error JSON encoding: %v", err)
+		}
+	}))
+	defer reg.Close()
+
+	lib := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		libScopes = r.URL.Query()["scope"]
+		if err := json.NewEncoder(w).Encode(map[string]string{"url": reg.URL}); err != nil {
+			t.Errorf("error JSON encoding: %v", err)
+		}
+	}))
+	defer lib.Close()
+
+	c, err := NewClient(&Config{BaseURL: lib.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	r, creds, name, err := c.newOCIRegistry(context.Background(), "entity/collection/container", []accessType{accessTypePull}, other)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := r.listTags(context.Background(), creds, name); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := libScopes, []string{"repository:other/collection/container:pull,push"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got library scopes %v, want %v", got, want)
+	}
+	if got, want := tokenScopes, []string{"repository:entity/collection/container:pull", "repository:other/collection/container:pull,push"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got token scopes %v, want %v", got, want)
+	}
+}
+
 func Test_getManifestFromIndex(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -167,3 +252,834 @@ func Test_getManifestFromIndex(t *testing.T) {
 		})
 	}
 }
+
+func Test_uploadBlobCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const sessionPath = "/v2/name/blobs/uploads/session"
+
+	var deleted bool
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/name/blobs/uploads/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Location", sessionPath)
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc(sessionPath, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPatch:
+			// Cancel the operation once the chunk is received, and wait for the client to disconnect.
+			if _, err := io.Copy(io.Discard, r.Body); err != nil {
+				t.Errorf("error reading request body: %v", err)
+			}
+			cancel()
+			<-r.Context().Done()
+		case http.MethodDelete:
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected method %v", r.Method)
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &ociRegistry{baseURL: u, httpClient: http.DefaultClient, logger: testLogger}
+
+	_, _, err = r.uploadBlob(ctx, &bearerTokenCredentials{authToken: "token"}, "name", 10, strings.NewReader("0123456789"))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+
+	if !deleted {
+		t.Error("blob upload session not cancelled")
+	}
+}
+
+func TestRegistryCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		creds    *RegistryCredentials
+		wantAuth string
+	}{
+		{"Issued", nil, "Bearer xxx"},
+		{"Basic", &RegistryCredentials{Username: "user", Password: "pass"}, "Basic dXNlcjpwYXNz"},
+		{"Token", &RegistryCredentials{Token: "static"}, "Bearer static"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var srv *httptest.Server
+
+			srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v1/oci-redirect":
+					response := struct {
+						Token       string `json:"token"`
+						RegistryURI string `json:"url"`
+					}{
+						Token:       "xxx",
+						RegistryURI: srv.URL,
+					}
+					if err := json.NewEncoder(w).Encode(&response); err != nil {
+						t.Errorf("error JSON encoding: %v", err)
+					}
+
+				case "/v2/entity/collection/container/tags/list":
+					if got := r.Header.Get("Authorization"); got != tt.wantAuth {
+						w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					if err := json.NewEncoder(w).Encode(map[string][]string{"tags": {"latest"}}); err != nil {
+						t.Errorf("error JSON encoding: %v", err)
+					}
+
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer srv.Close()
+
+			u, err := url.Parse(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := &Config{BaseURL: srv.URL, Logger: testLogger}
+			if tt.creds != nil {
+				cfg.RegistryCredentials = map[string]RegistryCredentials{u.Host: *tt.creds}
+			}
+
+			c, err := NewClient(cfg)
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			if _, err := c.ListTags(context.Background(), "entity/collection/container"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestAnonymousRegistryAccess(t *testing.T) {
+	tests := []struct {
+		name          string
+		redirect      bool // library grants direct access, without a token
+		anonymousURL  bool // AnonymousRegistryURL configured
+		allowAnon     bool // registry permits anonymous access
+		wantErr       bool
+		wantFallback  bool // image download falls back to library
+		wantNoRequest bool // registry not contacted
+	}{
+		{name: "NoToken", redirect: true, allowAnon: true},
+		{name: "RedirectFailed", anonymousURL: true, allowAnon: true},
+		{name: "Denied", anonymousURL: true, wantErr: true, wantFallback: true},
+		{name: "NotConfigured", wantErr: true, wantFallback: true, wantNoRequest: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested bool
+
+			var reg *httptest.Server
+
+			reg = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requested = true
+
+				if r.URL.Path == "/token" {
+					if got, want := r.URL.Query().Get("scope"), "repository:entity/collection/container:pull"; got != want {
+						t.Errorf("got scope %v, want %v", got, want)
+					}
+					if got, want := r.URL.Query().Get("service"), "registry"; got != want {
+						t.Errorf("got service %v, want %v", got, want)
+					}
+					if err := json.NewEncoder(w).Encode(map[string]string{"token": "anon"}); err != nil {
+						t.Errorf("error JSON encoding: %v", err)
+					}
+					return
+				}
+
+				if r.Header.Get("Authorization") != "Bearer anon" {
+					if tt.allowAnon {
+						w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%v/token",service="registry"`, reg.URL))
+					} else {
+						w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+					}
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				if r.URL.Path == "/v2/entity/collection/container/tags/list" {
+					if err := json.NewEncoder(w).Encode(map[string][]string{"tags": {"latest"}}); err != nil {
+						t.Errorf("error JSON encoding: %v", err)
+					}
+					return
+				}
+				w.WriteHeader(http.StatusNotFound)
+			}))
+			defer reg.Close()
+
+			lib := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/oci-redirect" || !tt.redirect {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				response := struct {
+					RegistryURI string `json:"url"`
+				}{
+					RegistryURI: reg.URL,
+				}
+				if err := json.NewEncoder(w).Encode(&response); err != nil {
+					t.Errorf("error JSON encoding: %v", err)
+				}
+			}))
+			defer lib.Close()
+
+			cfg := &Config{BaseURL: lib.URL, Logger: testLogger}
+			if tt.anonymousURL {
+				cfg.AnonymousRegistryURL = reg.URL
+			}
+
+			c, err := NewClient(cfg)
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			r, creds, name, err := c.newOCIRegistry(context.Background(), "entity/collection/container", []accessType{accessTypePull})
+			if err == nil {
+				_, err = r.listTags(context.Background(), creds, name)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			if tt.wantFallback {
+				_, _, _, _, err = c.ociImageBlob(context.Background(), "amd64", "entity/collection/container", "latest")
+				if !errors.Is(err, errOCIDownloadNotSupported) {
+					t.Errorf("got error %v, want %v", err, errOCIDownloadNotSupported)
+				}
+			}
+
+			if got, want := requested, !tt.wantNoRequest; got != want {
+				t.Errorf("got registry requested %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_getImageDetails(t *testing.T) {
+	layer := v1.Descriptor{
+		MediaType: mediaTypeSIFLayer,
+		Digest:    digest.FromString("image"),
+		Size:      5,
+	}
+
+	cb, err := json.Marshal(imageConfig{Architecture: "amd64", OS: "linux", RootFS: layer.Digest})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sifConfig := v1.Descriptor{MediaType: mediaTypeSIFConfig, Digest: digest.FromBytes(cb), Size: int64(len(cb))}
+
+	tests := []struct {
+		name     string
+		manifest v1.Manifest
+		arch     string
+		wantErr  bool
+	}{
+		{
+			name:     "ConfigMediaType",
+			manifest: v1.Manifest{Config: sifConfig, Layers: []v1.Descriptor{layer}},
+			arch:     "amd64",
+		},
+		{
+			name:     "ArtifactType",
+			manifest: v1.Manifest{ArtifactType: artifactTypeSIF, Config: sifConfig, Layers: []v1.Descriptor{layer}},
+			arch:     "amd64",
+		},
+		{
+			name:     "ArtifactTypeEmptyConfig",
+			manifest: v1.Manifest{ArtifactType: artifactTypeSIF, Config: v1.DescriptorEmptyJSON, Layers: []v1.Descriptor{layer}},
+			arch:     "amd64",
+		},
+		{
+			name:     "ArchMismatch",
+			manifest: v1.Manifest{ArtifactType: artifactTypeSIF, Config: sifConfig, Layers: []v1.Descriptor{layer}},
+			arch:     "arm64",
+			wantErr:  true,
+		},
+		{
+			name:     "UnexpectedArtifactType",
+			manifest: v1.Manifest{ArtifactType: "application/vnd.example", Config: sifConfig, Layers: []v1.Descriptor{layer}},
+			arch:     "amd64",
+			wantErr:  true,
+		},
+		{
+			name:     "EmptyConfigNoArtifactType",
+			manifest: v1.Manifest{Config: v1.DescriptorEmptyJSON, Layers: []v1.Descriptor{layer}},
+			arch:     "amd64",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mb, err := json.Marshal(tt.manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v2/name/manifests/tag":
+					if r.Header.Get("Accept") != v1.MediaTypeImageManifest {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					w.Header().Set("Content-Type", v1.MediaTypeImageManifest)
+					w.Header().Set("Docker-Content-Digest", digest.FromBytes(mb).String())
+					_, _ = w.Write(mb)
+				case "/v2/name/blobs/" + sifConfig.Digest.String():
+					_, _ = w.Write(cb)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer srv.Close()
+
+			u, err := url.Parse(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r := &ociRegistry{baseURL: u, httpClient: http.DefaultClient, logger: testLogger}
+
+			d, _, err := r.getImageDetails(context.Background(), nil, "name", "tag", tt.arch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && d.Digest != layer.Digest {
+				t.Errorf("got digest %v, want %v", d.Digest, layer.Digest)
+			}
+		})
+	}
+}
+
+func Test_uploadImageManifestArtifactType(t *testing.T) {
+	var m v1.Manifest
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+			t.Errorf("error decoding manifest: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &ociRegistry{baseURL: u, httpClient: http.DefaultClient, logger: testLogger}
+
+	if _, err := r.uploadImageManifest(context.Background(), nil, "name", "tag", digest.FromString("config"), digest.FromString("image"), 6, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := m.ArtifactType, artifactTypeSIF; got != want {
+		t.Errorf("got artifact type %v, want %v", got, want)
+	}
+	if got, want := m.Config.MediaType, mediaTypeSIFConfig; got != want {
+		t.Errorf("got config media type %v, want %v", got, want)
+	}
+}
+
+func Test_downloadManifestContentType(t *testing.T) {
+	manifest := []byte(`{"schemaVersion":2,"config":{"mediaType":"` + mediaTypeSIFConfig + `"},"layers":[]}`)
+	typedManifest := []byte(`{"schemaVersion":2,"mediaType":"` + v1.MediaTypeImageManifest + `","config":{},"layers":[]}`)
+	index := []byte(`{"schemaVersion":2,"manifests":[]}`)
+
+	tests := []struct {
+		name        string
+		lenient     bool
+		contentType string
+		body        []byte
+		noDigest    bool
+		wantErr     bool
+	}{
+		{"StrictExact", false, v1.MediaTypeImageManifest, manifest, false, false},
+		{"StrictCharset", false, v1.MediaTypeImageManifest + "; charset=utf-8", manifest, false, true},
+		{"StrictJSON", false, "application/json", manifest, false, true},
+		{"StrictNoDigest", false, v1.MediaTypeImageManifest, manifest, true, false},
+		{"LenientExact", true, v1.MediaTypeImageManifest, manifest, false, false},
+		{"LenientCharset", true, v1.MediaTypeImageManifest + "; charset=utf-8", manifest, false, false},
+		{"LenientJSONSniffStructure", true, "application/json", manifest, false, false},
+		{"LenientJSONSniffMediaType", true, "application/json", typedManifest, false, false},
+		{"LenientJSONIndex", true, "application/json", index, false, true},
+		{"LenientNoDigest", true, "application/json", manifest, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				if !tt.noDigest {
+					w.Header().Set("Docker-Content-Digest", digest.FromBytes(tt.body).String())
+				}
+				_, _ = w.Write(tt.body)
+			}))
+			defer srv.Close()
+
+			u, err := url.Parse(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r := &ociRegistry{baseURL: u, httpClient: http.DefaultClient, logger: testLogger, lenientContentType: tt.lenient}
+
+			d, _, err := r.downloadV1Manifest(context.Background(), nil, "name", "tag")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			if want := digest.FromBytes(tt.body); err == nil && d != want {
+				t.Errorf("got digest %v, want %v", d, want)
+			}
+		})
+	}
+}
+
+func Test_metadataSizeLimit(t *testing.T) {
+	// Oversized objects consist of valid JSON followed by whitespace padding.
+	manifest := []byte(`{"schemaVersion":2,"config":{"mediaType":"` + mediaTypeSIFConfig + `"},"layers":[]}`)
+	largeManifest := append(bytes.Repeat([]byte(" "), maxManifestSize), manifest...)
+
+	config := []byte(`{"architecture":"amd64","os":"linux","rootfs":"` + digest.FromString("image").String() + `"}`)
+	largeConfig := append(bytes.Repeat([]byte(" "), maxImageConfigSize), config...)
+
+	tests := []struct {
+		name    string
+		body    []byte
+		fetch   func(*ociRegistry, []byte) error
+		wantErr error
+	}{
+		{
+			name: "Manifest",
+			body: manifest,
+			fetch: func(r *ociRegistry, _ []byte) error {
+				_, _, err := r.downloadV1Manifest(context.Background(), nil, "name", "tag")
+				return err
+			},
+		},
+		{
+			name: "ManifestTooLarge",
+			body: largeManifest,
+			fetch: func(r *ociRegistry, _ []byte) error {
+				_, _, err := r.downloadV1Manifest(context.Background(), nil, "name", "tag")
+				return err
+			},
+			wantErr: ErrResponseTooLarge,
+		},
+		{
+			name: "Config",
+			body: config,
+			fetch: func(r *ociRegistry, b []byte) error {
+				_, err := r.getImageConfig(context.Background(), nil, "name", digest.FromBytes(b))
+				return err
+			},
+		},
+		{
+			name: "ConfigTooLarge",
+			body: largeConfig,
+			fetch: func(r *ociRegistry, b []byte) error {
+				_, err := r.getImageConfig(context.Background(), nil, "name", digest.FromBytes(b))
+				return err
+			},
+			wantErr: ErrResponseTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", v1.MediaTypeImageManifest)
+				w.Header().Set("Docker-Content-Digest", digest.FromBytes(tt.body).String())
+				_, _ = w.Write(tt.body)
+			}))
+			defer srv.Close()
+
+			u, err := url.Parse(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r := &ociRegistry{baseURL: u, httpClient: http.DefaultClient, logger: testLogger}
+
+			if err := tt.fetch(r, tt.body); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_describeManifest(t *testing.T) {
+	body := []byte(`{"schemaVersion":2,"mediaType":"` + v1.MediaTypeImageIndex + `","manifests":[]}`)
+	d := digest.FromBytes(body)
+
+	tests := []struct {
+		name       string
+		ref        string
+		headDigest bool // report digest in response to HEAD
+		wantGets   int
+	}{
+		{"HeadDigest", "latest", true, 0},
+		{"NoHeadDigest", "latest", false, 1},
+		{"DigestRef", d.String(), false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gets int
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", v1.MediaTypeImageIndex)
+				w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+
+				if r.Method == http.MethodHead {
+					if tt.headDigest {
+						w.Header().Set("Docker-Content-Digest", d.String())
+					}
+					return
+				}
+
+				gets++
+				_, _ = w.Write(body)
+			}))
+			defer srv.Close()
+
+			u, err := url.Parse(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r := &ociRegistry{baseURL: u, httpClient: http.DefaultClient, logger: testLogger}
+
+			desc, err := r.describeManifest(context.Background(), nil, "name", tt.ref)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := v1.Descriptor{MediaType: v1.MediaTypeImageIndex, Digest: d, Size: int64(len(body))}
+			if !reflect.DeepEqual(desc, want) {
+				t.Errorf("got descriptor %+v, want %+v", desc, want)
+			}
+			if got, want := gets, tt.wantGets; got != want {
+				t.Errorf("got %v GET requests, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_manifestDigest(t *testing.T) {
+	b := []byte(`{"schemaVersion":2}`)
+	d := digest.FromBytes(b)
+	other := digest.FromString("other")
+
+	tests := []struct {
+		name    string
+		header  string
+		ref     string
+		want    digest.Digest
+		wantErr error
+	}{
+		{"Header", d.String(), "tag", d, nil},
+		{"NoHeader", "", "tag", d, nil},
+		{"InvalidHeader", "sha256:bad", "tag", "", digest.ErrDigestInvalidLength},
+		{"DigestRef", "", d.String(), d, nil},
+		{"DigestRefHeaderMismatch", other.String(), d.String(), d, nil},
+		{"DigestRefMismatch", d.String(), other.String(), "", errDigestNotVerified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &http.Response{Header: http.Header{}}
+			if tt.header != "" {
+				res.Header.Set("Docker-Content-Digest", tt.header)
+			}
+
+			got, err := manifestDigest(res, b, tt.ref)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got digest %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_registryErrorFromResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		want        RegistryError
+		wantMessage string
+	}{
+		{
+			name:        "Error",
+			body:        `{"errors":[{"code":"MANIFEST_UNKNOWN","message":"manifest unknown","detail":{"Tag":"latest"}}]}`,
+			want:        RegistryError{StatusCode: http.StatusNotFound, Code: "MANIFEST_UNKNOWN", Message: "manifest unknown"},
+			wantMessage: "registry returned an error: 404: MANIFEST_UNKNOWN: manifest unknown",
+		},
+		{
+			name:        "CodeOnly",
+			body:        `{"errors":[{"code":"NAME_UNKNOWN"},{"code":"MANIFEST_UNKNOWN"}]}`,
+			want:        RegistryError{StatusCode: http.StatusNotFound, Code: "NAME_UNKNOWN"},
+			wantMessage: "registry returned an error: 404: NAME_UNKNOWN",
+		},
+		{
+			name:        "NoErrors",
+			body:        `{"errors":[]}`,
+			want:        RegistryError{StatusCode: http.StatusNotFound},
+			wantMessage: "registry returned an error: 404",
+		},
+		{
+			name:        "NotJSON",
+			body:        "404 page not found",
+			want:        RegistryError{StatusCode: http.StatusNotFound},
+			wantMessage: "registry returned an error: 404",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(tt.body))}
+
+			got := registryErrorFromResponse(res)
+			if *got != tt.want {
+				t.Errorf("got error %+v, want %+v", *got, tt.want)
+			}
+			if got, want := got.Error(), tt.wantMessage; got != want {
+				t.Errorf("got message %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func Test_registryNamespace(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/v2/entity/collection/container/manifests/latest", "entity/collection/container"},
+		{"/v2/name/blobs/sha256:0123", "name"},
+		{"/v2/name/blobs/uploads/session", "name"},
+		{"/v2/name/tags/list", "name"},
+		{"/v2/name/referrers/sha256:0123", "name"},
+		{"/v2/_catalog", ""},
+		{"/v2/", ""},
+		{"/v1/oci-redirect", ""},
+	}
+
+	for _, tt := range tests {
+		if got := registryNamespace(tt.path); got != tt.want {
+			t.Errorf("%v: got namespace %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func Test_doRequestPermissionDenied(t *testing.T) {
+	tests := []struct {
+		name         string
+		unauthorized bool // the first request is rejected with HTTP status 401
+		wantRequests int
+	}{
+		{"Forbidden", false, 1},
+		{"UnauthorizedThenForbidden", true, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				requests++
+				if tt.unauthorized && requests == 1 {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = io.WriteString(w, `{"errors":[{"code":"DENIED","message":"requested access to the resource is denied"}]}`)
+			}))
+			defer srv.Close()
+
+			u, err := url.Parse(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r := &ociRegistry{baseURL: u, httpClient: http.DefaultClient, logger: testLogger}
+
+			req, err := r.newRequest(context.Background(), http.MethodGet, manifestURL("entity/collection/container", "latest"), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = r.doRequest(req, &bearerTokenCredentials{authToken: "token"}, withNamespaceAccess("entity/collection/container", accessTypePull))
+			if !errors.Is(err, ErrPermissionDenied) {
+				t.Fatalf("got error %v, want %v", err, ErrPermissionDenied)
+			}
+
+			var pde *PermissionDeniedError
+			if !errors.As(err, &pde) {
+				t.Fatalf("got error %v, want permission denied error", err)
+			}
+			if got, want := pde.Namespace, "entity/collection/container"; got != want {
+				t.Errorf("got namespace %v, want %v", got, want)
+			}
+
+			var re *RegistryError
+			if !errors.As(err, &re) || re.Code != "DENIED" {
+				t.Errorf("got error %v, want registry error with code DENIED", err)
+			}
+
+			if got, want := pde.Error(), "permission denied on namespace entity/collection/container: registry returned an error: 403: DENIED: requested access to the resource is denied"; got != want {
+				t.Errorf("got message %q, want %q", got, want)
+			}
+
+			if got, want := requests, tt.wantRequests; got != want {
+				t.Errorf("got %v requests, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestStrictRegistryAccess(t *testing.T) {
+	tests := []struct {
+		name         string
+		code         int
+		strict       bool
+		wantFallback bool
+	}{
+		{"NotSupported", http.StatusNotFound, false, true},
+		{"NotSupportedStrict", http.StatusNotFound, true, true},
+		{"Forbidden", http.StatusForbidden, false, true},
+		{"ForbiddenStrict", http.StatusForbidden, true, false},
+		{"ServerError", http.StatusInternalServerError, false, true},
+		{"ServerErrorStrict", http.StatusInternalServerError, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got, want := r.URL.Path, "/v1/oci-redirect"; got != want {
+					t.Errorf("got path %v, want %v", got, want)
+				}
+				if err := jsonresp.WriteError(w, "access denied", tt.code); err != nil {
+					t.Errorf("error writing JSON error: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger, StrictRegistryAccess: tt.strict})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			_, _, _, err = c.newOCIRegistry(context.Background(), "entity/collection/container", []accessType{accessTypePull})
+			if err == nil {
+				t.Fatal("unexpected success")
+			}
+			if got, want := errors.Is(err, errOCIDownloadNotSupported), tt.wantFallback; got != want {
+				t.Errorf("got fallback %v, want %v (error %v)", got, want, err)
+			}
+			if !tt.wantFallback {
+				var je *jsonresp.Error
+				if !errors.As(err, &je) || je.Code != tt.code {
+					t.Errorf("got error %v, want server error with code %v", err, tt.code)
+				}
+			}
+		})
+	}
+}
+
+func Test_ociUploadImageVerifyBlobs(t *testing.T) {
+	image := testPartitionSIF(t, "amd64")
+	hash := strings.Replace(digest.FromBytes(image).String(), ":", ".", 1)
+
+	tests := []struct {
+		name        string
+		verifyBlobs bool
+		gcAfter     int // number of blob existence checks after which blobs are removed
+		wantErr     bool
+		wantIndexes []string
+	}{
+		{"NoVerify", false, 0, false, []string{"latest", "v1"}},
+		{"Retained", true, -1, false, []string{"latest", "v1"}},
+		{"RemovedBeforeManifest", true, 1, true, nil},
+		{"RemovedBeforeTag", true, 4, true, []string{"latest"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var checks int
+
+			srv := newTestOCIPushServer(t, func() bool {
+				checks++
+				return tt.gcAfter < 0 || checks <= tt.gcAfter
+			})
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			o := UploadOptions{SkipDedup: true, VerifyBlobs: tt.verifyBlobs}
+
+			res, err := c.ociUploadImage(context.Background(), bytes.NewReader(image), int64(len(image)), "name", "amd64", []string{"latest", "v1"}, "", nil, hash, o, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				var me *MissingBlobError
+				if !errors.As(err, &me) {
+					t.Errorf("got error %v, want missing blob error", err)
+				}
+				if !errors.Is(err, ErrNotFound) {
+					t.Errorf("got error %v, want %v", err, ErrNotFound)
+				}
+			}
+
+			var got []string
+			if res != nil {
+				for tag := range res.Indexes {
+					got = append(got, tag)
+				}
+			}
+			if len(got) != len(tt.wantIndexes) {
+				t.Fatalf("got indexes for tags %v, want %v", got, tt.wantIndexes)
+			}
+			for _, tag := range tt.wantIndexes {
+				if _, ok := res.Indexes[tag]; !ok {
+					t.Errorf("missing index for tag %v", tag)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// orderedWriter implements io.WriterAt, delivering content to an io.Writer strictly in order.
+// Content written ahead of the next offset to be delivered is buffered until the preceding
+// content has been written. Content written behind the next offset (ie. when a part is
+// re-attempted) has already been delivered, and is discarded.
+type orderedWriter struct {
+	mu       sync.Mutex
+	w        io.Writer
+	window   int64            // bytes ahead of next that parts may extend to
+	next     int64            // offset of next byte to deliver
+	pending  map[int64][]byte // buffered content, keyed by offset
+	advanced chan struct{}    // closed when next advances
+	err      error
+}
+
+// newOrderedWriter returns an orderedWriter that delivers content to w. Parts that end more than
+// window bytes beyond the next offset to be delivered are held back by reserve.
+func newOrderedWriter(w io.Writer, window int64) *orderedWriter {
+	return &orderedWriter{
+		w:        w,
+		window:   window,
+		pending:  make(map[int64][]byte),
+		advanced: make(chan struct{}),
+	}
+}
+
+// reserve blocks until the part consisting of the bytes from start to end (inclusive) may be
+// written without exceeding the reorder window, or ctx is done. The part containing the next
+// offset to be delivered is always admitted, so that the download progresses.
+func (ow *orderedWriter) reserve(ctx context.Context, start, end int64) error {
+	for {
+		ow.mu.Lock()
+		if err := ow.err; err != nil {
+			ow.mu.Unlock()
+			return err
+		}
+		if start <= ow.next || end-ow.next < ow.window {
+			ow.mu.Unlock()
+			return nil
+		}
+		advanced := ow.advanced
+		ow.mu.Unlock()
+
+		select {
+		case <-advanced:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// WriteAt writes p at offset off, delivering any content that is now contiguous with the content
+// already delivered.
+func (ow *orderedWriter) WriteAt(p []byte, off int64) (int, error) {
+	ow.mu.Lock()
+	defer ow.mu.Unlock()
+
+	if ow.err != nil {
+		return 0, ow.err
+	}
+
+	if end := off + int64(len(p)); end <= ow.next {
+		// Already delivered.
+		return len(p), nil
+	} else if off > ow.next {
+		ow.pending[off] = append([]byte(nil), p...)
+		return len(p), nil
+	}
+
+	ow.pending[off] = p
+	err := ow.flush()
+
+	// p must not be retained beyond the call.
+	delete(ow.pending, off)
+
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// flush delivers buffered content that is contiguous with the content already delivered. The
+// caller must hold ow.mu.
+func (ow *orderedWriter) flush() error {
+	start := ow.next
+
+	for found := true; found; {
+		found = false
+
+		for off, b := range ow.pending {
+			if off > ow.next {
+				continue
+			}
+			found = true
+
+			if end := off + int64(len(b)); end > ow.next {
+				if _, err := ow.w.Write(b[ow.next-off:]); err != nil {
+					ow.err = fmt.Errorf("error writing image content: %w", err)
+					close(ow.advanced)
+					return ow.err
+				}
+				ow.next = end
+			}
+			delete(ow.pending, off)
+		}
+	}
+
+	if ow.next > start {
+		close(ow.advanced)
+		ow.advanced = make(chan struct{})
+	}
+	return nil
+}
+
+// close returns an error if content remains buffered that could not be delivered, because the
+// content preceding it was never written.
+func (ow *orderedWriter) close() error {
+	ow.mu.Lock()
+	defer ow.mu.Unlock()
+
+	if ow.err != nil {
+		return ow.err
+	}
+	if len(ow.pending) > 0 {
+		return fmt.Errorf("image content incomplete after %d byte(s)", ow.next)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOrderedWriter(t *testing.T) {
+	tests := []struct {
+		name    string
+		writes  []string // "offset:content"
+		want    string
+		wantErr bool
+	}{
+		{"InOrder", []string{"0:abc", "3:def", "6:gh"}, "abcdefgh", false},
+		{"Reversed", []string{"6:gh", "3:def", "0:abc"}, "abcdefgh", false},
+		{"Rewrite", []string{"0:abc", "0:ab", "3:def"}, "abcdef", false},
+		{"Overlapping", []string{"4:ef", "2:cdef", "0:ab"}, "abcdef", false},
+		{"Gap", []string{"0:abc", "6:gh"}, "abc", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			ow := newOrderedWriter(&b, 1024)
+
+			for _, w := range tt.writes {
+				var off int64
+				var s string
+				if _, err := fmt.Sscanf(strings.Replace(w, ":", " ", 1), "%d %s", &off, &s); err != nil {
+					t.Fatalf("bad write %q: %v", w, err)
+				}
+
+				if n, err := ow.WriteAt([]byte(s), off); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				} else if n != len(s) {
+					t.Fatalf("got %v byte(s) written, want %v", n, len(s))
+				}
+			}
+
+			if err := ow.close(); (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			if got, want := b.String(), tt.want; got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) { return 0, io.ErrClosedPipe }
+
+func TestOrderedWriterError(t *testing.T) {
+	ow := newOrderedWriter(errWriter{}, 1024)
+
+	if _, err := ow.WriteAt([]byte("abc"), 0); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("got error %v, want %v", err, io.ErrClosedPipe)
+	}
+	if err := ow.reserve(context.Background(), 100, 200); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("got error %v, want %v", err, io.ErrClosedPipe)
+	}
+	if err := ow.close(); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("got error %v, want %v", err, io.ErrClosedPipe)
+	}
+}
+
+func TestOrderedWriterReserve(t *testing.T) {
+	var b bytes.Buffer
+
+	ow := newOrderedWriter(&b, 6)
+
+	// Parts within the window, and the part containing the next offset, are admitted.
+	if err := ow.reserve(context.Background(), 0, 99); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ow.reserve(context.Background(), 3, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Parts beyond the window block until the window advances.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := ow.reserve(ctx, 6, 8); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- ow.reserve(context.Background(), 6, 8)
+	}()
+
+	if _, err := ow.WriteAt([]byte("abc"), 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestMultipartDownloadOrdered(t *testing.T) {
+	const src = "123456789012345678901234567890"
+	size := int64(len(src))
+
+	var inFlight, maxInFlight atomic.Int64
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, end := parseRangeHeader(t, r.Header.Get("Range"))
+
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		// Deliver later parts first, to exercise reordering.
+		time.Sleep(time.Duration(size-start) * time.Millisecond)
+
+		w.Header().Set("Content-Length", fmt.Sprint(end-start+1))
+		w.WriteHeader(http.StatusPartialContent)
+
+		if _, err := io.WriteString(w, src[start:end+1]); err != nil {
+			t.Errorf("unexpected error writing http response: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	var b bytes.Buffer
+
+	spec := &Downloader{Concurrency: 4, PartSize: 3, ReorderBufferSize: 6}
+
+	ow := newOrderedWriter(&b, spec.reorderBufferSize())
+
+	if err := c.multipartDownload(context.Background(), &blobURL{u: srv.URL}, nil, ow, size, spec, &NoopProgressBar{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ow.close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := b.String(), src; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// A window of 6 bytes admits the part at the next offset, and one further part.
+	if got := maxInFlight.Load(); got > 2 {
+		t.Errorf("got %v parts in flight, want at most 2", got)
+	}
+}
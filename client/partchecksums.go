@@ -0,0 +1,114 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import "sync"
+
+// maxPartChecksumImages is the number of images for which part checksums are cached.
+const maxPartChecksumImages = 4
+
+// partChecksumKey identifies a part of an image by its offset and size.
+type partChecksumKey struct {
+	offset int64
+	size   int64
+}
+
+// partChecksumCache caches the checksums of the parts of uploaded images, keyed by the SHA256
+// checksum of the image, and the offset and size of each part. When the upload of an image is
+// re-attempted (ie. after a multipart upload is aborted), the checksums of parts are not
+// recomputed, avoiding re-reading the image to do so. Methods may be called concurrently, and are
+// no-ops on a nil *partChecksumCache.
+type partChecksumCache struct {
+	mu     sync.Mutex
+	images map[string]map[partChecksumKey]checksums
+	order  []string // images, in the order they were added
+}
+
+// newPartChecksumCache returns an empty cache.
+func newPartChecksumCache() *partChecksumCache {
+	return &partChecksumCache{images: make(map[string]map[partChecksumKey]checksums)}
+}
+
+// get returns the cached checksums of the part of the image with SHA256 checksum image, located at
+// offset and of size bytes, if checksums for each of algs are cached.
+func (pc *partChecksumCache) get(image string, offset, size int64, algs []ChecksumAlgorithm) (checksums, bool) {
+	if pc == nil {
+		return nil, false
+	}
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	cached, ok := pc.images[image][partChecksumKey{offset, size}]
+	if !ok {
+		return nil, false
+	}
+
+	sums := make(checksums, len(algs))
+	for _, alg := range algs {
+		sum, ok := cached[alg]
+		if !ok {
+			return nil, false
+		}
+		sums[alg] = sum
+	}
+	return sums, true
+}
+
+// put caches the checksums sums of the part of the image with SHA256 checksum image, located at
+// offset and of size bytes. If the checksums of too many images are cached, those of the image
+// added first are discarded.
+func (pc *partChecksumCache) put(image string, offset, size int64, sums checksums) {
+	if pc == nil || image == "" {
+		return
+	}
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	parts, ok := pc.images[image]
+	if !ok {
+		if len(pc.order) == maxPartChecksumImages {
+			delete(pc.images, pc.order[0])
+			pc.order = pc.order[1:]
+		}
+
+		parts = make(map[partChecksumKey]checksums)
+		pc.images[image] = parts
+		pc.order = append(pc.order, image)
+	}
+
+	key := partChecksumKey{offset, size}
+
+	merged := make(checksums, len(parts[key])+len(sums))
+	for alg, sum := range parts[key] {
+		merged[alg] = sum
+	}
+	for alg, sum := range sums {
+		merged[alg] = sum
+	}
+	parts[key] = merged
+}
+
+// forget discards the cached checksums of the image with SHA256 checksum image, once it has been
+// uploaded.
+func (pc *partChecksumCache) forget(image string) {
+	if pc == nil {
+		return
+	}
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if _, ok := pc.images[image]; !ok {
+		return
+	}
+	delete(pc.images, image)
+
+	for i, img := range pc.order {
+		if img == image {
+			pc.order = append(pc.order[:i], pc.order[i+1:]...)
+			break
+		}
+	}
+}
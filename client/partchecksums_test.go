@@ -0,0 +1,146 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func TestPartChecksumCache(t *testing.T) {
+	// Methods must be safe to call on a nil cache.
+	var nilCache *partChecksumCache
+	nilCache.put("image", 0, 1, checksums{ChecksumSHA256: "sum"})
+	if _, ok := nilCache.get("image", 0, 1, []ChecksumAlgorithm{ChecksumSHA256}); ok {
+		t.Error("unexpected cached checksums")
+	}
+	nilCache.forget("image")
+
+	pc := newPartChecksumCache()
+	pc.put("image", 0, 10, checksums{ChecksumSHA256: "sha256"})
+	pc.put("image", 0, 10, checksums{ChecksumMD5: "md5"})
+
+	tests := []struct {
+		name   string
+		image  string
+		offset int64
+		size   int64
+		algs   []ChecksumAlgorithm
+		want   checksums
+		wantOK bool
+	}{
+		{"SHA256", "image", 0, 10, []ChecksumAlgorithm{ChecksumSHA256}, checksums{ChecksumSHA256: "sha256"}, true},
+		{"Merged", "image", 0, 10, []ChecksumAlgorithm{ChecksumSHA256, ChecksumMD5}, checksums{ChecksumSHA256: "sha256", ChecksumMD5: "md5"}, true},
+		{"MissingAlgorithm", "image", 0, 10, []ChecksumAlgorithm{ChecksumCRC32C}, nil, false},
+		{"OtherOffset", "image", 10, 10, []ChecksumAlgorithm{ChecksumSHA256}, nil, false},
+		{"OtherSize", "image", 0, 5, []ChecksumAlgorithm{ChecksumSHA256}, nil, false},
+		{"OtherImage", "other", 0, 10, []ChecksumAlgorithm{ChecksumSHA256}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := pc.get(tt.image, tt.offset, tt.size, tt.algs)
+			if ok != tt.wantOK {
+				t.Fatalf("got ok %v, want %v", ok, tt.wantOK)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got checksums %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("Forget", func(t *testing.T) {
+		pc.forget("image")
+		if _, ok := pc.get("image", 0, 10, []ChecksumAlgorithm{ChecksumSHA256}); ok {
+			t.Error("unexpected cached checksums")
+		}
+	})
+
+	t.Run("Evict", func(t *testing.T) {
+		for i := 0; i <= maxPartChecksumImages; i++ {
+			pc.put(fmt.Sprint(i), 0, 10, checksums{ChecksumSHA256: "sha256"})
+		}
+
+		if _, ok := pc.get("0", 0, 10, []ChecksumAlgorithm{ChecksumSHA256}); ok {
+			t.Error("oldest image not evicted")
+		}
+		if _, ok := pc.get(fmt.Sprint(maxPartChecksumImages), 0, 10, []ChecksumAlgorithm{ChecksumSHA256}); !ok {
+			t.Error("newest image evicted")
+		}
+	})
+}
+
+func Test_multipartUploadPartCachedChecksums(t *testing.T) {
+	const (
+		imageID = "5cb9c34d7d960d82f5f5bc55"
+		sha256  = "84d89877f0d4041efb6bf91a16f0248f2fd573e6af05c19f96bedb9f882f7882"
+	)
+
+	var gotRequests []UploadImagePartRequest
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/v2/imagefile/"+imageID+"/_multipart", func(w http.ResponseWriter, r *http.Request) {
+		var req UploadImagePartRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("error decoding request: %v", err)
+		}
+		gotRequests = append(gotRequests, req)
+
+		response := UploadImagePart{PresignedURL: srv.URL + "/s3/part"}
+		if err := jsonresp.WriteResponse(w, &response, http.StatusOK); err != nil {
+			t.Errorf("error writing JSON response: %v", err)
+		}
+	})
+	mux.HandleFunc("/s3/part", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("ETag", "etag")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	c, err := NewClient(&Config{AuthToken: testToken, BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	store := objectStore{kind: ObjectStoreS3, s3Compliant: true}
+
+	// The first upload computes the part checksum, and the second uses the cached checksum. The
+	// content of the second upload differs, so that use of the cached checksum is evident.
+	for _, content := range []string{"0123456789", "9876543210"} {
+		r := strings.NewReader(content)
+
+		m := &uploadManager{
+			Source:   r,
+			Size:     r.Size(),
+			ImageID:  imageID,
+			UploadID: "uploadID",
+			SHA256:   "image",
+		}
+
+		if _, err := c.multipartUploadPart(context.Background(), 1, m, &defaultUploadCallback{r: r}, store); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if got, want := len(gotRequests), 2; got != want {
+		t.Fatalf("got %v request(s), want %v", got, want)
+	}
+	for i, req := range gotRequests {
+		if got, want := req.SHA256Checksum, sha256; got != want {
+			t.Errorf("request %v: got SHA256 checksum %v, want %v", i, got, want)
+		}
+	}
+}
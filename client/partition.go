@@ -0,0 +1,128 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// DownloadPartition downloads a single data object of type dt from the SIF image specified by ref
+// (ie. "library://entity/collection/container:tag") and arch, writing its content to w. The
+// descriptors of the image are read remotely, and only the byte range of the requested object is
+// downloaded, using the transfer parameters specified by Config.Downloader. This is useful to
+// extract small objects (such as the definition file or signatures) from large images.
+//
+// If dt is sif.DataPartition, the primary system partition is downloaded. Otherwise, the first
+// object of type dt is downloaded.
+func (c *Client) DownloadPartition(ctx context.Context, ref, arch string, dt sif.DataType, w io.Writer) error {
+	r, err := ParseAmbiguous(ref)
+	if err != nil {
+		return fmt.Errorf("malformed image ref: %w", err)
+	}
+
+	name := strings.TrimPrefix(r.Path, "/")
+	tag := "latest"
+	if len(r.Tags) > 0 {
+		tag = r.Tags[0]
+	}
+
+	ctx, id := ensureRequestID(ctx)
+
+	c.logger.Logf("Downloading %v object from image (request ID: %v)", dt, id)
+
+	u, creds, size, err := c.imageBlob(ctx, arch, name, tag)
+	if err != nil {
+		return err
+	}
+
+	f, err := c.loadRemoteSIF(ctx, u, creds, size)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.UnloadContainer(); err != nil {
+			c.logger.Logf("Failed to unload container: %v", err)
+		}
+	}()
+
+	var d sif.Descriptor
+	if dt == sif.DataPartition {
+		d, err = f.GetDescriptor(sif.WithPartitionType(sif.PartPrimSys))
+	} else {
+		var ds []sif.Descriptor
+		if ds, err = f.GetDescriptors(sif.WithDataType(dt)); err == nil && len(ds) == 0 {
+			err = fmt.Errorf("no %v object found", dt)
+		} else if err == nil {
+			d = ds[0]
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("error selecting object: %w", err)
+	}
+
+	if d.Offset()+d.Size() > size {
+		return fmt.Errorf("object %v exceeds image size", d.ID())
+	}
+
+	c.logger.Logf("Downloading object %v (%v byte(s) at offset %v)", d.ID(), d.Size(), d.Offset())
+
+	if d.Size() == 0 {
+		return nil
+	}
+
+	spec := c.downloader
+
+	// Deliver the object content to w in order, as parts are downloaded concurrently.
+	ow := newOrderedWriter(w, spec.reorderBufferSize())
+	ow.next = d.Offset()
+
+	var parts []filePartDescriptor
+	for start := d.Offset(); start < d.Offset()+d.Size(); start += spec.PartSize {
+		end := minInt64(start+spec.PartSize, d.Offset()+d.Size()) - 1
+
+		parts = append(parts, filePartDescriptor{part: len(parts) + 1, start: start, end: end, w: ow})
+	}
+
+	if err := c.downloadParts(ctx, u, creds, parts, spec.Concurrency, &NoopProgressBar{}); err != nil {
+		return err
+	}
+	return ow.close()
+}
+
+// loadRemoteSIF reads the global header and descriptors of the SIF image at u, of the specified
+// size, using range requests. The returned image contains no data objects, so the content of
+// descriptors must not be read from it.
+func (c *Client) loadRemoteSIF(ctx context.Context, u *blobURL, creds credentials, size int64) (*sif.FileImage, error) {
+	var b bytes.Buffer
+
+	ow := newOrderedWriter(&b, 0)
+
+	for n := int64(sifHeaderSize); ; n *= 2 {
+		n = minInt64(n, size)
+
+		if start := int64(b.Len()); start < n {
+			ps := &filePartDescriptor{part: 1, start: start, end: n - 1, w: ow}
+
+			if _, err := c.downloadPartWithRenewal(ctx, creds, u, ps); err != nil {
+				return nil, fmt.Errorf("error reading SIF header: %w", err)
+			}
+		}
+
+		f, err := sif.LoadContainer(sif.NewBuffer(b.Bytes()))
+		if err == nil {
+			return f, nil
+		}
+		if n >= size || n >= maxStreamedSIFHeaderSize {
+			return nil, fmt.Errorf("error reading SIF header: %w", err)
+		}
+	}
+}
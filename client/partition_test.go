@@ -0,0 +1,102 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+func TestDownloadPartition(t *testing.T) {
+	const deffile = "Bootstrap: library\nFrom: alpine\n"
+	rootfs := strings.Repeat("rootfs", 64*1024)
+
+	def, err := sif.NewDescriptorInput(sif.DataDeffile, strings.NewReader(deffile))
+	if err != nil {
+		t.Fatalf("error creating descriptor input: %v", err)
+	}
+	part, err := sif.NewDescriptorInput(sif.DataPartition, strings.NewReader(rootfs),
+		sif.OptPartitionMetadata(sif.FsSquash, sif.PartPrimSys, "amd64"),
+	)
+	if err != nil {
+		t.Fatalf("error creating descriptor input: %v", err)
+	}
+
+	var b sif.Buffer
+	f, err := sif.CreateContainer(&b, sif.OptCreateDeterministic(), sif.OptCreateWithDescriptors(def, part))
+	if err != nil {
+		t.Fatalf("error creating SIF: %v", err)
+	}
+	if err := f.UnloadContainer(); err != nil {
+		t.Fatalf("error unloading SIF: %v", err)
+	}
+	image := b.Bytes()
+
+	tests := []struct {
+		name     string
+		dt       sif.DataType
+		want     string
+		wantErr  bool
+		maxBytes int64
+	}{
+		{"Deffile", sif.DataDeffile, deffile, false, sifHeaderSize + int64(len(deffile))},
+		{"Partition", sif.DataPartition, rootfs, false, sifHeaderSize + int64(len(rootfs))},
+		{"NotFound", sif.DataSignature, "", true, sifHeaderSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lib := mockLibraryServer(t, image, true)
+			defer lib.Close()
+
+			var requested atomic.Int64
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/v1/oci-redirect" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				if v := r.Header.Get("Range"); v != "" {
+					start, end := parseRangeHeader(t, v)
+					requested.Add(end - start + 1)
+				}
+				lib.Config.Handler.ServeHTTP(w, r)
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{
+				BaseURL:    srv.URL,
+				Logger:     testLogger,
+				Downloader: &Downloader{Concurrency: 4, PartSize: 16 * 1024},
+			})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			var got bytes.Buffer
+
+			err = c.DownloadPartition(context.Background(), "library://entity/collection/container:tag", "amd64", tt.dt, &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			if got, want := got.String(), tt.want; got != want {
+				t.Errorf("got %v byte(s), want %v byte(s)", len(got), len(want))
+			}
+
+			if got, max := requested.Load(), tt.maxBytes; got > max {
+				t.Errorf("got %v byte(s) requested, want at most %v", got, max)
+			}
+		})
+	}
+}
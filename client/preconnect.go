@@ -0,0 +1,75 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Preconnect establishes n connections to the host identified by rawURL, or the library if rawURL
+// is empty, ahead of transfers that will make use of them. The connections, including any TLS and
+// HTTP/2 negotiation, are retained by the transport as idle connections, so that connection
+// setup is removed from the critical path of subsequent transfers, such as when launching many
+// short-lived downloads. The number of idle connections retained is limited by
+// TransportConfig.MaxIdleConnsPerHost.
+//
+// Connections are established by issuing concurrent HEAD requests to the root of the host. The
+// status of the responses is disregarded.
+func (c *Client) Preconnect(ctx context.Context, rawURL string, n int) error {
+	u := c.baseURL
+	if rawURL != "" {
+		var err error
+		if u, err = url.Parse(rawURL); err != nil {
+			return fmt.Errorf("malformed URL: %w", err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("malformed URL %q", rawURL)
+		}
+	}
+
+	target := (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}).String()
+
+	c.logger.Logf("Establishing %d connection(s) to %v", n, u.Host)
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	for i := 0; i < n; i++ {
+		g.Go(func() error {
+			return c.preconnect(ctx, target)
+		})
+	}
+
+	return g.Wait()
+}
+
+// preconnect issues a HEAD request to u, so that a connection to the host is established.
+func (c *Client) preconnect(ctx context.Context, u string) error {
+	ctx, cancel := c.metadataContext(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u, nil)
+	if err != nil {
+		return err
+	}
+
+	setRequestID(req)
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error establishing connection: %w", err)
+	}
+	defer res.Body.Close()
+
+	// Drain the body, so that the connection is returned to the idle pool.
+	_, err = io.Copy(io.Discard, res.Body)
+	return err
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func TestPreconnect(t *testing.T) {
+	const n = 3
+
+	var heads sync.WaitGroup
+	heads.Add(n)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			if got, want := r.URL.Path, "/"; got != want {
+				t.Errorf("got path %v, want %v", got, want)
+			}
+
+			// Hold each request until all have arrived, so that each requires a connection.
+			heads.Done()
+			heads.Wait()
+
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if err := jsonresp.WriteResponse(w, VersionInfo{Version: "1.0.0"}, http.StatusOK); err != nil {
+			t.Errorf("error writing JSON response: %v", err)
+		}
+	}))
+
+	var conns atomic.Int32
+	srv.Config.ConnState = func(_ net.Conn, s http.ConnState) {
+		if s == http.StateNew {
+			conns.Add(1)
+		}
+	}
+
+	srv.Start()
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL + "/library", Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	if err := c.Preconnect(context.Background(), "", n); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := conns.Load(), int32(n); got != want {
+		t.Fatalf("got %v connections, want %v", got, want)
+	}
+
+	// Subsequent requests re-use the established connections.
+	if _, err := c.GetVersion(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := conns.Load(), int32(n); got != want {
+		t.Errorf("got %v connections, want %v", got, want)
+	}
+}
+
+func TestPreconnectURL(t *testing.T) {
+	c, err := NewClient(&Config{Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	for _, u := range []string{"no-scheme", "http://", ":"} {
+		if err := c.Preconnect(context.Background(), u, 1); err == nil {
+			t.Errorf("%q: unexpected success", u)
+		}
+	}
+}
@@ -15,6 +15,9 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/opencontainers/go-digest"
 )
 
 // Downloader defines concurrency (# of requests) and part size for download operation.
@@ -29,6 +32,35 @@ type Downloader struct {
 	// Default is 32 KiB.
 	// Deprecated: this value will be ignored. It is retained for backwards compatibility.
 	BufferSize int64
+
+	// Verifier specifies verification of the downloaded content (if supplied). The result of
+	// verification is reported by DownloadImageWithSummary. Not supported by
+	// DownloadImageStream.
+	Verifier *DownloadVerifier
+
+	// ReorderBufferSize limits the span (in bytes) of the parts that may be downloaded ahead of
+	// the content delivered by DownloadImageStream. If zero, Concurrency * PartSize is used.
+	ReorderBufferSize int64
+
+	// VerifyImageConfig enables verification of the image config recorded in the OCI registry
+	// against the downloaded image. If the architecture, signed or encrypted properties, or root
+	// file system digest of the image do not match the config, an *ImageConfigMismatchError is
+	// returned. Not supported by DownloadImageStream.
+	VerifyImageConfig bool
+
+	// Sparse enables sparse writing of the destination, which must be a file (ie. an *os.File).
+	// Blocks of the image that consist entirely of zeros are not written, so that images such as
+	// raw overlay file systems occupy less space on disk. The destination is truncated before the
+	// download begins. Not supported by DownloadImageStream.
+	Sparse bool
+}
+
+// reorderBufferSize returns the reorder buffer size for d.
+func (d *Downloader) reorderBufferSize() int64 {
+	if d.ReorderBufferSize > 0 {
+		return d.ReorderBufferSize
+	}
+	return int64(d.Concurrency) * d.PartSize
 }
 
 // NoopProgressBar implements ProgressBarInterface to allow disabling the progress bar
@@ -71,40 +103,175 @@ type ProgressBar interface {
 }
 
 // DownloadImage implements a multi-part (concurrent) downloader for
-// Cloud Library images. spec is used to define transfer parameters. pb is an
+// Cloud Library images. spec is used to define transfer parameters. If spec is
+// nil, the parameters specified by Config.Downloader are used. pb is an
 // optional progress bar interface.  If pb is nil, NoopProgressBar is used.
 //
 // The downloader will handle source files of all sizes and is not limited to
 // only files larger than Downloader.PartSize. It will automatically adjust the
 // concurrency for source files that do not meet minimum size for multi-part
 // downloads.
+//
+// DownloadImage is retained for compatibility; PullImage provides further options.
 func (c *Client) DownloadImage(ctx context.Context, dst *os.File, arch, path, tag string, spec *Downloader, pb ProgressBar) error {
+	_, err := c.DownloadImageWithSummary(ctx, dst, arch, path, tag, spec, pb)
+	return err
+}
+
+// DownloadImageWithSummary behaves as DownloadImage, and additionally returns a summary of the
+// completed transfer.
+func (c *Client) DownloadImageWithSummary(ctx context.Context, dst *os.File, arch, path, tag string, spec *Downloader, pb ProgressBar) (*TransferSummary, error) {
+	return c.PullImage(ctx, dst, path, &DownloadOptions{Arch: arch, Tag: tag, Downloader: spec, ProgressBar: pb})
+}
+
+// DownloadImageStream behaves as DownloadImage, but writes the image to w, which need not support
+// seeking (ie. a pipe or network connection). Parts are downloaded concurrently, and delivered to
+// w strictly in order. Parts received ahead of those preceding them are buffered in memory, up to
+// spec.ReorderBufferSize bytes.
+func (c *Client) DownloadImageStream(ctx context.Context, w io.Writer, arch, path, tag string, spec *Downloader, pb ProgressBar) error {
+	ctx, id := ensureRequestID(ctx)
+
+	c.logger.Logf("Downloading image (request ID: %v)", id)
+
+	spec = c.downloadSpec(spec)
+
+	ow := newOrderedWriter(w, spec.reorderBufferSize())
+
+	if err := c.downloadImage(ctx, ow, path, &DownloadOptions{Arch: arch, Tag: tag, Downloader: spec, ProgressBar: pb}); err != nil {
+		return err
+	}
+	return ow.close()
+}
+
+// downloadSpec returns spec, or the default transfer parameters if spec is nil.
+func (c *Client) downloadSpec(spec *Downloader) *Downloader {
+	if spec == nil {
+		d := c.downloader
+		return &d
+	}
+	return spec
+}
+
+func (c *Client) downloadImage(ctx context.Context, dst io.WriterAt, path string, opts *DownloadOptions) error {
+	var o DownloadOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	if err := o.validate(); err != nil {
+		return err
+	}
+
+	ctx, cancel := c.operationContext(ctx)
+	defer cancel()
+
+	pb := o.ProgressBar
 	if pb == nil {
 		pb = &NoopProgressBar{}
 	}
 
+	spec := c.downloadSpec(o.Downloader)
+
 	if strings.Contains(path, ":") {
 		return fmt.Errorf("malformed image path: %s", path)
 	}
 
 	name := strings.TrimPrefix(path, "/")
+	tag := o.Tag
 	if tag == "" {
 		tag = "latest"
 	}
 
-	// Attempt to download from OCI registry directly
-	if err := c.ociDownloadImage(ctx, arch, name, tag, dst, spec, pb); err != nil {
-		if !errors.Is(err, errOCIDownloadNotSupported) {
+	var dv *downloadVerification
+	if spec.Verifier != nil {
+		ra, ok := dst.(io.ReaderAt)
+		if !ok {
+			return errors.New("download verification requires a seekable destination")
+		}
+
+		var err error
+		if dv, err = newDownloadVerification(spec.Verifier, ra); err != nil {
 			return err
 		}
+		ctx = withDownloadVerification(ctx, dv)
+	}
 
-		c.logger.Log("Fallback to (legacy) library download")
+	if spec.VerifyImageConfig {
+		if _, ok := dst.(io.ReaderAt); !ok {
+			return errors.New("image config verification requires a seekable destination")
+		}
+	}
+
+	if o.NoShare {
+		ctx = withoutSharedDownloads(ctx)
+	}
+
+	var sw *sparseWriter
+	if spec.Sparse {
+		var err error
+		if sw, err = sparseWriterFor(dst); err != nil {
+			return err
+		}
+		dst = sw
+	}
+
+	if o.MaxBytesPerSecond > 0 {
+		ctx = withRateLimiter(ctx, newRateLimiter(o.MaxBytesPerSecond, c.sleeper))
+	}
+
+	// Content written to a stream cannot be resumed.
+	var rt *resumeTracker
+	if _, ok := dst.(*orderedWriter); !ok {
+		rt = newResumeTracker(name, o.Arch, tag)
+		ctx = withResumeTracker(ctx, rt)
+	}
+
+	err := c.downloadImageFrom(ctx, o.Backend, o.Arch, name, tag, dst, spec, pb)
 
-		return c.libraryDownloadImage(ctx, arch, name, tag, dst, spec, pb)
+	// Extend the destination to include skipped blocks, including those of an interrupted
+	// download, so that it may be resumed.
+	if sw != nil {
+		if serr := sw.finish(); serr != nil && err == nil {
+			err = serr
+		}
+	}
+
+	if err != nil {
+		return rt.wrap(err)
+	}
+
+	if dv != nil {
+		res, err := dv.complete()
+		if err != nil {
+			return fmt.Errorf("error verifying downloaded image: %w", err)
+		}
+		transferStatsFromContext(ctx).setVerification(res)
 	}
 	return nil
 }
 
+// downloadImageFrom downloads the image from the specified backend. If backend is empty, the image
+// is downloaded from the OCI registry if supported, and the library otherwise.
+func (c *Client) downloadImageFrom(ctx context.Context, backend, arch, name, tag string, dst io.WriterAt, spec *Downloader, pb ProgressBar) error {
+	stats := transferStatsFromContext(ctx)
+
+	if backend != TransferBackendLibrary {
+		// Attempt to download from OCI registry directly
+		stats.setBackend(TransferBackendOCI)
+		resumeTrackerFromContext(ctx).setBackend(TransferBackendOCI)
+		err := c.ociDownloadImage(ctx, arch, name, tag, dst, spec, pb)
+		if backend == TransferBackendOCI || !errors.Is(err, errOCIDownloadNotSupported) {
+			return err
+		}
+
+		c.logger.Log("Fallback to (legacy) library download")
+	}
+
+	stats.setBackend(TransferBackendLibrary)
+	resumeTrackerFromContext(ctx).setBackend(TransferBackendLibrary)
+	return c.libraryDownloadImage(ctx, arch, name, tag, dst, spec, pb)
+}
+
 func (c *Client) libraryDownloadImage(ctx context.Context, arch, name, tag string, dst io.WriterAt, spec *Downloader, pb ProgressBar) error {
 	if arch != "" && !c.apiAtLeast(ctx, APIVersionV2ArchTags) {
 		c.logger.Log("This library does not support architecture specific tags")
@@ -117,37 +284,24 @@ func (c *Client) libraryDownloadImage(ctx context.Context, arch, name, tag strin
 
 	c.logger.Logf("Pulling from URL: %s", apiPath)
 
-	customHTTPClient := &http.Client{
-		Transport: c.httpClient.Transport,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			if req.Response.StatusCode == http.StatusSeeOther {
-				return http.ErrUseLastResponse
-			}
-			maxRedir := 10
-			if len(via) >= maxRedir {
-				return fmt.Errorf("stopped after %d redirects", maxRedir)
-			}
-			return nil
-		},
-		Jar:     c.httpClient.Jar,
-		Timeout: c.httpClient.Timeout,
-	}
-
-	req, err := c.newRequest(ctx, http.MethodGet, apiPath, q.Encode(), nil)
+	res, err := c.requestLibraryImage(ctx, apiPath, q.Encode())
 	if err != nil {
 		return err
 	}
+	defer res.Body.Close()
 
-	res, err := customHTTPClient.Do(req)
-	if err != nil {
-		return err
+	if res.StatusCode == http.StatusOK || res.StatusCode == http.StatusSeeOther {
+		transferStatsFromContext(ctx).setCacheHints(parseCacheHints(tag, res.Header, time.Now()))
 	}
-	defer res.Body.Close()
 
 	if res.StatusCode == http.StatusNotFound {
 		return fmt.Errorf("requested image was not found in the library")
 	}
 
+	if res.StatusCode == http.StatusUnauthorized {
+		return c.unauthorizedError(res)
+	}
+
 	if res.StatusCode == http.StatusOK {
 		// Library endpoint does not provide HTTP redirection response, treat as single stream download
 
@@ -158,32 +312,214 @@ func (c *Client) libraryDownloadImage(ctx context.Context, arch, name, tag strin
 			return err
 		}
 
+		if res.Uncompressed {
+			// Content-Length describes the compressed content; use the image size from the
+			// image metadata, so that progress reflects the decompressed content.
+			img, err := c.GetImage(ctx, arch, fmt.Sprintf("%v:%v", name, tag))
+			if err != nil {
+				return err
+			}
+			size = img.Size
+
+			c.logger.Logf("Downloading compressed image content (%v byte(s) decompressed)", size)
+		}
+
 		return c.download(ctx, dst, res.Body, size, pb)
 	}
 
 	if res.StatusCode != http.StatusSeeOther {
-		return fmt.Errorf("unexpected HTTP status %d: %v", res.StatusCode, err)
+		return responseError(res, "error downloading image")
+	}
+
+	// Release the redirect response before issuing further requests
+	res.Body.Close()
+
+	u, creds, size, err := c.libraryImageBlob(ctx, arch, name, tag, res)
+	if err != nil {
+		return err
+	}
+
+	// Use redirect URL to download artifact
+	return c.multipartDownload(ctx, u, creds, dst, size, spec, pb)
+}
+
+// imageBlob returns the URL, credentials and size of the blob containing the image with
+// the specified name, tag and architecture, from the OCI registry if supported, and the library
+// otherwise.
+func (c *Client) imageBlob(ctx context.Context, arch, name, tag string) (*blobURL, credentials, int64, error) {
+	u, creds, size, _, err := c.ociImageBlob(ctx, arch, name, tag)
+	if err == nil || !errors.Is(err, errOCIDownloadNotSupported) {
+		return u, creds, size, err
+	}
+
+	u, creds, err = c.libraryImageBlobURL(ctx, arch, name, tag)
+	if err != nil {
+		return nil, nil, 0, err
 	}
 
+	size, err = c.libraryImageSize(ctx, arch, name, tag, u)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return u, creds, size, nil
+}
+
+// imageBlobURL returns the URL and credentials of the blob containing the image with the
+// specified name, tag and architecture, from the OCI registry if supported, and the library
+// otherwise. Unlike imageBlob, the image metadata is not consulted when downloading from the
+// library.
+func (c *Client) imageBlobURL(ctx context.Context, arch, name, tag string) (*blobURL, credentials, error) {
+	u, creds, _, _, err := c.ociImageBlob(ctx, arch, name, tag)
+	if err == nil || !errors.Is(err, errOCIDownloadNotSupported) {
+		return u, creds, err
+	}
+	return c.libraryImageBlobURL(ctx, arch, name, tag)
+}
+
+// libraryImageBlobURL returns the URL and credentials of the blob containing the image with the
+// specified name, tag and architecture, to which the library redirects.
+func (c *Client) libraryImageBlobURL(ctx context.Context, arch, name, tag string) (*blobURL, credentials, error) {
+	q := url.Values{}
+	q.Add("arch", arch)
+
+	res, err := c.requestLibraryImage(ctx, fmt.Sprintf("v1/imagefile/%v:%v", name, tag), q.Encode())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusSeeOther:
+		// Release the redirect response before issuing further requests
+		res.Body.Close()
+
+		return c.libraryBlobURL(arch, name, tag, res)
+	case http.StatusNotFound:
+		return nil, nil, fmt.Errorf("requested image was not found in the library")
+	case http.StatusOK:
+		return nil, nil, fmt.Errorf("library endpoint does not support concurrent downloads")
+	default:
+		return nil, nil, responseError(res, "error locating image")
+	}
+}
+
+func (c *Client) libraryImageBlob(ctx context.Context, arch, name, tag string, res *http.Response) (*blobURL, credentials, int64, error) {
+	u, creds, err := c.libraryBlobURL(arch, name, tag, res)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	size, err := c.libraryImageSize(ctx, arch, name, tag, u)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return u, creds, size, nil
+}
+
+// libraryImageSize returns the size of the image recorded in the library metadata, and records
+// the digest of the image in u.
+func (c *Client) libraryImageSize(ctx context.Context, arch, name, tag string, u *blobURL) (int64, error) {
 	// Get image metadata to determine image size
 	img, err := c.GetImage(ctx, arch, fmt.Sprintf("%v:%v", name, tag))
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	// Library image hashes take the form "sha256.<hex>".
+	if d := digest.Digest(strings.Replace(img.Hash, ".", ":", 1)); d.Validate() == nil {
+		u.digest = d
 	}
 
+	return img.Size, nil
+}
+
+// libraryBlobURL returns the URL and credentials of the blob to which the library redirected in
+// res.
+func (c *Client) libraryBlobURL(arch, name, tag string, res *http.Response) (*blobURL, credentials, error) {
+	apiPath := fmt.Sprintf("v1/imagefile/%v:%v", name, tag)
+	q := url.Values{}
+	q.Add("arch", arch)
+
 	redirectURL, err := url.Parse(res.Header.Get("Location"))
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+	if err := c.checkPresignedURL(redirectURL.String()); err != nil {
+		return nil, nil, err
 	}
 
 	var creds credentials
-	if c.authToken != "" && samehost(c.baseURL, redirectURL) {
+	if token := requestToken(res.Request); token != "" && samehost(c.baseURL, redirectURL) {
 		// Only include credentials if redirected to same host as base URL
-		creds = bearerTokenCredentials{authToken: c.authToken}
+		creds = bearerTokenCredentials{authToken: token}
 	}
 
-	// Use redirect URL to download artifact
-	return c.multipartDownload(ctx, redirectURL.String(), creds, dst, img.Size, spec, pb)
+	// Re-issue library request to obtain a fresh redirect URL should the current one expire
+	renew := func(ctx context.Context) (string, error) {
+		res, err := c.requestLibraryImage(ctx, apiPath, q.Encode())
+		if err != nil {
+			return "", err
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusSeeOther {
+			return "", responseError(res, "error renewing image URL")
+		}
+		if err := c.checkPresignedURL(res.Header.Get("Location")); err != nil {
+			return "", err
+		}
+		return res.Header.Get("Location"), nil
+	}
+
+	return &blobURL{u: redirectURL.String(), renew: renew}, creds, nil
+}
+
+// requestLibraryImage issues a request for the image file at apiPath. A "303 See Other" redirect
+// is not followed, so that the caller may download from the redirect location directly.
+func (c *Client) requestLibraryImage(ctx context.Context, apiPath, rawQuery string) (*http.Response, error) {
+	customHTTPClient := &http.Client{
+		Transport: c.httpClient.Transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if req.Response.StatusCode == http.StatusSeeOther {
+				return http.ErrUseLastResponse
+			}
+			maxRedir := 10
+			if len(via) >= maxRedir {
+				return fmt.Errorf("stopped after %d redirects", maxRedir)
+			}
+			return nil
+		},
+		Jar:     c.httpClient.Jar,
+		Timeout: c.httpClient.Timeout,
+	}
+
+	req, err := c.newRequest(ctx, http.MethodGet, apiPath, rawQuery, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.compressedImages {
+		// Prevent net/http from negotiating compression transparently, which would leave the
+		// (uncompressed) image size unknown.
+		req.Header.Set("Accept-Encoding", "identity")
+	}
+
+	res, err := customHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res, err = c.retryUnauthorized(customHTTPClient, req, res); err != nil {
+		return nil, err
+	}
+
+	c.relayServerWarning(res)
+
+	if err := c.decodeResponse(res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
 }
 
 // samehost returns true if host1 and host2 are, in fact, the same host by
@@ -207,14 +543,21 @@ func parseContentLengthHeader(val string) (int64, error) {
 }
 
 // download implements a simple, single stream downloader
-func (c *Client) download(_ context.Context, w io.WriterAt, r io.Reader, size int64, pb ProgressBar) error {
+func (c *Client) download(ctx context.Context, w io.WriterAt, r io.Reader, size int64, pb ProgressBar) error {
+	downloadVerificationFromContext(ctx).setSize(size)
+
 	pb.Init(size)
 	defer pb.Wait()
 
-	proxyReader := pb.ProxyReader(r)
+	r, nc := countNetwork(r, pb)
+
+	proxyReader := pb.ProxyReader(limitReader(ctx, r))
 	defer proxyReader.Close()
 
 	written, err := io.Copy(&filePartDescriptor{start: 0, end: size - 1, w: w}, proxyReader)
+	if err == nil && size >= 0 && written != size {
+		err = fmt.Errorf("downloaded %v byte(s), expected %v", written, size)
+	}
 	if err != nil {
 		pb.Abort(true)
 
@@ -223,5 +566,7 @@ func (c *Client) download(_ context.Context, w io.WriterAt, r io.Reader, size in
 
 	c.logger.Logf("Downloaded %v byte(s)", written)
 
+	transferStatsFromContext(ctx).addPartNetwork(written, nc.n)
+
 	return nil
 }
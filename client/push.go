@@ -15,15 +15,14 @@ import (
 	"net/url"
 	"strings"
 
-	jsonresp "github.com/sylabs/json-resp"
-	"golang.org/x/sync/errgroup"
+	"github.com/opencontainers/go-digest"
 )
 
 const (
-	// minimumPartSize is the minimum size of a part in a multipart upload;
-	// this liberty is taken by defining this value on the client-side to
-	// prevent a round-trip to the server. The server will return HTTP status
-	// 400 if the requested multipart upload size is less than 5MiB.
+	// minimumPartSize is the size above which a multipart upload is attempted, until the
+	// backend library server advertises the minimum part size of its object store (see
+	// OptionMinimumPartSize). The server will return HTTP status 400 if the requested multipart
+	// upload size is less than 5MiB.
 	minimumPartSize = 64 * 1024 * 1024
 
 	// OptionS3Compliant indicates a 100% S3 compatible object store is being used by backend library server
@@ -45,6 +44,16 @@ type UploadCallback interface {
 	Finish()
 }
 
+// RewindUploadCallback is an UploadCallback that is notified when content already read through
+// its reader is to be read again, as when the upload of a part is re-attempted. Implementations
+// that report progress may use Rewind to avoid counting the content twice.
+type RewindUploadCallback interface {
+	UploadCallback
+
+	// Rewind is called before n bytes already read through the reader are read again.
+	Rewind(n int64)
+}
+
 // Default upload callback
 type defaultUploadCallback struct {
 	r io.Reader
@@ -64,51 +73,54 @@ func (c *defaultUploadCallback) Terminate() {
 func (c *defaultUploadCallback) Finish() {
 }
 
-// calculateChecksums uses a TeeReader to calculate MD5 and SHA256
-// checksums concurrently
-func calculateChecksums(r io.Reader) (string, string, int64, error) {
-	pr, pw := io.Pipe()
-	tr := io.TeeReader(r, pw)
+// UploadImage will push a specified image from an io.ReadSeeker up to the
+// Container Library, The timeout value for this operation is set within
+// the context. It is recommended to use a large value (ie. 1800 seconds) to
+// prevent timeout when uploading large images.
+//
+// The entity, collection and container are created if they do not exist,
+// unless disabled by Config.Upload or opts, in which case an error wrapping
+// ErrNotFound is returned. opts override the upload options specified by
+// Config.Upload for this upload.
+//
+// Tags are applied individually once the image is uploaded. If some tags
+// cannot be applied, the remaining tags are still applied, and a *TagsError
+// describing the failed tags is returned, so that they may be retried (see
+// SetTags).
+//
+// When uploading to an OCI registry, callback is notified of each phase of
+// the upload if it implements PhaseUploadCallback.
+func (c *Client) UploadImage(ctx context.Context, r io.ReadSeeker, path, arch string, tags []string, description string, callback UploadCallback, opts ...UploadOption) (*UploadImageComplete, error) {
+	res, _, err := c.UploadImageWithSummary(ctx, r, path, arch, tags, description, callback, opts...)
+	return res, err
+}
 
-	var g errgroup.Group
+// UploadImageWithSummary behaves as UploadImage, and additionally returns a summary of the
+// completed transfer. If the image is already present in the library, the summary reports no
+// parts transferred.
+func (c *Client) UploadImageWithSummary(ctx context.Context, r io.ReadSeeker, path, arch string, tags []string, description string, callback UploadCallback, opts ...UploadOption) (*UploadImageComplete, *TransferSummary, error) {
+	ctx, id := ensureRequestID(ctx)
 
-	var md5checksum string
-	var sha256checksum string
-	var fileSize int64
+	c.logger.Logf("Uploading image (request ID: %v)", id)
 
-	// compute MD5 checksum for comparison with S3 checksum
-	g.Go(func() error {
-		// The pipe writer must be closed so sha256 computation gets EOF and will
-		// complete.
-		defer pw.Close()
-		var err error
+	ctx, stats := withTransferStats(ctx)
 
-		md5checksum, fileSize, err = md5sum(tr)
-		if err != nil {
-			return fmt.Errorf("error calculating MD5 checksum: %v", err)
-		}
-		return nil
-	})
+	res, err := c.uploadImage(ctx, r, path, arch, tags, description, callback, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, stats.summary(), nil
+}
 
-	// Compute sha256
-	g.Go(func() error {
-		var err error
-		sha256checksum, _, err = sha256sum(pr)
-		if err != nil {
-			return fmt.Errorf("error calculating SHA checksum: %v", err)
-		}
-		return nil
-	})
+func (c *Client) uploadImage(ctx context.Context, r io.ReadSeeker, path, arch string, tags []string, description string, callback UploadCallback, opts []UploadOption) (*UploadImageComplete, error) {
+	ctx, cancel := c.operationContext(ctx)
+	defer cancel()
 
-	err := g.Wait()
-	return md5checksum, sha256checksum, fileSize, err
-}
+	o, err := c.uploadOptions(opts)
+	if err != nil {
+		return nil, err
+	}
 
-// UploadImage will push a specified image from an io.ReadSeeker up to the
-// Container Library, The timeout value for this operation is set within
-// the context. It is recommended to use a large value (ie. 1800 seconds) to
-// prevent timeout when uploading large images.
-func (c *Client) UploadImage(ctx context.Context, r io.ReadSeeker, path, arch string, tags []string, description string, callback UploadCallback) (*UploadImageComplete, error) {
 	if !IsLibraryPushRef(path) {
 		return nil, fmt.Errorf("malformed image path: %s", path)
 	}
@@ -118,11 +130,38 @@ func (c *Client) UploadImage(ctx context.Context, r io.ReadSeeker, path, arch st
 		return nil, fmt.Errorf("malformed image path: %s", path)
 	}
 
-	// calculate sha256 and md5 checksums
-	md5Checksum, imageHash, fileSize, err := calculateChecksums(r)
+	if c.validateUploads {
+		if err := c.validateUploadImage(r, arch); err != nil {
+			return nil, err
+		}
+	}
+
+	var labels map[string]string
+	if c.describeUploads && description == "" {
+		if description, labels, err = c.uploadMetadata(r); err != nil {
+			return nil, err
+		}
+	}
+
+	if text := o.DescriptionTemplate; text != "" {
+		d := UploadDescription{
+			Path:        fmt.Sprintf("%s/%s/%s", entityName, collectionName, containerName),
+			Arch:        arch,
+			Tags:        tags,
+			Description: description,
+		}
+		if description, err = c.templateDescription(r, text, d); err != nil {
+			return nil, err
+		}
+	}
+
+	// calculate checksums; sha256 checksum is always calculated, as it identifies the image
+	algs, deferMD5 := c.imageChecksumAlgorithms(o.ChecksumAlgorithms)
+	sums, fileSize, err := computeChecksumsReadAhead(r, algs, o.HashReadAhead, o.HashBufferSize)
 	if err != nil {
 		return nil, fmt.Errorf("error calculating checksums: %v", err)
 	}
+	imageHash := sums[ChecksumSHA256]
 
 	// rollback to top of file
 	if _, err = r.Seek(0, io.SeekStart); err != nil {
@@ -131,17 +170,121 @@ func (c *Client) UploadImage(ctx context.Context, r io.ReadSeeker, path, arch st
 
 	c.logger.Logf("Image hash computed as %s", imageHash)
 
-	if err := c.ociUploadImage(ctx, r, fileSize, strings.TrimPrefix(path, "library://"), arch, tags, description, "sha256."+imageHash, callback); err == nil {
-		return nil, nil
+	// The OCI registry creates repositories on push, so the container is resolved prior to upload
+	// when it must already exist, or when it must be created as private.
+	var container *Container
+	if o.DisableCreateMissing || o.Private {
+		if container, err = c.ensureContainer(ctx, entityName, collectionName, containerName, o); err != nil {
+			return nil, err
+		}
+	}
+
+	stats := transferStatsFromContext(ctx)
+
+	stats.setBackend(TransferBackendOCI)
+	pushed, err := c.ociUploadImage(ctx, r, fileSize, strings.TrimPrefix(path, "library://"), arch, tags, description, labels, "sha256."+imageHash, o, callback)
+	stats.setOCIPush(pushed)
+	if err == nil {
+		return nil, c.publishUploadChecksums(ctx, arch, fmt.Sprintf("%s/%s/%s", entityName, collectionName, containerName), fileSize, sums)
 	} else if !errors.Is(err, errOCIDownloadNotSupported) {
 		// Return OCI upload error or fallback to legacy download
 		return nil, err
 	}
 
+	if detachedSignerFromContext(ctx) != nil {
+		return nil, ErrDetachedSignatureNotSupported
+	}
+
 	c.logger.Log("Fallback to (legacy) library upload")
 
+	stats.setBackend(TransferBackendLibrary)
+
+	if container == nil {
+		if container, err = c.ensureContainer(ctx, entityName, collectionName, containerName, o); err != nil {
+			return nil, err
+		}
+	}
+
+	computedName := fmt.Sprintf("%s/%s/%s", entityName, collectionName, containerName)
+
+	// Find or create image
+	image, err := c.GetImage(ctx, arch, computedName+":"+"sha256."+imageHash)
+	if err != nil {
+		if err != ErrNotFound {
+			return nil, err
+		}
+		// Create image
+		c.logger.Logf("Image %s does not exist in library - creating it.", imageHash)
+		image, err = c.createImage(ctx, "sha256."+imageHash, container.ID, description, labels)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var res *UploadImageComplete
+
+	if !image.Uploaded || o.SkipDedup {
+		// upload image
+
+		if callback == nil {
+			// use default (noop) upload callback
+			callback = &defaultUploadCallback{r: r}
+		}
+
+		if deferMD5 {
+			if sums[ChecksumMD5], err = c.deferredMD5(ctx, r); err != nil {
+				return nil, err
+			}
+		}
+
+		metadata := map[string]string{
+			"sha256sum": imageHash,
+			"md5sum":    sums[ChecksumMD5],
+			"sha512sum": sums[ChecksumSHA512],
+			"crc32c":    sums[ChecksumCRC32C],
+		}
+
+		res, err = c.postFileWrapper(ctx, r, fileSize, image.ID, callback, metadata, o.RequireMultipart)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		c.logger.Logf("Image is already present in the library - not uploading.")
+	}
+
+	if err := c.publishUploadChecksums(ctx, arch, computedName, fileSize, sums); err != nil {
+		return nil, err
+	}
+
+	// set tags on image
+	c.logger.Logf("Setting tags against uploaded image")
+
+	changes, err := c.applyTags(ctx, container.ID, arch, image.ID, append(tags, parsedTags...), true)
+	stats.setTags(changes)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ensureContainer returns the container named by entityName, collectionName and containerName.
+// Unless o.DisableCreateMissing is set, the entity, collection and container are created if they
+// do not exist. Otherwise, an error wrapping ErrNotFound is returned if the container does not
+// exist.
+func (c *Client) ensureContainer(ctx context.Context, entityName, collectionName, containerName string, o UploadOptions) (*Container, error) {
+	if o.DisableCreateMissing {
+		ref := fmt.Sprintf("%s/%s/%s", entityName, collectionName, containerName)
+
+		container, err := c.GetContainer(ctx, ref)
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("container %s does not exist: %w", ref, err)
+		}
+		return container, err
+	}
+
 	// Find or create entity
-	entity, err := c.getEntity(ctx, entityName)
+	entity, err := c.GetEntity(ctx, entityName)
 	if err != nil {
 		if err != ErrNotFound {
 			return nil, err
@@ -155,7 +298,7 @@ func (c *Client) UploadImage(ctx context.Context, r io.ReadSeeker, path, arch st
 
 	// Find or create collection
 	qualifiedCollectionName := fmt.Sprintf("%s/%s", entityName, collectionName)
-	collection, err := c.getCollection(ctx, qualifiedCollectionName)
+	collection, err := c.GetCollection(ctx, qualifiedCollectionName)
 	if err != nil {
 		if err != ErrNotFound {
 			return nil, err
@@ -170,77 +313,127 @@ func (c *Client) UploadImage(ctx context.Context, r io.ReadSeeker, path, arch st
 
 	// Find or create container
 	computedName := fmt.Sprintf("%s/%s", qualifiedCollectionName, containerName)
-	container, err := c.getContainer(ctx, computedName)
+	container, err := c.GetContainer(ctx, computedName)
 	if err != nil {
 		if err != ErrNotFound {
 			return nil, err
 		}
 		// Create container
 		c.logger.Logf("Container %s does not exist in library - creating it.", containerName)
-		container, err = c.createContainer(ctx, containerName, collection.ID)
+		container, err = c.createContainer(ctx, containerName, collection.ID, o.Private)
 		if err != nil {
 			return nil, err
 		}
 	}
+	return container, nil
+}
 
-	// Find or create image
-	image, err := c.GetImage(ctx, arch, computedName+":"+"sha256."+imageHash)
-	if err != nil {
-		if err != ErrNotFound {
-			return nil, err
-		}
-		// Create image
-		c.logger.Logf("Image %s does not exist in library - creating it.", imageHash)
-		image, err = c.createImage(ctx, "sha256."+imageHash, container.ID, description)
-		if err != nil {
-			return nil, err
+// imageChecksumAlgorithms returns the checksum algorithms computed over an image prior to upload,
+// given the configured algorithms algs. The MD5 checksum of an image is only required by library
+// servers that do not identify images by SHA256 checksum alone, so unless it is verified or
+// published by the client, it is omitted, and deferMD5 is set. See deferredMD5.
+func (c *Client) imageChecksumAlgorithms(algs []ChecksumAlgorithm) (res []ChecksumAlgorithm, deferMD5 bool) {
+	if !hasChecksumAlgorithm(algs, ChecksumMD5) || c.verifyChecksums || c.publishChecksums {
+		return algs, false
+	}
+
+	res = make([]ChecksumAlgorithm, 0, len(algs))
+	for _, alg := range algs {
+		if alg != ChecksumMD5 {
+			res = append(res, alg)
 		}
 	}
+	return res, true
+}
 
-	var res *UploadImageComplete
+// deferredMD5 returns the MD5 checksum of the image read from r, if required by the library
+// server, or an empty string otherwise. On return, r is positioned at the start of the image.
+func (c *Client) deferredMD5(ctx context.Context, r io.ReadSeeker) (string, error) {
+	if !c.apiBetween(ctx, APIVersionV2Upload, APIVersionV2SHA256Upload) {
+		c.logger.Log("Skipping MD5 checksum calculation (not required by server)")
+		return "", nil
+	}
 
-	if !image.Uploaded {
-		// upload image
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	sums, _, err := computeChecksums(r, []ChecksumAlgorithm{ChecksumMD5})
+	if err != nil {
+		return "", fmt.Errorf("error calculating checksums: %v", err)
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return sums[ChecksumMD5], nil
+}
 
-		if callback == nil {
-			// use default (noop) upload callback
-			callback = &defaultUploadCallback{r: r}
-		}
+// publishUploadChecksums publishes the checksums sums, computed over an image of the specified size
+// uploaded to the container identified by name, if enabled.
+func (c *Client) publishUploadChecksums(ctx context.Context, arch, name string, size int64, sums checksums) error {
+	if !c.publishChecksums {
+		return nil
+	}
 
-		metadata := map[string]string{
-			"sha256sum": imageHash,
-			"md5sum":    md5Checksum,
-		}
+	c.logger.Logf("Publishing image checksums")
 
-		res, err = c.postFileWrapper(ctx, r, fileSize, image.ID, callback, metadata)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		c.logger.Logf("Image is already present in the library - not uploading.")
+	return c.PublishImageChecksums(ctx, arch, name+":sha256."+sums[ChecksumSHA256], &ImageChecksums{
+		Size:      size,
+		Checksums: sums,
+	})
+}
+
+// ImageUploaded returns true if an image with SHA256 checksum sha256sum has already been uploaded
+// to the container at path (ie. "library://entity/collection/container"). Build systems can use
+// this to skip UploadImage (including the checksum calculation) when an image is unchanged.
+//
+// An error is returned if the presence of the image cannot be determined, so that a failure to
+// reach the registry is not mistaken for an image that has not been uploaded.
+func (c *Client) ImageUploaded(ctx context.Context, path, sha256sum string) (bool, error) {
+	if !IsLibraryPushRef(path) {
+		return false, fmt.Errorf("malformed image path: %s", path)
 	}
 
-	// set tags on image
-	c.logger.Logf("Setting tags against uploaded image")
+	entityName, collectionName, containerName, parsedTags := ParseLibraryPath(path)
+	if len(parsedTags) != 0 {
+		return false, fmt.Errorf("malformed image path: %s", path)
+	}
 
-	if c.apiAtLeast(ctx, APIVersionV2ArchTags) {
-		if err := c.setTagsV2(ctx, container.ID, arch, image.ID, append(tags, parsedTags...)); err != nil {
-			return nil, err
-		}
-		return res, nil
+	sha256sum = strings.TrimPrefix(sha256sum, "sha256.")
+
+	d := digest.NewDigestFromEncoded(digest.SHA256, sha256sum)
+	if err := d.Validate(); err != nil {
+		return false, fmt.Errorf("invalid image hash '%v': %w", sha256sum, err)
 	}
 
-	c.logger.Logf("This library does not support multiple architectures per tag.")
+	computedName := fmt.Sprintf("%s/%s/%s", entityName, collectionName, containerName)
 
-	c.logger.Logf("This tag will replace any already uploaded with the same name.")
+	reg, creds, name, err := c.newOCIRegistry(ctx, computedName, []accessType{accessTypePull})
+	if err != nil && !errors.Is(err, errOCIDownloadNotSupported) {
+		return false, err
+	}
+	if err == nil {
+		ok, err := reg.existingImageBlob(ctx, creds, name, d)
+		if err != nil {
+			var re *RegistryError
+			if errors.As(err, &re) && re.StatusCode == http.StatusNotFound {
+				return false, nil
+			}
+			return false, fmt.Errorf("error checking for existing image: %w", err)
+		}
+		return ok, nil
+	}
 
-	if err := c.setTags(ctx, container.ID, image.ID, append(tags, parsedTags...)); err != nil {
-		return nil, err
+	image, err := c.GetImage(ctx, "", computedName+":sha256."+sha256sum)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		}
+		return false, err
 	}
-	return res, nil
+	return image.Uploaded, nil
 }
 
-func (c *Client) postFileWrapper(ctx context.Context, r io.ReadSeeker, fileSize int64, imageID string, callback UploadCallback, metadata map[string]string) (*UploadImageComplete, error) {
+func (c *Client) postFileWrapper(ctx context.Context, r io.ReadSeeker, fileSize int64, imageID string, callback UploadCallback, metadata map[string]string, requireMultipart bool) (*UploadImageComplete, error) {
 	var err error
 
 	// use callback to set up source file reader
@@ -255,7 +448,7 @@ func (c *Client) postFileWrapper(ctx context.Context, r io.ReadSeeker, fileSize
 		// remote does not support sha256, it will be ignored and fallback
 		// to md5. If the remote is aware of sha256, will be used and md5
 		// will be ignored.
-		res, err = c.postFileV2(ctx, r, fileSize, imageID, callback, metadata)
+		res, err = c.postFileV2(ctx, r, fileSize, imageID, callback, metadata, requireMultipart)
 	} else {
 		// fallback to legacy upload
 		res, err = c.postFile(ctx, fileSize, imageID, callback)
@@ -283,17 +476,23 @@ func (c *Client) postFile(ctx context.Context, fileSize int64, imageID string, c
 	req, _ := c.newRequest(ctx, http.MethodPost, postURL, "", callback.GetReader())
 	// Content length is required by the API
 	req.ContentLength = fileSize
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("error uploading file to server: %s", err.Error())
 	}
 	defer res.Body.Close()
+	if res.StatusCode == http.StatusUnauthorized {
+		return nil, c.unauthorizedError(res)
+	}
+	if err := modificationError(res); err != nil {
+		return nil, err
+	}
 	if res.StatusCode != http.StatusOK {
-		if err := jsonresp.ReadError(res.Body); err != nil {
-			return nil, fmt.Errorf("sending file did not succeed: %v", err)
-		}
-		return nil, fmt.Errorf("sending file did not succeed: http status code %d", res.StatusCode)
+		return nil, responseError(res, "sending file did not succeed")
 	}
+
+	transferStatsFromContext(ctx).addPart(fileSize)
+
 	return nil, nil
 }
 
@@ -301,21 +500,27 @@ func (c *Client) postFile(ctx context.Context, fileSize int64, imageID string, c
 // a three step operation: "create" upload image request, which returns a
 // URL to issue an http PUT operation against, and then finally calls the
 // completion endpoint once upload is complete.
-func (c *Client) postFileV2(ctx context.Context, r io.ReadSeeker, fileSize int64, imageID string, callback UploadCallback, metadata map[string]string) (*UploadImageComplete, error) {
-	if fileSize > minimumPartSize {
-		// only attempt multipart upload if size greater than S3 minimum
+//
+// If the library server does not support multipart uploads, the image is uploaded in a single
+// part, unless requireMultipart is set, in which case a *MultipartUnsupportedError is returned.
+func (c *Client) postFileV2(ctx context.Context, r io.ReadSeeker, fileSize int64, imageID string, callback UploadCallback, metadata map[string]string, requireMultipart bool) (*UploadImageComplete, error) {
+	if fileSize > c.multipartThreshold.get(c.baseURL.String()) {
+		// only attempt multipart upload if size greater than object store minimum
 		c.logger.Log("Attempting to use multipart uploader")
 
 		var err error
 		var res *UploadImageComplete
 
-		res, err = c.postFileV2Multipart(ctx, r, fileSize, imageID, callback)
+		res, err = c.postFileV2Multipart(ctx, r, fileSize, imageID, callback, metadata["sha256sum"])
 		if err != nil {
 			// if the error is anything other than ErrNotFound, fallback to legacy (single part)
 			// uploader.
 			if err != ErrNotFound {
 				return nil, err
 			}
+			if requireMultipart {
+				return nil, &MultipartUnsupportedError{Size: fileSize}
+			}
 			// fallthrough to legacy (single part) uploader
 		} else {
 			// multipart upload successful
@@ -329,15 +534,30 @@ func (c *Client) postFileV2(ctx context.Context, r io.ReadSeeker, fileSize int64
 	return c.legacyPostFileV2(ctx, fileSize, imageID, callback, metadata)
 }
 
+// MultipartUnsupportedError is returned when multipart upload is required (see
+// UploadOptions.RequireMultipart), but the library server does not support multipart uploads.
+type MultipartUnsupportedError struct {
+	// Size of the image, in bytes.
+	Size int64
+}
+
+func (e *MultipartUnsupportedError) Error() string {
+	return fmt.Sprintf("multipart upload of %d byte image not supported by library server", e.Size)
+}
+
 // uploadManager contains common params for multipart part function
 type uploadManager struct {
 	Source   io.ReadSeeker
 	Size     int64
 	ImageID  string
 	UploadID string
+	// ChecksumAlgorithms advertised by the backend library server for each part
+	ChecksumAlgorithms []ChecksumAlgorithm
+	// SHA256 checksum of the image, identifying cached part checksums
+	SHA256 string
 }
 
-func (c *Client) postFileV2Multipart(ctx context.Context, r io.ReadSeeker, fileSize int64, imageID string, callback UploadCallback) (*UploadImageComplete, error) {
+func (c *Client) postFileV2Multipart(ctx context.Context, r io.ReadSeeker, fileSize int64, imageID string, callback UploadCallback, sha256sum string) (*UploadImageComplete, error) {
 	// initiate multipart upload with backend to determine number of expected
 	// parts and part size
 	response, err := c.startMultipartUpload(ctx, fileSize, imageID)
@@ -347,13 +567,21 @@ func (c *Client) postFileV2Multipart(ctx context.Context, r io.ReadSeeker, fileS
 		return nil, err
 	}
 
-	c.logger.Logf("Multi-part upload: ID=[%s] totalParts=[%d] partSize=[%d]", response.UploadID, response.TotalParts, fileSize)
+	c.logger.Logf("Multi-part upload: ID=[%s] totalParts=[%d] partSize=[%d]", response.UploadID, response.TotalParts, response.PartSize)
 
-	// Enable S3 compliance mode by default
-	val := response.Options[OptionS3Compliant]
-	s3Compliant := val == "" || val == "true"
+	limits := multipartLimitsFromOptions(response.Options)
+	c.multipartThreshold.put(c.baseURL.String(), limits)
 
-	c.logger.Logf("S3 compliant option: %v", s3Compliant)
+	if err := limits.check(response, fileSize); err != nil {
+		mgr := &uploadManager{ImageID: imageID, UploadID: response.UploadID}
+		return nil, c.abortMultipartUploadOnError(ctx, mgr, err)
+	}
+
+	store := objectStoreFromOptions(response.Options)
+
+	c.logger.Logf("Object store: %v (SHA256 checksum header: %v)", store.kind, store.requiresSHA256())
+
+	partChecksumAlgorithms := parseChecksumAlgorithms(response.Options[OptionChecksumAlgorithms])
 
 	// maintain list of completed parts which will be passed to the completion function
 	completedParts := []CompletedPart{}
@@ -366,22 +594,23 @@ func (c *Client) postFileV2Multipart(ctx context.Context, r io.ReadSeeker, fileS
 		c.logger.Logf("Uploading part %d (%d bytes)", nPart, partSize)
 
 		mgr := &uploadManager{
-			Source:   r,
-			Size:     partSize,
-			ImageID:  imageID,
-			UploadID: response.UploadID,
+			Source:             r,
+			Size:               partSize,
+			ImageID:            imageID,
+			UploadID:           response.UploadID,
+			ChecksumAlgorithms: partChecksumAlgorithms,
+			SHA256:             sha256sum,
 		}
 
-		// include "X-Amz-Content-Sha256" header only if object store is 100% S3 compatible
-		etag, err := c.multipartUploadPart(ctx, nPart, mgr, callback, s3Compliant)
+		etag, err := c.multipartUploadPart(ctx, nPart, mgr, callback, store)
 		if err != nil {
 			// error uploading part
 			c.logger.Logf("Error uploading part %d: %v", nPart, err)
 
-			if err := c.abortMultipartUpload(ctx, mgr); err != nil {
-				c.logger.Logf("Error aborting multipart upload: %v", err)
-			}
-			return nil, err
+			start := fileSize - bytesRemaining
+			err = &PartError{PartNumber: nPart, Start: start, End: start + partSize - 1, Err: err}
+
+			return nil, c.abortMultipartUploadOnError(ctx, mgr, err)
 		}
 
 		// append completed part info to list
@@ -393,10 +622,26 @@ func (c *Client) postFileV2Multipart(ctx context.Context, r io.ReadSeeker, fileS
 
 	c.logger.Logf("Uploaded %d parts", response.TotalParts)
 
-	return c.completeMultipartUpload(ctx, &completedParts, &uploadManager{
+	mgr := &uploadManager{
 		ImageID:  imageID,
 		UploadID: response.UploadID,
-	})
+	}
+
+	res, err := c.completeMultipartUpload(ctx, &completedParts, mgr)
+	if err != nil {
+		return nil, c.abortMultipartUploadOnError(ctx, mgr, err)
+	}
+
+	if c.verifyChecksums {
+		if err := verifyUploadComplete(res, sha256sum); err != nil {
+			return nil, err
+		}
+	}
+
+	// The checksums of parts are not required once the upload is complete.
+	c.partChecksums.forget(sha256sum)
+
+	return res, nil
 }
 
 // getPartSize returns number of bytes to read for "next" part. This value will
@@ -459,6 +704,8 @@ func (c *Client) legacyPostFileV2(ctx context.Context, fileSize int64, imageID s
 		Size:           fileSize,
 		SHA256Checksum: metadata["sha256sum"],
 		MD5Checksum:    metadata["md5sum"],
+		SHA512Checksum: metadata["sha512sum"],
+		CRC32CChecksum: metadata["crc32c"],
 	}
 
 	objJSON, err := c.apiCreate(ctx, postURL, body)
@@ -481,9 +728,11 @@ func (c *Client) legacyPostFileV2(ctx context.Context, fileSize int64, imageID s
 	if err != nil {
 		return nil, fmt.Errorf("error parsing presigned URL")
 	}
+	if err := c.checkPresignedURL(presignedURL); err != nil {
+		return nil, err
+	}
 
-	// parse presigned URL to determine if we need to send sha256 checksum
-	useSHA256Checksum := remoteSHA256ChecksumSupport(parsedURL)
+	store := objectStoreFromURL(parsedURL)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPut, presignedURL, callback.GetReader())
 	if err != nil {
@@ -491,11 +740,9 @@ func (c *Client) legacyPostFileV2(ctx context.Context, fileSize int64, imageID s
 	}
 
 	req.ContentLength = fileSize
-	req.Header.Set("Content-Type", "application/octet-stream")
 
-	if useSHA256Checksum {
-		req.Header.Set("x-amz-content-sha256", metadata["sha256sum"])
-	}
+	store.modifyRequest(req, checksums{ChecksumSHA256: metadata["sha256sum"]}, false)
+	setRequestID(req)
 
 	resp, err := c.httpClient.Do(req)
 	callback.Finish()
@@ -504,8 +751,16 @@ func (c *Client) legacyPostFileV2(ctx context.Context, fileSize int64, imageID s
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error uploading image: HTTP status %d", resp.StatusCode)
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, withResponseRequestID(fmt.Errorf("error uploading image: %w", objectStoreErrorFromResponse(resp)), resp)
+	}
+
+	transferStatsFromContext(ctx).addPart(fileSize)
+
+	if c.verifyChecksums && metadata["md5sum"] != "" {
+		if _, err := verifyETag(0, resp.Header.Get("ETag"), metadata["md5sum"]); err != nil {
+			return nil, err
+		}
 	}
 
 	// send (PUT) image upload completion
@@ -523,46 +778,172 @@ func (c *Client) legacyPostFileV2(ctx context.Context, fileSize int64, imageID s
 	if err := json.Unmarshal(objJSON, &uploadResp); err != nil {
 		return nil, fmt.Errorf("error decoding upload response: %v", err)
 	}
+
+	if c.verifyChecksums {
+		if err := verifyUploadComplete(&uploadResp.Data, metadata["sha256sum"]); err != nil {
+			return nil, err
+		}
+	}
 	return &uploadResp.Data, nil
 }
 
-func getPartSHA256Sum(r io.Reader, size int64) (string, error) {
-	// calculate sha256sum of part
-	tmpChunk := io.LimitReader(r, size)
-	chunkHash, _, err := sha256sum(tmpChunk)
-	return chunkHash, err
+// partChecksumAlgorithms returns the checksum algorithms to be computed for each part uploaded to
+// store.
+func (c *Client) partChecksumAlgorithms(store objectStore, advertised []ChecksumAlgorithm) []ChecksumAlgorithm {
+	var algs []ChecksumAlgorithm
+
+	// include "X-Amz-Content-Sha256" header only if object store is 100% S3 compatible
+	if store.requiresSHA256() || hasChecksumAlgorithm(advertised, ChecksumSHA256) {
+		algs = append(algs, ChecksumSHA256)
+	}
+
+	// MD5 checksum is used for comparison with ETag
+	if c.verifyChecksums {
+		algs = append(algs, ChecksumMD5)
+	}
+
+	for _, alg := range advertised {
+		if !hasChecksumAlgorithm(algs, alg) {
+			algs = append(algs, alg)
+		}
+	}
+	return algs
 }
 
-func (c *Client) multipartUploadPart(ctx context.Context, partNumber int, m *uploadManager, callback UploadCallback, includeSHA256ChecksumHeader bool) (string, error) {
-	var chunkHash string
-	var err error
+func (c *Client) multipartUploadPart(ctx context.Context, partNumber int, m *uploadManager, callback UploadCallback, store objectStore) (string, error) {
+	// record offset of part, so it can be re-read if the upload of the part is re-attempted
+	offset, err := m.Source.Seek(0, io.SeekCurrent)
+	if err != nil {
+		c.logger.Logf("Error determining file pointer: %v", err)
+		return "", err
+	}
+
+	sums := checksums{}
+
+	if algs := c.partChecksumAlgorithms(store, m.ChecksumAlgorithms); len(algs) > 0 {
+		if cached, ok := c.partChecksums.get(m.SHA256, offset, m.Size, algs); ok {
+			c.logger.Logf("Using cached checksums of part %d", partNumber)
+
+			sums = cached
+		} else {
+			// calculate checksums of part being uploaded
+			if sums, _, err = computeChecksums(io.LimitReader(m.Source, m.Size), algs); err != nil {
+				c.logger.Logf("Error calculating part checksums: %v", err)
+				return "", err
+			}
+
+			c.partChecksums.put(m.SHA256, offset, m.Size, sums)
+
+			// rollback file pointer to beginning of part
+			if _, err := m.Source.Seek(offset, io.SeekStart); err != nil {
+				c.logger.Logf("Error repositioning file pointer: %v", err)
+				return "", err
+			}
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		etag, err := c.uploadPart(ctx, partNumber, m, callback, store, sums, offset)
+		if err == nil && c.verifyChecksums {
+			var verified bool
+			if verified, err = verifyETag(partNumber, etag, sums[ChecksumMD5]); err == nil && !verified {
+				c.logger.Logf("Part %d ETag is not an MD5 checksum; skipping verification", partNumber)
+			}
+		}
+		if err == nil {
+			c.logger.Logf("Part %d accepted (ETag: %s)", partNumber, etag)
+
+			transferStatsFromContext(ctx).addPart(m.Size)
+
+			return etag, nil
+		}
+
+		if ctx.Err() != nil || !isRetryable(err) {
+			return "", err
+		}
+
+		delay, retry := c.retryPolicy.Retry(attempt, err)
+		if !retry {
+			return "", err
+		}
+
+		c.logger.Logf("Error uploading part %d (attempt %d): %v; retrying in %v", partNumber, attempt+1, err, delay)
+
+		transferStatsFromContext(ctx).addRetry()
 
-	if includeSHA256ChecksumHeader {
-		// calculate sha256sum of part being uploaded
-		chunkHash, err = getPartSHA256Sum(m.Source, int64(m.Size))
+		// back off before re-attempting upload of part
+		if err := c.sleeper.Sleep(ctx, delay); err != nil {
+			return "", err
+		}
+
+		if err := c.rewindPart(m, callback, offset); err != nil {
+			return "", err
+		}
+	}
+}
+
+// rewindPart repositions the source to offset, the beginning of the part being uploaded, so that
+// the upload of the part may be re-attempted. The content read since is reported to callback, if
+// it is a RewindUploadCallback.
+func (c *Client) rewindPart(m *uploadManager, callback UploadCallback, offset int64) error {
+	pos, err := m.Source.Seek(0, io.SeekCurrent)
+	if err == nil {
+		_, err = m.Source.Seek(offset, io.SeekStart)
+	}
+	if err != nil {
+		c.logger.Logf("Error repositioning file pointer: %v", err)
+		return err
+	}
+
+	if rc, ok := callback.(RewindUploadCallback); ok && pos > offset {
+		rc.Rewind(pos - offset)
+	}
+	return nil
+}
+
+// uploadPart makes a single attempt to upload the part located at offset in the source. If the
+// presigned URL is rejected by the object store, a fresh presigned URL is requested.
+func (c *Client) uploadPart(ctx context.Context, partNumber int, m *uploadManager, callback UploadCallback, store objectStore, sums checksums, offset int64) (string, error) {
+	for attempt := 0; ; attempt++ {
+		presignedURL, err := c.getPartPresignedURL(ctx, partNumber, m, store, sums)
 		if err != nil {
-			c.logger.Logf("Error calculating SHA256 checksum: %v", err)
 			return "", err
 		}
 
-		// rollback file pointer to beginning of part
-		if _, err := m.Source.Seek(-(int64(m.Size)), io.SeekCurrent); err != nil {
-			c.logger.Logf("Error repositioning file pointer: %v", err)
+		etag, err := c.putPart(ctx, presignedURL, m, callback, store, sums)
+		if !errors.Is(err, errPresignedURLExpired) || attempt >= maxPresignedURLRenewals {
+			return etag, err
+		}
+
+		c.logger.Logf("Presigned URL for part %d rejected; requesting a new one", partNumber)
+
+		transferStatsFromContext(ctx).addRetry()
+
+		if err := c.rewindPart(m, callback, offset); err != nil {
 			return "", err
 		}
 	}
+}
 
-	// send request to cloud-library for presigned PUT url
+// getPartPresignedURL requests a presigned PUT URL for the specified part from cloud-library.
+func (c *Client) getPartPresignedURL(ctx context.Context, partNumber int, m *uploadManager, store objectStore, sums checksums) (string, error) {
 	uri := fmt.Sprintf("v2/imagefile/%s/_multipart", m.ImageID)
 
 	c.logger.Logf("multipartUploadPart calling %s", uri)
 
-	objJSON, err := c.apiUpdate(ctx, uri, UploadImagePartRequest{
+	body := UploadImagePartRequest{
 		PartSize:       m.Size,
 		UploadID:       m.UploadID,
 		PartNumber:     partNumber,
-		SHA256Checksum: chunkHash,
-	})
+		SHA512Checksum: sums[ChecksumSHA512],
+		CRC32CChecksum: sums[ChecksumCRC32C],
+	}
+
+	if store.requiresSHA256() || hasChecksumAlgorithm(m.ChecksumAlgorithms, ChecksumSHA256) {
+		body.SHA256Checksum = sums[ChecksumSHA256]
+	}
+
+	objJSON, err := c.apiUpdate(ctx, uri, body)
 	if err != nil {
 		return "", err
 	}
@@ -571,18 +952,27 @@ func (c *Client) multipartUploadPart(ctx context.Context, partNumber int, m *upl
 	if err := json.Unmarshal(objJSON, &res); err != nil {
 		return "", err
 	}
+	if err := c.checkPresignedURL(res.Data.PresignedURL); err != nil {
+		return "", err
+	}
+	return res.Data.PresignedURL, nil
+}
 
-	// send request to S3
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, res.Data.PresignedURL, io.LimitReader(callback.GetReader(), m.Size))
+// putPart uploads a single part to the object store using presignedURL, returning the token
+// identifying the part (ie. the ETag for S3 compatible object stores).
+func (c *Client) putPart(ctx context.Context, presignedURL string, m *uploadManager, callback UploadCallback, store objectStore, sums checksums) (string, error) {
+	ctx, cancel := c.partContext(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, presignedURL, io.LimitReader(callback.GetReader(), m.Size))
 	if err != nil {
 		return "", fmt.Errorf("error creating request: %v", err)
 	}
 
 	// add headers to be signed
 	req.ContentLength = m.Size
-	if includeSHA256ChecksumHeader {
-		req.Header.Add("x-amz-content-sha256", chunkHash)
-	}
+	store.modifyRequest(req, sums, true)
+	setRequestID(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -591,17 +981,19 @@ func (c *Client) multipartUploadPart(ctx context.Context, partNumber int, m *upl
 	}
 	defer resp.Body.Close()
 
-	// process response from S3
-	if resp.StatusCode != http.StatusOK {
-		c.logger.Logf("Object store returned an error: %d", resp.StatusCode)
-		return "", fmt.Errorf("object store returned an error: %d", resp.StatusCode)
-	}
+	// process response from object store
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		err := objectStoreErrorFromResponse(resp)
 
-	etag := resp.Header.Get("ETag")
+		c.logger.Logf("Upload to object store failed: %v", err)
 
-	c.logger.Logf("Part %d accepted (ETag: %s)", partNumber, etag)
+		if err.expired() {
+			return "", withResponseRequestID(fmt.Errorf("%w: %w", errPresignedURLExpired, err), resp)
+		}
+		return "", withResponseRequestID(err, resp)
+	}
 
-	return etag, nil
+	return store.partToken(resp), nil
 }
 
 func (c *Client) completeMultipartUpload(ctx context.Context, completedParts *[]CompletedPart, m *uploadManager) (*UploadImageComplete, error) {
@@ -628,7 +1020,7 @@ func (c *Client) completeMultipartUpload(ctx context.Context, completedParts *[]
 		return nil, err
 	}
 
-	if res.Data.ContainerURL == "" {
+	if res.Data.ContainerURL == "" && res.Data.SHA256Checksum == "" {
 		// success w/o detailed upload complete response
 		return nil, nil
 	}
@@ -636,6 +1028,21 @@ func (c *Client) completeMultipartUpload(ctx context.Context, completedParts *[]
 	return &res.Data, nil
 }
 
+// abortMultipartUploadOnError aborts the multipart upload described by m following error err,
+// which is returned along with any error encountered aborting the upload. The upload is aborted
+// even if ctx has been cancelled, so that the upload session is not left dangling.
+func (c *Client) abortMultipartUploadOnError(ctx context.Context, m *uploadManager, err error) error {
+	ctx, cancel := cleanupContext(ctx)
+	defer cancel()
+
+	if abortErr := c.abortMultipartUpload(ctx, m); abortErr != nil {
+		c.logger.Logf("Error aborting multipart upload: %v", abortErr)
+
+		return errors.Join(err, fmt.Errorf("error aborting multipart upload: %w", abortErr))
+	}
+	return err
+}
+
 func (c *Client) abortMultipartUpload(ctx context.Context, m *uploadManager) error {
 	c.logger.Logf("Aborting multipart upload ID: %s", m.UploadID)
 
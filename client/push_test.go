@@ -8,12 +8,16 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 
 	jsonresp "github.com/sylabs/json-resp"
@@ -491,3 +495,844 @@ func Test_startMultipartUpload(t *testing.T) {
 		})
 	}
 }
+
+func Test_multipartUploadPartExpiredURL(t *testing.T) {
+	const imageID = "5cb9c34d7d960d82f5f5bc55"
+
+	tests := []struct {
+		name          string
+		rejectedPUTs  int
+		notExpired    bool // rejections deny access, rather than report expiry
+		wantURLs      int
+		expectError   bool
+		wantPartBytes string
+	}{
+		{"NotExpired", 0, false, 1, false, "0123456789"},
+		{"ExpiredOnce", 1, false, 2, false, "0123456789"},
+		{"AlwaysExpired", 2, false, 2, true, ""},
+		{"Forbidden", 1, true, 1, true, ""},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var urlRequests, putRequests int
+			var got []byte
+
+			mux := http.NewServeMux()
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			mux.HandleFunc("/v2/imagefile/"+imageID+"/_multipart", func(w http.ResponseWriter, _ *http.Request) {
+				urlRequests++
+
+				response := UploadImagePart{PresignedURL: fmt.Sprintf("%v/s3/part?attempt=%d", srv.URL, urlRequests)}
+				if err := jsonresp.WriteResponse(w, &response, http.StatusOK); err != nil {
+					t.Fatalf("error writing JSON response: %v", err)
+				}
+			})
+			mux.HandleFunc("/s3/part", func(w http.ResponseWriter, r *http.Request) {
+				putRequests++
+
+				b, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Fatalf("error reading part: %v", err)
+				}
+
+				if putRequests <= tt.rejectedPUTs {
+					w.WriteHeader(http.StatusForbidden)
+					if !tt.notExpired {
+						_, _ = io.WriteString(w, expiredURLBody)
+					}
+					return
+				}
+				got = b
+
+				w.Header().Set("ETag", "etag")
+				w.WriteHeader(http.StatusOK)
+			})
+
+			c, err := NewClient(&Config{AuthToken: testToken, BaseURL: srv.URL, Logger: testLogger, UploadPartRetries: -1})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			r := strings.NewReader("0123456789")
+
+			m := &uploadManager{
+				Source:   r,
+				Size:     r.Size(),
+				ImageID:  imageID,
+				UploadID: "uploadID",
+			}
+
+			etag, err := c.multipartUploadPart(context.Background(), 1, m, &defaultUploadCallback{r: r}, objectStore{kind: ObjectStoreS3, s3Compliant: true})
+			if (err != nil) != tt.expectError {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got, want := urlRequests, tt.wantURLs; got != want {
+				t.Errorf("got %v presigned URL requests, want %v", got, want)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got, want := etag, "etag"; got != want {
+				t.Errorf("got ETag %v, want %v", got, want)
+			}
+
+			if got, want := string(got), tt.wantPartBytes; got != want {
+				t.Errorf("got part data %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// rewindUploadCallback is a RewindUploadCallback that records the net number of bytes read, as a
+// progress bar would.
+type rewindUploadCallback struct {
+	r io.Reader
+	n int64
+}
+
+func (c *rewindUploadCallback) InitUpload(_ int64, r io.Reader) { c.r = r }
+func (c *rewindUploadCallback) GetReader() io.Reader            { return c }
+func (c *rewindUploadCallback) Terminate()                      {}
+func (c *rewindUploadCallback) Finish()                         {}
+func (c *rewindUploadCallback) Rewind(n int64)                  { c.n -= n }
+
+func (c *rewindUploadCallback) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func Test_multipartUploadPartRetry(t *testing.T) {
+	const imageID = "5cb9c34d7d960d82f5f5bc55"
+
+	tests := []struct {
+		name        string
+		retries     int
+		failedPUTs  int
+		failCode    int
+		wantPUTs    int
+		expectError bool
+	}{
+		{"NoFailures", 2, 0, http.StatusInternalServerError, 1, false},
+		{"Recovered", 2, 2, http.StatusInternalServerError, 3, false},
+		{"Exhausted", 2, 3, http.StatusInternalServerError, 3, true},
+		{"Disabled", -1, 1, http.StatusInternalServerError, 1, true},
+		{"TooManyRequests", 2, 1, http.StatusTooManyRequests, 2, false},
+		{"Forbidden", 2, 1, http.StatusForbidden, 1, true},
+		{"BadRequest", 2, 1, http.StatusBadRequest, 1, true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var putRequests int
+			var got []byte
+
+			mux := http.NewServeMux()
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			mux.HandleFunc("/v2/imagefile/"+imageID+"/_multipart", func(w http.ResponseWriter, _ *http.Request) {
+				response := UploadImagePart{PresignedURL: srv.URL + "/s3/part"}
+				if err := jsonresp.WriteResponse(w, &response, http.StatusOK); err != nil {
+					t.Fatalf("error writing JSON response: %v", err)
+				}
+			})
+			mux.HandleFunc("/s3/part", func(w http.ResponseWriter, r *http.Request) {
+				putRequests++
+
+				b, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Fatalf("error reading part: %v", err)
+				}
+
+				if putRequests <= tt.failedPUTs {
+					w.WriteHeader(tt.failCode)
+					return
+				}
+				got = b
+
+				w.Header().Set("ETag", "etag")
+				w.WriteHeader(http.StatusOK)
+			})
+
+			c, err := NewClient(&Config{AuthToken: testToken, BaseURL: srv.URL, Logger: testLogger, UploadPartRetries: tt.retries})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+			c.sleeper = noSleep
+
+			// Upload second part of source, to ensure source is rewound to part offset
+			r := strings.NewReader("0123456789abcdefghij")
+			if _, err := r.Seek(10, io.SeekStart); err != nil {
+				t.Fatal(err)
+			}
+
+			m := &uploadManager{
+				Source:   r,
+				Size:     10,
+				ImageID:  imageID,
+				UploadID: "uploadID",
+			}
+
+			callback := &rewindUploadCallback{r: r}
+
+			_, err = c.multipartUploadPart(context.Background(), 2, m, callback, objectStore{kind: ObjectStoreS3, s3Compliant: true})
+			if (err != nil) != tt.expectError {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got, want := putRequests, tt.wantPUTs; got != want {
+				t.Errorf("got %v PUT requests, want %v", got, want)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got, want := string(got), "abcdefghij"; got != want {
+				t.Errorf("got part data %q, want %q", got, want)
+			}
+			if got, want := callback.n, m.Size; got != want {
+				t.Errorf("got %v bytes of progress, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_multipartUploadPartVerifyETag(t *testing.T) {
+	const imageID = "5cb9c34d7d960d82f5f5bc55"
+
+	tests := []struct {
+		name        string
+		etag        string
+		expectError bool
+	}{
+		// MD5 checksum of "0123456789"
+		{"Match", `"781e5e245d69b566979b86e28d23f2c7"`, false},
+		{"Mismatch", `"00000000000000000000000000000000"`, true},
+		{"NotMD5", `"opaque-etag"`, false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			mux.HandleFunc("/v2/imagefile/"+imageID+"/_multipart", func(w http.ResponseWriter, _ *http.Request) {
+				response := UploadImagePart{PresignedURL: srv.URL + "/s3/part"}
+				if err := jsonresp.WriteResponse(w, &response, http.StatusOK); err != nil {
+					t.Fatalf("error writing JSON response: %v", err)
+				}
+			})
+			mux.HandleFunc("/s3/part", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("ETag", tt.etag)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			c, err := NewClient(&Config{
+				AuthToken:             testToken,
+				BaseURL:               srv.URL,
+				Logger:                testLogger,
+				UploadPartRetries:     -1,
+				VerifyUploadChecksums: true,
+			})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			r := strings.NewReader("0123456789")
+
+			m := &uploadManager{
+				Source:   r,
+				Size:     r.Size(),
+				ImageID:  imageID,
+				UploadID: "uploadID",
+			}
+
+			_, err = c.multipartUploadPart(context.Background(), 1, m, &defaultUploadCallback{r: r}, objectStore{kind: ObjectStoreS3})
+			if (err != nil) != tt.expectError {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var cme *ChecksumMismatchError
+			if err != nil && !errors.As(err, &cme) {
+				t.Fatalf("got error type %T, want %T", err, cme)
+			}
+		})
+	}
+}
+
+func Test_multipartUploadPartChecksums(t *testing.T) {
+	const imageID = "5cb9c34d7d960d82f5f5bc55"
+
+	tests := []struct {
+		name             string
+		store            objectStore
+		advertised       []ChecksumAlgorithm
+		wantRequest      UploadImagePartRequest
+		wantCRC32C       string
+		wantSHA256Header string
+	}{
+		{
+			name:  "S3Compliant",
+			store: objectStore{kind: ObjectStoreS3, s3Compliant: true},
+			wantRequest: UploadImagePartRequest{
+				SHA256Checksum: "84d89877f0d4041efb6bf91a16f0248f2fd573e6af05c19f96bedb9f882f7882",
+			},
+			wantSHA256Header: "84d89877f0d4041efb6bf91a16f0248f2fd573e6af05c19f96bedb9f882f7882",
+		},
+		{
+			name:        "S3NotCompliant",
+			store:       objectStore{kind: ObjectStoreS3},
+			wantRequest: UploadImagePartRequest{},
+		},
+		{
+			name:       "CRC32CAdvertised",
+			store:      objectStore{kind: ObjectStoreS3},
+			advertised: []ChecksumAlgorithm{ChecksumCRC32C},
+			wantRequest: UploadImagePartRequest{
+				CRC32CChecksum: "KAwGng==",
+			},
+			wantCRC32C: "KAwGng==",
+		},
+		{
+			name:       "SHA512Advertised",
+			store:      objectStore{kind: ObjectStoreGCS},
+			advertised: []ChecksumAlgorithm{ChecksumSHA512},
+			wantRequest: UploadImagePartRequest{
+				SHA512Checksum: "bb96c2fc40d2d54617d6f276febe571f623a8dadf0b734855299b0e107fda32cf6b69f2da32b36445d73690b93cbd0f7bfc20e0f7f28553d2a4428f23b716e90",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRequest UploadImagePartRequest
+			var gotHeader http.Header
+
+			mux := http.NewServeMux()
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			mux.HandleFunc("/v2/imagefile/"+imageID+"/_multipart", func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&gotRequest); err != nil {
+					t.Fatalf("error decoding request: %v", err)
+				}
+
+				response := UploadImagePart{PresignedURL: srv.URL + "/s3/part"}
+				if err := jsonresp.WriteResponse(w, &response, http.StatusOK); err != nil {
+					t.Fatalf("error writing JSON response: %v", err)
+				}
+			})
+			mux.HandleFunc("/s3/part", func(w http.ResponseWriter, r *http.Request) {
+				gotHeader = r.Header
+
+				w.Header().Set("ETag", "etag")
+				w.WriteHeader(http.StatusOK)
+			})
+
+			c, err := NewClient(&Config{AuthToken: testToken, BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			r := strings.NewReader("0123456789")
+
+			m := &uploadManager{
+				Source:             r,
+				Size:               r.Size(),
+				ImageID:            imageID,
+				UploadID:           "uploadID",
+				ChecksumAlgorithms: tt.advertised,
+			}
+
+			if _, err := c.multipartUploadPart(context.Background(), 1, m, &defaultUploadCallback{r: r}, tt.store); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := tt.wantRequest
+			want.PartSize = 10
+			want.UploadID = "uploadID"
+			want.PartNumber = 1
+
+			if got := gotRequest; got != want {
+				t.Errorf("got part request %+v, want %+v", got, want)
+			}
+			if got, want := gotHeader.Get("x-amz-checksum-crc32c"), tt.wantCRC32C; got != want {
+				t.Errorf("got CRC32C header %q, want %q", got, want)
+			}
+			if got, want := gotHeader.Get("x-amz-content-sha256"), tt.wantSHA256Header; got != want {
+				t.Errorf("got SHA256 header %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestImageUploaded(t *testing.T) {
+	const sha256sum = "e50a30881ace3d5944f5661d222db7bee5296be9e4dc7c1fcb7604bcae926e88"
+
+	tests := []struct {
+		name        string
+		path        string
+		hash        string
+		code        int
+		body        interface{}
+		want        bool
+		expectError bool
+	}{
+		{"Uploaded", "library://test-user/test-collection/test-container", sha256sum, http.StatusOK, ImageResponse{Data: Image{Uploaded: true}}, true, false},
+		{"PrefixedHash", "test-user/test-collection/test-container", "sha256." + sha256sum, http.StatusOK, ImageResponse{Data: Image{Uploaded: true}}, true, false},
+		{"NotUploaded", "test-user/test-collection/test-container", sha256sum, http.StatusOK, ImageResponse{Data: Image{Uploaded: false}}, false, false},
+		{"NotFound", "test-user/test-collection/test-container", sha256sum, http.StatusNotFound, nil, false, false},
+		{"ServerError", "test-user/test-collection/test-container", sha256sum, http.StatusInternalServerError, nil, false, true},
+		{"BadPath", "test-container", sha256sum, http.StatusOK, nil, false, true},
+		{"BadHash", "test-user/test-collection/test-container", "abc", http.StatusOK, nil, false, true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			m := mockService{
+				t:        t,
+				code:     tt.code,
+				body:     tt.body,
+				httpPath: "/v1/images/test-user/test-collection/test-container:sha256." + sha256sum,
+			}
+
+			m.Run()
+			defer m.Stop()
+
+			c, err := NewClient(&Config{AuthToken: testToken, BaseURL: m.baseURI, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			uploaded, err := c.ImageUploaded(context.Background(), tt.path, tt.hash)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got, want := uploaded, tt.want; got != want {
+				t.Errorf("got uploaded %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestImageUploadedOCI(t *testing.T) {
+	const sha256sum = "e50a30881ace3d5944f5661d222db7bee5296be9e4dc7c1fcb7604bcae926e88"
+
+	tests := []struct {
+		name         string
+		redirectCode int
+		blobCode     int
+		strict       bool
+		want         bool
+		wantErr      bool
+	}{
+		{"Uploaded", http.StatusOK, http.StatusOK, false, true, false},
+		{"NotFound", http.StatusOK, http.StatusNotFound, false, false, false},
+		{"RegistryError", http.StatusOK, http.StatusInternalServerError, false, false, true},
+		{"Forbidden", http.StatusOK, http.StatusForbidden, false, false, true},
+		{"RedirectErrorStrict", http.StatusInternalServerError, http.StatusOK, true, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			mux.HandleFunc("/v1/oci-redirect", func(w http.ResponseWriter, _ *http.Request) {
+				if tt.redirectCode != http.StatusOK {
+					w.WriteHeader(tt.redirectCode)
+					return
+				}
+				if err := json.NewEncoder(w).Encode(map[string]string{"token": "token", "url": srv.URL, "name": "name"}); err != nil {
+					t.Errorf("error encoding response: %v", err)
+				}
+			})
+			mux.HandleFunc("/v2/name/blobs/", func(w http.ResponseWriter, r *http.Request) {
+				if tt.blobCode == http.StatusOK {
+					w.Header().Set("Docker-Content-Digest", strings.TrimPrefix(r.URL.Path, "/v2/name/blobs/"))
+				}
+				w.WriteHeader(tt.blobCode)
+			})
+			mux.HandleFunc("/v1/images/", func(_ http.ResponseWriter, r *http.Request) {
+				t.Errorf("unexpected library request: %v", r.URL.Path)
+			})
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, StrictRegistryAccess: tt.strict, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			uploaded, err := c.ImageUploaded(context.Background(), "test-user/test-collection/test-container", sha256sum)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			if got, want := uploaded, tt.want; got != want {
+				t.Errorf("got uploaded %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_postFileV2MultipartCancel(t *testing.T) {
+	const imageID = "5cb9c34d7d960d82f5f5bc55"
+
+	tests := []struct {
+		name          string
+		cancelPhase   string
+		wantCompleted bool
+	}{
+		{"CancelPart", "part", false},
+		{"CancelComplete", "complete", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var aborted, completed bool
+
+			// cancelAndWait cancels the operation, and waits for the client to disconnect.
+			cancelAndWait := func(r *http.Request) {
+				if _, err := io.Copy(io.Discard, r.Body); err != nil {
+					t.Errorf("error reading request body: %v", err)
+				}
+				cancel()
+				<-r.Context().Done()
+			}
+
+			mux := http.NewServeMux()
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			mux.HandleFunc("/v2/imagefile/"+imageID+"/_multipart", func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					response := MultipartUpload{UploadID: "uploadID", TotalParts: 1, PartSize: 10}
+					if err := jsonresp.WriteResponse(w, &response, http.StatusOK); err != nil {
+						t.Errorf("error writing JSON response: %v", err)
+					}
+					return
+				}
+
+				response := UploadImagePart{PresignedURL: srv.URL + "/s3/part"}
+				if err := jsonresp.WriteResponse(w, &response, http.StatusOK); err != nil {
+					t.Errorf("error writing JSON response: %v", err)
+				}
+			})
+			mux.HandleFunc("/s3/part", func(w http.ResponseWriter, r *http.Request) {
+				if tt.cancelPhase == "part" {
+					cancelAndWait(r)
+					return
+				}
+				w.Header().Set("ETag", "etag")
+				w.WriteHeader(http.StatusOK)
+			})
+			mux.HandleFunc("/v2/imagefile/"+imageID+"/_multipart_complete", func(_ http.ResponseWriter, r *http.Request) {
+				completed = true
+				cancelAndWait(r)
+			})
+			mux.HandleFunc("/v2/imagefile/"+imageID+"/_multipart_abort", func(w http.ResponseWriter, r *http.Request) {
+				if err := r.Context().Err(); err != nil {
+					t.Errorf("unexpected context error: %v", err)
+				}
+				aborted = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			c, err := NewClient(&Config{AuthToken: testToken, BaseURL: srv.URL, Logger: testLogger, UploadPartRetries: -1})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			r := strings.NewReader("0123456789")
+
+			_, err = c.postFileV2Multipart(ctx, r, 10, imageID, &defaultUploadCallback{r: r}, "")
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("got error %v, want %v", err, context.Canceled)
+			}
+
+			if got, want := completed, tt.wantCompleted; got != want {
+				t.Errorf("got completed %v, want %v", got, want)
+			}
+
+			if !aborted {
+				t.Error("multipart upload not aborted")
+			}
+		})
+	}
+}
+
+func Test_multipartUploadPartObjectStoreError(t *testing.T) {
+	const imageID = "5cb9c34d7d960d82f5f5bc55"
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/v2/imagefile/"+imageID+"/_multipart", func(w http.ResponseWriter, _ *http.Request) {
+		response := UploadImagePart{PresignedURL: srv.URL + "/s3/part"}
+		if err := jsonresp.WriteResponse(w, &response, http.StatusOK); err != nil {
+			t.Errorf("error writing JSON response: %v", err)
+		}
+	})
+	mux.HandleFunc("/s3/part", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `<Error><Code>EntityTooSmall</Code><Message>Your proposed upload is smaller than the minimum allowed object size.</Message></Error>`)
+	})
+
+	c, err := NewClient(&Config{AuthToken: testToken, BaseURL: srv.URL, Logger: testLogger, UploadPartRetries: -1})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	r := strings.NewReader("0123456789")
+
+	m := &uploadManager{
+		Source:   r,
+		Size:     10,
+		ImageID:  imageID,
+		UploadID: "uploadID",
+	}
+
+	_, err = c.multipartUploadPart(context.Background(), 1, m, &defaultUploadCallback{r: r}, objectStore{kind: ObjectStoreS3, s3Compliant: true})
+
+	var oe *ObjectStoreError
+	if !errors.As(err, &oe) {
+		t.Fatalf("got error %v, want ObjectStoreError", err)
+	}
+
+	want := ObjectStoreError{
+		StatusCode: http.StatusBadRequest,
+		Code:       "EntityTooSmall",
+		Message:    "Your proposed upload is smaller than the minimum allowed object size.",
+	}
+	if *oe != want {
+		t.Errorf("got error %+v, want %+v", *oe, want)
+	}
+}
+
+func TestUploadImageCreateMissing(t *testing.T) {
+	var requests []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		if r.Method == http.MethodGet && r.URL.Path == "/v1/containers/entity/collection/container" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{
+		BaseURL: srv.URL,
+		Logger:  testLogger,
+		Upload:  &UploadOptions{DisableCreateMissing: true},
+	})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	_, err = c.UploadImage(context.Background(), strings.NewReader("image"), "entity/collection/container", "amd64", []string{"latest"}, "", nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, ErrNotFound)
+	}
+
+	if got, want := len(requests), 1; got != want {
+		t.Errorf("got %v request(s), want %v", got, want)
+	}
+}
+
+func Test_imageChecksumAlgorithms(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          Config
+		algs         []ChecksumAlgorithm
+		want         []ChecksumAlgorithm
+		wantDeferMD5 bool
+	}{
+		{
+			name:         "Default",
+			algs:         defaultChecksumAlgorithms,
+			want:         []ChecksumAlgorithm{ChecksumSHA256},
+			wantDeferMD5: true,
+		},
+		{
+			name: "NoMD5",
+			algs: []ChecksumAlgorithm{ChecksumSHA256, ChecksumCRC32C},
+			want: []ChecksumAlgorithm{ChecksumSHA256, ChecksumCRC32C},
+		},
+		{
+			name: "VerifyChecksums",
+			cfg:  Config{VerifyUploadChecksums: true},
+			algs: defaultChecksumAlgorithms,
+			want: defaultChecksumAlgorithms,
+		},
+		{
+			name: "PublishChecksums",
+			cfg:  Config{PublishChecksums: true},
+			algs: defaultChecksumAlgorithms,
+			want: defaultChecksumAlgorithms,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.Logger = testLogger
+
+			c, err := NewClient(&cfg)
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			got, deferMD5 := c.imageChecksumAlgorithms(tt.algs)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got algorithms %v, want %v", got, tt.want)
+			}
+			if deferMD5 != tt.wantDeferMD5 {
+				t.Errorf("got defer MD5 %v, want %v", deferMD5, tt.wantDeferMD5)
+			}
+		})
+	}
+}
+
+func Test_deferredMD5(t *testing.T) {
+	const image = "0123456789"
+
+	tests := []struct {
+		name       string
+		apiVersion string
+		want       string
+	}{
+		{"Legacy", "1.0.0", ""},
+		{"V2Upload", APIVersionV2ArchTags, "781e5e245d69b566979b86e28d23f2c7"},
+		{"SHA256Upload", APIVersionV2SHA256Upload, ""},
+		{"Unknown", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/version" {
+					t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+				}
+				if err := jsonresp.WriteResponse(w, VersionInfo{APIVersion: tt.apiVersion}, http.StatusOK); err != nil {
+					t.Errorf("error writing JSON response: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			r := strings.NewReader(image)
+			if _, err := r.Seek(4, io.SeekStart); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := c.deferredMD5(context.Background(), r)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got MD5 %q, want %q", got, tt.want)
+			}
+
+			if tt.want != "" {
+				if pos, _ := r.Seek(0, io.SeekCurrent); pos != 0 {
+					t.Errorf("got position %v, want 0", pos)
+				}
+			}
+		})
+	}
+}
+
+func Test_postFileV2RequireMultipart(t *testing.T) {
+	const imageID = "5cb9c34d7d960d82f5f5bc55"
+
+	tests := []struct {
+		name             string
+		requireMultipart bool
+		wantLegacy       bool
+	}{
+		{"Fallback", false, true},
+		{"RequireMultipart", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var legacy bool
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v2/imagefile/" + imageID + "/_multipart":
+					w.WriteHeader(http.StatusNotFound)
+				case "/v2/imagefile/" + imageID:
+					// Fail the legacy upload once requested; its content is not of interest.
+					legacy = true
+					w.WriteHeader(http.StatusBadRequest)
+				default:
+					t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			size := int64(minimumPartSize + 1)
+			r := io.NewSectionReader(zeroReaderAt{}, 0, size)
+
+			_, err = c.postFileV2(context.Background(), r, size, imageID, &defaultUploadCallback{r: r}, map[string]string{}, tt.requireMultipart)
+			if err == nil {
+				t.Fatal("unexpected success")
+			}
+
+			var me *MultipartUnsupportedError
+			if got, want := errors.As(err, &me), tt.requireMultipart; got != want {
+				t.Errorf("got multipart unsupported error %v, want %v (error: %v)", got, want, err)
+			}
+			if me != nil && me.Size != size {
+				t.Errorf("got size %v, want %v", me.Size, size)
+			}
+			if legacy != tt.wantLegacy {
+				t.Errorf("got legacy upload %v, want %v", legacy, tt.wantLegacy)
+			}
+		})
+	}
+}
+
+// zeroReaderAt reads zeroed content.
+type zeroReaderAt struct{}
+
+func (zeroReaderAt) ReadAt(p []byte, _ int64) (int, error) {
+	clear(p)
+	return len(p), nil
+}
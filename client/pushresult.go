@@ -0,0 +1,27 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import "github.com/opencontainers/go-digest"
+
+// OCIPushResult describes the artifacts published by an upload to an OCI registry, so that callers
+// may record exactly what was published (ie. to sign the image manifest, or attach referrers).
+type OCIPushResult struct {
+	// Name of the repository in the OCI registry, which may differ from the library path of the
+	// image when the library server maps names.
+	Name string
+	// Image is the digest of the image blob.
+	Image digest.Digest
+	// Config is the digest of the image configuration.
+	Config digest.Digest
+	// Manifest is the digest of the image manifest.
+	Manifest digest.Digest
+	// Signature is the digest of the detached signature manifest, if a detached signature was
+	// uploaded.
+	Signature digest.Digest
+	// Indexes maps each tag successfully applied to the digest of the image index it refers to.
+	Indexes map[string]digest.Digest
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+)
+
+func Test_ociUploadImageResult(t *testing.T) {
+	image := testPartitionSIF(t, "amd64")
+	imageDigest := digest.FromBytes(image)
+	hash := strings.Replace(imageDigest.String(), ":", ".", 1)
+
+	srv := newTestOCIPushServer(t, nil)
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	res, err := c.ociUploadImage(context.Background(), bytes.NewReader(image), int64(len(image)), "name", "amd64", []string{"latest", "v1"}, "", nil, hash, UploadOptions{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := res.Name, "name"; got != want {
+		t.Errorf("got name %v, want %v", got, want)
+	}
+	if got, want := res.Image, imageDigest; got != want {
+		t.Errorf("got image digest %v, want %v", got, want)
+	}
+	for _, d := range []digest.Digest{res.Config, res.Manifest} {
+		if err := d.Validate(); err != nil {
+			t.Errorf("invalid digest %q: %v", d, err)
+		}
+	}
+	if res.Signature != "" {
+		t.Errorf("got signature digest %v, want none", res.Signature)
+	}
+	if got, want := len(res.Indexes), 2; got != want {
+		t.Fatalf("got %v indexes, want %v", got, want)
+	}
+	for _, tag := range []string{"latest", "v1"} {
+		if err := res.Indexes[tag].Validate(); err != nil {
+			t.Errorf("invalid index digest for tag %v: %v", tag, err)
+		}
+	}
+}
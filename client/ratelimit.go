@@ -0,0 +1,92 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// rateLimitChunkSize is the maximum number of bytes read from a rate limited reader at once, so
+// that content is delivered at an even pace.
+const rateLimitChunkSize = 32 * 1024
+
+// rateLimiter limits the rate at which bytes are transferred, across all readers sharing it.
+type rateLimiter struct {
+	rate    int64 // bytes per second
+	sleeper Sleeper
+	now     func() time.Time
+
+	mu   sync.Mutex
+	next time.Time // time at which the next transfer may begin
+}
+
+// newRateLimiter returns a rateLimiter that permits rate bytes per second, waiting using sleeper.
+func newRateLimiter(rate int64, sleeper Sleeper) *rateLimiter {
+	return &rateLimiter{rate: rate, sleeper: sleeper, now: time.Now}
+}
+
+// wait blocks until a transfer of n bytes is permitted, or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	l.mu.Lock()
+	now := l.now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	d := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(n) * time.Second / time.Duration(l.rate))
+	l.mu.Unlock()
+
+	if d <= 0 {
+		return nil
+	}
+	return l.sleeper.Sleep(ctx, d)
+}
+
+type rateLimiterKey struct{}
+
+// withRateLimiter returns a context carrying l.
+func withRateLimiter(ctx context.Context, l *rateLimiter) context.Context {
+	return context.WithValue(ctx, rateLimiterKey{}, l)
+}
+
+// rateLimiterFromContext returns the *rateLimiter carried by ctx, or nil if not present.
+func rateLimiterFromContext(ctx context.Context) *rateLimiter {
+	l, _ := ctx.Value(rateLimiterKey{}).(*rateLimiter)
+	return l
+}
+
+// rateLimitedReader is an io.Reader that limits the rate at which content is read.
+type rateLimitedReader struct {
+	r    io.Reader
+	wait func(n int) error
+}
+
+// limitReader returns a reader that reads from r at the rate permitted by the rate limiter carried
+// by ctx, or r if ctx does not carry a rate limiter.
+func limitReader(ctx context.Context, r io.Reader) io.Reader {
+	l := rateLimiterFromContext(ctx)
+	if l == nil {
+		return r
+	}
+	return &rateLimitedReader{r: r, wait: func(n int) error { return l.wait(ctx, n) }}
+}
+
+func (r *rateLimitedReader) Read(p []byte) (int, error) {
+	if len(p) > rateLimitChunkSize {
+		p = p[:rateLimitChunkSize]
+	}
+
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if werr := r.wait(n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
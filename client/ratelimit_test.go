@@ -0,0 +1,82 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	s := &recordingSleeper{}
+
+	now := time.Unix(0, 0)
+
+	l := newRateLimiter(1024, s)
+	l.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	// The first transfer is permitted immediately, and subsequent transfers are delayed until the
+	// preceding transfers would complete at the permitted rate.
+	for _, n := range []int{1024, 512, 512} {
+		if err := l.wait(ctx, n); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if got, want := s.delays, []time.Duration{time.Second, 1500 * time.Millisecond}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got delays %v, want %v", got, want)
+	}
+
+	// Once idle, transfers are again permitted immediately.
+	now = now.Add(time.Minute)
+	s.delays = nil
+
+	if err := l.wait(ctx, 1024); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(s.delays) != 0 {
+		t.Errorf("got delays %v, want none", s.delays)
+	}
+}
+
+func TestLimitReader(t *testing.T) {
+	r := bytes.NewReader(make([]byte, 4*rateLimitChunkSize))
+
+	if got := limitReader(context.Background(), r); got != io.Reader(r) {
+		t.Error("reader unexpectedly limited")
+	}
+
+	s := &recordingSleeper{}
+
+	ctx := withRateLimiter(context.Background(), newRateLimiter(rateLimitChunkSize, s))
+
+	// Reads are limited to rateLimitChunkSize, so the content is delivered in four reads.
+	if _, err := io.ReadFull(limitReader(ctx, r), make([]byte, 4*rateLimitChunkSize)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := len(s.delays), 3; got != want {
+		t.Errorf("got %v delays, want %v", got, want)
+	}
+
+	// Waits are abandoned when the context is done.
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	r.Reset(make([]byte, 4*rateLimitChunkSize))
+
+	if _, err := io.Copy(io.Discard, limitReader(ctx, r)); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// GetContainerReadme returns the README (long-form description, typically Markdown) of the
+// container identified by ref (ie. "entity/collection/container"), as displayed by the web UI.
+// Returns ErrNotFound if the container is not found.
+func (c *Client) GetContainerReadme(ctx context.Context, ref string) (string, error) {
+	co, err := c.GetContainer(ctx, strings.TrimPrefix(ref, "/"))
+	if err != nil {
+		return "", err
+	}
+	return co.FullDescription, nil
+}
+
+// SetContainerReadme replaces the README (long-form description, typically Markdown) of the
+// container identified by ref (ie. "entity/collection/container"), so that documentation may be
+// published alongside images. An empty readme removes the README.
+func (c *Client) SetContainerReadme(ctx context.Context, ref, readme string) error {
+	path := "v1/containers/" + strings.TrimPrefix(ref, "/") + "/_readme"
+
+	if _, err := c.apiUpdate(ctx, path, ReadmeRequest{FullDescription: readme}); err != nil {
+		return fmt.Errorf("error setting container README: %w", err)
+	}
+	return nil
+}
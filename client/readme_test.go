@@ -0,0 +1,69 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func TestContainerReadme(t *testing.T) {
+	const readme = "# Container\n\nUsage instructions."
+
+	var stored string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/containers/entity/collection/container":
+			if err := jsonresp.WriteResponse(w, Container{Name: "container", FullDescription: stored}, http.StatusOK); err != nil {
+				t.Errorf("error writing JSON response: %v", err)
+			}
+		case r.Method == http.MethodPut && r.URL.Path == "/v1/containers/entity/collection/container/_readme":
+			var body ReadmeRequest
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("error decoding request: %v", err)
+			}
+			stored = body.FullDescription
+
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	if err := c.SetContainerReadme(context.Background(), "entity/collection/container", readme); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := c.GetContainerReadme(context.Background(), "entity/collection/container")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got != readme {
+		t.Errorf("got readme %q, want %q", got, readme)
+	}
+
+	if _, err := c.GetContainerReadme(context.Background(), "entity/collection/missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, ErrNotFound)
+	}
+
+	if err := c.SetContainerReadme(context.Background(), "entity/collection/missing", readme); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, ErrNotFound)
+	}
+}
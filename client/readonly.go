@@ -0,0 +1,88 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+var (
+	// ErrReadOnly is returned when the library server rejects a modification (such as a push)
+	// because the target container or collection is read-only (HTTP status 423).
+	ErrReadOnly = errors.New("container or collection is read-only")
+
+	// ErrConflict is returned when the library server rejects a modification (such as a push)
+	// because it conflicts with existing state, for example replacing a tag that may not be
+	// overwritten (HTTP status 409).
+	ErrConflict = errors.New("conflict with existing state")
+)
+
+// modificationError returns an error wrapping ErrReadOnly or ErrConflict, along with the error
+// returned by the server (if any), if res has HTTP status 423 or 409 respectively. Otherwise, nil
+// is returned. If an error is returned, the response body is consumed.
+func modificationError(res *http.Response) error {
+	var err error
+
+	switch res.StatusCode {
+	case http.StatusLocked:
+		err = ErrReadOnly
+	case http.StatusConflict:
+		err = ErrConflict
+	default:
+		return nil
+	}
+
+	if serverErr := jsonresp.ReadError(res.Body); serverErr != nil {
+		err = fmt.Errorf("%w: %v", err, serverErr)
+	}
+	return withResponseRequestID(err, res)
+}
+
+// SetContainerReadOnly marks the container identified by ref (ie. "entity/collection/container")
+// read-only (frozen), or writable. Pushes to a read-only container are rejected with ErrReadOnly,
+// preventing released images from being overwritten.
+func (c *Client) SetContainerReadOnly(ctx context.Context, ref string, readOnly bool) error {
+	if _, err := c.apiUpdate(ctx, "v1/containers/"+ref+"/_readonly", ReadOnlyRequest{ReadOnly: readOnly}); err != nil {
+		return fmt.Errorf("error setting container read-only state: %w", err)
+	}
+	return nil
+}
+
+// SetCollectionReadOnly marks the collection identified by ref (ie. "entity/collection")
+// read-only (frozen), or writable. Pushes to containers within a read-only collection are
+// rejected with ErrReadOnly.
+func (c *Client) SetCollectionReadOnly(ctx context.Context, ref string, readOnly bool) error {
+	if _, err := c.apiUpdate(ctx, "v1/collections/"+ref+"/_readonly", ReadOnlyRequest{ReadOnly: readOnly}); err != nil {
+		return fmt.Errorf("error setting collection read-only state: %w", err)
+	}
+	return nil
+}
+
+// ContainerReadOnly returns true if the container identified by ref (ie.
+// "entity/collection/container") is read-only (frozen). Returns ErrNotFound if the container is
+// not found.
+func (c *Client) ContainerReadOnly(ctx context.Context, ref string) (bool, error) {
+	co, err := c.GetContainer(ctx, ref)
+	if err != nil {
+		return false, err
+	}
+	return co.ReadOnly, nil
+}
+
+// CollectionReadOnly returns true if the collection identified by ref (ie. "entity/collection")
+// is read-only (frozen). Returns ErrNotFound if the collection is not found.
+func (c *Client) CollectionReadOnly(ctx context.Context, ref string) (bool, error) {
+	co, err := c.GetCollection(ctx, ref)
+	if err != nil {
+		return false, err
+	}
+	return co.ReadOnly, nil
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func TestSetReadOnly(t *testing.T) {
+	tests := []struct {
+		name     string
+		set      func(c *Client, readOnly bool) error
+		wantPath string
+	}{
+		{
+			name: "Container",
+			set: func(c *Client, readOnly bool) error {
+				return c.SetContainerReadOnly(context.Background(), "entity/collection/container", readOnly)
+			},
+			wantPath: "/v1/containers/entity/collection/container/_readonly",
+		},
+		{
+			name: "Collection",
+			set: func(c *Client, readOnly bool) error {
+				return c.SetCollectionReadOnly(context.Background(), "entity/collection", readOnly)
+			},
+			wantPath: "/v1/collections/entity/collection/_readonly",
+		},
+	}
+
+	for _, tt := range tests {
+		for _, readOnly := range []bool{true, false} {
+			t.Run(tt.name, func(t *testing.T) {
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if got, want := r.Method, http.MethodPut; got != want {
+						t.Errorf("got method %v, want %v", got, want)
+					}
+					if got, want := r.URL.Path, tt.wantPath; got != want {
+						t.Errorf("got path %v, want %v", got, want)
+					}
+
+					var body ReadOnlyRequest
+					if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+						t.Errorf("error decoding request: %v", err)
+					}
+					if got, want := body.ReadOnly, readOnly; got != want {
+						t.Errorf("got read-only %v, want %v", got, want)
+					}
+
+					w.WriteHeader(http.StatusNoContent)
+				}))
+				defer srv.Close()
+
+				c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+				if err != nil {
+					t.Fatalf("error initializing client: %v", err)
+				}
+
+				if err := tt.set(c, readOnly); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestContainerReadOnly(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/containers/entity/collection/container":
+			if err := jsonresp.WriteResponse(w, &Container{ReadOnly: true}, http.StatusOK); err != nil {
+				t.Errorf("error writing JSON response: %v", err)
+			}
+		case "/v1/collections/entity/collection":
+			if err := jsonresp.WriteResponse(w, &Collection{}, http.StatusOK); err != nil {
+				t.Errorf("error writing JSON response: %v", err)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	if ro, err := c.ContainerReadOnly(context.Background(), "entity/collection/container"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	} else if !ro {
+		t.Error("got container writable, want read-only")
+	}
+
+	if ro, err := c.CollectionReadOnly(context.Background(), "entity/collection"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	} else if ro {
+		t.Error("got collection read-only, want writable")
+	}
+
+	if _, err := c.ContainerReadOnly(context.Background(), "entity/collection/other"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestModificationError(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    int
+		wantErr error
+	}{
+		{"Locked", http.StatusLocked, ErrReadOnly},
+		{"Conflict", http.StatusConflict, ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if err := jsonresp.WriteError(w, "container is frozen", tt.code); err != nil {
+					t.Errorf("error writing JSON error: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			err = c.setTagV2(context.Background(), "containerID", ArchImageTag{Arch: "amd64", Tag: "latest", ImageID: "imageID"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+
+			_, err = c.createImage(context.Background(), "sha256."+testImageSHA256, "containerID", "", nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
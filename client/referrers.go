@@ -0,0 +1,139 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// artifactRef returns the container name and reference (tag or digest) of ref (ie.
+// "entity/collection/container:tag" or "entity/collection/container@sha256:..."). If ref does not
+// specify a tag or digest, "latest" is returned.
+func artifactRef(ref string) (name, reference string) {
+	ref = strings.TrimPrefix(ref, "/")
+
+	if name, d, ok := strings.Cut(ref, "@"); ok {
+		return name, d
+	}
+
+	name, reference, _ = strings.Cut(ref, ":")
+	if reference == "" {
+		reference = "latest"
+	}
+	return name, reference
+}
+
+// uploadArtifact uploads an artifact of type artifactType to the registry, consisting of a single
+// layer containing b of type mediaType. The artifact manifest refers to the manifest described by
+// subject, and is annotated with annotations. On success, the artifact manifest digest is
+// returned.
+func (r *ociRegistry) uploadArtifact(ctx context.Context, creds credentials, name, artifactType string, subject v1.Descriptor, mediaType string, b []byte, annotations map[string]string) (digest.Digest, error) {
+	config := v1.DescriptorEmptyJSON
+	if _, _, err := r.uploadBlob(ctx, creds, name, config.Size, bytes.NewReader(config.Data)); err != nil {
+		return "", fmt.Errorf("error uploading artifact config: %w", err)
+	}
+	config.Data = nil
+
+	d, size, err := r.uploadBlob(ctx, creds, name, int64(len(b)), bytes.NewReader(b))
+	if err != nil {
+		return "", fmt.Errorf("error uploading artifact: %w", err)
+	}
+
+	m := v1.Manifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    v1.MediaTypeImageManifest,
+		ArtifactType: artifactType,
+		Config:       config,
+		Layers: []v1.Descriptor{
+			{
+				MediaType: mediaType,
+				Digest:    d,
+				Size:      size,
+			},
+		},
+		Subject:     &subject,
+		Annotations: annotations,
+	}
+	return r.uploadV1Manifest(ctx, creds, name, "", m)
+}
+
+// listReferrers returns descriptors of the manifests that refer to the manifest with digest d,
+// using the referrers API. If artifactType is not empty, only manifests of that artifact type are
+// returned.
+func (r *ociRegistry) listReferrers(ctx context.Context, creds credentials, name string, d digest.Digest, artifactType string) ([]v1.Descriptor, error) {
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	u := &url.URL{Path: fmt.Sprintf("v2/%v/referrers/%v", name, d)}
+	if artifactType != "" {
+		u.RawQuery = url.Values{"artifactType": {artifactType}}.Encode()
+	}
+
+	req, err := r.newRequest(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", v1.MediaTypeImageIndex)
+
+	res, err := r.doRequest(req, creds, withNamespaceAccess(name, accessTypePull))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var idx v1.Index
+	if err := json.NewDecoder(limitResponse(res.Body, r.maxResponseSize)).Decode(&idx); err != nil {
+		return nil, fmt.Errorf("error decoding referrers: %w", err)
+	}
+
+	// Registries are not required to apply the artifact type filter.
+	ds := make([]v1.Descriptor, 0, len(idx.Manifests))
+	for _, m := range idx.Manifests {
+		if artifactType == "" || m.ArtifactType == artifactType {
+			ds = append(ds, m)
+		}
+	}
+	return ds, nil
+}
+
+// downloadArtifact downloads the artifact with manifest digest d from the registry, returning the
+// descriptor and content of its single layer. If the layer exceeds maxSize bytes, an error is
+// returned.
+func (r *ociRegistry) downloadArtifact(ctx context.Context, creds credentials, name string, d digest.Digest, maxSize int64) (v1.Descriptor, []byte, error) {
+	_, m, err := r.downloadV1Manifest(ctx, creds, name, d.String())
+	if err != nil {
+		return v1.Descriptor{}, nil, fmt.Errorf("error getting artifact manifest: %w", err)
+	}
+	if len(m.Layers) != 1 {
+		return v1.Descriptor{}, nil, fmt.Errorf("unexpected number of artifact layers: %v", len(m.Layers))
+	}
+	l := m.Layers[0]
+
+	if l.Size > maxSize {
+		return v1.Descriptor{}, nil, fmt.Errorf("artifact exceeds maximum size of %d bytes", maxSize)
+	}
+
+	b, err := r.downloadBlobBytes(ctx, creds, name, l.Digest, maxSize)
+	if err != nil {
+		return v1.Descriptor{}, nil, err
+	}
+
+	if got := digest.FromBytes(b); got != l.Digest {
+		return v1.Descriptor{}, nil, fmt.Errorf("unexpected artifact digest: %v != %v", got, l.Digest)
+	}
+	return l, b, nil
+}
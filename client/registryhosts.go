@@ -0,0 +1,81 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrRegistryHostRefused is returned when the library server redirects the client to an OCI
+// registry that is not permitted by the configuration of the client.
+var ErrRegistryHostRefused = errors.New("registry host refused")
+
+// RegistryHostError records the refusal of an OCI registry endpoint.
+type RegistryHostError struct {
+	URL    string // URL of the registry
+	Reason string // reason for refusal
+}
+
+func (e *RegistryHostError) Error() string {
+	return fmt.Sprintf("%v: %v: %v", ErrRegistryHostRefused, e.URL, e.Reason)
+}
+
+// Is returns true if target is ErrRegistryHostRefused.
+func (e *RegistryHostError) Is(target error) bool {
+	return target == ErrRegistryHostRefused
+}
+
+// registryHostPolicy determines the OCI registry endpoints the client accepts from the library
+// server.
+type registryHostPolicy struct {
+	allowed      []string
+	denied       []string
+	requireHTTPS bool
+}
+
+// matchHost returns true if host u matches pattern. A pattern containing a port (ie.
+// "registry.example.com:5000") must match the host and port exactly. Otherwise, the pattern is
+// matched against the host name only. A pattern with a leading "*." (ie. "*.example.com") matches
+// any subdomain.
+func matchHost(pattern string, u *url.URL) bool {
+	pattern = strings.ToLower(pattern)
+
+	host := strings.ToLower(u.Hostname())
+	if strings.Contains(strings.TrimPrefix(pattern, "*."), ":") {
+		host = strings.ToLower(u.Host)
+	}
+
+	if suffix, ok := strings.CutPrefix(pattern, "*"); ok && strings.HasPrefix(suffix, ".") {
+		return strings.HasSuffix(host, suffix)
+	}
+	return host == pattern
+}
+
+// check returns an error if the policy does not permit the registry at u.
+func (p registryHostPolicy) check(u *url.URL) error {
+	if p.requireHTTPS && u.Scheme != "https" {
+		return &RegistryHostError{URL: u.String(), Reason: "HTTPS required"}
+	}
+
+	for _, pattern := range p.denied {
+		if matchHost(pattern, u) {
+			return &RegistryHostError{URL: u.String(), Reason: "host denied"}
+		}
+	}
+
+	if len(p.allowed) == 0 {
+		return nil
+	}
+	for _, pattern := range p.allowed {
+		if matchHost(pattern, u) {
+			return nil
+		}
+	}
+	return &RegistryHostError{URL: u.String(), Reason: "host not allowed"}
+}
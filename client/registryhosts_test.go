@@ -0,0 +1,107 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func Test_registryHostPolicyCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  registryHostPolicy
+		url     string
+		wantErr bool
+	}{
+		{"Default", registryHostPolicy{}, "http://registry.example.com", false},
+		{"Allowed", registryHostPolicy{allowed: []string{"registry.example.com"}}, "https://registry.example.com", false},
+		{"AllowedAnyPort", registryHostPolicy{allowed: []string{"registry.example.com"}}, "https://registry.example.com:5000", false},
+		{"AllowedCase", registryHostPolicy{allowed: []string{"Registry.Example.com"}}, "https://registry.example.com", false},
+		{"AllowedPort", registryHostPolicy{allowed: []string{"registry.example.com:5000"}}, "https://registry.example.com:5000", false},
+		{"AllowedPortMismatch", registryHostPolicy{allowed: []string{"registry.example.com:5000"}}, "https://registry.example.com", true},
+		{"AllowedWildcard", registryHostPolicy{allowed: []string{"*.example.com"}}, "https://registry.example.com", false},
+		{"AllowedWildcardApex", registryHostPolicy{allowed: []string{"*.example.com"}}, "https://example.com", true},
+		{"AllowedWildcardSuffix", registryHostPolicy{allowed: []string{"*.example.com"}}, "https://registry.badexample.com", true},
+		{"NotAllowed", registryHostPolicy{allowed: []string{"registry.example.com"}}, "https://other.example.com", true},
+		{"Denied", registryHostPolicy{denied: []string{"registry.example.com"}}, "https://registry.example.com", true},
+		{"DeniedOther", registryHostPolicy{denied: []string{"registry.example.com"}}, "https://other.example.com", false},
+		{"DeniedPrecedence", registryHostPolicy{allowed: []string{"*.example.com"}, denied: []string{"registry.example.com"}}, "https://registry.example.com", true},
+		{"RequireHTTPS", registryHostPolicy{requireHTTPS: true}, "https://registry.example.com", false},
+		{"RequireHTTPSRefused", registryHostPolicy{requireHTTPS: true}, "http://registry.example.com", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = tt.policy.check(u)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrRegistryHostRefused) {
+				t.Errorf("got error %v, want %v", err, ErrRegistryHostRefused)
+			}
+		})
+	}
+}
+
+func TestRegistryHostRefused(t *testing.T) {
+	reg := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to refused registry: %v %v", r.Method, r.URL.Path)
+	}))
+	defer reg.Close()
+
+	lib := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if err := json.NewEncoder(w).Encode(map[string]string{"token": "token", "url": reg.URL}); err != nil {
+			t.Errorf("error JSON encoding: %v", err)
+		}
+	}))
+	defer lib.Close()
+
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"NotAllowed", Config{AllowedRegistryHosts: []string{"registry.example.com"}}},
+		{"Denied", Config{DeniedRegistryHosts: []string{"127.0.0.1"}}},
+		{"RequireHTTPS", Config{RequireRegistryHTTPS: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r authEventRecorder
+
+			cfg := tt.cfg
+			cfg.BaseURL = lib.URL
+			cfg.StrictRegistryAccess = true
+			cfg.AuthEventHandler = r.handle
+			cfg.Logger = testLogger
+
+			c, err := NewClient(&cfg)
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			_, _, _, err = c.newOCIRegistry(context.Background(), "entity/collection/container", []accessType{accessTypePull})
+			if !errors.Is(err, ErrRegistryHostRefused) {
+				t.Fatalf("got error %v, want %v", err, ErrRegistryHostRefused)
+			}
+
+			if ev, ok := r.kind(AuthEventRegistryToken); !ok || !errors.Is(ev.Err, ErrRegistryHostRefused) {
+				t.Errorf("got event %+v, want error %v", ev, ErrRegistryHostRefused)
+			}
+		})
+	}
+}
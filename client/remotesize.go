@@ -0,0 +1,103 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// GetRemoteSize returns the size of the image specified by ref and arch, as reported by the server
+// hosting the image content, rather than the image metadata. This is useful when the metadata may
+// lag behind the content, such as when content is replicated.
+//
+// The size is determined using a HEAD request or, if the server does not support HEAD requests
+// (ie. a URL presigned for GET requests only), a request for the first byte of the content.
+func (c *Client) GetRemoteSize(ctx context.Context, ref, arch string) (int64, error) {
+	r, err := ParseAmbiguous(ref)
+	if err != nil {
+		return 0, fmt.Errorf("malformed image ref: %w", err)
+	}
+
+	name := strings.TrimPrefix(r.Path, "/")
+	tag := "latest"
+	if len(r.Tags) > 0 {
+		tag = r.Tags[0]
+	}
+
+	u, creds, err := c.imageBlobURL(ctx, arch, name, tag)
+	if err != nil {
+		return 0, err
+	}
+	return c.remoteSize(ctx, u.get(), creds)
+}
+
+// remoteSize returns the size of the content at u.
+func (c *Client) remoteSize(ctx context.Context, u string, creds credentials) (int64, error) {
+	ctx, cancel := c.metadataContext(ctx)
+	defer cancel()
+
+	res, err := c.probeBlob(ctx, http.MethodHead, u, creds, "")
+	if err != nil {
+		return 0, err
+	}
+	res.Body.Close()
+
+	if res.StatusCode == http.StatusOK && res.ContentLength >= 0 {
+		return res.ContentLength, nil
+	}
+
+	c.logger.Logf("Unable to determine size using HEAD request (status %d); requesting first byte", res.StatusCode)
+
+	res, err = c.probeBlob(ctx, http.MethodGet, u, creds, "bytes=0-0")
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusPartialContent:
+		size, err := parseContentRange(res.Header.Get("Content-Range"))
+		if err != nil {
+			return 0, withResponseRequestID(fmt.Errorf("malformed Content-Range header: %w", err), res)
+		}
+		return size, nil
+	case http.StatusOK:
+		// The server ignored the Range header, and is returning the entire content.
+		if res.ContentLength >= 0 {
+			return res.ContentLength, nil
+		}
+		return 0, withResponseRequestID(errors.New("unable to determine size of content"), res)
+	default:
+		return 0, withResponseRequestID(fmt.Errorf("unexpected HTTP status %d", res.StatusCode), res)
+	}
+}
+
+// probeBlob issues a request using the specified method for the content at u. If rng is not
+// empty, it is supplied as the Range header.
+func (c *Client) probeBlob(ctx context.Context, method, u string, creds credentials, rng string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if creds != nil {
+		if err := creds.ModifyRequest(req); err != nil {
+			return nil, err
+		}
+	}
+
+	if rng != "" {
+		req.Header.Set("Range", rng)
+	}
+
+	setRequestID(req)
+
+	return c.httpClient.Do(req)
+}
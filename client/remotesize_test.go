@@ -0,0 +1,125 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetRemoteSize(t *testing.T) {
+	const content = "0123456789"
+
+	tests := []struct {
+		name      string
+		blob      http.HandlerFunc
+		want      int64
+		expectErr bool
+	}{
+		{
+			name: "Head",
+			blob: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodHead {
+					t.Errorf("unexpected method %v", r.Method)
+				}
+				w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+			},
+			want: int64(len(content)),
+		},
+		{
+			name: "Range",
+			blob: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodHead {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+
+				if got, want := r.Header.Get("Range"), "bytes=0-0"; got != want {
+					t.Errorf("got range %q, want %q", got, want)
+				}
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-0/%d", len(content)))
+				w.WriteHeader(http.StatusPartialContent)
+				fmt.Fprint(w, content[:1])
+			},
+			want: int64(len(content)),
+		},
+		{
+			name: "RangeIgnored",
+			blob: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodHead {
+					w.WriteHeader(http.StatusMethodNotAllowed)
+					return
+				}
+				fmt.Fprint(w, content)
+			},
+			want: int64(len(content)),
+		},
+		{
+			name: "MalformedContentRange",
+			blob: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodHead {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				w.Header().Set("Content-Range", "malformed")
+				w.WriteHeader(http.StatusPartialContent)
+			},
+			expectErr: true,
+		},
+		{
+			name: "NotFound",
+			blob: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/v1/oci-redirect":
+					w.WriteHeader(http.StatusNotFound)
+				case strings.HasPrefix(r.URL.Path, "/v1/imagefile/"):
+					if got, want := r.URL.Path, "/v1/imagefile/entity/collection/container:tag"; got != want {
+						t.Errorf("got path %v, want %v", got, want)
+					}
+					w.Header().Set("Location", "http://"+r.Host+"/blob")
+					w.WriteHeader(http.StatusSeeOther)
+				case r.URL.Path == "/blob":
+					tt.blob(w, r)
+				default:
+					// Notably, the image metadata is not consulted.
+					t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			size, err := c.GetRemoteSize(context.Background(), "library:entity/collection/container:tag", "amd64")
+			if tt.expectErr && err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got, want := size, tt.want; got != want {
+				t.Errorf("got size %v, want %v", got, want)
+			}
+		})
+	}
+}
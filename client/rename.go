@@ -0,0 +1,137 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// maxRenameRedirects is the maximum number of alias redirects followed when renaming a container
+// or collection.
+const maxRenameRedirects = 5
+
+// RenameContainer renames the container identified by ref (ie. "entity/collection/container") to
+// newName, within the same collection, and returns the renamed container. The images and tags of
+// the container are retained.
+//
+// Following a rename, the server may retain the previous name as an alias, redirecting requests
+// to the renamed container. If ref is such an alias, the container to which it refers is renamed.
+func (c *Client) RenameContainer(ctx context.Context, ref, newName string) (*Container, error) {
+	var res ContainerResponse
+	if err := c.rename(ctx, "v1/containers/"+strings.TrimPrefix(ref, "/"), newName, &res); err != nil {
+		return nil, fmt.Errorf("error renaming container: %w", err)
+	}
+	return &res.Data, nil
+}
+
+// RenameCollection renames the collection identified by ref (ie. "entity/collection") to newName,
+// within the same entity, and returns the renamed collection. The containers within the collection
+// are retained.
+//
+// Following a rename, the server may retain the previous name as an alias, redirecting requests
+// to the renamed collection. If ref is such an alias, the collection to which it refers is
+// renamed.
+func (c *Client) RenameCollection(ctx context.Context, ref, newName string) (*Collection, error) {
+	var res CollectionResponse
+	if err := c.rename(ctx, "v1/collections/"+strings.TrimPrefix(ref, "/"), newName, &res); err != nil {
+		return nil, fmt.Errorf("error renaming collection: %w", err)
+	}
+	return &res.Data, nil
+}
+
+// rename renames the object at path to newName, decoding the response into v.
+//
+// Redirects are not followed by net/http, since a PUT request redirected with HTTP status 301 or
+// 302 is re-issued as a GET request, which would appear to succeed without renaming the object.
+// Instead, redirects to the library host are re-issued as rename requests.
+func (c *Client) rename(ctx context.Context, path, newName string, v interface{}) error {
+	if newName == "" || strings.ContainsAny(newName, "/:") {
+		return fmt.Errorf("invalid name %q", newName)
+	}
+
+	body, err := json.Marshal(RenameRequest{Name: newName})
+	if err != nil {
+		return fmt.Errorf("error encoding object to JSON: %w", err)
+	}
+
+	ctx, cancel := c.metadataContext(ctx)
+	defer cancel()
+
+	customHTTPClient := &http.Client{
+		Transport: c.httpClient.Transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+		Jar:     c.httpClient.Jar,
+		Timeout: c.httpClient.Timeout,
+	}
+
+	c.logger.Logf("rename calling %s", path)
+
+	req, err := c.newRequest(ctx, http.MethodPut, path+"/_rename", "", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	for redirects := 0; ; redirects++ {
+		res, err := customHTTPClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("error making request to server: %w", err)
+		}
+
+		if res, err = c.retryUnauthorized(customHTTPClient, req, res); err != nil {
+			return fmt.Errorf("error making request to server: %w", err)
+		}
+
+		c.relayServerWarning(res)
+
+		if err := c.decodeResponse(res); err != nil {
+			res.Body.Close()
+			return err
+		}
+
+		switch res.StatusCode {
+		case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		default:
+			defer res.Body.Close()
+
+			if err := c.checkStatusCode(res, []int{http.StatusOK}); err != nil {
+				return err
+			}
+
+			if err := json.NewDecoder(limitResponse(res.Body, c.maxResponseSize)).Decode(v); err != nil {
+				return fmt.Errorf("error decoding response from server: %w", err)
+			}
+			return nil
+		}
+
+		res.Body.Close()
+
+		loc, err := res.Location()
+		if err != nil {
+			return fmt.Errorf("error following redirect: %w", err)
+		}
+
+		if !samehost(c.baseURL, loc) {
+			return fmt.Errorf("refusing to follow redirect to %v", loc.Redacted())
+		}
+
+		if redirects >= maxRenameRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRenameRedirects)
+		}
+
+		c.logger.Logf("%s is an alias; renaming %s", path, loc.Path)
+
+		if req, err = c.newRequest(ctx, http.MethodPut, loc.Path, loc.RawQuery, bytes.NewReader(body)); err != nil {
+			return err
+		}
+	}
+}
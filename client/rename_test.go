@@ -0,0 +1,121 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func TestRenameContainer(t *testing.T) {
+	tests := []struct {
+		name      string
+		ref       string
+		newName   string
+		location  string
+		wantErr   error
+		expectErr bool
+	}{
+		{name: "OK", ref: "entity/collection/container", newName: "renamed"},
+		{name: "Alias", ref: "entity/collection/alias", newName: "renamed"},
+		{name: "CrossHostRedirect", ref: "entity/collection/alias", newName: "renamed", location: "http://example.com/v1/containers/entity/collection/container/_rename", expectErr: true},
+		{name: "NotFound", ref: "entity/collection/missing", newName: "renamed", wantErr: ErrNotFound, expectErr: true},
+		{name: "InvalidName", ref: "entity/collection/container", newName: "a/b", expectErr: true},
+		{name: "EmptyName", ref: "entity/collection/container", newName: "", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got, want := r.Method, http.MethodPut; got != want {
+					t.Errorf("got method %v, want %v", got, want)
+				}
+
+				switch r.URL.Path {
+				case "/v1/containers/entity/collection/alias/_rename":
+					loc := tt.location
+					if loc == "" {
+						loc = "/v1/containers/entity/collection/container/_rename"
+					}
+					http.Redirect(w, r, loc, http.StatusMovedPermanently)
+				case "/v1/containers/entity/collection/container/_rename":
+					var body RenameRequest
+					if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+						t.Errorf("error decoding request: %v", err)
+					}
+
+					if err := jsonresp.WriteResponse(w, Container{ID: "containerID", Name: body.Name}, http.StatusOK); err != nil {
+						t.Errorf("error writing JSON response: %v", err)
+					}
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			co, err := c.RenameContainer(context.Background(), tt.ref, tt.newName)
+			if tt.expectErr && err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got, want := co.Name, tt.newName; got != want {
+				t.Errorf("got name %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestRenameCollection(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Path, "/v1/collections/entity/collection/_rename"; got != want {
+			t.Errorf("got path %v, want %v", got, want)
+		}
+
+		var body RenameRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("error decoding request: %v", err)
+		}
+
+		if err := jsonresp.WriteResponse(w, Collection{ID: "collectionID", Name: body.Name}, http.StatusOK); err != nil {
+			t.Errorf("error writing JSON response: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	co, err := c.RenameCollection(context.Background(), "entity/collection", "renamed")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := co.Name, "renamed"; got != want {
+		t.Errorf("got name %v, want %v", got, want)
+	}
+}
@@ -5,11 +5,15 @@
 
 package client
 
+import "time"
+
 // UploadImageRequest is sent to initiate V2 image upload
 type UploadImageRequest struct {
 	Size           int64  `json:"filesize"`
 	MD5Checksum    string `json:"md5sum,omitempty"`
 	SHA256Checksum string `json:"sha256sum,omitempty"`
+	SHA512Checksum string `json:"sha512sum,omitempty"`
+	CRC32CChecksum string `json:"crc32c,omitempty"`
 }
 
 // UploadImageCompleteRequest is sent to complete V2 image upload; it is
@@ -27,6 +31,8 @@ type UploadImagePartRequest struct {
 	UploadID       string `json:"uploadID"`
 	PartNumber     int    `json:"partNumber"`
 	SHA256Checksum string `json:"sha256sum"`
+	SHA512Checksum string `json:"sha512sum,omitempty"`
+	CRC32CChecksum string `json:"crc32c,omitempty"`
 }
 
 // CompletedPart represents a single part of a multipart image upload
@@ -45,3 +51,43 @@ type CompleteMultipartUploadRequest struct {
 type AbortMultipartUploadRequest struct {
 	UploadID string `json:"uploadID"`
 }
+
+// ReadOnlyRequest is sent to mark a container or collection read-only (frozen), or writable
+type ReadOnlyRequest struct {
+	ReadOnly bool `json:"readOnly"`
+}
+
+// RenameRequest is sent to rename a container or collection
+type RenameRequest struct {
+	Name string `json:"name"`
+}
+
+// ReadmeRequest is sent to replace the README (long-form description) of a container
+type ReadmeRequest struct {
+	FullDescription string `json:"fullDescription"`
+}
+
+// ACLRequest is sent to replace the access control list of a container or collection
+type ACLRequest struct {
+	Entries []ACLEntry `json:"entries"`
+}
+
+// WebhookRequest is sent to register a webhook
+type WebhookRequest struct {
+	// URL to which event payloads are delivered.
+	URL string `json:"url"`
+	// Events of which the webhook is notified.
+	Events []WebhookEvent `json:"events"`
+	// Secret used to sign payloads. If empty, the server generates a secret.
+	Secret string `json:"secret,omitempty"`
+}
+
+// TokenRequest is sent to create an access token
+type TokenRequest struct {
+	// Description is a human-readable description of the purpose of the token.
+	Description string `json:"description,omitempty"`
+	// Scopes limits the operations permitted using the token. If empty, the token is not limited.
+	Scopes []string `json:"scopes,omitempty"`
+	// Expiry is the time at which the token expires. If zero, the server default applies.
+	Expiry time.Time `json:"expiry"`
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+)
+
+// requestIDHeader is the HTTP header used to correlate requests made by the client with server
+// logs.
+const requestIDHeader = "X-Request-ID"
+
+// serverRequestIDHeaders are the HTTP headers that may contain the ID assigned to a request by a
+// server, in order of preference.
+var serverRequestIDHeaders = []string{
+	requestIDHeader,
+	"X-Amz-Request-Id",
+	"X-Ms-Request-Id",
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying request ID id. The request ID is included in the
+// X-Request-ID header of every HTTP request made using the returned context, allowing operations
+// consisting of multiple requests to be traced across client and server logs. Operations that
+// are not supplied a request ID generate one.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// newRequestID returns a randomly generated request ID.
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// ensureRequestID returns ctx and the request ID it carries, generating a request ID if ctx does
+// not already carry one.
+func ensureRequestID(ctx context.Context) (context.Context, string) {
+	if id, ok := RequestIDFromContext(ctx); ok {
+		return ctx, id
+	}
+
+	id := newRequestID()
+	return WithRequestID(ctx, id), id
+}
+
+// setRequestID sets the X-Request-ID header of req to the request ID carried by the request
+// context. If the context does not carry a request ID, one is generated for the request.
+func setRequestID(req *http.Request) {
+	id, ok := RequestIDFromContext(req.Context())
+	if !ok {
+		id = newRequestID()
+	}
+	req.Header.Set(requestIDHeader, id)
+}
+
+// RequestIDError wraps an error returned in response to a request, along with the ID of the
+// request. The server-provided request ID is preferred, if present.
+type RequestIDError struct {
+	RequestID string
+	Err       error
+}
+
+func (e *RequestIDError) Error() string {
+	return fmt.Sprintf("%v (request ID: %v)", e.Err, e.RequestID)
+}
+
+func (e *RequestIDError) Unwrap() error { return e.Err }
+
+// responseRequestID returns the ID of the request that resulted in res.
+func responseRequestID(res *http.Response) string {
+	for _, h := range serverRequestIDHeaders {
+		if id := res.Header.Get(h); id != "" {
+			return id
+		}
+	}
+	if res.Request != nil {
+		return res.Request.Header.Get(requestIDHeader)
+	}
+	return ""
+}
+
+// withResponseRequestID wraps err in a RequestIDError containing the ID of the request that
+// resulted in res. If no request ID is available, err is returned unmodified.
+func withResponseRequestID(err error, res *http.Response) error {
+	if id := responseRequestID(res); id != "" {
+		return &RequestIDError{RequestID: id, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_setRequestID(t *testing.T) {
+	tests := []struct {
+		name   string
+		wantID string
+	}{
+		{"Generated", ""},
+		{"FromContext", "abc123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.wantID != "" {
+				ctx = WithRequestID(ctx, tt.wantID)
+			}
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			setRequestID(req)
+
+			got := req.Header.Get(requestIDHeader)
+			if got == "" {
+				t.Fatal("request ID not set")
+			}
+			if tt.wantID != "" && got != tt.wantID {
+				t.Errorf("got request ID %v, want %v", got, tt.wantID)
+			}
+		})
+	}
+}
+
+func Test_ensureRequestID(t *testing.T) {
+	ctx, id := ensureRequestID(context.Background())
+	if id == "" {
+		t.Fatal("request ID not generated")
+	}
+	if got, _ := RequestIDFromContext(ctx); got != id {
+		t.Errorf("got request ID %v, want %v", got, id)
+	}
+
+	if _, got := ensureRequestID(ctx); got != id {
+		t.Errorf("got request ID %v, want %v", got, id)
+	}
+}
+
+func TestRequestIDPropagation(t *testing.T) {
+	tests := []struct {
+		name     string
+		serverID string
+		wantID   string
+	}{
+		{"ClientID", "", "client-id"},
+		{"ServerID", "server-id", "server-id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got, want := r.Header.Get(requestIDHeader), "client-id"; got != want {
+					t.Errorf("got request ID %v, want %v", got, want)
+				}
+				if tt.serverID != "" {
+					w.Header().Set(requestIDHeader, tt.serverID)
+				}
+				w.WriteHeader(http.StatusInternalServerError)
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			_, err = c.apiGet(WithRequestID(context.Background(), "client-id"), "v1/test")
+
+			var rerr *RequestIDError
+			if !errors.As(err, &rerr) {
+				t.Fatalf("got error %v, want RequestIDError", err)
+			}
+			if got, want := rerr.RequestID, tt.wantID; got != want {
+				t.Errorf("got request ID %v, want %v", got, want)
+			}
+		})
+	}
+}
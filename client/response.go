@@ -27,6 +27,12 @@ type ContainerResponse struct {
 	Error *jsonresp.Error `json:"error,omitempty"`
 }
 
+// ContainersResponse - Response from the API for a container list request
+type ContainersResponse struct {
+	Data  []Container     `json:"data"`
+	Error *jsonresp.Error `json:"error,omitempty"`
+}
+
 // ImageResponse - Response from the API for an Image request
 type ImageResponse struct {
 	Data  Image           `json:"data"`
@@ -80,6 +86,8 @@ type QuotaResponse struct {
 type UploadImageComplete struct {
 	Quota        QuotaResponse `json:"quota"`
 	ContainerURL string        `json:"containerUrl"`
+	// SHA256Checksum is the checksum of the stored object, if reported by the server
+	SHA256Checksum string `json:"sha256sum,omitempty"`
 }
 
 // UploadImageCompleteResponse is the response to the upload image completion request
@@ -118,3 +126,63 @@ type CompleteMultipartUploadResponse struct {
 	Data  UploadImageComplete `json:"data"`
 	Error *jsonresp.Error     `json:"error,omitempty"`
 }
+
+// BlockManifestResponse - Response from the API for an image block manifest request
+type BlockManifestResponse struct {
+	Data  BlockManifest   `json:"data"`
+	Error *jsonresp.Error `json:"error,omitempty"`
+}
+
+// ImageChecksumsResponse - Response from the API for an image checksums request
+type ImageChecksumsResponse struct {
+	Data  ImageChecksums  `json:"data"`
+	Error *jsonresp.Error `json:"error,omitempty"`
+}
+
+// SBOMResponse - Response from the API for an image SBOM request
+type SBOMResponse struct {
+	Data  SBOM            `json:"data"`
+	Error *jsonresp.Error `json:"error,omitempty"`
+}
+
+// CollectionUsageResponse - Response from the API for a collection usage request
+type CollectionUsageResponse struct {
+	Data  CollectionUsage `json:"data"`
+	Error *jsonresp.Error `json:"error,omitempty"`
+}
+
+// ACLResponse - Response from the API for an access control list request
+type ACLResponse struct {
+	Data  []ACLEntry      `json:"data"`
+	Error *jsonresp.Error `json:"error,omitempty"`
+}
+
+// AccessTokenResponse - Response from the API for an access token create request
+type AccessTokenResponse struct {
+	Data  AccessToken     `json:"data"`
+	Error *jsonresp.Error `json:"error,omitempty"`
+}
+
+// AccessTokensResponse - Response from the API for an access token list request
+type AccessTokensResponse struct {
+	Data  []AccessToken   `json:"data"`
+	Error *jsonresp.Error `json:"error,omitempty"`
+}
+
+// AuditEventsResponse - Response from the API for an audit event list request
+type AuditEventsResponse struct {
+	Data  AuditEventPage  `json:"data"`
+	Error *jsonresp.Error `json:"error,omitempty"`
+}
+
+// WebhookResponse - Response from the API for a webhook create request
+type WebhookResponse struct {
+	Data  Webhook         `json:"data"`
+	Error *jsonresp.Error `json:"error,omitempty"`
+}
+
+// WebhooksResponse - Response from the API for a webhook list request
+type WebhooksResponse struct {
+	Data  []Webhook       `json:"data"`
+	Error *jsonresp.Error `json:"error,omitempty"`
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"errors"
+	"io"
+)
+
+// defaultMaxResponseSize is the default maximum size of an API response body.
+const defaultMaxResponseSize = 64 * 1024 * 1024
+
+// ErrResponseTooLarge is returned when the body of an API response exceeds the maximum response
+// size.
+var ErrResponseTooLarge = errors.New("response too large")
+
+// limitedReader reads from r, returning ErrResponseTooLarge if more than n bytes are available.
+type limitedReader struct {
+	r io.Reader
+	n int64 // bytes remaining
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	// Read at most one byte beyond the limit, to detect an oversized response.
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+
+	n, err := l.r.Read(p)
+	if int64(n) > l.n {
+		n, l.n = int(l.n), 0
+		return n, ErrResponseTooLarge
+	}
+	l.n -= int64(n)
+	return n, err
+}
+
+// limitedWriter writes to w, returning ErrResponseTooLarge if more than n bytes are written.
+type limitedWriter struct {
+	w io.Writer
+	n int64 // bytes remaining
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > l.n {
+		n, err := l.w.Write(p[:l.n])
+		l.n -= int64(n)
+		if err != nil {
+			return n, err
+		}
+		return n, ErrResponseTooLarge
+	}
+
+	n, err := l.w.Write(p)
+	l.n -= int64(n)
+	return n, err
+}
+
+// limitResponse returns a reader that reads from the response body r, returning
+// ErrResponseTooLarge if the body exceeds max bytes. If max is not positive, r is returned.
+func limitResponse(r io.Reader, max int64) io.Reader {
+	if max <= 0 {
+		return r
+	}
+	return &limitedReader{r: r, n: max}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func TestLimitResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		max     int64
+		wantErr error
+	}{
+		{"Unlimited", "0123456789", 0, nil},
+		{"UnderLimit", "0123456789", 11, nil},
+		{"AtLimit", "0123456789", 10, nil},
+		{"OverLimit", "0123456789", 9, ErrResponseTooLarge},
+		{"Empty", "", 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Read a byte at a time, to exercise the limit across multiple reads.
+			b, err := io.ReadAll(limitResponse(iotest.OneByteReader(strings.NewReader(tt.body)), tt.max))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && string(b) != tt.body {
+				t.Errorf("got body %q, want %q", b, tt.body)
+			}
+			if err != nil && int64(len(b)) > tt.max {
+				t.Errorf("read %v bytes, limit %v", len(b), tt.max)
+			}
+		})
+	}
+}
+
+func TestLimitedWriter(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		max     int64
+		wantErr error
+	}{
+		{"UnderLimit", "0123456789", 11, nil},
+		{"AtLimit", "0123456789", 10, nil},
+		{"OverLimit", "0123456789", 9, ErrResponseTooLarge},
+		{"Empty", "", 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+
+			// Write a byte at a time, to exercise the limit across multiple writes.
+			_, err := io.Copy(&limitedWriter{w: &sb, n: tt.max}, iotest.OneByteReader(strings.NewReader(tt.body)))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && sb.String() != tt.body {
+				t.Errorf("got body %q, want %q", sb.String(), tt.body)
+			}
+			if int64(sb.Len()) > tt.max {
+				t.Errorf("wrote %v bytes, limit %v", sb.Len(), tt.max)
+			}
+		})
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if err := jsonresp.WriteResponse(w, testSearch, http.StatusOK); err != nil {
+			t.Errorf("error writing JSON response: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name            string
+		maxResponseSize int64
+		wantErr         error
+	}{
+		{"Default", 0, nil},
+		{"Disabled", -1, nil},
+		{"TooLarge", 16, ErrResponseTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger, MaxResponseSize: tt.maxResponseSize})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			if _, err := c.Search(context.Background(), map[string]string{"value": "test"}); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got search error %v, want %v", err, tt.wantErr)
+			}
+
+			if _, err := c.apiGet(context.Background(), "v1/search"); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got get error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -21,9 +21,20 @@ import (
 // ErrNotFound is returned by when a resource is not found (http status 404)
 var ErrNotFound = errors.New("not found")
 
+// maxErrorResponseSize is the maximum size of an error response body that is read.
+const maxErrorResponseSize = 64 * 1024
+
 func (c *Client) apiGet(ctx context.Context, path string) (objJSON []byte, err error) {
 	c.logger.Logf("apiGet calling %s", path)
-	return c.doGETRequest(ctx, path)
+
+	// Identical concurrent requests are coalesced.
+	v, err := coalesce(ctx, &c.inflightRequests, http.MethodGet+" "+path, func() (interface{}, error) {
+		return c.doGETRequest(ctx, path)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
 }
 
 func (c *Client) apiCreate(ctx context.Context, url string, o interface{}) (objJSON []byte, err error) {
@@ -52,14 +63,53 @@ func (c *Client) doDeleteRequest(ctx context.Context, path string) (objJSON []by
 	return c.commonRequestHandler(ctx, "DELETE", path, nil, []int{http.StatusOK})
 }
 
+// apiGetJSON calls path, decoding the response into v as it is read, rather than buffering the
+// entire response. It is used for endpoints that may return large responses.
+func (c *Client) apiGetJSON(ctx context.Context, path string, v interface{}) error {
+	c.logger.Logf("apiGetJSON calling %s", path)
+
+	ctx, cancel := c.metadataContext(ctx)
+	defer cancel()
+
+	res, err := c.commonRequest(ctx, "GET", path, nil, []int{http.StatusOK})
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if err := json.NewDecoder(limitResponse(res.Body, c.maxResponseSize)).Decode(v); err != nil {
+		return fmt.Errorf("error decoding response from server: %w", err)
+	}
+	return nil
+}
+
 func (c *Client) commonRequestHandler(ctx context.Context, method string, path string, o interface{}, acceptedStatusCodes []int) (objJSON []byte, err error) {
+	ctx, cancel := c.metadataContext(ctx)
+	defer cancel()
+
+	res, err := c.commonRequest(ctx, method, path, o, acceptedStatusCodes)
+	if err != nil {
+		return []byte{}, err
+	}
+	defer res.Body.Close()
+
+	objJSON, err = io.ReadAll(limitResponse(res.Body, c.maxResponseSize))
+	if err != nil {
+		return []byte{}, fmt.Errorf("error reading response from server:\n\t%w", err)
+	}
+	return objJSON, nil
+}
+
+// commonRequest makes a request, returning the response if its status code is one of
+// acceptedStatusCodes. The caller is responsible for closing the response body.
+func (c *Client) commonRequest(ctx context.Context, method string, path string, o interface{}, acceptedStatusCodes []int) (*http.Response, error) {
 	var payload io.Reader
 
 	// only PUT and POST methods
 	if method != "GET" && method != "DELETE" {
 		s, err := json.Marshal(o)
 		if err != nil {
-			return []byte{}, fmt.Errorf("error encoding object to JSON:\n\t%v", err)
+			return nil, fmt.Errorf("error encoding object to JSON:\n\t%v", err)
 		}
 		payload = bytes.NewBuffer(s)
 	}
@@ -67,36 +117,61 @@ func (c *Client) commonRequestHandler(ctx context.Context, method string, path s
 	// split url containing query into component pieces (path and raw query)
 	u, err := url.Parse(path)
 	if err != nil {
-		return []byte{}, fmt.Errorf("error parsing url:\n\t%v", err)
+		return nil, fmt.Errorf("error parsing url:\n\t%v", err)
 	}
 
 	req, err := c.newRequest(ctx, method, u.Path, u.RawQuery, payload)
 	if err != nil {
-		return []byte{}, fmt.Errorf("error creating %s request:\n\t%v", method, err)
+		return nil, fmt.Errorf("error creating %s request:\n\t%w", method, err)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
-		return []byte{}, fmt.Errorf("error making request to server:\n\t%v", err)
+		return nil, fmt.Errorf("error making request to server:\n\t%w", err)
 	}
-	defer res.Body.Close()
 
-	// check http status code
+	if err := c.checkStatusCode(res, acceptedStatusCodes); err != nil {
+		res.Body.Close()
+		return nil, err
+	}
+	return res, nil
+}
+
+// checkStatusCode returns an error if the status code of res is not one of acceptedStatusCodes.
+func (c *Client) checkStatusCode(res *http.Response, acceptedStatusCodes []int) error {
 	if res.StatusCode == http.StatusNotFound {
-		return []byte{}, ErrNotFound
+		return ErrNotFound
+	}
+	if res.StatusCode == http.StatusUnauthorized {
+		return c.unauthorizedError(res)
+	}
+	if err := modificationError(res); err != nil {
+		return err
 	}
 	if !isValidStatusCode(res.StatusCode, acceptedStatusCodes) {
-		err := jsonresp.ReadError(res.Body)
-		if err != nil {
-			return []byte{}, fmt.Errorf("request did not succeed: %v", err)
-		}
-		return []byte{}, fmt.Errorf("request did not succeed: http status code: %d", res.StatusCode)
+		return responseError(res, "request did not succeed")
 	}
-	objJSON, err = io.ReadAll(res.Body)
-	if err != nil {
-		return []byte{}, fmt.Errorf("error reading response from server:\n\t%v", err)
+	return nil
+}
+
+// responseError returns an error describing unsuccessful response res, prefixed with msg. If the
+// response body contains an error payload, the returned error wraps the corresponding
+// *jsonresp.Error. The response body is consumed.
+func responseError(res *http.Response, msg string) error {
+	if err := jsonresp.ReadError(io.LimitReader(res.Body, maxErrorResponseSize)); err != nil {
+		return withResponseRequestID(fmt.Errorf("%v: %w", msg, err), res)
 	}
-	return objJSON, nil
+	return withResponseRequestID(fmt.Errorf("%v: %w", msg, &httpStatusError{code: res.StatusCode}), res)
+}
+
+// httpStatusError records the status code of an unsuccessful response that does not contain an
+// error payload.
+type httpStatusError struct {
+	code int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("http status code: %d", e.code)
 }
 
 func isValidStatusCode(statusCode int, acceptedStatusCodes []int) bool {
@@ -8,8 +8,14 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
 	"reflect"
+	"strings"
 	"testing"
+
+	jsonresp "github.com/sylabs/json-resp"
 )
 
 func Test_apiUpdate(t *testing.T) {
@@ -69,3 +75,42 @@ func Test_apiUpdate(t *testing.T) {
 		})
 	}
 }
+
+func Test_responseError(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantErr     error
+		wantMessage string
+	}{
+		{
+			name:        "JSONError",
+			body:        `{"error":{"code":400,"message":"invalid arch"}}`,
+			wantErr:     &jsonresp.Error{Code: http.StatusBadRequest, Message: "invalid arch"},
+			wantMessage: "request did not succeed: invalid arch (400 Bad Request)",
+		},
+		{
+			name:        "NotJSON",
+			body:        "Bad Request",
+			wantMessage: "request did not succeed: http status code: 400",
+		},
+		{
+			name:        "Empty",
+			wantMessage: "request did not succeed: http status code: 400",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(strings.NewReader(tt.body))}
+
+			err := responseError(res, "request did not succeed")
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+			if got, want := err.Error(), tt.wantMessage; got != want {
+				t.Errorf("got message %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,497 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// ResumeStateVersion is the version of the resume state format produced by MarshalResumeState.
+const ResumeStateVersion = 1
+
+// resumeURLMargin is the minimum remaining validity of a URL recorded in a resume state for it to
+// be reused.
+const resumeURLMargin = time.Minute
+
+var (
+	// ErrResumeStateVersion is returned when a resume state has an unsupported version.
+	ErrResumeStateVersion = errors.New("unsupported resume state version")
+
+	// ErrResumeStateStale is returned when a resume state no longer describes the image it
+	// identifies, such as when the tag has since been moved to another image.
+	ErrResumeStateStale = errors.New("resume state does not match image")
+)
+
+// ResumeState describes the progress of an interrupted multipart download, so that it may be
+// resumed using ResumeDownload, possibly by another process or on another machine sharing the
+// destination. It is serialized using MarshalResumeState.
+type ResumeState struct {
+	// Version of the resume state format.
+	Version int `json:"version"`
+	// Ref, Arch and Tag identify the image being downloaded.
+	Ref  string `json:"ref"`
+	Arch string `json:"arch,omitempty"`
+	Tag  string `json:"tag"`
+	// Backend from which the image is downloaded (ie. TransferBackendOCI or
+	// TransferBackendLibrary).
+	Backend string `json:"backend"`
+	// Digest of the image, if known.
+	Digest digest.Digest `json:"digest,omitempty"`
+	// Size of the image.
+	Size int64 `json:"size"`
+	// PartSize is the size of each part of the download. The final part may be smaller.
+	PartSize int64 `json:"partSize"`
+	// Completed lists the parts (starting at 1) that have been written to the destination.
+	Completed []int `json:"completed"`
+	// URL from which the image content is downloaded, if it may be reused without credentials.
+	URL string `json:"url,omitempty"`
+	// URLExpires is the time at which URL expires. URL is only reused if valid for at least a
+	// minute.
+	URLExpires time.Time `json:"urlExpires,omitempty"`
+}
+
+// parts returns the number of parts of the download.
+func (s *ResumeState) parts() int {
+	return int(1 + (s.Size-1)/s.PartSize)
+}
+
+// validate returns an error if s is not a valid resume state.
+func (s *ResumeState) validate() error {
+	if s.Version != ResumeStateVersion {
+		return fmt.Errorf("%w: %v", ErrResumeStateVersion, s.Version)
+	}
+
+	switch s.Backend {
+	case TransferBackendOCI, TransferBackendLibrary:
+	default:
+		return fmt.Errorf("invalid resume state: unsupported backend %q", s.Backend)
+	}
+
+	if s.Ref == "" || s.Tag == "" {
+		return errors.New("invalid resume state: image not specified")
+	}
+
+	if s.Size <= 0 || s.PartSize <= 0 {
+		return errors.New("invalid resume state: invalid size")
+	}
+
+	if s.Digest != "" {
+		if err := s.Digest.Validate(); err != nil {
+			return fmt.Errorf("invalid resume state: %w", err)
+		}
+	}
+
+	for _, part := range s.Completed {
+		if part < 1 || part > s.parts() {
+			return fmt.Errorf("invalid resume state: invalid part %v", part)
+		}
+	}
+	return nil
+}
+
+// MarshalResumeState returns the JSON encoding of s.
+func MarshalResumeState(s *ResumeState) ([]byte, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(s)
+}
+
+// UnmarshalResumeState decodes the resume state encoded by MarshalResumeState. An error wrapping
+// ErrResumeStateVersion is returned if the version of the state is not supported.
+func UnmarshalResumeState(b []byte) (*ResumeState, error) {
+	var s ResumeState
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("error decoding resume state: %w", err)
+	}
+
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ResumableDownloadError is returned when a multipart download fails after some parts have been
+// written to the destination. The download may be resumed by passing State to ResumeDownload.
+type ResumableDownloadError struct {
+	// State describes the progress of the download.
+	State *ResumeState
+	// Err is the error that interrupted the download.
+	Err error
+}
+
+func (e *ResumableDownloadError) Error() string { return e.Err.Error() }
+
+func (e *ResumableDownloadError) Unwrap() error { return e.Err }
+
+// ResumeDownload resumes the interrupted download described by state, downloading the parts not
+// yet written to dst. The Arch, Tag and Backend of opts are ignored in favour of those recorded in
+// state. If dst is an io.ReaderAt and the digest of the image is known, the complete content is
+// verified once downloaded. A summary of the resumed transfer is returned.
+//
+// Sparse writing (see Downloader.Sparse) is not applied to the remaining parts, since the
+// destination may contain content written by an incomplete part of the interrupted download.
+//
+// If the download is interrupted again, the returned error is a *ResumableDownloadError
+// describing the progress made.
+func (c *Client) ResumeDownload(ctx context.Context, dst io.WriterAt, state *ResumeState, opts *DownloadOptions) (*TransferSummary, error) {
+	if state == nil {
+		return nil, errors.New("resume state is required")
+	}
+	if err := state.validate(); err != nil {
+		return nil, err
+	}
+
+	var o DownloadOptions
+	if opts != nil {
+		o = *opts
+	}
+	o.Arch, o.Tag, o.Backend = state.Arch, state.Tag, state.Backend
+
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, id := ensureRequestID(ctx)
+
+	c.logger.Logf("Resuming download of %v (request ID: %v)", state.Ref, id)
+
+	ctx, stats := withTransferStats(ctx)
+
+	ctx, cancel := c.operationContext(ctx)
+	defer cancel()
+
+	pb := o.ProgressBar
+	if pb == nil {
+		pb = &NoopProgressBar{}
+	}
+
+	spec := c.downloadSpec(o.Downloader)
+
+	v := spec.Verifier
+	if v == nil && state.Digest != "" {
+		v = &DownloadVerifier{Digest: state.Digest}
+	}
+
+	var dv *downloadVerification
+	if ra, ok := dst.(io.ReaderAt); ok && v != nil {
+		var err error
+		if dv, err = newDownloadVerification(v, ra); err != nil {
+			return nil, err
+		}
+		dv.setSize(state.Size)
+		ctx = withDownloadVerification(ctx, dv)
+	} else if spec.Verifier != nil {
+		return nil, errors.New("download verification requires a seekable destination")
+	}
+
+	if o.MaxBytesPerSecond > 0 {
+		ctx = withRateLimiter(ctx, newRateLimiter(o.MaxBytesPerSecond, c.sleeper))
+	}
+
+	rt := resumeTrackerFromState(state)
+	ctx = withResumeTracker(ctx, rt)
+
+	stats.setBackend(state.Backend)
+
+	u, creds, err := c.resumeBlobURL(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	rt.begin(u, creds, state.Size, state.PartSize)
+
+	done := make(map[int]bool, len(state.Completed))
+	for _, part := range state.Completed {
+		done[part] = true
+	}
+
+	var (
+		parts   []filePartDescriptor
+		written int64
+	)
+	for n := 0; n < state.parts(); n++ {
+		start := int64(n) * state.PartSize
+		end := start + minInt64(state.PartSize, state.Size-start) - 1
+
+		if done[n+1] {
+			// Parts written by the interrupted download are read back from dst.
+			dv.partDone(n+1, start, end)
+			written += end - start + 1
+			continue
+		}
+		parts = append(parts, filePartDescriptor{part: n + 1, start: start, end: end, size: state.Size, w: dst})
+	}
+
+	c.logger.Logf("Resuming download: %d of %d part(s) remaining", len(parts), state.parts())
+
+	pb.Init(state.Size)
+	defer pb.Wait()
+
+	pb.IncrBy(int(written))
+
+	if err := c.downloadParts(ctx, u, creds, parts, spec.Concurrency, pb); err != nil {
+		pb.Abort(true)
+
+		return nil, rt.wrap(err)
+	}
+
+	if dv != nil {
+		res, err := dv.complete()
+		if err != nil {
+			return nil, fmt.Errorf("error verifying downloaded image: %w", err)
+		}
+		stats.setVerification(res)
+	}
+	return stats.summary(), nil
+}
+
+// resumeBlobURL returns the URL and credentials from which to resume the download described by s.
+// The URL recorded in s is reused if it remains valid, and is permitted as a presigned URL (see
+// checkPresignedURL); otherwise the image is located again, and an error wrapping
+// ErrResumeStateStale is returned if it no longer matches s.
+func (c *Client) resumeBlobURL(ctx context.Context, s *ResumeState) (*blobURL, credentials, error) {
+	if s.Backend == TransferBackendLibrary && s.URL != "" && time.Until(s.URLExpires) >= resumeURLMargin {
+		if err := c.checkPresignedURL(s.URL); err != nil {
+			return nil, nil, err
+		}
+
+		c.logger.Log("Reusing image URL from resume state")
+
+		renew := func(ctx context.Context) (string, error) {
+			u, _, err := c.libraryImageBlobURL(ctx, s.Arch, s.Ref, s.Tag)
+			if err != nil {
+				return "", err
+			}
+			return u.get(), nil
+		}
+		return &blobURL{u: s.URL, renew: renew, digest: s.Digest}, nil, nil
+	}
+
+	var (
+		u     *blobURL
+		creds credentials
+		size  int64
+		err   error
+	)
+
+	if s.Backend == TransferBackendOCI {
+		u, creds, size, _, err = c.ociImageBlob(ctx, s.Arch, s.Ref, s.Tag)
+	} else {
+		if u, creds, err = c.libraryImageBlobURL(ctx, s.Arch, s.Ref, s.Tag); err == nil {
+			size, err = c.libraryImageSize(ctx, s.Arch, s.Ref, s.Tag, u)
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if size != s.Size {
+		return nil, nil, fmt.Errorf("%w: size %d, want %d", ErrResumeStateStale, size, s.Size)
+	}
+	if s.Digest != "" && u.digest != "" && u.digest != s.Digest {
+		return nil, nil, fmt.Errorf("%w: digest %v, want %v", ErrResumeStateStale, u.digest, s.Digest)
+	}
+	return u, creds, nil
+}
+
+// presignedURLExpiry returns the time at which the presigned URL rawURL expires, or the zero time
+// if unknown. S3 (and GCS) V4 signatures, S3 V2 signatures and Azure SAS URLs are recognized.
+func presignedURLExpiry(rawURL string) time.Time {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return time.Time{}
+	}
+	q := u.Query()
+
+	for _, p := range []string{"X-Amz", "X-Goog"} {
+		date, expires := q.Get(p+"-Date"), q.Get(p+"-Expires")
+		if date == "" || expires == "" {
+			continue
+		}
+
+		t, err := time.Parse("20060102T150405Z", date)
+		if err != nil {
+			return time.Time{}
+		}
+		secs, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil {
+			return time.Time{}
+		}
+		return t.Add(time.Duration(secs) * time.Second)
+	}
+
+	if expires := q.Get("Expires"); expires != "" {
+		if secs, err := strconv.ParseInt(expires, 10, 64); err == nil {
+			return time.Unix(secs, 0).UTC()
+		}
+	}
+
+	if se := q.Get("se"); se != "" {
+		if t, err := time.Parse(time.RFC3339, se); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// resumeTracker records the progress of a multipart download, so that it may be resumed should it
+// be interrupted. Methods may be called concurrently, and are no-ops on a nil *resumeTracker.
+type resumeTracker struct {
+	mu        sync.Mutex
+	ref       string
+	arch      string
+	tag       string
+	backend   string
+	u         *blobURL
+	reuseURL  bool // URL may be reused without credentials
+	size      int64
+	partSize  int64
+	done      map[int]bool
+	started   bool
+	abandoned bool
+}
+
+// newResumeTracker returns a *resumeTracker for the download of the image identified by ref, arch
+// and tag.
+func newResumeTracker(ref, arch, tag string) *resumeTracker {
+	return &resumeTracker{ref: ref, arch: arch, tag: tag, done: make(map[int]bool)}
+}
+
+// resumeTrackerFromState returns a *resumeTracker that continues to track the download described
+// by s.
+func resumeTrackerFromState(s *ResumeState) *resumeTracker {
+	rt := newResumeTracker(s.Ref, s.Arch, s.Tag)
+	rt.backend = s.Backend
+	for _, part := range s.Completed {
+		rt.done[part] = true
+	}
+	return rt
+}
+
+type resumeTrackerKey struct{}
+
+// withResumeTracker returns a context carrying rt.
+func withResumeTracker(ctx context.Context, rt *resumeTracker) context.Context {
+	return context.WithValue(ctx, resumeTrackerKey{}, rt)
+}
+
+// resumeTrackerFromContext returns the *resumeTracker carried by ctx, or nil if not present.
+func resumeTrackerFromContext(ctx context.Context) *resumeTracker {
+	rt, _ := ctx.Value(resumeTrackerKey{}).(*resumeTracker)
+	return rt
+}
+
+// setBackend records the backend from which the image is downloaded.
+func (rt *resumeTracker) setBackend(backend string) {
+	if rt == nil {
+		return
+	}
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	rt.backend = backend
+}
+
+// begin records the start of a multipart download of size bytes from u, in parts of partSize
+// bytes.
+func (rt *resumeTracker) begin(u *blobURL, creds credentials, size, partSize int64) {
+	if rt == nil {
+		return
+	}
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if rt.started && (rt.size != size || rt.partSize != partSize) {
+		rt.done = make(map[int]bool)
+	}
+
+	rt.u = u
+	rt.reuseURL = creds == nil
+	rt.size = size
+	rt.partSize = partSize
+	rt.started = true
+	rt.abandoned = false
+}
+
+// partDone records that part has been written to the destination.
+func (rt *resumeTracker) partDone(part int) {
+	if rt == nil {
+		return
+	}
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	rt.done[part] = true
+}
+
+// abandon records that the parts written can no longer be used to resume the download, such as
+// when the download reverts to a single stream.
+func (rt *resumeTracker) abandon() {
+	if rt == nil {
+		return
+	}
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	rt.abandoned = true
+}
+
+// state returns the resume state of the download, or nil if the download cannot be resumed.
+func (rt *resumeTracker) state() *ResumeState {
+	if rt == nil {
+		return nil
+	}
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if !rt.started || rt.abandoned || len(rt.done) == 0 {
+		return nil
+	}
+
+	s := &ResumeState{
+		Version:  ResumeStateVersion,
+		Ref:      rt.ref,
+		Arch:     rt.arch,
+		Tag:      rt.tag,
+		Backend:  rt.backend,
+		Digest:   rt.u.digest,
+		Size:     rt.size,
+		PartSize: rt.partSize,
+	}
+
+	for part := range rt.done {
+		s.Completed = append(s.Completed, part)
+	}
+	sort.Ints(s.Completed)
+
+	if rt.reuseURL && rt.backend == TransferBackendLibrary {
+		u := rt.u.get()
+		if expires := presignedURLExpiry(u); !expires.IsZero() {
+			s.URL, s.URLExpires = u, expires
+		}
+	}
+	return s
+}
+
+// wrap returns a *ResumableDownloadError wrapping err if the download can be resumed, and err
+// otherwise.
+func (rt *resumeTracker) wrap(err error) error {
+	if s := rt.state(); s != nil {
+		return &ResumableDownloadError{State: s, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,304 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+// resumeServer serves an image from the library, redirecting to a presigned blob URL. While fail is
+// set, requests for the part starting at failStart fail.
+type resumeServer struct {
+	t         *testing.T
+	content   []byte
+	hash      string
+	failStart int64
+
+	mu      sync.Mutex
+	fail    bool
+	renewed bool
+	starts  []int64
+}
+
+func (s *resumeServer) setFail(b bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fail = b
+	s.renewed = false
+	s.starts = nil
+}
+
+func (s *resumeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/version":
+		if err := jsonresp.WriteResponse(w, VersionInfo{APIVersion: "1.0.0"}, http.StatusOK); err != nil {
+			s.t.Errorf("error writing JSON response: %v", err)
+		}
+	case strings.HasPrefix(r.URL.Path, "/v1/images/"):
+		if err := jsonresp.WriteResponse(w, Image{Hash: s.hash, Size: int64(len(s.content))}, http.StatusOK); err != nil {
+			s.t.Errorf("error writing JSON response: %v", err)
+		}
+	case strings.HasPrefix(r.URL.Path, "/v1/imagefile/"):
+		s.mu.Lock()
+		s.renewed = true
+		s.mu.Unlock()
+
+		date := time.Now().UTC().Format("20060102T150405Z")
+		w.Header().Set("Location", "http://"+r.Host+"/blob?X-Amz-Date="+date+"&X-Amz-Expires=3600")
+		w.WriteHeader(http.StatusSeeOther)
+	case r.URL.Path == "/blob":
+		start, end := parseRangeHeader(s.t, r.Header.Get("Range"))
+
+		s.mu.Lock()
+		s.starts = append(s.starts, start)
+		fail := s.fail && start == s.failStart
+		s.mu.Unlock()
+
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(s.content)))
+		w.Header().Set("Content-Length", fmt.Sprint(end-start+1))
+		w.WriteHeader(http.StatusPartialContent)
+
+		if _, err := w.Write(s.content[start : end+1]); err != nil {
+			s.t.Errorf("error writing response: %v", err)
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestResumeDownload(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 50)
+	sum := sha256.Sum256(content)
+
+	s := &resumeServer{t: t, content: content, hash: "sha256." + hex.EncodeToString(sum[:]), failStart: 200, fail: true}
+
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	spec := &Downloader{Concurrency: 1, PartSize: 100}
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger, Sleeper: noSleep})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	dst := &inMemoryBuffer{buf: make([]byte, len(content))}
+
+	_, err = c.PullImage(context.Background(), dst, "entity/collection/container", &DownloadOptions{Downloader: spec})
+
+	var re *ResumableDownloadError
+	if !errors.As(err, &re) {
+		t.Fatalf("got error %v, want ResumableDownloadError", err)
+	}
+
+	var pe *PartError
+	if !errors.As(err, &pe) {
+		t.Errorf("got error %v, want PartError", err)
+	}
+
+	st := re.State
+	if got, want := st.Completed, []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got completed parts %v, want %v", got, want)
+	}
+	if got, want := st.Backend, TransferBackendLibrary; got != want {
+		t.Errorf("got backend %v, want %v", got, want)
+	}
+	if got, want := string(st.Digest), "sha256:"+hex.EncodeToString(sum[:]); got != want {
+		t.Errorf("got digest %v, want %v", got, want)
+	}
+	if st.URL == "" || time.Until(st.URLExpires) < 59*time.Minute {
+		t.Errorf("got URL %v (expires %v), want reusable URL", st.URL, st.URLExpires)
+	}
+
+	b, err := MarshalResumeState(st)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s.setFail(false)
+
+	// Resume using another client, as another process would.
+	c, err = NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	st, err = UnmarshalResumeState(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spec.Verifier = &DownloadVerifier{Digest: st.Digest, PartSums: true}
+
+	summary, err := c.ResumeDownload(context.Background(), dst, st, &DownloadOptions{Downloader: spec})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !bytes.Equal(dst.Bytes(), content) {
+		t.Error("downloaded content does not match")
+	}
+
+	if got, want := s.starts, []int64{200, 300, 400}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got requested parts %v, want %v", got, want)
+	}
+	if s.renewed {
+		t.Error("URL unexpectedly renewed")
+	}
+
+	if summary.Verification == nil || !summary.Verification.Verified {
+		t.Fatalf("got verification %+v, want verified", summary.Verification)
+	}
+
+	var wantSums []string
+	for start := 0; start < len(content); start += 100 {
+		sum := sha256.Sum256(content[start : start+100])
+		wantSums = append(wantSums, hex.EncodeToString(sum[:]))
+	}
+	if got, want := summary.Verification.PartSums, wantSums; !reflect.DeepEqual(got, want) {
+		t.Errorf("got part sums %v, want %v", got, want)
+	}
+}
+
+func TestResumeDownloadInsecureURL(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 50)
+	sum := sha256.Sum256(content)
+
+	s := &resumeServer{t: t, content: content, hash: "sha256." + hex.EncodeToString(sum[:])}
+
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	st := &ResumeState{
+		Version:    ResumeStateVersion,
+		Ref:        "entity/collection/container",
+		Tag:        "latest",
+		Backend:    TransferBackendLibrary,
+		Size:       int64(len(content)),
+		PartSize:   100,
+		Completed:  []int{1},
+		URL:        "http://bucket.example.com/blob?X-Amz-Signature=abc",
+		URLExpires: time.Now().Add(time.Hour),
+	}
+
+	dst := &inMemoryBuffer{buf: make([]byte, len(content))}
+
+	if _, err := c.ResumeDownload(context.Background(), dst, st, nil); !errors.Is(err, ErrInsecureEndpoint) {
+		t.Errorf("got error %v, want %v", err, ErrInsecureEndpoint)
+	}
+}
+
+func TestResumeDownloadStale(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 50)
+	sum := sha256.Sum256(content)
+
+	s := &resumeServer{t: t, content: content, hash: "sha256." + hex.EncodeToString(sum[:])}
+
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	st := &ResumeState{
+		Version:   ResumeStateVersion,
+		Ref:       "entity/collection/container",
+		Tag:       "latest",
+		Backend:   TransferBackendLibrary,
+		Digest:    "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+		Size:      int64(len(content)),
+		PartSize:  100,
+		Completed: []int{1},
+	}
+
+	dst := &inMemoryBuffer{buf: make([]byte, len(content))}
+
+	if _, err := c.ResumeDownload(context.Background(), dst, st, nil); !errors.Is(err, ErrResumeStateStale) {
+		t.Errorf("got error %v, want %v", err, ErrResumeStateStale)
+	}
+}
+
+func TestUnmarshalResumeState(t *testing.T) {
+	tests := []struct {
+		name        string
+		json        string
+		expectErr   bool
+		wantVersion bool
+	}{
+		{"OK", `{"version":1,"ref":"e/c/i","tag":"latest","backend":"library","size":10,"partSize":4,"completed":[1,3]}`, false, false},
+		{"Version", `{"version":2,"ref":"e/c/i","tag":"latest","backend":"library","size":10,"partSize":4}`, true, true},
+		{"Part", `{"version":1,"ref":"e/c/i","tag":"latest","backend":"library","size":10,"partSize":4,"completed":[4]}`, true, false},
+		{"Backend", `{"version":1,"ref":"e/c/i","tag":"latest","backend":"ftp","size":10,"partSize":4}`, true, false},
+		{"Size", `{"version":1,"ref":"e/c/i","tag":"latest","backend":"oci","partSize":4}`, true, false},
+		{"Malformed", `{"version":`, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := UnmarshalResumeState([]byte(tt.json))
+			if tt.expectErr && err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got, want := errors.Is(err, ErrResumeStateVersion), tt.wantVersion; got != want {
+				t.Errorf("got version error %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_presignedURLExpiry(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want time.Time
+	}{
+		{"S3", "https://bucket.s3.amazonaws.com/key?X-Amz-Date=20260101T000000Z&X-Amz-Expires=900", time.Date(2026, 1, 1, 0, 15, 0, 0, time.UTC)},
+		{"GCS", "https://storage.googleapis.com/b/k?X-Goog-Date=20260101T000000Z&X-Goog-Expires=60", time.Date(2026, 1, 1, 0, 1, 0, 0, time.UTC)},
+		{"S3V2", "https://bucket.s3.amazonaws.com/key?Expires=1767225600", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"Azure", "https://account.blob.core.windows.net/c/b?se=2026-01-01T00:00:00Z&sig=x", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"Unknown", "https://example.com/blob", time.Time{}},
+		{"BadDate", "https://bucket.s3.amazonaws.com/key?X-Amz-Date=bad&X-Amz-Expires=900", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := presignedURLExpiry(tt.url); !got.Equal(tt.want) {
+				t.Errorf("got expiry %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+// RetryPolicy determines whether a failed operation (such as the upload of a part) is
+// re-attempted, and the delay before it is. Operations that fail with a permanent error (such as
+// an authorization failure, or a checksum mismatch) are not re-attempted, regardless of the
+// policy.
+type RetryPolicy interface {
+	// Retry is called when attempt (numbered from zero) of an operation fails with err. It returns
+	// the delay before the operation is re-attempted, or false if the operation is abandoned.
+	Retry(attempt int, err error) (time.Duration, bool)
+}
+
+// ExponentialBackoff is a RetryPolicy that re-attempts a failed operation up to Retries times,
+// doubling the delay before each successive attempt. Only operations that fail with a transient
+// error (such as a network error, or a 5xx or 429 response) are re-attempted.
+type ExponentialBackoff struct {
+	// Retries is the maximum number of times an operation is re-attempted.
+	Retries int
+	// Delay is the delay before the first re-attempt.
+	Delay time.Duration
+	// MaxDelay limits the delay before any re-attempt. If zero, the delay is not limited.
+	MaxDelay time.Duration
+}
+
+// Retry implements RetryPolicy.
+func (b ExponentialBackoff) Retry(attempt int, err error) (time.Duration, bool) {
+	if attempt >= b.Retries || !isRetryable(err) {
+		return 0, false
+	}
+
+	d := b.Delay
+	for i := 0; i < attempt && d > 0; i++ {
+		if b.MaxDelay > 0 && d >= b.MaxDelay {
+			break
+		}
+		d *= 2
+	}
+
+	if b.MaxDelay > 0 && d > b.MaxDelay {
+		d = b.MaxDelay
+	}
+	return d, true
+}
+
+// isRetryable returns true if err, returned by a failed attempt of an operation, may be transient,
+// such that a re-attempt may succeed. Network errors, server errors (5xx), rate limiting (429) and
+// expired presigned URLs are transient. Other errors, such as authorization failures, other 4xx
+// responses and checksum mismatches, are permanent.
+func isRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, ErrUnauthorized) {
+		return false
+	}
+	if errors.Is(err, errPresignedURLExpired) {
+		return true
+	}
+
+	var (
+		pde *PermissionDeniedError
+		cme *ChecksumMismatchError
+		ose *ObjectStoreError
+		re  *RegistryError
+		je  *jsonresp.Error
+		se  *httpStatusError
+		ne  net.Error
+	)
+	switch {
+	case errors.As(err, &pde), errors.As(err, &cme):
+		return false
+	case errors.As(err, &ose):
+		return isRetryableStatus(ose.StatusCode)
+	case errors.As(err, &re):
+		return isRetryableStatus(re.StatusCode)
+	case errors.As(err, &je):
+		return isRetryableStatus(je.Code)
+	case errors.As(err, &se):
+		return isRetryableStatus(se.code)
+	case errors.As(err, &ne):
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// isRetryableStatus returns true if HTTP status code indicates a transient failure.
+func isRetryableStatus(code int) bool {
+	return code >= 500 || code == http.StatusTooManyRequests
+}
+
+// Sleeper waits between attempts of an operation. Substituting a Sleeper allows retry behaviour to
+// be observed (and timeouts simulated) without real delays.
+type Sleeper interface {
+	// Sleep blocks until d has elapsed, or ctx is done. If ctx is done, ctx.Err() is returned.
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// SleeperFunc is an adapter to allow the use of an ordinary function as a Sleeper.
+type SleeperFunc func(ctx context.Context, d time.Duration) error
+
+// Sleep calls f(ctx, d).
+func (f SleeperFunc) Sleep(ctx context.Context, d time.Duration) error {
+	return f(ctx, d)
+}
+
+// timerSleeper is a Sleeper that waits in real time.
+type timerSleeper struct{}
+
+// Sleep implements Sleeper.
+func (timerSleeper) Sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+// noSleep is a Sleeper that does not wait.
+var noSleep = SleeperFunc(func(ctx context.Context, _ time.Duration) error {
+	return ctx.Err()
+})
+
+// recordingSleeper is a Sleeper that records the delays requested of it, without waiting.
+type recordingSleeper struct {
+	mu     sync.Mutex
+	delays []time.Duration
+}
+
+func (s *recordingSleeper) Sleep(ctx context.Context, d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.delays = append(s.delays, d)
+	return ctx.Err()
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	transient := &PartError{PartNumber: 1, Err: &ObjectStoreError{StatusCode: http.StatusInternalServerError}}
+
+	tests := []struct {
+		name    string
+		policy  ExponentialBackoff
+		attempt int
+		err     error
+		want    time.Duration
+		wantOK  bool
+	}{
+		{"First", ExponentialBackoff{Retries: 3, Delay: time.Second}, 0, transient, time.Second, true},
+		{"Second", ExponentialBackoff{Retries: 3, Delay: time.Second}, 1, transient, 2 * time.Second, true},
+		{"Third", ExponentialBackoff{Retries: 3, Delay: time.Second}, 2, transient, 4 * time.Second, true},
+		{"Exhausted", ExponentialBackoff{Retries: 3, Delay: time.Second}, 3, transient, 0, false},
+		{"Disabled", ExponentialBackoff{}, 0, transient, 0, false},
+		{"MaxDelay", ExponentialBackoff{Retries: 3, Delay: time.Second, MaxDelay: 3 * time.Second}, 2, transient, 3 * time.Second, true},
+		{"NoDelay", ExponentialBackoff{Retries: 3}, 2, transient, 0, true},
+		{"Overflow", ExponentialBackoff{Retries: 100, Delay: time.Second, MaxDelay: time.Minute}, 99, transient, time.Minute, true},
+		{"Forbidden", ExponentialBackoff{Retries: 3, Delay: time.Second}, 0, &PartError{PartNumber: 1, Err: &ObjectStoreError{StatusCode: http.StatusForbidden}}, 0, false},
+		{"BadRequest", ExponentialBackoff{Retries: 3, Delay: time.Second}, 0, &PartError{PartNumber: 1, Err: &ObjectStoreError{StatusCode: http.StatusBadRequest}}, 0, false},
+		{"Permanent", ExponentialBackoff{Retries: 3, Delay: time.Second}, 0, errors.New("error"), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.policy.Retry(tt.attempt, tt.err)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("got %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func Test_isRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"Nil", nil, false},
+		{"Network", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"UnexpectedEOF", fmt.Errorf("error reading response: %w", io.ErrUnexpectedEOF), true},
+		{"Timeout", context.DeadlineExceeded, true},
+		{"Canceled", context.Canceled, false},
+		{"ObjectStoreServerError", &ObjectStoreError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"ObjectStoreTooManyRequests", &ObjectStoreError{StatusCode: http.StatusTooManyRequests}, true},
+		{"ObjectStoreForbidden", &ObjectStoreError{StatusCode: http.StatusForbidden, Code: "AccessDenied"}, false},
+		{"ObjectStoreBadRequest", &ObjectStoreError{StatusCode: http.StatusBadRequest}, false},
+		{"PresignedURLExpired", fmt.Errorf("%w: %w", errPresignedURLExpired, &ObjectStoreError{StatusCode: http.StatusForbidden}), true},
+		{"RegistryServerError", &RegistryError{StatusCode: http.StatusBadGateway}, true},
+		{"PermissionDenied", &PermissionDeniedError{Err: &RegistryError{StatusCode: http.StatusForbidden}}, false},
+		{"LibraryServerError", &jsonresp.Error{Code: http.StatusInternalServerError}, true},
+		{"LibraryBadRequest", &jsonresp.Error{Code: http.StatusBadRequest}, false},
+		{"LibraryStatus", &RequestIDError{RequestID: "id", Err: &httpStatusError{code: http.StatusBadGateway}}, true},
+		{"Unauthorized", ErrTokenExpired, false},
+		{"ChecksumMismatch", &ChecksumMismatchError{PartNumber: 1, Algorithm: "md5"}, false},
+		{"Other", errors.New("error"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := isRetryable(tt.err), tt.want; got != want {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_multipartUploadPartRetryPolicy(t *testing.T) {
+	const imageID = "5cb9c34d7d960d82f5f5bc55"
+
+	tests := []struct {
+		name       string
+		cfg        Config
+		code       int
+		wantPUTs   int
+		wantDelays []time.Duration
+	}{
+		{
+			name:       "Default",
+			wantPUTs:   4,
+			wantDelays: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+		{
+			name:       "UploadPartRetries",
+			cfg:        Config{UploadPartRetries: 1},
+			wantPUTs:   2,
+			wantDelays: []time.Duration{time.Second},
+		},
+		{
+			name: "RetryPolicy",
+			cfg: Config{
+				UploadPartRetries: 1,
+				RetryPolicy:       ExponentialBackoff{Retries: 2, Delay: time.Minute, MaxDelay: time.Minute},
+			},
+			wantPUTs:   3,
+			wantDelays: []time.Duration{time.Minute, time.Minute},
+		},
+		{
+			name:     "Forbidden",
+			code:     http.StatusForbidden,
+			wantPUTs: 1,
+		},
+		{
+			name:     "BadRequest",
+			code:     http.StatusBadRequest,
+			wantPUTs: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var putRequests int
+
+			mux := http.NewServeMux()
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			mux.HandleFunc("/v2/imagefile/"+imageID+"/_multipart", func(w http.ResponseWriter, _ *http.Request) {
+				response := UploadImagePart{PresignedURL: srv.URL + "/s3/part"}
+				if err := jsonresp.WriteResponse(w, &response, http.StatusOK); err != nil {
+					t.Errorf("error writing JSON response: %v", err)
+				}
+			})
+			mux.HandleFunc("/s3/part", func(w http.ResponseWriter, _ *http.Request) {
+				putRequests++
+
+				code := tt.code
+				if code == 0 {
+					code = http.StatusInternalServerError
+				}
+				w.WriteHeader(code)
+			})
+
+			s := &recordingSleeper{}
+
+			cfg := tt.cfg
+			cfg.AuthToken = testToken
+			cfg.BaseURL = srv.URL
+			cfg.Logger = testLogger
+			cfg.Sleeper = s
+
+			c, err := NewClient(&cfg)
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			r := strings.NewReader("0123456789")
+
+			m := &uploadManager{
+				Source:   r,
+				Size:     10,
+				ImageID:  imageID,
+				UploadID: "uploadID",
+			}
+
+			if _, err := c.multipartUploadPart(context.Background(), 1, m, &defaultUploadCallback{r: r}, objectStore{kind: ObjectStoreS3, s3Compliant: true}); err == nil {
+				t.Fatal("unexpected success")
+			}
+
+			if got, want := putRequests, tt.wantPUTs; got != want {
+				t.Errorf("got %v PUT requests, want %v", got, want)
+			}
+
+			if got, want := s.delays, tt.wantDelays; !reflect.DeepEqual(got, want) {
+				t.Errorf("got delays %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestTimerSleeperCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := (timerSleeper{}).Sleep(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
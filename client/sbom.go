@@ -0,0 +1,164 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Media types of SBOM documents that may be associated with an image.
+const (
+	MediaTypeSPDXJSON      = "application/spdx+json"
+	MediaTypeCycloneDXJSON = "application/vnd.cyclonedx+json"
+	MediaTypeCycloneDXXML  = "application/vnd.cyclonedx+xml"
+)
+
+// sbomMediaTypes are the supported SBOM document media types.
+var sbomMediaTypes = []string{MediaTypeSPDXJSON, MediaTypeCycloneDXJSON, MediaTypeCycloneDXXML}
+
+// maxSBOMSize is the maximum size of an SBOM document.
+const maxSBOMSize = 64 * 1024 * 1024
+
+// AttachSBOM associates the SBOM document read from r, of type mediaType (ie. MediaTypeSPDXJSON),
+// with the image identified by ref (ie. "entity/collection/container:tag"). If supported, the
+// document is uploaded to the OCI registry as an artifact referring to the image; otherwise, the
+// library SBOM endpoint is used.
+func (c *Client) AttachSBOM(ctx context.Context, ref, mediaType string, r io.Reader) error {
+	if !isSBOMMediaType(mediaType) {
+		return fmt.Errorf("unsupported SBOM media type: %v", mediaType)
+	}
+
+	name, tag := artifactRef(ref)
+
+	b, err := io.ReadAll(io.LimitReader(r, maxSBOMSize+1))
+	if err != nil {
+		return fmt.Errorf("error reading SBOM: %w", err)
+	}
+	if len(b) > maxSBOMSize {
+		return fmt.Errorf("SBOM exceeds maximum size of %d bytes", maxSBOMSize)
+	}
+
+	if err := c.ociAttachSBOM(ctx, name, tag, mediaType, b); err != nil {
+		if !errors.Is(err, errOCIDownloadNotSupported) {
+			return err
+		}
+
+		c.logger.Log("Fallback to (legacy) library SBOM upload")
+
+		if _, err := c.apiUpdate(ctx, "v1/sboms/"+name+":"+tag, SBOM{MediaType: mediaType, Content: b}); err != nil {
+			return fmt.Errorf("error attaching SBOM: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetSBOM returns the SBOM document most recently associated with the image identified by ref
+// (ie. "entity/collection/container:tag"); returns ErrNotFound if no SBOM is associated with the
+// image.
+func (c *Client) GetSBOM(ctx context.Context, ref string) (*SBOM, error) {
+	name, tag := artifactRef(ref)
+
+	s, err := c.ociGetSBOM(ctx, name, tag)
+	if err != nil {
+		if !errors.Is(err, errOCIDownloadNotSupported) {
+			return nil, err
+		}
+
+		c.logger.Log("Fallback to (legacy) library SBOM download")
+
+		b, err := c.apiGet(ctx, "v1/sboms/"+name+":"+tag)
+		if err != nil {
+			return nil, err
+		}
+		var res SBOMResponse
+		if err := json.Unmarshal(b, &res); err != nil {
+			return nil, fmt.Errorf("error decoding SBOM: %v", err)
+		}
+		return &res.Data, nil
+	}
+	return s, nil
+}
+
+// isSBOMMediaType returns true if mediaType is a supported SBOM document media type.
+func isSBOMMediaType(mediaType string) bool {
+	for _, mt := range sbomMediaTypes {
+		if mt == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// ociAttachSBOM uploads SBOM document b of type mediaType to the OCI registry, as an artifact
+// referring to the manifest associated with name/tag.
+func (c *Client) ociAttachSBOM(ctx context.Context, name, tag, mediaType string, b []byte) error {
+	reg, creds, name, err := c.newOCIRegistry(ctx, name, []accessType{accessTypePull, accessTypePush})
+	if err != nil {
+		return err
+	}
+
+	subject, err := reg.describeManifest(ctx, creds, name, tag)
+	if err != nil {
+		return fmt.Errorf("error resolving tag: %w", err)
+	}
+
+	annotations := map[string]string{
+		v1.AnnotationCreated: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	d, err := reg.uploadArtifact(ctx, creds, name, mediaType, subject, mediaType, b, annotations)
+	if err != nil {
+		return fmt.Errorf("error uploading SBOM: %w", err)
+	}
+
+	c.logger.Logf("Attached SBOM %v to %v", d, subject.Digest)
+	return nil
+}
+
+// ociGetSBOM returns the SBOM document most recently associated with the manifest associated with
+// name/tag in the OCI registry.
+func (c *Client) ociGetSBOM(ctx context.Context, name, tag string) (*SBOM, error) {
+	reg, creds, name, err := c.newOCIRegistry(ctx, name, []accessType{accessTypePull})
+	if err != nil {
+		return nil, err
+	}
+
+	subject, err := reg.resolveManifest(ctx, creds, name, tag)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving tag: %w", err)
+	}
+
+	referrers, err := reg.listReferrers(ctx, creds, name, subject, "")
+	if err != nil {
+		return nil, fmt.Errorf("error listing referrers: %w", err)
+	}
+
+	var latest *v1.Descriptor
+	for i, d := range referrers {
+		if !isSBOMMediaType(d.ArtifactType) {
+			continue
+		}
+		if latest == nil || d.Annotations[v1.AnnotationCreated] >= latest.Annotations[v1.AnnotationCreated] {
+			latest = &referrers[i]
+		}
+	}
+	if latest == nil {
+		return nil, ErrNotFound
+	}
+
+	l, b, err := reg.downloadArtifact(ctx, creds, name, latest.Digest, maxSBOMSize)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading SBOM: %w", err)
+	}
+	return &SBOM{MediaType: l.MediaType, Content: b}, nil
+}
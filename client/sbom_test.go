@@ -0,0 +1,276 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+// mockArtifactRegistry is a minimal in-memory OCI registry, supporting blob and manifest upload,
+// and the referrers API.
+type mockArtifactRegistry struct {
+	t         *testing.T
+	mu        sync.Mutex
+	blobs     map[digest.Digest][]byte
+	manifests map[string][]byte // keyed by tag or digest
+	types     map[string]string // manifest media type, keyed by tag or digest
+	referrers map[digest.Digest][]v1.Descriptor
+	uploads   map[string]*bytes.Buffer
+}
+
+func newMockArtifactRegistry(t *testing.T) *mockArtifactRegistry {
+	m := &mockArtifactRegistry{
+		t:         t,
+		blobs:     make(map[digest.Digest][]byte),
+		manifests: make(map[string][]byte),
+		types:     make(map[string]string),
+		referrers: make(map[digest.Digest][]v1.Descriptor),
+		uploads:   make(map[string]*bytes.Buffer),
+	}
+
+	idx, err := json.Marshal(v1.Index{MediaType: v1.MediaTypeImageIndex})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ref := range []string{"latest", digest.FromBytes(idx).String()} {
+		m.manifests[ref] = idx
+		m.types[ref] = v1.MediaTypeImageIndex
+	}
+
+	return m
+}
+
+// manifestDigest returns the digest of the manifest associated with ref.
+func (m *mockArtifactRegistry) manifestDigest(ref string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, ok := m.manifests[ref]
+	if !ok {
+		return "", fmt.Errorf("manifest %v not found", ref)
+	}
+	return digest.FromBytes(b).String(), nil
+}
+
+func (m *mockArtifactRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r.Header.Get("Authorization") == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	const prefix = "/v2/entity/collection/container/"
+
+	path := strings.TrimPrefix(r.URL.Path, prefix)
+
+	switch {
+	case path == "blobs/uploads/" && r.Method == http.MethodPost:
+		session := "blobs/uploads/" + strconv.Itoa(len(m.uploads))
+		m.uploads[session] = &bytes.Buffer{}
+		w.Header().Set("Location", prefix+session)
+		w.WriteHeader(http.StatusAccepted)
+
+	case strings.HasPrefix(path, "blobs/uploads/") && r.Method == http.MethodPatch:
+		if _, err := io.Copy(m.uploads[path], r.Body); err != nil {
+			m.t.Errorf("error reading request body: %v", err)
+		}
+		w.Header().Set("Location", r.URL.Path)
+		w.WriteHeader(http.StatusAccepted)
+
+	case strings.HasPrefix(path, "blobs/uploads/") && r.Method == http.MethodPut:
+		m.blobs[digest.Digest(r.URL.Query().Get("digest"))] = m.uploads[path].Bytes()
+		w.WriteHeader(http.StatusCreated)
+
+	case strings.HasPrefix(path, "blobs/") && r.Method == http.MethodGet:
+		b, ok := m.blobs[digest.Digest(strings.TrimPrefix(path, "blobs/"))]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(b)
+
+	case strings.HasPrefix(path, "manifests/") && r.Method == http.MethodPut:
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			m.t.Errorf("error reading request body: %v", err)
+		}
+		d := digest.FromBytes(b)
+		m.manifests[d.String()] = b
+		m.types[d.String()] = r.Header.Get("Content-Type")
+
+		var mf v1.Manifest
+		if err := json.Unmarshal(b, &mf); err != nil {
+			m.t.Errorf("error decoding manifest: %v", err)
+		}
+		if mf.Subject != nil {
+			m.referrers[mf.Subject.Digest] = append(m.referrers[mf.Subject.Digest], v1.Descriptor{
+				MediaType:    v1.MediaTypeImageManifest,
+				ArtifactType: mf.ArtifactType,
+				Digest:       d,
+				Size:         int64(len(b)),
+				Annotations:  mf.Annotations,
+			})
+		}
+		w.WriteHeader(http.StatusCreated)
+
+	case strings.HasPrefix(path, "manifests/"):
+		ref := strings.TrimPrefix(path, "manifests/")
+		b, ok := m.manifests[ref]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", m.types[ref])
+		w.Header().Set("Docker-Content-Digest", digest.FromBytes(b).String())
+		w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(b)
+		}
+
+	case strings.HasPrefix(path, "referrers/"):
+		idx := v1.Index{
+			MediaType: v1.MediaTypeImageIndex,
+			Manifests: m.referrers[digest.Digest(strings.TrimPrefix(path, "referrers/"))],
+		}
+		w.Header().Set("Content-Type", v1.MediaTypeImageIndex)
+		if err := json.NewEncoder(w).Encode(idx); err != nil {
+			m.t.Errorf("error JSON encoding: %v", err)
+		}
+
+	default:
+		m.t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestSBOM(t *testing.T) {
+	tests := []struct {
+		name string
+		oci  bool
+	}{
+		{"OCI", true},
+		{"Library", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := newMockArtifactRegistry(t)
+
+			var librarySBOM *SBOM
+
+			var srv *httptest.Server
+			srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/v1/oci-redirect":
+					if !tt.oci {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+
+					response := struct {
+						Token       string `json:"token"`
+						RegistryURI string `json:"url"`
+						Name        string `json:"name"`
+					}{
+						Token:       "xxx",
+						RegistryURI: srv.URL,
+						Name:        "entity/collection/container",
+					}
+					if err := json.NewEncoder(w).Encode(&response); err != nil {
+						t.Errorf("error JSON encoding: %v", err)
+					}
+
+				case strings.HasPrefix(r.URL.Path, "/v2/"):
+					reg.ServeHTTP(w, r)
+
+				case r.URL.Path == "/v1/sboms/entity/collection/container:latest" && r.Method == http.MethodPut:
+					librarySBOM = &SBOM{}
+					if err := json.NewDecoder(r.Body).Decode(librarySBOM); err != nil {
+						t.Errorf("error decoding SBOM: %v", err)
+					}
+					if err := jsonresp.WriteResponse(w, librarySBOM, http.StatusOK); err != nil {
+						t.Errorf("error writing JSON response: %v", err)
+					}
+
+				case r.URL.Path == "/v1/sboms/entity/collection/container:latest" && r.Method == http.MethodGet:
+					if librarySBOM == nil {
+						if err := jsonresp.WriteError(w, "not found", http.StatusNotFound); err != nil {
+							t.Errorf("error writing JSON error: %v", err)
+						}
+						return
+					}
+					if err := jsonresp.WriteResponse(w, librarySBOM, http.StatusOK); err != nil {
+						t.Errorf("error writing JSON response: %v", err)
+					}
+
+				default:
+					t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			ctx := context.Background()
+			ref := "entity/collection/container:latest"
+
+			if _, err := c.GetSBOM(ctx, ref); !errors.Is(err, ErrNotFound) {
+				t.Errorf("got error %v, want %v", err, ErrNotFound)
+			}
+
+			if err := c.AttachSBOM(ctx, ref, "text/plain", strings.NewReader("sbom")); err == nil {
+				t.Error("unexpected success attaching unsupported media type")
+			}
+
+			docs := []struct {
+				mediaType string
+				content   string
+			}{
+				{MediaTypeSPDXJSON, `{"spdxVersion":"SPDX-2.3"}`},
+				{MediaTypeCycloneDXJSON, `{"bomFormat":"CycloneDX"}`},
+			}
+			for _, d := range docs {
+				if err := c.AttachSBOM(ctx, ref, d.mediaType, strings.NewReader(d.content)); err != nil {
+					t.Fatalf("error attaching SBOM: %v", err)
+				}
+			}
+
+			s, err := c.GetSBOM(ctx, "entity/collection/container")
+			if err != nil {
+				t.Fatalf("error getting SBOM: %v", err)
+			}
+
+			want := docs[len(docs)-1]
+			if got := s.MediaType; got != want.mediaType {
+				t.Errorf("got media type %v, want %v", got, want.mediaType)
+			}
+			if got := string(s.Content); got != want.content {
+				t.Errorf("got content %q, want %q", got, want.content)
+			}
+		})
+	}
+}
@@ -7,11 +7,50 @@ package client
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
+	"strings"
 )
 
+// MinQueryLength is the minimum length of the value of a search query.
+const MinQueryLength = 3
+
+var (
+	// ErrQueryRequired is returned when the value of a search query is not specified.
+	ErrQueryRequired = errors.New("search query ('value') must be specified")
+	// ErrQueryTooShort is returned when the value of a search query is shorter than
+	// MinQueryLength. Use errors.As with a *QueryTooShortError to obtain the minimum length.
+	ErrQueryTooShort = errors.New("search query too short")
+)
+
+// QueryTooShortError is returned when the value of a search query is shorter than the minimum
+// length.
+type QueryTooShortError struct {
+	// Query is the value of the search query.
+	Query string
+	// MinLength is the minimum length of the value of a search query.
+	MinLength int
+}
+
+func (e *QueryTooShortError) Error() string {
+	return fmt.Sprintf("bad query '%s'. You must search for at least %d characters", e.Query, e.MinLength)
+}
+
+func (e *QueryTooShortError) Is(target error) bool { return target == ErrQueryTooShort }
+
+// validateQueryValue returns an error if value is not a valid search query value.
+func validateQueryValue(value string) error {
+	if value == "" {
+		return ErrQueryRequired
+	}
+	if len(value) < MinQueryLength {
+		return &QueryTooShortError{Query: value, MinLength: MinQueryLength}
+	}
+	return nil
+}
+
 // Search performs a library search, returning any matching collections,
 // containers, entities, or images.
 //
@@ -19,11 +58,16 @@ import (
 // (ie. "amd64") or "signed" (valid values "true" or "false").
 //
 // "value" is a required keyword for all searches. It will be matched against
-// all collections (Entity, Collection, Container, and Image)
+// all collections (Entity, Collection, Container, and Image). If it is not
+// specified, ErrQueryRequired is returned. If it is shorter than
+// MinQueryLength, a *QueryTooShortError is returned.
 //
 // Multiple architectures may be searched by specifying a comma-separated list
 // (ie. "amd64,arm64") for the value of "arch".
 //
+// Results may be sorted by specifying "sortBy" (see SortField) and "order"
+// (see SortOrder).
+//
 // Match all collections with name "thename":
 //
 //	c.Search(ctx, map[string]string{"value": "thename"})
@@ -37,30 +81,176 @@ import (
 //
 // Note: if 'arch' and/or 'signed' are specified, the search is limited in
 // scope only to the "Image" collection.
+//
+// Unless "includeTags" is specified as "false", matching images include the
+// tags pointing at them, and the path of their container (see
+// Image.LibraryURIs).
 func (c *Client) Search(ctx context.Context, args map[string]string) (*SearchResults, error) {
-	// "value" is minimally required in "args"
-	value, ok := args["value"]
-	if !ok {
-		return nil, fmt.Errorf("search query ('value') must be specified")
+	v := url.Values{}
+	v.Set("includeTags", "true")
+	for key, value := range args {
+		v.Set(key, value)
 	}
 
-	if len(value) < 3 {
-		return nil, fmt.Errorf("bad query '%s'. You must search for at least 3 characters", value)
+	return c.search(ctx, v)
+}
+
+// SortField specifies the field by which results are sorted.
+type SortField string
+
+const (
+	// SortByName sorts results by name.
+	SortByName SortField = "name"
+	// SortByCreated sorts results by creation time.
+	SortByCreated SortField = "created"
+	// SortByDownloads sorts results by download count.
+	SortByDownloads SortField = "downloads"
+	// SortBySize sorts results by size.
+	SortBySize SortField = "size"
+)
+
+// SortOrder specifies the order in which results are sorted.
+type SortOrder string
+
+const (
+	// SortAscending sorts results in ascending order.
+	SortAscending SortOrder = "asc"
+	// SortDescending sorts results in descending order.
+	SortDescending SortOrder = "desc"
+)
+
+// validateSort returns an error if by or order are not valid.
+func validateSort(by SortField, order SortOrder) error {
+	switch by {
+	case "", SortByName, SortByCreated, SortByDownloads, SortBySize:
+	default:
+		return fmt.Errorf("invalid sort field '%s'", by)
+	}
+
+	switch order {
+	case "":
+	case SortAscending, SortDescending:
+		if by == "" {
+			return fmt.Errorf("sort order '%s' specified without sort field", order)
+		}
+	default:
+		return fmt.Errorf("invalid sort order '%s'", order)
 	}
+	return nil
+}
+
+// SearchQuery specifies a library search. Filters that are not set (zero
+// valued) are not applied.
+type SearchQuery struct {
+	// Value is matched against all collections (Entity, Collection, Container,
+	// and Image). At least MinQueryLength characters are required.
+	Value string
+	// Arch limits results to images of any of the specified architectures
+	// (ie. "amd64").
+	Arch []string
+	// Signed limits results to signed (or unsigned) images.
+	Signed *bool
+	// Encrypted limits results to encrypted (or unencrypted) images.
+	Encrypted *bool
+	// Entity limits results to the specified entity.
+	Entity string
+	// Collection limits results to collections with the specified name.
+	Collection string
+	// ExcludeTags omits the tags pointing at matching images from results.
+	ExcludeTags bool
+	// SortBy specifies the field by which results are sorted. If empty, the
+	// server default order applies.
+	SortBy SortField
+	// Order specifies the order in which results are sorted. If empty, the
+	// server default order applies.
+	Order SortOrder
+}
 
+// validate returns an error if q is not a valid search query.
+func (q *SearchQuery) validate() error {
+	if err := validateQueryValue(q.Value); err != nil {
+		return err
+	}
+
+	for _, arch := range q.Arch {
+		if arch == "" || strings.ContainsAny(arch, ", ") {
+			return fmt.Errorf("invalid architecture '%s'", arch)
+		}
+	}
+
+	if q.Entity != "" && !IsRefPart(q.Entity) {
+		return fmt.Errorf("invalid entity '%s'", q.Entity)
+	}
+	if q.Collection != "" && !IsRefPart(q.Collection) {
+		return fmt.Errorf("invalid collection '%s'", q.Collection)
+	}
+	return validateSort(q.SortBy, q.Order)
+}
+
+// values returns the query parameters corresponding to q.
+func (q *SearchQuery) values() url.Values {
 	v := url.Values{}
-	for key, value := range args {
-		v.Set(key, value)
+	v.Set("value", q.Value)
+
+	if len(q.Arch) > 0 {
+		v.Set("arch", strings.Join(q.Arch, ","))
+	}
+	if q.Signed != nil {
+		v.Set("signed", strconv.FormatBool(*q.Signed))
+	}
+	if q.Encrypted != nil {
+		v.Set("encrypted", strconv.FormatBool(*q.Encrypted))
+	}
+	if q.Entity != "" {
+		v.Set("entity", q.Entity)
+	}
+	if q.Collection != "" {
+		v.Set("collection", q.Collection)
 	}
+	v.Set("includeTags", strconv.FormatBool(!q.ExcludeTags))
+	if q.SortBy != "" {
+		v.Set("sortBy", string(q.SortBy))
+	}
+	if q.Order != "" {
+		v.Set("order", string(q.Order))
+	}
+
+	return v
+}
+
+// SearchWithQuery performs a library search specified by q, returning any
+// matching collections, containers, entities, or images. The query is
+// validated before the request is made.
+//
+// Match all signed images with name "imagename" and arch "amd64" or "arm64":
+//
+//	signed := true
+//	c.SearchWithQuery(ctx, &SearchQuery{
+//	    Value:  "imagename",
+//	    Arch:   []string{"amd64", "arm64"},
+//	    Signed: &signed,
+//	})
+func (c *Client) SearchWithQuery(ctx context.Context, q *SearchQuery) (*SearchResults, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	return c.search(ctx, q.values())
+}
 
-	resJSON, err := c.apiGet(ctx, "v1/search?"+v.Encode())
-	if err != nil {
+// search performs a library search using query parameters v. The search query
+// value is validated before the request is made, so that all searches are
+// subject to the same validation.
+func (c *Client) search(ctx context.Context, v url.Values) (*SearchResults, error) {
+	if err := validateQueryValue(v.Get("value")); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
 	var res SearchResponse
-	if err := json.Unmarshal(resJSON, &res); err != nil {
-		return nil, fmt.Errorf("error decoding results: %v", err)
+	if err := c.apiGetJSON(ctx, "v1/search?"+v.Encode(), &res); err != nil {
+		return nil, err
 	}
 
 	return &res.Data, nil
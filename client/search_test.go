@@ -7,7 +7,9 @@ package client
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"net/url"
 	"reflect"
 	"testing"
 
@@ -23,6 +25,7 @@ func Test_Search(t *testing.T) {
 		searchArgs    map[string]string
 		expectResults *SearchResults
 		expectError   bool
+		wantErr       error
 	}{
 		{
 			description: "ValidRequest",
@@ -46,6 +49,30 @@ func Test_Search(t *testing.T) {
 			expectResults: &testSearch,
 			expectError:   false,
 		},
+		{
+			description: "IncludeTagsDefault",
+			searchArgs:  map[string]string{"value": "test"},
+			reqCallback: func(r *http.Request, t *testing.T) {
+				if got, want := r.URL.Query().Get("includeTags"), "true"; got != want {
+					t.Errorf("got includeTags %v, want %v", got, want)
+				}
+			},
+			code:          http.StatusOK,
+			body:          jsonresp.Response{Data: testSearch},
+			expectResults: &testSearch,
+		},
+		{
+			description: "IncludeTagsDisabled",
+			searchArgs:  map[string]string{"value": "test", "includeTags": "false"},
+			reqCallback: func(r *http.Request, t *testing.T) {
+				if got, want := r.URL.Query().Get("includeTags"), "false"; got != want {
+					t.Errorf("got includeTags %v, want %v", got, want)
+				}
+			},
+			code:          http.StatusOK,
+			body:          jsonresp.Response{Data: testSearch},
+			expectResults: &testSearch,
+		},
 		{
 			description: "InternalServerError",
 			searchArgs:  map[string]string{"value": "test"},
@@ -57,12 +84,21 @@ func Test_Search(t *testing.T) {
 			searchArgs:  map[string]string{},
 			code:        http.StatusBadRequest,
 			expectError: true,
+			wantErr:     ErrQueryRequired,
+		},
+		{
+			description: "EmptyValue",
+			searchArgs:  map[string]string{"value": ""},
+			code:        http.StatusBadRequest,
+			expectError: true,
+			wantErr:     ErrQueryRequired,
 		},
 		{
 			description: "InvalidValue",
 			searchArgs:  map[string]string{"value": "aa"},
 			code:        http.StatusBadRequest,
 			expectError: true,
+			wantErr:     ErrQueryTooShort,
 		},
 	}
 
@@ -93,9 +129,175 @@ func Test_Search(t *testing.T) {
 			if err == nil && tt.expectError {
 				t.Errorf("Unexpected success. Expected error.")
 			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
 			if !reflect.DeepEqual(results, tt.expectResults) {
 				t.Errorf("Got created collection %v - expected %v", results, tt.expectResults)
 			}
 		})
 	}
 }
+
+func TestImageLibraryURIs(t *testing.T) {
+	tests := []struct {
+		name string
+		img  Image
+		want []string
+	}{
+		{
+			name: "ContainerPath",
+			img:  Image{ContainerPath: "entity/collection/container", Tags: []string{"latest", "v1"}},
+			want: []string{"library://entity/collection/container:latest", "library://entity/collection/container:v1"},
+		},
+		{
+			name: "Names",
+			img:  Image{EntityName: "entity", CollectionName: "collection", ContainerName: "container", Tags: []string{"latest"}},
+			want: []string{"library://entity/collection/container:latest"},
+		},
+		{
+			name: "NoTags",
+			img:  Image{ContainerPath: "entity/collection/container"},
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.img.LibraryURIs(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got URIs %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateQueryValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr error
+	}{
+		{"OK", "abc", nil},
+		{"Empty", "", ErrQueryRequired},
+		{"Short", "ab", ErrQueryTooShort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateQueryValue(tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+
+			var qe *QueryTooShortError
+			if errors.As(err, &qe) && qe.MinLength != MinQueryLength {
+				t.Errorf("got minimum length %v, want %v", qe.MinLength, MinQueryLength)
+			}
+		})
+	}
+}
+
+func TestSearchCanceled(t *testing.T) {
+	c, err := NewClient(&Config{BaseURL: "https://library.example.com", Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := c.Search(ctx, map[string]string{"value": "test"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestSearchWithQuery(t *testing.T) {
+	yes, no := true, false
+
+	tests := []struct {
+		name       string
+		q          SearchQuery
+		wantValues url.Values
+		wantErr    bool
+	}{
+		{
+			name:       "Value",
+			q:          SearchQuery{Value: "test"},
+			wantValues: url.Values{"value": {"test"}, "includeTags": {"true"}},
+		},
+		{
+			name: "AllFilters",
+			q: SearchQuery{
+				Value:       "test",
+				Arch:        []string{"amd64", "arm64"},
+				Signed:      &yes,
+				Encrypted:   &no,
+				Entity:      "entity",
+				Collection:  "collection",
+				ExcludeTags: true,
+			},
+			wantValues: url.Values{
+				"value":       {"test"},
+				"arch":        {"amd64,arm64"},
+				"signed":      {"true"},
+				"encrypted":   {"false"},
+				"entity":      {"entity"},
+				"collection":  {"collection"},
+				"includeTags": {"false"},
+			},
+		},
+		{
+			name:       "Sort",
+			q:          SearchQuery{Value: "test", SortBy: SortByDownloads, Order: SortDescending},
+			wantValues: url.Values{"value": {"test"}, "includeTags": {"true"}, "sortBy": {"downloads"}, "order": {"desc"}},
+		},
+		{
+			name:       "SortDefaultOrder",
+			q:          SearchQuery{Value: "test", SortBy: SortByName},
+			wantValues: url.Values{"value": {"test"}, "includeTags": {"true"}, "sortBy": {"name"}},
+		},
+		{name: "ShortValue", q: SearchQuery{Value: "te"}, wantErr: true},
+		{name: "EmptyArch", q: SearchQuery{Value: "test", Arch: []string{""}}, wantErr: true},
+		{name: "CommaArch", q: SearchQuery{Value: "test", Arch: []string{"amd64,arm64"}}, wantErr: true},
+		{name: "InvalidEntity", q: SearchQuery{Value: "test", Entity: "Entity/"}, wantErr: true},
+		{name: "InvalidCollection", q: SearchQuery{Value: "test", Collection: "a b"}, wantErr: true},
+		{name: "InvalidSortBy", q: SearchQuery{Value: "test", SortBy: "popularity"}, wantErr: true},
+		{name: "InvalidOrder", q: SearchQuery{Value: "test", SortBy: SortBySize, Order: "up"}, wantErr: true},
+		{name: "OrderWithoutSortBy", q: SearchQuery{Value: "test", Order: SortAscending}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mockService{
+				t:    t,
+				code: http.StatusOK,
+				body: jsonresp.Response{Data: testSearch},
+				reqCallback: func(r *http.Request, t *testing.T) {
+					if tt.wantErr {
+						t.Error("unexpected request")
+					}
+					if got := r.URL.Query(); !reflect.DeepEqual(got, tt.wantValues) {
+						t.Errorf("got query %v, want %v", got, tt.wantValues)
+					}
+				},
+				httpPath: "/v1/search",
+			}
+
+			m.Run()
+			defer m.Stop()
+
+			c, err := NewClient(&Config{AuthToken: testToken, BaseURL: m.baseURI})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			results, err := c.SearchWithQuery(context.Background(), &tt.q)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(*results, testSearch) {
+				t.Errorf("got results %v, want %v", results, testSearch)
+			}
+		})
+	}
+}
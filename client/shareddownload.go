@@ -0,0 +1,129 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/go-log/log"
+	"github.com/opencontainers/go-digest"
+)
+
+// sharedDownloads deduplicates concurrent downloads of the same blob, as identified by its digest.
+// The first download of a blob proceeds as normal. Downloads of the same blob that begin while it
+// is in progress wait for it to complete, and copy the content from its destination.
+type sharedDownloads struct {
+	mu       sync.Mutex
+	logger   log.Logger
+	inflight map[digest.Digest]*sharedDownload
+}
+
+// sharedDownload is a download in progress, the content of which may be copied by other
+// downloads of the same blob.
+type sharedDownload struct {
+	done      chan struct{}  // closed when the download completes
+	r         io.ReaderAt    // destination of the download
+	err       error          // error of the download, valid once done is closed
+	followers sync.WaitGroup // downloads copying content from r
+}
+
+func newSharedDownloads(logger log.Logger) *sharedDownloads {
+	return &sharedDownloads{logger: logger, inflight: make(map[digest.Digest]*sharedDownload)}
+}
+
+// do downloads the blob with digest d of the specified size to w by calling download, unless a
+// download of the same blob is already in progress. In that case, the content is copied from the
+// destination of that download once it completes, or download is called if it fails.
+//
+// Content is only shared from a destination that implements io.ReaderAt. The first download does
+// not return until other downloads have copied its content, so that the destination is not
+// released while in use.
+func (s *sharedDownloads) do(ctx context.Context, d digest.Digest, w io.WriterAt, size int64, pb ProgressBar, download func() error) error {
+	s.mu.Lock()
+
+	if sd, ok := s.inflight[d]; ok {
+		sd.followers.Add(1)
+		s.mu.Unlock()
+
+		defer sd.followers.Done()
+
+		s.logger.Logf("Waiting for download of %v in progress", d)
+
+		select {
+		case <-sd.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if sd.err != nil {
+			s.logger.Logf("Shared download of %v failed; downloading independently", d)
+			return download()
+		}
+		return copyDownload(ctx, w, sd.r, size, pb)
+	}
+
+	ra, ok := w.(io.ReaderAt)
+	if !ok {
+		s.mu.Unlock()
+		return download()
+	}
+
+	sd := &sharedDownload{done: make(chan struct{}), r: ra}
+	s.inflight[d] = sd
+	s.mu.Unlock()
+
+	err := download()
+
+	// Remove the download before it completes, so that no further followers are added once
+	// followers are awaited.
+	s.mu.Lock()
+	delete(s.inflight, d)
+	s.mu.Unlock()
+
+	sd.err = err
+	close(sd.done)
+
+	sd.followers.Wait()
+
+	return err
+}
+
+// sharedCopyChunkSize is the size of the chunks in which shared content is copied.
+const sharedCopyChunkSize = 1024 * 1024
+
+// copyDownload copies size bytes of downloaded content from r to w, reporting progress to pb.
+func copyDownload(ctx context.Context, w io.WriterAt, r io.ReaderAt, size int64, pb ProgressBar) error {
+	pb.Init(size)
+	defer pb.Wait()
+
+	pw := &progressWriter{
+		w:  &filePartDescriptor{part: 1, start: 0, end: size - 1, w: w},
+		pb: pb,
+	}
+	src := io.NewSectionReader(r, 0, size)
+
+	for written := int64(0); written < size; {
+		err := ctx.Err()
+		if err == nil {
+			var n int64
+			n, err = io.CopyN(pw, src, minInt64(sharedCopyChunkSize, size-written))
+			written += n
+		}
+		if err != nil {
+			pb.Abort(true)
+
+			return fmt.Errorf("error copying shared download: %w", err)
+		}
+	}
+
+	// The content was copied from another download, rather than transferred over the network.
+	transferStatsFromContext(ctx).addPartNetwork(size, 0)
+
+	return nil
+}
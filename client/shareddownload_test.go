@@ -0,0 +1,141 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// joinLogger signals on joined when a download waits for a download in progress.
+type joinLogger struct {
+	stdLogger
+	joined chan struct{}
+}
+
+func (l *joinLogger) Logf(f string, v ...interface{}) {
+	l.stdLogger.Logf(f, v...)
+
+	if strings.HasPrefix(f, "Waiting for download") {
+		l.joined <- struct{}{}
+	}
+}
+
+// writerAtOnly hides all methods of w other than WriteAt.
+type writerAtOnly struct {
+	w *inMemoryBuffer
+}
+
+func (w writerAtOnly) WriteAt(p []byte, off int64) (int, error) { return w.w.WriteAt(p, off) }
+
+func Test_sharedDownloads(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 1024))
+	size := int64(len(content))
+	d := digest.FromBytes(content)
+
+	errDownload := errors.New("download error")
+
+	tests := []struct {
+		name            string
+		leaderErr       error
+		notSeekable     bool
+		cancelFollower  bool
+		wantFollowerErr error
+		wantDownloads   int
+	}{
+		{name: "Shared", wantDownloads: 1},
+		{name: "LeaderError", leaderErr: errDownload, wantDownloads: 2},
+		{name: "NotSeekable", notSeekable: true, wantDownloads: 2},
+		{name: "FollowerCancelled", cancelFollower: true, wantFollowerErr: context.Canceled, wantDownloads: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &joinLogger{joined: make(chan struct{}, 1)}
+			s := newSharedDownloads(l)
+
+			var downloads atomic.Int32
+			started := make(chan struct{}, 2)
+			download := func(w *inMemoryBuffer, release <-chan struct{}, err error) func() error {
+				return func() error {
+					downloads.Add(1)
+					started <- struct{}{}
+					<-release
+					if err != nil {
+						return err
+					}
+					_, err := w.WriteAt(content, 0)
+					return err
+				}
+			}
+
+			leaderBuf := &inMemoryBuffer{buf: make([]byte, size)}
+			leaderRelease := make(chan struct{})
+
+			var leaderDst io.WriterAt = leaderBuf
+			if tt.notSeekable {
+				leaderDst = writerAtOnly{leaderBuf}
+			}
+
+			leaderErr := make(chan error, 1)
+			go func() {
+				leaderErr <- s.do(context.Background(), d, leaderDst, size, &NoopProgressBar{}, download(leaderBuf, leaderRelease, tt.leaderErr))
+			}()
+			<-started
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			followerBuf := &inMemoryBuffer{buf: make([]byte, size)}
+			followerRelease := make(chan struct{})
+			close(followerRelease)
+
+			followerErr := make(chan error, 1)
+			go func() {
+				followerErr <- s.do(ctx, d, followerBuf, size, &NoopProgressBar{}, download(followerBuf, followerRelease, nil))
+			}()
+
+			if tt.notSeekable {
+				// The follower downloads independently, without waiting for the leader.
+				<-started
+			} else {
+				<-l.joined
+			}
+
+			if tt.cancelFollower {
+				cancel()
+				if err := <-followerErr; !errors.Is(err, tt.wantFollowerErr) {
+					t.Errorf("got follower error %v, want %v", err, tt.wantFollowerErr)
+				}
+			}
+
+			close(leaderRelease)
+
+			if err := <-leaderErr; !errors.Is(err, tt.leaderErr) {
+				t.Errorf("got leader error %v, want %v", err, tt.leaderErr)
+			}
+
+			if !tt.cancelFollower {
+				if err := <-followerErr; err != nil {
+					t.Fatalf("got follower error %v", err)
+				}
+				if got := string(followerBuf.Bytes()); got != string(content) {
+					t.Error("follower content mismatch")
+				}
+			}
+
+			if got := int(downloads.Load()); got != tt.wantDownloads {
+				t.Errorf("got %v downloads, want %v", got, tt.wantDownloads)
+			}
+		})
+	}
+}
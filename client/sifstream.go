@@ -0,0 +1,123 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// maxStreamedSIFHeaderSize bounds the content buffered to parse the global header and descriptors
+// of a streamed SIF image.
+const maxStreamedSIFHeaderSize = 64 * 1024 * 1024
+
+// SIFObjectHandler is called for each data object of a SIF image, in the order in which the
+// objects are stored in the image. r reads the content of the object described by d, and is only
+// valid for the duration of the call. Content not read by the handler is discarded.
+type SIFObjectHandler func(d sif.Descriptor, r io.Reader) error
+
+// DownloadImageObjects downloads the SIF image with the specified arch, path and tag, calling fn
+// for each data object in the image as its content is received. This allows tools that unpack
+// an image (ie. extract the root file system) to do so without staging the image on disk. Parts
+// are downloaded concurrently according to spec, as described by DownloadImageStream.
+//
+// Data objects that overlap (such that an object begins before the end of the preceding object)
+// cannot be streamed, and result in an error.
+func (c *Client) DownloadImageObjects(ctx context.Context, arch, path, tag string, spec *Downloader, pb ProgressBar, fn SIFObjectHandler) error {
+	pr, pw := io.Pipe()
+
+	downloadErr := make(chan error, 1)
+	go func() {
+		err := c.DownloadImageStream(ctx, pw, arch, path, tag, spec, pb)
+		pw.CloseWithError(err)
+		downloadErr <- err
+	}()
+
+	err := readSIFObjects(pr, fn)
+	if err == nil {
+		// Consume content following the final object, so that the download completes.
+		_, err = io.Copy(io.Discard, pr)
+	}
+
+	// Abort the download if the image was not consumed in its entirety.
+	pr.CloseWithError(err)
+
+	if derr := <-downloadErr; err == nil {
+		err = derr
+	}
+	return err
+}
+
+// readSIFObjects reads a SIF image from r, calling fn for each data object in the order in which
+// the objects are stored.
+func readSIFObjects(r io.Reader, fn SIFObjectHandler) error {
+	var header bytes.Buffer
+
+	f, err := readSIFHeader(r, &header)
+	if err != nil {
+		return err
+	}
+
+	ds, err := f.GetDescriptors()
+	if err != nil {
+		return err
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i].Offset() < ds[j].Offset() })
+
+	// Objects are read from the buffered content, followed by the remainder of the stream.
+	src := io.MultiReader(&header, r)
+
+	var pos int64
+	for _, d := range ds {
+		if d.Offset() < pos {
+			return fmt.Errorf("SIF object %v overlaps preceding object", d.ID())
+		}
+
+		if _, err := io.CopyN(io.Discard, src, d.Offset()-pos); err != nil {
+			return fmt.Errorf("error reading SIF image: %w", err)
+		}
+
+		lr := &io.LimitedReader{R: src, N: d.Size()}
+		if err := fn(d, lr); err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(io.Discard, lr); err != nil {
+			return fmt.Errorf("error reading SIF image: %w", err)
+		}
+		if lr.N > 0 {
+			return fmt.Errorf("error reading SIF image: %w", io.ErrUnexpectedEOF)
+		}
+		pos = d.Offset() + d.Size()
+	}
+
+	return nil
+}
+
+// readSIFHeader reads the global header and descriptors of a SIF image from r. The content read
+// from r, which may extend beyond the descriptors, is written to header. The returned image
+// contains no data objects, so the content of descriptors must not be read from it.
+func readSIFHeader(r io.Reader, header *bytes.Buffer) (*sif.FileImage, error) {
+	for n := int64(sifHeaderSize); ; n *= 2 {
+		if _, err := io.CopyN(header, r, n-int64(header.Len())); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+
+		f, err := sif.LoadContainer(sif.NewBuffer(header.Bytes()))
+		if err == nil {
+			return f, nil
+		}
+		if int64(header.Len()) < n || n >= maxStreamedSIFHeaderSize {
+			return nil, fmt.Errorf("error reading SIF header: %w", err)
+		}
+	}
+}
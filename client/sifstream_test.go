@@ -0,0 +1,180 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// testSIF returns a SIF image containing a generic data object for each of objects.
+func testSIF(t *testing.T, objects ...string) []byte {
+	t.Helper()
+
+	var dis []sif.DescriptorInput
+	for _, o := range objects {
+		di, err := sif.NewDescriptorInput(sif.DataGeneric, strings.NewReader(o))
+		if err != nil {
+			t.Fatalf("error creating descriptor input: %v", err)
+		}
+		dis = append(dis, di)
+	}
+
+	var b sif.Buffer
+	f, err := sif.CreateContainer(&b, sif.OptCreateDeterministic(), sif.OptCreateWithDescriptors(dis...))
+	if err != nil {
+		t.Fatalf("error creating SIF: %v", err)
+	}
+	if err := f.UnloadContainer(); err != nil {
+		t.Fatalf("error unloading SIF: %v", err)
+	}
+	return b.Bytes()
+}
+
+func Test_readSIFObjects(t *testing.T) {
+	image := testSIF(t, "object one", "object two", strings.Repeat("3", 2*sifHeaderSize))
+
+	errHandler := errors.New("handler error")
+
+	tests := []struct {
+		name    string
+		image   []byte
+		readN   int // bytes read by handler; -1 reads all
+		failOn  int // object (1-based) for which the handler fails
+		want    []string
+		wantErr bool
+		errIs   error
+	}{
+		{
+			name:  "All",
+			image: image,
+			readN: -1,
+			want:  []string{"object one", "object two", strings.Repeat("3", 2*sifHeaderSize)},
+		},
+		{
+			name:  "Partial",
+			image: image,
+			readN: 3,
+			want:  []string{"obj", "obj", "333"},
+		},
+		{
+			name:    "HandlerError",
+			image:   image,
+			readN:   -1,
+			failOn:  2,
+			want:    []string{"object one"},
+			wantErr: true,
+			errIs:   errHandler,
+		},
+		{
+			name:    "Truncated",
+			image:   image[:len(image)-1],
+			readN:   3,
+			want:    []string{"obj", "obj", "333"},
+			wantErr: true,
+			errIs:   io.ErrUnexpectedEOF,
+		},
+		{
+			name:    "NotSIF",
+			image:   []byte("not a SIF"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+
+			err := readSIFObjects(bytes.NewReader(tt.image), func(d sif.Descriptor, r io.Reader) error {
+				if len(got)+1 == tt.failOn {
+					return errHandler
+				}
+
+				if tt.readN >= 0 {
+					r = io.LimitReader(r, int64(tt.readN))
+				}
+
+				b, err := io.ReadAll(r)
+				if err != nil {
+					return err
+				}
+				got = append(got, string(b))
+				return nil
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if tt.errIs != nil && !errors.Is(err, tt.errIs) {
+				t.Fatalf("got error %v, want %v", err, tt.errIs)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got objects %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownloadImageObjects(t *testing.T) {
+	image := testSIF(t, "object one", "object two")
+
+	lib := mockLibraryServer(t, image, true)
+	defer lib.Close()
+
+	// Direct OCI registry access is not supported by the mock library server.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/oci-redirect" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		lib.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	var got []string
+
+	err = c.DownloadImageObjects(context.Background(), "amd64", "entity/collection/container", "tag",
+		&Downloader{Concurrency: 4, PartSize: 1024}, nil,
+		func(_ sif.Descriptor, r io.Reader) error {
+			b, err := io.ReadAll(r)
+			if err != nil {
+				return err
+			}
+			got = append(got, string(b))
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{"object one", "object two"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got objects %q, want %q", got, want)
+	}
+
+	errHandler := errors.New("handler error")
+
+	err = c.DownloadImageObjects(context.Background(), "amd64", "entity/collection/container", "tag",
+		&Downloader{Concurrency: 4, PartSize: 1024}, nil,
+		func(sif.Descriptor, io.Reader) error { return errHandler })
+	if !errors.Is(err, errHandler) {
+		t.Errorf("got error %v, want %v", err, errHandler)
+	}
+}
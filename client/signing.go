@@ -0,0 +1,129 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// artifactTypeSIFSignature is the artifact type of a detached SIF image signature.
+const artifactTypeSIFSignature = "application/vnd.sylabs.sif.signature.v1"
+
+var (
+	// ErrSigningFailed is returned when an image could not be signed prior to upload.
+	ErrSigningFailed = errors.New("image signing failed")
+
+	// ErrDetachedSignatureNotSupported is returned when a detached signature is requested, and
+	// the image cannot be uploaded to an OCI registry.
+	ErrDetachedSignatureNotSupported = errors.New("detached signatures require an OCI registry")
+)
+
+// ImageSigner signs SIF images in place prior to upload.
+type ImageSigner interface {
+	// Sign adds one or more signatures to f, such as by using integrity.NewSigner from the
+	// github.com/sylabs/sif/v2/pkg/integrity package with the supplied key material.
+	Sign(ctx context.Context, f *sif.FileImage) error
+}
+
+// DetachedSigner produces signatures that are uploaded alongside an image.
+type DetachedSigner interface {
+	// SignDetached returns a signature of the SIF image with digest d, and the media type of the
+	// signature.
+	SignDetached(ctx context.Context, d digest.Digest) (sig []byte, mediaType string, err error)
+}
+
+// UploadSigning specifies the signing of an image as part of its upload.
+type UploadSigning struct {
+	// Signer, if set, adds signatures to the image prior to upload. The image is modified in
+	// place.
+	Signer ImageSigner
+	// DetachedSigner, if set, produces a signature that is uploaded to the OCI registry as an
+	// artifact referring to the image manifest. If the image cannot be uploaded to an OCI
+	// registry, ErrDetachedSignatureNotSupported is returned.
+	DetachedSigner DetachedSigner
+}
+
+// SignableImage is an image that may be signed in place and uploaded, such as an *os.File opened
+// for reading and writing.
+type SignableImage interface {
+	io.Reader
+	sif.ReadWriter
+}
+
+// UploadSignedImage behaves as UploadImage, signing the image read from rw according to s prior
+// to upload.
+func (c *Client) UploadSignedImage(ctx context.Context, rw SignableImage, path, arch string, tags []string, description string, s *UploadSigning, callback UploadCallback, opts ...UploadOption) (*UploadImageComplete, error) {
+	if s.Signer != nil {
+		if err := signImage(ctx, rw, s.Signer); err != nil {
+			return nil, err
+		}
+
+		if _, err := rw.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("error seeking to start stream: %v", err)
+		}
+	}
+
+	if s.DetachedSigner != nil {
+		ctx = withDetachedSigner(ctx, s.DetachedSigner)
+	}
+
+	return c.UploadImage(ctx, rw, path, arch, tags, description, callback, opts...)
+}
+
+// signImage adds signatures to the SIF image in rw using signer.
+func signImage(ctx context.Context, rw sif.ReadWriter, signer ImageSigner) error {
+	f, err := sif.LoadContainer(rw, sif.OptLoadWithCloseOnUnload(false))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSigningFailed, err)
+	}
+
+	if err := signer.Sign(ctx, f); err != nil {
+		_ = f.UnloadContainer()
+		return fmt.Errorf("%w: %w", ErrSigningFailed, err)
+	}
+
+	if err := f.UnloadContainer(); err != nil {
+		return fmt.Errorf("%w: %w", ErrSigningFailed, err)
+	}
+	return nil
+}
+
+type detachedSignerKey struct{}
+
+// withDetachedSigner returns a context carrying s.
+func withDetachedSigner(ctx context.Context, s DetachedSigner) context.Context {
+	return context.WithValue(ctx, detachedSignerKey{}, s)
+}
+
+// detachedSignerFromContext returns the DetachedSigner carried by ctx, or nil if not present.
+func detachedSignerFromContext(ctx context.Context) DetachedSigner {
+	s, _ := ctx.Value(detachedSignerKey{}).(DetachedSigner)
+	return s
+}
+
+// uploadSignature uploads a detached signature of the SIF image with digest imageDigest, produced
+// by s, to the registry. The signature manifest refers to the image manifest described by subject.
+func (r *ociRegistry) uploadSignature(ctx context.Context, creds credentials, name string, subject v1.Descriptor, imageDigest digest.Digest, s DetachedSigner) (d digest.Digest, err error) {
+	r.logger.Logf("Starting signature upload: name=[%v], subject=[%v]", name, subject.Digest)
+	defer func(t time.Time) {
+		r.logger.Logf("Finished signature upload: took=[%v] digest=[%v], err=[%v]", time.Since(t), d.String(), err)
+	}(time.Now())
+
+	sig, mediaType, err := s.SignDetached(ctx, imageDigest)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrSigningFailed, err)
+	}
+
+	return r.uploadArtifact(ctx, creds, name, artifactTypeSIFSignature, subject, mediaType, sig, nil)
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// testSigner adds a signature object to an image, or returns err if set.
+type testSigner struct {
+	err error
+}
+
+func (s testSigner) Sign(_ context.Context, f *sif.FileImage) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	di, err := sif.NewDescriptorInput(sif.DataSignature, strings.NewReader("signature"),
+		sif.OptSignatureMetadata(crypto.SHA256, []byte("fingerprint")),
+	)
+	if err != nil {
+		return err
+	}
+	return f.AddObject(di)
+}
+
+// testDetachedSigner returns a signature of the supplied digest, or err if set.
+type testDetachedSigner struct {
+	err error
+}
+
+func (s testDetachedSigner) SignDetached(_ context.Context, d digest.Digest) ([]byte, string, error) {
+	if s.err != nil {
+		return nil, "", s.err
+	}
+	return []byte("signature of " + d.String()), "application/vnd.test.signature", nil
+}
+
+func Test_signImage(t *testing.T) {
+	errSign := errors.New("sign error")
+
+	tests := []struct {
+		name       string
+		image      []byte
+		signer     ImageSigner
+		wantErr    error
+		wantSigned bool
+	}{
+		{"Signed", testSIF(t, "object"), testSigner{}, nil, true},
+		{"SignerError", testSIF(t, "object"), testSigner{err: errSign}, errSign, false},
+		{"NotSIF", []byte("not a SIF image"), testSigner{}, ErrSigningFailed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "image.sif")
+			if err := os.WriteFile(path, tt.image, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := os.OpenFile(path, os.O_RDWR, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			err = signImage(context.Background(), f, tt.signer)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrSigningFailed) {
+					t.Errorf("got error %v, want %v", err, ErrSigningFailed)
+				}
+				return
+			}
+
+			fi, err := sif.LoadContainerFromPath(path, sif.OptLoadWithFlag(os.O_RDONLY))
+			if err != nil {
+				t.Fatalf("error loading SIF: %v", err)
+			}
+			defer fi.UnloadContainer() //nolint:errcheck
+
+			if signed, _ := getSigned(fi); signed != tt.wantSigned {
+				t.Errorf("got signed %v, want %v", signed, tt.wantSigned)
+			}
+		})
+	}
+}
+
+func Test_uploadSignature(t *testing.T) {
+	const sessionPath = "/v2/name/blobs/uploads/session"
+
+	blobs := make(map[digest.Digest]bool)
+	var m v1.Manifest
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/name/blobs/uploads/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Location", sessionPath)
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc(sessionPath, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPatch:
+			if _, err := io.Copy(io.Discard, r.Body); err != nil {
+				t.Errorf("error reading request body: %v", err)
+			}
+			w.Header().Set("Location", sessionPath)
+			w.WriteHeader(http.StatusAccepted)
+		case http.MethodPut:
+			blobs[digest.Digest(r.URL.Query().Get("digest"))] = true
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("unexpected method %v", r.Method)
+		}
+	})
+	mux.HandleFunc("/v2/name/manifests/", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+			t.Errorf("error decoding manifest: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &ociRegistry{baseURL: u, httpClient: http.DefaultClient, logger: testLogger}
+
+	creds := &bearerTokenCredentials{authToken: "token"}
+	subject := v1.Descriptor{MediaType: v1.MediaTypeImageManifest, Digest: digest.FromString("manifest"), Size: 8}
+	imageDigest := digest.FromString("image")
+
+	if _, err := r.uploadSignature(context.Background(), creds, "name", subject, imageDigest, testDetachedSigner{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := m.ArtifactType, artifactTypeSIFSignature; got != want {
+		t.Errorf("got artifact type %v, want %v", got, want)
+	}
+	if m.Subject == nil || m.Subject.Digest != subject.Digest || m.Subject.Size != subject.Size {
+		t.Errorf("got subject %v, want %v", m.Subject, subject)
+	}
+	if got, want := m.Config.Digest, v1.DescriptorEmptyJSON.Digest; got != want {
+		t.Errorf("got config digest %v, want %v", got, want)
+	}
+	if len(m.Layers) != 1 {
+		t.Fatalf("got %v layers, want 1", len(m.Layers))
+	}
+	if got, want := m.Layers[0].Digest, digest.FromString("signature of "+imageDigest.String()); got != want {
+		t.Errorf("got signature digest %v, want %v", got, want)
+	}
+	for _, d := range []digest.Digest{m.Config.Digest, m.Layers[0].Digest} {
+		if !blobs[d] {
+			t.Errorf("blob %v not uploaded", d)
+		}
+	}
+
+	// A signer error is reported consistently.
+	errSign := errors.New("sign error")
+	_, err = r.uploadSignature(context.Background(), creds, "name", subject, imageDigest, testDetachedSigner{err: errSign})
+	if !errors.Is(err, ErrSigningFailed) || !errors.Is(err, errSign) {
+		t.Errorf("got error %v, want %v", err, ErrSigningFailed)
+	}
+}
+
+func TestUploadSignedImageDetachedLegacy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/oci-redirect" {
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "image.sif")
+	if err := os.WriteFile(path, testPartitionSIF(t, "amd64"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	s := &UploadSigning{Signer: testSigner{}, DetachedSigner: testDetachedSigner{}}
+
+	_, err = c.UploadSignedImage(context.Background(), f, "library://entity/collection/container", "amd64", []string{"latest"}, "", s, nil)
+	if !errors.Is(err, ErrDetachedSignatureNotSupported) {
+		t.Errorf("got error %v, want %v", err, ErrDetachedSignatureNotSupported)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// sparseBlockSize is the size of the blocks that are examined for zeros when writing sparsely. It
+// matches the block size of common file systems.
+const sparseBlockSize = 4096
+
+// sparseDestination is a destination to which content may be written sparsely, such as an
+// *os.File.
+type sparseDestination interface {
+	io.WriterAt
+	io.ReaderAt
+	Truncate(size int64) error
+}
+
+// sparseWriter writes to a destination that initially contains only zeros, skipping blocks that
+// consist entirely of zeros so that the destination is left sparse. Methods may be called
+// concurrently.
+type sparseWriter struct {
+	f sparseDestination
+
+	mu  sync.Mutex
+	end int64 // offset following the furthest byte written or skipped
+}
+
+// newSparseWriter truncates f, and returns a sparseWriter that writes to it.
+func newSparseWriter(f sparseDestination) (*sparseWriter, error) {
+	if err := f.Truncate(0); err != nil {
+		return nil, fmt.Errorf("error truncating destination: %w", err)
+	}
+	return &sparseWriter{f: f}, nil
+}
+
+// sparseWriterFor returns a sparseWriter that writes to dst, if dst supports sparse writing.
+func sparseWriterFor(dst io.WriterAt) (*sparseWriter, error) {
+	f, ok := dst.(sparseDestination)
+	if !ok {
+		return nil, errors.New("sparse writing requires a file destination")
+	}
+	return newSparseWriter(f)
+}
+
+// isZero reports whether b consists entirely of zeros.
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteAt writes p to the destination at offset off. Blocks of p that consist entirely of zeros
+// are not written. Blocks are aligned to offsets in the destination, so that partial blocks at
+// either end of p may also be skipped.
+func (w *sparseWriter) WriteAt(p []byte, off int64) (int, error) {
+	// start of a run of non-zero blocks that is pending a write, or -1 if none.
+	pending := -1
+
+	flush := func(i int) error {
+		if pending < 0 {
+			return nil
+		}
+		_, err := w.f.WriteAt(p[pending:i], off+int64(pending))
+		pending = -1
+		return err
+	}
+
+	for i := 0; i < len(p); {
+		n := sparseBlockSize - int((off+int64(i))%sparseBlockSize)
+		if n > len(p)-i {
+			n = len(p) - i
+		}
+
+		if isZero(p[i : i+n]) {
+			if err := flush(i); err != nil {
+				return i, err
+			}
+		} else if pending < 0 {
+			pending = i
+		}
+		i += n
+	}
+
+	if err := flush(len(p)); err != nil {
+		return 0, err
+	}
+
+	w.mu.Lock()
+	if e := off + int64(len(p)); e > w.end {
+		w.end = e
+	}
+	w.mu.Unlock()
+
+	return len(p), nil
+}
+
+// ReadAt reads from the destination at offset off. Skipped blocks beyond the end of the
+// destination read as zeros.
+func (w *sparseWriter) ReadAt(p []byte, off int64) (int, error) {
+	n, err := w.f.ReadAt(p, off)
+	if !errors.Is(err, io.EOF) {
+		return n, err
+	}
+
+	w.mu.Lock()
+	end := w.end
+	w.mu.Unlock()
+
+	if off+int64(len(p)) > end {
+		return n, err
+	}
+
+	clear(p[n:])
+	return len(p), nil
+}
+
+// finish extends the destination to include skipped blocks beyond its end, so that it contains
+// all content written.
+func (w *sparseWriter) finish() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.end == 0 {
+		return nil
+	}
+
+	if err := w.f.Truncate(w.end); err != nil {
+		return fmt.Errorf("error extending destination: %w", err)
+	}
+	return nil
+}
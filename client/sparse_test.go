@@ -0,0 +1,163 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeRecordingFile is a sparseDestination that records the ranges written to it.
+type writeRecordingFile struct {
+	inMemoryBuffer
+	writes [][2]int64 // offset, length
+}
+
+func (f *writeRecordingFile) WriteAt(p []byte, off int64) (int, error) {
+	f.writes = append(f.writes, [2]int64{off, int64(len(p))})
+
+	if e := off + int64(len(p)); e > int64(len(f.buf)) {
+		f.buf = append(f.buf, make([]byte, e-int64(len(f.buf)))...)
+	}
+	return f.inMemoryBuffer.WriteAt(p, off)
+}
+
+func (f *writeRecordingFile) Truncate(size int64) error {
+	if size <= int64(len(f.buf)) {
+		f.buf = f.buf[:size]
+	} else {
+		f.buf = append(f.buf, make([]byte, size-int64(len(f.buf)))...)
+	}
+	return nil
+}
+
+func Test_sparseWriter(t *testing.T) {
+	block := func(c byte) []byte { return bytes.Repeat([]byte{c}, sparseBlockSize) }
+	zero := block(0)
+
+	tests := []struct {
+		name       string
+		off        int64
+		p          []byte
+		wantWrites [][2]int64
+	}{
+		{"Zero", 0, zero, nil},
+		{"NonZero", 0, block('a'), [][2]int64{{0, sparseBlockSize}}},
+		{"Mixed", 0, bytes.Join([][]byte{block('a'), zero, block('b'), block('c')}, nil), [][2]int64{{0, sparseBlockSize}, {2 * sparseBlockSize, 2 * sparseBlockSize}}},
+		{"Unaligned", sparseBlockSize - 2, append([]byte{'a', 'a'}, zero...), [][2]int64{{sparseBlockSize - 2, 2}}},
+		{"PartialZero", 2, append(make([]byte, sparseBlockSize-2), 'a'), [][2]int64{{sparseBlockSize, 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &writeRecordingFile{}
+
+			w, err := newSparseWriter(f)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			n, err := w.WriteAt(tt.p, tt.off)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got, want := n, len(tt.p); got != want {
+				t.Errorf("got %v byte(s) written, want %v", got, want)
+			}
+
+			if got, want := f.writes, tt.wantWrites; !reflect.DeepEqual(got, want) {
+				t.Errorf("got writes %v, want %v", got, want)
+			}
+
+			// Skipped blocks beyond the end of the destination read as zeros.
+			b := make([]byte, len(tt.p))
+			if _, err := w.ReadAt(b, tt.off); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(b, tt.p) {
+				t.Error("read content does not match")
+			}
+
+			if err := w.finish(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got, want := int64(len(f.buf)), tt.off+int64(len(tt.p)); got != want {
+				t.Errorf("got size %v, want %v", got, want)
+			}
+			if !bytes.Equal(f.buf[tt.off:], tt.p) {
+				t.Error("content does not match")
+			}
+		})
+	}
+}
+
+func TestPullImageSparse(t *testing.T) {
+	// Mostly zero content, with zero blocks at the end of the image.
+	sampleBytes := make([]byte, 64*sparseBlockSize)
+	copy(sampleBytes[3*sparseBlockSize:], "superblock")
+	copy(sampleBytes[40*sparseBlockSize+7:], "inode")
+
+	lib := mockLibraryServer(t, sampleBytes, true)
+	defer lib.Close()
+
+	// Direct OCI registry access is not supported by the mock library server
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/oci-redirect" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		lib.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "image.sif")
+
+	// Stale content in the destination must not survive the download.
+	if err := os.WriteFile(path, bytes.Repeat([]byte{0xff}, len(sampleBytes)+100), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	opts := &DownloadOptions{Downloader: &Downloader{Concurrency: 4, PartSize: 16 * sparseBlockSize, Sparse: true}}
+
+	if _, err := c.PullImage(context.Background(), f, "entity/collection/container", opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, sampleBytes) {
+		t.Errorf("got %v byte(s), content does not match", len(b))
+	}
+
+	// Sparse writing requires a file destination.
+	dst := &inMemoryBuffer{buf: make([]byte, len(sampleBytes))}
+	if _, err := c.PullImage(context.Background(), dst, "entity/collection/container", opts); err == nil {
+		t.Error("unexpected success")
+	}
+
+	if err := c.DownloadImageStream(context.Background(), &bytes.Buffer{}, "", "entity/collection/container", "", opts.Downloader, nil); err == nil {
+		t.Error("unexpected success")
+	}
+}
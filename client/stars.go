@@ -0,0 +1,92 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// starPath returns the API path used to star the container identified by ref.
+func starPath(ref string) string {
+	return "v1/containers/" + strings.TrimPrefix(ref, "/") + "/_star"
+}
+
+// StarContainer stars the container identified by ref (ie. "entity/collection/container") on
+// behalf of the authenticated user. Starring a container that is already starred has no effect.
+func (c *Client) StarContainer(ctx context.Context, ref string) error {
+	if _, err := c.apiUpdate(ctx, starPath(ref), struct{}{}); err != nil {
+		return fmt.Errorf("error starring container: %w", err)
+	}
+	return nil
+}
+
+// UnstarContainer removes the star of the authenticated user from the container identified by ref
+// (ie. "entity/collection/container").
+func (c *Client) UnstarContainer(ctx context.Context, ref string) error {
+	if _, err := c.doDeleteRequest(ctx, starPath(ref)); err != nil {
+		return fmt.Errorf("error unstarring container: %w", err)
+	}
+	return nil
+}
+
+// ListStarredContainers returns the containers starred by the authenticated user.
+func (c *Client) ListStarredContainers(ctx context.Context) ([]Container, error) {
+	return c.listContainers(ctx, "v1/starred")
+}
+
+// ContainerRanking specifies the order in which ranked containers are listed.
+type ContainerRanking string
+
+const (
+	// RankByDownloads lists the most downloaded containers first.
+	RankByDownloads ContainerRanking = "downloads"
+	// RankByUpdated lists the most recently updated containers first.
+	RankByUpdated ContainerRanking = "updated"
+	// RankByStars lists the most starred containers first.
+	RankByStars ContainerRanking = "stars"
+)
+
+// ListRankedContainers returns up to limit public containers ranked as specified by by, such as
+// the most downloaded or recently updated containers listed by the library home page. If limit is
+// zero, the server default applies.
+func (c *Client) ListRankedContainers(ctx context.Context, by ContainerRanking, limit int) ([]Container, error) {
+	switch by {
+	case RankByDownloads, RankByUpdated, RankByStars:
+	default:
+		return nil, fmt.Errorf("invalid ranking %q", by)
+	}
+
+	if limit < 0 {
+		return nil, errors.New("limit must not be negative")
+	}
+
+	v := url.Values{}
+	v.Set("by", string(by))
+	if limit > 0 {
+		v.Set("limit", strconv.Itoa(limit))
+	}
+
+	return c.listContainers(ctx, "v1/containers/_ranked?"+v.Encode())
+}
+
+// listContainers returns the containers listed by path.
+func (c *Client) listContainers(ctx context.Context, path string) ([]Container, error) {
+	b, err := c.apiGet(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var res ContainersResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("error decoding containers: %v", err)
+	}
+	return res.Data, nil
+}
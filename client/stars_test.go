@@ -0,0 +1,133 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func TestStarContainer(t *testing.T) {
+	starred := make(map[string]bool)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/containers/entity/collection/container/_star":
+			switch r.Method {
+			case http.MethodPut:
+				starred["container"] = true
+			case http.MethodDelete:
+				delete(starred, "container")
+			default:
+				t.Errorf("unexpected method %v", r.Method)
+			}
+			if err := jsonresp.WriteResponse(w, nil, http.StatusOK); err != nil {
+				t.Errorf("error writing JSON response: %v", err)
+			}
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/starred":
+			var cs []Container
+			for name := range starred {
+				cs = append(cs, Container{Name: name})
+			}
+			if err := jsonresp.WriteResponse(w, cs, http.StatusOK); err != nil {
+				t.Errorf("error writing JSON response: %v", err)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	if err := c.StarContainer(ctx, "entity/collection/container"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cs, err := c.ListStarredContainers(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := cs, []Container{{Name: "container"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got containers %+v, want %+v", got, want)
+	}
+
+	if err := c.UnstarContainer(ctx, "entity/collection/container"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if starred["container"] {
+		t.Error("container still starred")
+	}
+
+	if err := c.StarContainer(ctx, "entity/collection/missing"); err == nil {
+		t.Error("unexpected success")
+	}
+}
+
+func TestListRankedContainers(t *testing.T) {
+	tests := []struct {
+		name      string
+		by        ContainerRanking
+		limit     int
+		wantQuery string
+		expectErr bool
+	}{
+		{"Downloads", RankByDownloads, 10, "by=downloads&limit=10", false},
+		{"Updated", RankByUpdated, 0, "by=updated", false},
+		{"Stars", RankByStars, 5, "by=stars&limit=5", false},
+		{"BadRanking", "size", 0, "", true},
+		{"BadLimit", RankByDownloads, -1, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got, want := r.URL.Path, "/v1/containers/_ranked"; got != want {
+					t.Errorf("got path %v, want %v", got, want)
+				}
+				if got, want := r.URL.RawQuery, tt.wantQuery; got != want {
+					t.Errorf("got query %v, want %v", got, want)
+				}
+
+				cs := []Container{{Name: "a", DownloadCount: 2}, {Name: "b", DownloadCount: 1}}
+				if err := jsonresp.WriteResponse(w, cs, http.StatusOK); err != nil {
+					t.Errorf("error writing JSON response: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			cs, err := c.ListRankedContainers(context.Background(), tt.by, tt.limit)
+			if tt.expectErr && err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+
+			if got, want := len(cs), 2; got != want {
+				t.Errorf("got %v containers, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// TransferBackendOCI indicates a transfer made directly to/from an OCI registry.
+	TransferBackendOCI = "oci"
+	// TransferBackendLibrary indicates a transfer made using the (legacy) library API.
+	TransferBackendLibrary = "library"
+)
+
+// TransferSummary describes a completed image transfer, allowing callers to log and alert on
+// degraded transfer performance.
+type TransferSummary struct {
+	// Backend used for the transfer (ie. TransferBackendOCI or TransferBackendLibrary).
+	Backend string
+	// Bytes of image data transferred.
+	Bytes int64
+	// NetworkBytes is the number of bytes of image data transferred over the network. It differs
+	// from Bytes when image content is transferred compressed (see
+	// Config.CompressedImageDownloads), or when content is copied from a shared download (see
+	// Config.ShareDownloads) rather than transferred over the network.
+	NetworkBytes int64
+	// Parts transferred. Single stream transfers consist of one part.
+	Parts int
+	// Retries of parts, including those caused by expired presigned URLs.
+	Retries int
+	// SingleStreamFallback is true if a concurrent download reverted to a single stream, because
+	// the server did not honour range requests.
+	SingleStreamFallback bool
+	// Elapsed time of the operation.
+	Elapsed time.Duration
+	// Verification describes the verification of downloaded content, if requested using
+	// Downloader.Verifier.
+	Verification *VerificationResult
+	// Tags applied to an uploaded image, when the image is uploaded using the library API.
+	Tags []TagChange
+	// Cache describes how long the resolution of a downloaded image may be reused, as indicated
+	// by the server resolving the image. It is nil for uploads, or if the image was not resolved
+	// (ie. when resuming a download).
+	Cache *CacheHints
+	// OCI describes the artifacts published by an upload to an OCI registry. It is nil for
+	// downloads, or uploads made using the library API.
+	OCI *OCIPushResult
+}
+
+// Throughput returns the average throughput of the transfer, in bytes per second.
+func (s *TransferSummary) Throughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Bytes) / s.Elapsed.Seconds()
+}
+
+// NetworkThroughput returns the average network throughput of the transfer, in bytes per second.
+func (s *TransferSummary) NetworkThroughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.NetworkBytes) / s.Elapsed.Seconds()
+}
+
+// transferStats accumulates statistics for a transfer. Methods may be called concurrently, and
+// are no-ops on a nil *transferStats.
+type transferStats struct {
+	mu       sync.Mutex
+	start    time.Time
+	backend  string
+	bytes    int64
+	network  int64
+	parts    int
+	retries  int
+	fallback bool
+	verified *VerificationResult
+	tags     []TagChange
+	cache    *CacheHints
+	oci      *OCIPushResult
+}
+
+type transferStatsKey struct{}
+
+// withTransferStats returns a context carrying a new *transferStats, which is also returned.
+func withTransferStats(ctx context.Context) (context.Context, *transferStats) {
+	s := &transferStats{start: time.Now()}
+	return context.WithValue(ctx, transferStatsKey{}, s), s
+}
+
+// transferStatsFromContext returns the *transferStats carried by ctx, or nil if not present.
+func transferStatsFromContext(ctx context.Context) *transferStats {
+	s, _ := ctx.Value(transferStatsKey{}).(*transferStats)
+	return s
+}
+
+// setBackend records the backend used for the transfer.
+func (s *transferStats) setBackend(backend string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.backend = backend
+}
+
+// addPart records a part of n bytes that has been transferred over the network.
+func (s *transferStats) addPart(n int64) {
+	s.addPartNetwork(n, n)
+}
+
+// addPartNetwork records a part of n bytes that has been transferred, of which network bytes were
+// transferred over the network.
+func (s *transferStats) addPartNetwork(n, network int64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.parts++
+	s.bytes += n
+	s.network += network
+}
+
+// addRetry records a part retry.
+func (s *transferStats) addRetry() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.retries++
+}
+
+// setSingleStreamFallback records that a concurrent download reverted to a single stream.
+func (s *transferStats) setSingleStreamFallback() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fallback = true
+}
+
+// setVerification records the result of content verification.
+func (s *transferStats) setVerification(res *VerificationResult) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.verified = res
+}
+
+// setTags records the tags applied to an uploaded image.
+func (s *transferStats) setTags(tags []TagChange) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tags = tags
+}
+
+// setCacheHints records the cache hints of the image being downloaded. Only the first hints are
+// recorded, since they describe the response resolving the image; subsequent responses (ie.
+// manifests fetched by digest) are not relevant.
+func (s *transferStats) setCacheHints(h *CacheHints) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cache == nil {
+		s.cache = h
+	}
+}
+
+// setOCIPush records the artifacts published by an upload to an OCI registry.
+func (s *transferStats) setOCIPush(res *OCIPushResult) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.oci = res
+}
+
+// summary returns a summary of the transfer statistics.
+func (s *transferStats) summary() *TransferSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return &TransferSummary{
+		Backend:              s.backend,
+		Bytes:                s.bytes,
+		NetworkBytes:         s.network,
+		Parts:                s.parts,
+		Retries:              s.retries,
+		SingleStreamFallback: s.fallback,
+		Elapsed:              time.Since(s.start),
+		Verification:         s.verified,
+		Tags:                 s.tags,
+		Cache:                s.cache,
+		OCI:                  s.oci,
+	}
+}
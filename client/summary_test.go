@@ -0,0 +1,80 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTransferSummaryThroughput(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary TransferSummary
+		want    float64
+	}{
+		{"Zero", TransferSummary{}, 0},
+		{"NoElapsed", TransferSummary{Bytes: 1024}, 0},
+		{"OneSecond", TransferSummary{Bytes: 1024, Elapsed: time.Second}, 1024},
+		{"HalfSecond", TransferSummary{Bytes: 1024, Elapsed: 500 * time.Millisecond}, 2048},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.summary.Throughput(); got != tt.want {
+				t.Errorf("got throughput %v, want %v", got, tt.want)
+			}
+
+			// Network throughput is computed identically, from network bytes.
+			ns := tt.summary
+			ns.NetworkBytes, ns.Bytes = ns.Bytes, 0
+			if got := ns.NetworkThroughput(); got != tt.want {
+				t.Errorf("got network throughput %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransferStats(t *testing.T) {
+	// Methods must be safe to call when stats are not being collected
+	stats := transferStatsFromContext(context.Background())
+	if stats != nil {
+		t.Fatal("unexpected transfer stats in context")
+	}
+	stats.setBackend(TransferBackendOCI)
+	stats.addPart(1)
+	stats.addPartNetwork(1, 0)
+	stats.addRetry()
+
+	ctx, stats := withTransferStats(context.Background())
+	if got := transferStatsFromContext(ctx); got != stats {
+		t.Fatal("transfer stats not present in context")
+	}
+
+	stats.setBackend(TransferBackendLibrary)
+	stats.addPart(10)
+	stats.addRetry()
+	stats.addPart(5)
+	stats.addPartNetwork(20, 4)
+
+	s := stats.summary()
+	if got, want := s.Backend, TransferBackendLibrary; got != want {
+		t.Errorf("got backend %v, want %v", got, want)
+	}
+	if got, want := s.Bytes, int64(35); got != want {
+		t.Errorf("got %v bytes, want %v", got, want)
+	}
+	if got, want := s.NetworkBytes, int64(19); got != want {
+		t.Errorf("got %v network bytes, want %v", got, want)
+	}
+	if got, want := s.Parts, 3; got != want {
+		t.Errorf("got %v parts, want %v", got, want)
+	}
+	if got, want := s.Retries, 1; got != want {
+		t.Errorf("got %v retries, want %v", got, want)
+	}
+}
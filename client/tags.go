@@ -0,0 +1,284 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// ErrTagExists is returned when applying a tag that refers to another image, when replacement of
+// tags is not permitted.
+var ErrTagExists = errors.New("tag refers to another image")
+
+// TagChange describes the application of a tag to an image.
+type TagChange struct {
+	// Arch of the tag, or empty if the library does not support architecture specific tags.
+	Arch string
+	// Tag applied to the image.
+	Tag string
+	// ImageID is the ID of the image to which the tag refers, or empty if the tag was deleted.
+	ImageID string
+	// PreviousImageID is the ID of the image to which the tag referred before it was applied, or
+	// empty if the tag was created.
+	PreviousImageID string
+}
+
+// Created reports whether the tag was created.
+func (tc TagChange) Created() bool {
+	return tc.PreviousImageID == ""
+}
+
+// Replaced reports whether the tag previously referred to another image.
+func (tc TagChange) Replaced() bool {
+	return tc.PreviousImageID != "" && tc.ImageID != "" && tc.PreviousImageID != tc.ImageID
+}
+
+// Deleted reports whether the tag was deleted.
+func (tc TagChange) Deleted() bool {
+	return tc.PreviousImageID != "" && tc.ImageID == ""
+}
+
+// TagError describes the failure to apply a tag.
+type TagError struct {
+	// Arch of the tag, or empty if the tag is not architecture specific.
+	Arch string
+	// Tag that could not be applied.
+	Tag string
+	// Err is the reason the tag could not be applied.
+	Err error
+}
+
+func (e *TagError) Error() string {
+	return fmt.Sprintf("error setting tag %v (arch: %q): %v", e.Tag, e.Arch, e.Err)
+}
+
+func (e *TagError) Unwrap() error { return e.Err }
+
+// TagsError is returned when some of the tags applied to an image could not be applied. Neither
+// the library nor OCI registries support applying several tags atomically, so each tag is applied
+// individually, and a failure to apply one tag does not prevent the others from being applied.
+// Callers may retry just the failed tags (see FailedTags).
+type TagsError struct {
+	// Applied are the tags that were applied.
+	Applied []string
+	// Failed describes the tags that could not be applied, in the order they were attempted.
+	Failed []*TagError
+}
+
+func (e *TagsError) Error() string {
+	msgs := make([]string, 0, len(e.Failed))
+	for _, te := range e.Failed {
+		msgs = append(msgs, te.Error())
+	}
+	return fmt.Sprintf("failed to set %d of %d tag(s): %v", len(e.Failed), len(e.Applied)+len(e.Failed), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors of the failed tags, so that errors.Is and errors.As examine each.
+func (e *TagsError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+	for _, te := range e.Failed {
+		errs = append(errs, te)
+	}
+	return errs
+}
+
+// FailedTags returns the tags that could not be applied.
+func (e *TagsError) FailedTags() []string {
+	tags := make([]string, 0, len(e.Failed))
+	for _, te := range e.Failed {
+		tags = append(tags, te.Tag)
+	}
+	return tags
+}
+
+// tagResults accumulates the results of applying tags individually.
+type tagResults struct {
+	applied []string
+	failed  []*TagError
+}
+
+// add records the result of applying tag for arch.
+func (tr *tagResults) add(arch, tag string, err error) {
+	if err != nil {
+		tr.failed = append(tr.failed, &TagError{Arch: arch, Tag: tag, Err: err})
+		return
+	}
+	tr.applied = append(tr.applied, tag)
+}
+
+// err returns a *TagsError describing the failed tags, or nil if all tags were applied.
+func (tr *tagResults) err() error {
+	if len(tr.failed) == 0 {
+		return nil
+	}
+	return &TagsError{Applied: tr.applied, Failed: tr.failed}
+}
+
+// SetTags applies tags to the image with the specified ID, in the container identified by ref (ie.
+// "entity/collection/container"), for the specified architecture. The changes made are returned,
+// so that callers may report tags that were created and replaced.
+//
+// If replace is false and any of the tags refers to another image, no tags are applied, and an
+// error wrapping ErrTagExists is returned.
+//
+// If some of the tags cannot be applied, the remaining tags are still applied. The changes made
+// are returned along with a *TagsError describing the tags that could not be applied.
+func (c *Client) SetTags(ctx context.Context, ref, arch, imageID string, tags []string, replace bool) ([]TagChange, error) {
+	co, err := c.GetContainer(ctx, strings.TrimPrefix(ref, "/"))
+	if err != nil {
+		return nil, err
+	}
+	return c.applyTags(ctx, co.ID, arch, imageID, tags, replace)
+}
+
+// TagExistingImage applies tags to the already uploaded image with SHA256 checksum imageHash (ie.
+// "sha256.<hex>" or "<hex>"), in the container identified by ref (ie.
+// "entity/collection/container"), for the specified architecture. Existing tags are replaced.
+// Unlike UploadImage, the image content is not required, so an uploaded image may be promoted to
+// new tags without access to the image file. The changes made are returned, as for SetTags.
+//
+// If the image does not exist in the container, or its upload did not complete, an error wrapping
+// ErrNotFound is returned.
+func (c *Client) TagExistingImage(ctx context.Context, ref, imageHash, arch string, tags []string) ([]TagChange, error) {
+	ref = strings.TrimPrefix(ref, "/")
+	if strings.Contains(ref, ":") {
+		return nil, fmt.Errorf("malformed container ref: %s", ref)
+	}
+
+	imageHash = strings.TrimPrefix(imageHash, "sha256.")
+	if err := digest.NewDigestFromEncoded(digest.SHA256, imageHash).Validate(); err != nil {
+		return nil, fmt.Errorf("invalid image hash '%v': %w", imageHash, err)
+	}
+
+	co, err := c.GetContainer(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := c.GetImage(ctx, arch, ref+":sha256."+imageHash)
+	if err != nil {
+		return nil, err
+	}
+	if !image.Uploaded {
+		return nil, fmt.Errorf("%w: image sha256.%v has not been uploaded", ErrNotFound, imageHash)
+	}
+
+	return c.applyTags(ctx, co.ID, arch, image.ID, tags, true)
+}
+
+// applyTags applies tags to the image with the specified ID in the specified container, using
+// architecture specific tags if supported by the library.
+func (c *Client) applyTags(ctx context.Context, containerID, arch, imageID string, tags []string, replace bool) ([]TagChange, error) {
+	if c.apiAtLeast(ctx, APIVersionV2ArchTags) {
+		return c.setTagsV2(ctx, containerID, arch, imageID, tags, replace)
+	}
+
+	c.logger.Logf("This library does not support multiple architectures per tag.")
+
+	c.logger.Logf("This tag will replace any already uploaded with the same name.")
+
+	return c.setTags(ctx, containerID, imageID, tags, replace)
+}
+
+// planTagChanges returns the changes made by applying tags for arch to the image with the
+// specified ID, given the existing tags for arch. If replace is false and any tag refers to
+// another image, an error wrapping ErrTagExists is returned.
+func planTagChanges(arch, imageID string, tags []string, existing TagMap, replace bool) ([]TagChange, error) {
+	changes := make([]TagChange, 0, len(tags))
+
+	for _, tag := range tags {
+		tc := TagChange{Arch: arch, Tag: tag, ImageID: imageID, PreviousImageID: existing[tag]}
+
+		if !replace && tc.Replaced() {
+			return nil, fmt.Errorf("%w: tag %v refers to image %v", ErrTagExists, tag, tc.PreviousImageID)
+		}
+
+		changes = append(changes, tc)
+	}
+	return changes, nil
+}
+
+// logTagChange logs the application of a tag described by tc.
+func (c *Client) logTagChange(tc TagChange) {
+	switch {
+	case tc.Created():
+		c.logger.Logf("Setting tag %s (arch: %q, image: %s, created)", tc.Tag, tc.Arch, tc.ImageID)
+	case tc.Replaced():
+		c.logger.Logf("Setting tag %s (arch: %q, image: %s, replaces image %s)", tc.Tag, tc.Arch, tc.ImageID, tc.PreviousImageID)
+	default:
+		c.logger.Logf("Setting tag %s (arch: %q, image: %s, unchanged)", tc.Tag, tc.Arch, tc.ImageID)
+	}
+}
+
+// ListTags returns the sorted tags of the container identified by ref (ie.
+// "entity/collection/container"), across all architectures. If supported, tags are listed using
+// the OCI registry directly; otherwise, the library tags endpoint is used.
+func (c *Client) ListTags(ctx context.Context, ref string) ([]string, error) {
+	name := strings.TrimPrefix(ref, "/")
+
+	tags, err := c.ociListTags(ctx, name)
+	if err != nil {
+		if !errors.Is(err, errOCIDownloadNotSupported) {
+			return nil, err
+		}
+
+		c.logger.Log("Fallback to (legacy) library tag list")
+
+		if tags, err = c.libraryListTags(ctx, name); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// ociListTags returns the tags of the container with the specified name in the OCI registry.
+func (c *Client) ociListTags(ctx context.Context, name string) ([]string, error) {
+	reg, creds, name, err := c.newOCIRegistry(ctx, name, []accessType{accessTypePull})
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := reg.listTags(ctx, creds, name)
+	if err != nil {
+		return nil, fmt.Errorf("error listing tags: %w", err)
+	}
+	return tags, nil
+}
+
+// libraryListTags returns the tags of the container with the specified name, using the library
+// tags endpoint.
+func (c *Client) libraryListTags(ctx context.Context, name string) ([]string, error) {
+	co, err := c.GetContainer(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	archTags, err := c.getTagsV2(ctx, co.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+
+	var tags []string
+	for _, tm := range archTags {
+		for tag := range tm {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags, nil
+}
@@ -0,0 +1,446 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func TestListTags(t *testing.T) {
+	allTags := []string{"latest", "v1", "v2", "v3"}
+
+	tests := []struct {
+		name     string
+		oci      bool
+		pageSize int
+	}{
+		{"OCI", true, 0},
+		{"OCIPaginated", true, 1},
+		{"Library", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var srv *httptest.Server
+
+			srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v1/oci-redirect":
+					if !tt.oci {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+
+					response := struct {
+						Token       string `json:"token"`
+						RegistryURI string `json:"url"`
+						Name        string `json:"name"`
+					}{
+						Token:       "xxx",
+						RegistryURI: srv.URL,
+						Name:        "entity/collection/container",
+					}
+					if err := json.NewEncoder(w).Encode(&response); err != nil {
+						t.Errorf("error JSON encoding: %v", err)
+					}
+
+				case "/v2/entity/collection/container/tags/list":
+					tags := []string{"v3", "latest", "v2", "v1"}
+
+					if tt.pageSize > 0 {
+						start := 0
+						if last := r.URL.Query().Get("last"); last != "" {
+							for i, tag := range tags {
+								if tag == last {
+									start = i + 1
+								}
+							}
+						}
+						end := start + tt.pageSize
+						if end < len(tags) {
+							q := url.Values{}
+							q.Set("n", strconv.Itoa(tt.pageSize))
+							q.Set("last", tags[end-1])
+							w.Header().Set("Link", fmt.Sprintf(`<%v?%v>; rel="next"`, r.URL.Path, q.Encode()))
+						} else {
+							end = len(tags)
+						}
+						tags = tags[start:end]
+					}
+
+					w.Header().Set("Content-Type", "application/json")
+					if err := json.NewEncoder(w).Encode(map[string]interface{}{"name": "entity/collection/container", "tags": tags}); err != nil {
+						t.Errorf("error JSON encoding: %v", err)
+					}
+
+				case "/v1/containers/entity/collection/container":
+					if err := jsonresp.WriteResponse(w, &Container{ID: "containerID"}, http.StatusOK); err != nil {
+						t.Errorf("error writing JSON response: %v", err)
+					}
+
+				case "/v2/tags/containerID":
+					tags := ArchTagMap{
+						"amd64": {"latest": "1", "v1": "2", "v2": "3"},
+						"arm64": {"latest": "4", "v3": "5"},
+					}
+					if err := jsonresp.WriteResponse(w, tags, http.StatusOK); err != nil {
+						t.Errorf("error writing JSON response: %v", err)
+					}
+
+				default:
+					t.Errorf("unexpected request to %v", r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			tags, err := c.ListTags(context.Background(), "entity/collection/container")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got, want := tags, allTags; !reflect.DeepEqual(got, want) {
+				t.Errorf("got tags %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_nextLink(t *testing.T) {
+	reqURL, err := url.Parse("https://registry/v2/name/tags/list")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		links []string
+		want  string
+	}{
+		{"None", nil, ""},
+		{"Relative", []string{`</v2/name/tags/list?n=1&last=a>; rel="next"`}, "https://registry/v2/name/tags/list?n=1&last=a"},
+		{"Absolute", []string{`<https://other/v2/name/tags/list?last=a>; rel=next`}, "https://other/v2/name/tags/list?last=a"},
+		{"Multiple", []string{`</prev>; rel="prev", </next>; rel="next"`}, "https://registry/next"},
+		{"MultipleHeaders", []string{`</prev>; rel="prev"`, `</next>; type="x"; rel="next"`}, "https://registry/next"},
+		{"NoNext", []string{`</prev>; rel="prev"`}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &http.Response{
+				Header:  http.Header{"Link": tt.links},
+				Request: &http.Request{URL: reqURL},
+			}
+
+			u, err := nextLink(res)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := ""
+			if u != nil {
+				got = u.String()
+			}
+			if got != tt.want {
+				t.Errorf("got link %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetTags(t *testing.T) {
+	const (
+		containerID = "containerID"
+		imageID     = "imageID"
+	)
+
+	existing := ArchTagMap{
+		"amd64": {"latest": "oldImageID", "v1": imageID},
+		"arm64": {"v2": "otherImageID"},
+	}
+
+	tests := []struct {
+		name        string
+		tags        []string
+		replace     bool
+		want        []TagChange
+		wantErr     error
+		wantApplied []string
+		wantFailed  []string
+	}{
+		{
+			name:    "Replace",
+			tags:    []string{"latest", "v1", "v2"},
+			replace: true,
+			want: []TagChange{
+				{Arch: "amd64", Tag: "latest", ImageID: imageID, PreviousImageID: "oldImageID"},
+				{Arch: "amd64", Tag: "v1", ImageID: imageID, PreviousImageID: imageID},
+				{Arch: "amd64", Tag: "v2", ImageID: imageID},
+			},
+			wantApplied: []string{"latest", "v1", "v2"},
+		},
+		{
+			name:        "NoReplace",
+			tags:        []string{"v1", "v2"},
+			want:        []TagChange{{Arch: "amd64", Tag: "v1", ImageID: imageID, PreviousImageID: imageID}, {Arch: "amd64", Tag: "v2", ImageID: imageID}},
+			wantApplied: []string{"v1", "v2"},
+		},
+		{
+			name:    "NoReplaceExists",
+			tags:    []string{"v2", "latest"},
+			wantErr: ErrTagExists,
+		},
+		{
+			name:    "PartialFailure",
+			tags:    []string{"v2", "fail", "v3"},
+			replace: true,
+			want: []TagChange{
+				{Arch: "amd64", Tag: "v2", ImageID: imageID},
+				{Arch: "amd64", Tag: "v3", ImageID: imageID},
+			},
+			wantApplied: []string{"v2", "v3"},
+			wantFailed:  []string{"fail"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var applied []string
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body interface{}
+
+				switch {
+				case r.URL.Path == "/version":
+					body = VersionInfo{APIVersion: "2.0.0"}
+				case r.URL.Path == "/v1/containers/entity/collection/container":
+					body = Container{ID: containerID}
+				case r.Method == http.MethodGet && r.URL.Path == "/v2/tags/"+containerID:
+					body = existing
+				case r.Method == http.MethodPost && r.URL.Path == "/v2/tags/"+containerID:
+					var tag ArchImageTag
+					if err := json.NewDecoder(r.Body).Decode(&tag); err != nil {
+						t.Errorf("error decoding request: %v", err)
+					}
+					if tag.Tag == "fail" {
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					applied = append(applied, tag.Tag)
+				default:
+					t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				if err := jsonresp.WriteResponse(w, body, http.StatusOK); err != nil {
+					t.Errorf("error writing JSON response: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			got, err := c.SetTags(context.Background(), "entity/collection/container", "amd64", imageID, tt.tags, tt.replace)
+			if tt.wantFailed != nil {
+				var te *TagsError
+				if !errors.As(err, &te) {
+					t.Fatalf("got error %v, want *TagsError", err)
+				}
+				if got := te.FailedTags(); !reflect.DeepEqual(got, tt.wantFailed) {
+					t.Errorf("got failed tags %v, want %v", got, tt.wantFailed)
+				}
+				if !reflect.DeepEqual(te.Applied, tt.wantApplied) {
+					t.Errorf("got reported applied tags %v, want %v", te.Applied, tt.wantApplied)
+				}
+			} else if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got changes %+v, want %+v", got, tt.want)
+			}
+
+			if !reflect.DeepEqual(applied, tt.wantApplied) {
+				t.Errorf("got applied tags %v, want %v", applied, tt.wantApplied)
+			}
+		})
+	}
+}
+
+func TestTagChange(t *testing.T) {
+	tests := []struct {
+		name         string
+		tc           TagChange
+		wantCreated  bool
+		wantReplaced bool
+		wantDeleted  bool
+	}{
+		{"Created", TagChange{Tag: "latest", ImageID: "a"}, true, false, false},
+		{"Replaced", TagChange{Tag: "latest", ImageID: "a", PreviousImageID: "b"}, false, true, false},
+		{"Unchanged", TagChange{Tag: "latest", ImageID: "a", PreviousImageID: "a"}, false, false, false},
+		{"Deleted", TagChange{Tag: "latest", PreviousImageID: "a"}, false, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tc.Created(); got != tt.wantCreated {
+				t.Errorf("got created %v, want %v", got, tt.wantCreated)
+			}
+			if got := tt.tc.Replaced(); got != tt.wantReplaced {
+				t.Errorf("got replaced %v, want %v", got, tt.wantReplaced)
+			}
+			if got := tt.tc.Deleted(); got != tt.wantDeleted {
+				t.Errorf("got deleted %v, want %v", got, tt.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestTagExistingImage(t *testing.T) {
+	const (
+		containerID = "containerID"
+		hash        = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	)
+
+	tests := []struct {
+		name        string
+		ref         string
+		hash        string
+		uploaded    bool
+		missing     bool
+		want        []TagChange
+		wantApplied []string
+		wantErr     error
+		expectErr   bool
+	}{
+		{
+			name:        "OK",
+			ref:         "entity/collection/container",
+			hash:        "sha256." + hash,
+			uploaded:    true,
+			want:        []TagChange{{Arch: "amd64", Tag: "v2", ImageID: "imageID"}, {Arch: "amd64", Tag: "latest", ImageID: "imageID", PreviousImageID: "oldImageID"}},
+			wantApplied: []string{"v2", "latest"},
+		},
+		{
+			name:        "UnprefixedHash",
+			ref:         "/entity/collection/container",
+			hash:        hash,
+			uploaded:    true,
+			want:        []TagChange{{Arch: "amd64", Tag: "v2", ImageID: "imageID"}, {Arch: "amd64", Tag: "latest", ImageID: "imageID", PreviousImageID: "oldImageID"}},
+			wantApplied: []string{"v2", "latest"},
+		},
+		{
+			name:      "NotUploaded",
+			ref:       "entity/collection/container",
+			hash:      hash,
+			wantErr:   ErrNotFound,
+			expectErr: true,
+		},
+		{
+			name:      "NotFound",
+			ref:       "entity/collection/container",
+			hash:      hash,
+			missing:   true,
+			wantErr:   ErrNotFound,
+			expectErr: true,
+		},
+		{
+			name:      "BadHash",
+			ref:       "entity/collection/container",
+			hash:      "sha256.abc",
+			expectErr: true,
+		},
+		{
+			name:      "TaggedRef",
+			ref:       "entity/collection/container:latest",
+			hash:      hash,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var applied []string
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body interface{}
+
+				switch {
+				case r.URL.Path == "/version":
+					body = VersionInfo{APIVersion: "2.0.0"}
+				case r.URL.Path == "/v1/containers/entity/collection/container":
+					body = Container{ID: containerID}
+				case r.URL.Path == "/v1/images/entity/collection/container:sha256."+hash:
+					if tt.missing {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					body = Image{ID: "imageID", Uploaded: tt.uploaded}
+				case r.Method == http.MethodGet && r.URL.Path == "/v2/tags/"+containerID:
+					body = ArchTagMap{"amd64": {"latest": "oldImageID"}}
+				case r.Method == http.MethodPost && r.URL.Path == "/v2/tags/"+containerID:
+					var tag ArchImageTag
+					if err := json.NewDecoder(r.Body).Decode(&tag); err != nil {
+						t.Errorf("error decoding request: %v", err)
+					}
+					applied = append(applied, tag.Tag)
+				default:
+					t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				if err := jsonresp.WriteResponse(w, body, http.StatusOK); err != nil {
+					t.Errorf("error writing JSON response: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			got, err := c.TagExistingImage(context.Background(), tt.ref, tt.hash, "amd64", []string{"v2", "latest"})
+			if tt.expectErr && err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got changes %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(applied, tt.wantApplied) {
+				t.Errorf("got applied tags %v, want %v", applied, tt.wantApplied)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TagEvent is delivered by WatchTags when the tags of a container change, or polling fails.
+type TagEvent struct {
+	// Change describes the change to a tag. Not set if Err is non-nil.
+	Change TagChange
+	// Err is the error encountered polling the tags of the container. Polling continues at the
+	// next interval.
+	Err error
+}
+
+// tagsValidators holds the validators of a tags response, used to make conditional requests.
+type tagsValidators struct {
+	etag         string
+	lastModified string
+}
+
+// WatchTags polls the tags of the container identified by ref (ie. "entity/collection/container")
+// at the specified interval, delivering an event on the returned channel for each tag that is
+// created, moved to another image, or deleted. Polling uses conditional requests, so that
+// unchanged tags are not transferred.
+//
+// The tags of the container are retrieved before WatchTags returns, so that an error is returned
+// if the container cannot be found. Errors encountered while polling are delivered as events. The
+// returned channel is closed when ctx is done.
+func (c *Client) WatchTags(ctx context.Context, ref string, interval time.Duration) (<-chan TagEvent, error) {
+	if interval <= 0 {
+		return nil, errors.New("watch interval must be positive")
+	}
+
+	co, err := c.GetContainer(ctx, strings.TrimPrefix(ref, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	archTags, tv, _, err := c.getTagsConditional(ctx, co.ID, tagsValidators{})
+	if err != nil {
+		return nil, fmt.Errorf("error watching tags: %w", err)
+	}
+
+	ch := make(chan TagEvent)
+
+	go func() {
+		defer close(ch)
+
+		send := func(ev TagEvent) bool {
+			select {
+			case ch <- ev:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for {
+			if err := c.sleeper.Sleep(ctx, interval); err != nil {
+				return
+			}
+
+			next, ntv, modified, err := c.getTagsConditional(ctx, co.ID, tv)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				c.logger.Logf("Error polling tags of %s: %v", ref, err)
+
+				if !send(TagEvent{Err: err}) {
+					return
+				}
+				continue
+			}
+			if !modified {
+				continue
+			}
+
+			for _, tc := range diffTags(archTags, next) {
+				if !send(TagEvent{Change: tc}) {
+					return
+				}
+			}
+			archTags, tv = next, ntv
+		}
+	}()
+
+	return ch, nil
+}
+
+// getTagsConditional returns the tags of the specified container, if modified since the response
+// identified by tv. If not modified, modified is false and the returned tags are nil. Libraries
+// that do not support architecture specific tags report tags with an empty architecture.
+func (c *Client) getTagsConditional(ctx context.Context, containerID string, tv tagsValidators) (tags ArchTagMap, _ tagsValidators, modified bool, err error) {
+	v2 := c.apiAtLeast(ctx, APIVersionV2ArchTags)
+
+	path := "v1/tags/" + containerID
+	if v2 {
+		path = "v2/tags/" + containerID
+	}
+
+	ctx, cancel := c.metadataContext(ctx)
+	defer cancel()
+
+	req, err := c.newRequest(ctx, http.MethodGet, path, "", nil)
+	if err != nil {
+		return nil, tv, false, fmt.Errorf("error creating request to server:\n\t%v", err)
+	}
+	if tv.etag != "" {
+		req.Header.Set("If-None-Match", tv.etag)
+	}
+	if tv.lastModified != "" {
+		req.Header.Set("If-Modified-Since", tv.lastModified)
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, tv, false, fmt.Errorf("error making request to server:\n\t%w", err)
+	}
+	defer res.Body.Close()
+
+	if err := c.checkStatusCode(res, []int{http.StatusOK, http.StatusNotModified}); err != nil {
+		return nil, tv, false, err
+	}
+	if res.StatusCode == http.StatusNotModified {
+		return nil, tv, false, nil
+	}
+
+	ntv := tagsValidators{etag: res.Header.Get("ETag"), lastModified: res.Header.Get("Last-Modified")}
+
+	dec := json.NewDecoder(limitResponse(res.Body, c.maxResponseSize))
+
+	if v2 {
+		var tagRes ArchTagsResponse
+		if err := dec.Decode(&tagRes); err != nil {
+			return nil, tv, false, fmt.Errorf("error decoding tags: %w", err)
+		}
+		return tagRes.Data, ntv, true, nil
+	}
+
+	var tagRes TagsResponse
+	if err := dec.Decode(&tagRes); err != nil {
+		return nil, tv, false, fmt.Errorf("error decoding tags: %w", err)
+	}
+	return ArchTagMap{"": tagRes.Data}, ntv, true, nil
+}
+
+// diffTags returns the changes that transform the tags prev into next, sorted by architecture and
+// tag.
+func diffTags(prev, next ArchTagMap) []TagChange {
+	var changes []TagChange
+
+	for arch, tm := range next {
+		for tag, id := range tm {
+			if old := prev[arch][tag]; old != id {
+				changes = append(changes, TagChange{Arch: arch, Tag: tag, ImageID: id, PreviousImageID: old})
+			}
+		}
+	}
+
+	for arch, tm := range prev {
+		for tag, id := range tm {
+			if _, ok := next[arch][tag]; !ok {
+				changes = append(changes, TagChange{Arch: arch, Tag: tag, PreviousImageID: id})
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Arch != changes[j].Arch {
+			return changes[i].Arch < changes[j].Arch
+		}
+		return changes[i].Tag < changes[j].Tag
+	})
+	return changes
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func TestWatchTags(t *testing.T) {
+	// Successive states of the tags of the container. The server advances to the next state on
+	// each poll, and reports the final state as unmodified.
+	states := []ArchTagMap{
+		{"amd64": {"latest": "a", "v1": "a"}},
+		{"amd64": {"latest": "a", "v1": "a"}},
+		{"amd64": {"latest": "b", "v1": "a", "v2": "b"}},
+		{"amd64": {"latest": "b", "v2": "b"}, "arm64": {"latest": "c"}},
+	}
+
+	var (
+		mu           sync.Mutex
+		polls        int
+		conditionals int
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/version":
+			if err := jsonresp.WriteResponse(w, VersionInfo{APIVersion: "2.0.0"}, http.StatusOK); err != nil {
+				t.Errorf("error writing JSON response: %v", err)
+			}
+		case "/v1/containers/entity/collection/container":
+			if err := jsonresp.WriteResponse(w, Container{ID: "containerID"}, http.StatusOK); err != nil {
+				t.Errorf("error writing JSON response: %v", err)
+			}
+		case "/v2/tags/containerID":
+			mu.Lock()
+			defer mu.Unlock()
+
+			i := polls
+			if i >= len(states) {
+				i = len(states) - 1
+			}
+			polls++
+
+			etag := fmt.Sprintf(`"%d"`, i)
+			if i > 0 && reflect.DeepEqual(states[i], states[i-1]) {
+				etag = fmt.Sprintf(`"%d"`, i-1)
+			}
+
+			if inm := r.Header.Get("If-None-Match"); inm != "" {
+				conditionals++
+				if inm == etag {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+			}
+
+			w.Header().Set("ETag", etag)
+			if err := jsonresp.WriteResponse(w, states[i], http.StatusOK); err != nil {
+				t.Errorf("error writing JSON response: %v", err)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	s := &recordingSleeper{}
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger, Sleeper: s})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := c.WatchTags(ctx, "entity/collection/container", time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []TagChange{
+		{Arch: "amd64", Tag: "latest", ImageID: "b", PreviousImageID: "a"},
+		{Arch: "amd64", Tag: "v2", ImageID: "b"},
+		{Arch: "amd64", Tag: "v1", PreviousImageID: "a"},
+		{Arch: "arm64", Tag: "latest", ImageID: "c"},
+	}
+
+	var got []TagChange
+	for len(got) < len(want) {
+		ev := <-ch
+		if ev.Err != nil {
+			t.Fatalf("unexpected error: %v", ev.Err)
+		}
+		got = append(got, ev.Change)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got changes %+v, want %+v", got, want)
+	}
+
+	cancel()
+	for range ch {
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if conditionals == 0 {
+		t.Error("no conditional requests made")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, d := range s.delays {
+		if d != time.Minute {
+			t.Errorf("got delay %v, want %v", d, time.Minute)
+		}
+	}
+}
+
+func TestWatchTagsErrors(t *testing.T) {
+	var polls int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/containers/entity/collection/container":
+			if err := jsonresp.WriteResponse(w, Container{ID: "containerID"}, http.StatusOK); err != nil {
+				t.Errorf("error writing JSON response: %v", err)
+			}
+		case "/v1/tags/containerID":
+			polls++
+			if polls > 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if err := jsonresp.WriteResponse(w, TagMap{"latest": "a"}, http.StatusOK); err != nil {
+				t.Errorf("error writing JSON response: %v", err)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger, Sleeper: noSleep})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if _, err := c.WatchTags(ctx, "entity/collection/container", 0); err == nil {
+		t.Error("unexpected success")
+	}
+
+	if _, err := c.WatchTags(ctx, "entity/collection/missing", time.Second); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, ErrNotFound)
+	}
+
+	ch, err := c.WatchTags(ctx, "entity/collection/container", time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ev := <-ch; ev.Err == nil {
+		t.Errorf("got event %+v, want error", ev)
+	}
+
+	cancel()
+	for range ch {
+	}
+}
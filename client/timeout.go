@@ -0,0 +1,66 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// Timeouts specifies limits on the duration of operations performed by the client. Unlike the
+// Timeout of an http.Client, which applies to every request alike, the limits distinguish short
+// metadata requests from image transfers that may legitimately take hours. Each limit is enforced
+// using a context derived from the context passed to the client. A zero value means no limit.
+type Timeouts struct {
+	// Metadata limits each request that does not transfer image content, including library API
+	// requests, OCI registry authorization, and the retrieval of OCI manifests and image configs.
+	// The limit includes the time taken to read the response. If zero, the retrieval of OCI
+	// manifests and image configs is nonetheless limited to one minute; set a negative value to
+	// remove all limits.
+	Metadata time.Duration
+	// Part limits each attempt to transfer a part of a multipart upload or concurrent download.
+	// An upload part that exceeds the limit is retried, subject to Config.UploadPartRetries.
+	Part time.Duration
+	// Operation limits each image upload or download in its entirety, including any metadata
+	// requests and retries.
+	Operation time.Duration
+}
+
+// defaultRegistryMetadataTimeout is the limit on the retrieval of each OCI manifest and image
+// config, when no metadata timeout is specified.
+const defaultRegistryMetadataTimeout = time.Minute
+
+// withTimeout returns a context derived from ctx that is cancelled after d elapses. If d is not
+// positive, ctx is returned unmodified.
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// metadataContext returns a context derived from ctx, limited by the metadata timeout.
+func (c *Client) metadataContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, c.timeouts.Metadata)
+}
+
+// registryMetadataTimeout returns the limit on the retrieval of each OCI manifest and image config.
+func (c *Client) registryMetadataTimeout() time.Duration {
+	if c.timeouts.Metadata == 0 {
+		return defaultRegistryMetadataTimeout
+	}
+	return c.timeouts.Metadata
+}
+
+// partContext returns a context derived from ctx, limited by the part timeout.
+func (c *Client) partContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, c.timeouts.Part)
+}
+
+// operationContext returns a context derived from ctx, limited by the operation timeout.
+func (c *Client) operationContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, c.timeouts.Operation)
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func TestTimeoutsMetadata(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{
+		BaseURL:  srv.URL,
+		Logger:   testLogger,
+		Timeouts: &Timeouts{Metadata: 50 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	if _, err := c.GetVersion(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRegistryMetadataTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeouts *Timeouts
+		want     time.Duration
+	}{
+		{"Default", nil, defaultRegistryMetadataTimeout},
+		{"Metadata", &Timeouts{Metadata: 30 * time.Second}, 30 * time.Second},
+		{"Disabled", &Timeouts{Metadata: -1}, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(&Config{Logger: testLogger, Timeouts: tt.timeouts})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			if got, want := c.registryMetadataTimeout(), tt.want; got != want {
+				t.Errorf("got timeout %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestTimeoutsPartDownload(t *testing.T) {
+	const src = "123456789012345678901234567890"
+	size := int64(len(src))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, end := parseRangeHeader(t, r.Header.Get("Range"))
+
+		// Stall the third part until the client gives up.
+		if start == 6 {
+			<-r.Context().Done()
+			return
+		}
+
+		w.Header().Set("Content-Length", fmt.Sprintf("%v", end-start+1))
+		w.WriteHeader(http.StatusPartialContent)
+
+		if _, err := io.Copy(w, strings.NewReader(src[start:end+1])); err != nil {
+			t.Errorf("unexpected error writing http response: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{
+		Logger:   testLogger,
+		Timeouts: &Timeouts{Part: 50 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	dst := &inMemoryBuffer{buf: make([]byte, size)}
+
+	err = c.multipartDownload(context.Background(), &blobURL{u: srv.URL}, nil, dst, size, &Downloader{Concurrency: 1, PartSize: 3}, &NoopProgressBar{})
+
+	var pe *PartError
+	if !errors.As(err, &pe) {
+		t.Fatalf("got error %v, want PartError", err)
+	}
+	if got, want := pe.PartNumber, 3; got != want {
+		t.Errorf("got part number %v, want %v", got, want)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestTimeoutsPartUploadRetried(t *testing.T) {
+	const imageID = "5cb9c34d7d960d82f5f5bc55"
+
+	var putRequests atomic.Int32
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/v2/imagefile/"+imageID+"/_multipart", func(w http.ResponseWriter, _ *http.Request) {
+		response := UploadImagePart{PresignedURL: srv.URL + "/s3/part"}
+		if err := jsonresp.WriteResponse(w, &response, http.StatusOK); err != nil {
+			t.Errorf("error writing JSON response: %v", err)
+		}
+	})
+	mux.HandleFunc("/s3/part", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.Copy(io.Discard, r.Body); err != nil {
+			t.Errorf("error reading part: %v", err)
+		}
+
+		// Stall the first attempt until the client gives up.
+		if putRequests.Add(1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+
+		w.Header().Set("ETag", "etag")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	c, err := NewClient(&Config{
+		AuthToken: testToken,
+		BaseURL:   srv.URL,
+		Logger:    testLogger,
+		Timeouts:  &Timeouts{Part: 50 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+	c.sleeper = noSleep
+
+	r := strings.NewReader("0123456789")
+
+	m := &uploadManager{
+		Source:   r,
+		Size:     10,
+		ImageID:  imageID,
+		UploadID: "uploadID",
+	}
+
+	if _, err := c.multipartUploadPart(context.Background(), 1, m, &defaultUploadCallback{r: r}, objectStore{kind: ObjectStoreS3, s3Compliant: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := putRequests.Load(), int32(2); got != want {
+		t.Errorf("got %v PUT requests, want %v", got, want)
+	}
+}
+
+func TestTimeoutsOperation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{
+		BaseURL:  srv.URL,
+		Logger:   testLogger,
+		Timeouts: &Timeouts{Operation: 50 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	dst := &inMemoryBuffer{}
+
+	err = c.downloadImage(context.Background(), dst, "entity/collection/container", &DownloadOptions{Arch: "amd64"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ListAccessTokens returns the access tokens of the authenticated user. Token values are not
+// returned.
+func (c *Client) ListAccessTokens(ctx context.Context) ([]AccessToken, error) {
+	b, err := c.apiGet(ctx, "v1/tokens")
+	if err != nil {
+		return nil, err
+	}
+	var res AccessTokensResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("error decoding access tokens: %v", err)
+	}
+	return res.Data, nil
+}
+
+// CreateAccessToken creates an access token for the authenticated user, limited to the scopes and
+// expiry specified by tr. The token value is returned in the Token field of the result, and cannot
+// be retrieved later.
+func (c *Client) CreateAccessToken(ctx context.Context, tr TokenRequest) (*AccessToken, error) {
+	if !tr.Expiry.IsZero() && !tr.Expiry.After(time.Now()) {
+		return nil, fmt.Errorf("token expiry %v is in the past", tr.Expiry)
+	}
+
+	b, err := c.apiCreate(ctx, "v1/tokens", tr)
+	if err != nil {
+		return nil, fmt.Errorf("error creating access token: %w", err)
+	}
+	var res AccessTokenResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("error decoding access token: %v", err)
+	}
+	return &res.Data, nil
+}
+
+// RevokeAccessToken revokes the access token identified by id. Returns ErrNotFound if the token
+// is not found.
+func (c *Client) RevokeAccessToken(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("token ID is required")
+	}
+
+	if _, err := c.doDeleteRequest(ctx, "v1/tokens/"+url.PathEscape(id)); err != nil {
+		return fmt.Errorf("error revoking access token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func TestListAccessTokens(t *testing.T) {
+	want := []AccessToken{
+		{ID: "1", Description: "ci", Scopes: []string{"pull"}, Created: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Path, "/v1/tokens"; got != want {
+			t.Errorf("got path %v, want %v", got, want)
+		}
+		if err := jsonresp.WriteResponse(w, want, http.StatusOK); err != nil {
+			t.Errorf("error writing JSON response: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	got, err := c.ListAccessTokens(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got tokens %+v, want %+v", got, want)
+	}
+}
+
+func TestCreateAccessToken(t *testing.T) {
+	expiry := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name    string
+		tr      TokenRequest
+		wantErr bool
+	}{
+		{"Expiry", TokenRequest{Description: "ci", Scopes: []string{"pull", "push"}, Expiry: expiry}, false},
+		{"NoExpiry", TokenRequest{Scopes: []string{"pull"}}, false},
+		{"Expired", TokenRequest{Expiry: time.Now().Add(-time.Hour)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got, want := r.Method, http.MethodPost; got != want {
+					t.Errorf("got method %v, want %v", got, want)
+				}
+				if got, want := r.URL.Path, "/v1/tokens"; got != want {
+					t.Errorf("got path %v, want %v", got, want)
+				}
+
+				var tr TokenRequest
+				if err := json.NewDecoder(r.Body).Decode(&tr); err != nil {
+					t.Errorf("error decoding request: %v", err)
+				}
+				if !reflect.DeepEqual(tr, tt.tr) {
+					t.Errorf("got request %+v, want %+v", tr, tt.tr)
+				}
+
+				at := AccessToken{
+					ID:          "1",
+					Description: tr.Description,
+					Scopes:      tr.Scopes,
+					Expiry:      tr.Expiry,
+					Token:       "secret",
+				}
+				if err := jsonresp.WriteResponse(w, &at, http.StatusCreated); err != nil {
+					t.Errorf("error writing JSON response: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			at, err := c.CreateAccessToken(context.Background(), tt.tr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got, want := at.Token, "secret"; got != want {
+				t.Errorf("got token %v, want %v", got, want)
+			}
+			if got, want := at.Expiry, tt.tr.Expiry; !got.Equal(want) {
+				t.Errorf("got expiry %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestRevokeAccessToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		code    int
+		wantErr error
+	}{
+		{"OK", "1", http.StatusOK, nil},
+		{"NotFound", "2", http.StatusNotFound, ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got, want := r.Method, http.MethodDelete; got != want {
+					t.Errorf("got method %v, want %v", got, want)
+				}
+				if got, want := r.URL.Path, "/v1/tokens/"+tt.id; got != want {
+					t.Errorf("got path %v, want %v", got, want)
+				}
+				w.WriteHeader(tt.code)
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			if err := c.RevokeAccessToken(context.Background(), tt.id); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
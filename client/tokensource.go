@@ -0,0 +1,92 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TokenSource supplies the auth token included in requests made to the library server, allowing
+// the token to be rotated during long-running operations such as UploadImage. Implementations must
+// be safe for concurrent use.
+type TokenSource interface {
+	// Token returns the auth token to include in a request. An empty token disables
+	// authentication.
+	Token(ctx context.Context) (string, error)
+	// Refresh returns a new auth token, after the library server rejected a request made using
+	// token rejected with HTTP status 401.
+	Refresh(ctx context.Context, rejected string) (string, error)
+}
+
+// token returns the auth token to include in a request made using ctx.
+func (c *Client) token(ctx context.Context) (string, error) {
+	if c.tokenSource == nil {
+		return c.authToken, nil
+	}
+
+	token, err := c.tokenSource.Token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error obtaining auth token: %w", err)
+	}
+	return token, nil
+}
+
+// requestToken returns the auth token included in request r.
+func requestToken(r *http.Request) string {
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+// retryUnauthorized re-attempts req using hc, if res indicates the library server rejected it with
+// HTTP status 401, and a new auth token is obtained from the token source of the client. The
+// request is re-attempted at most once, and only if its body can be reset. Otherwise, res is
+// returned.
+func (c *Client) retryUnauthorized(hc *http.Client, req *http.Request, res *http.Response) (*http.Response, error) {
+	if res.StatusCode != http.StatusUnauthorized || c.tokenSource == nil || !samehost(c.baseURL, req.URL) {
+		return res, nil
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return res, nil
+	}
+
+	rejected := requestToken(req)
+
+	token, err := c.tokenSource.Refresh(req.Context(), rejected)
+	c.emitAuthEvent(tokenEvent(AuthEventRefresh, req.URL.Host, nil, token, err))
+	if err != nil {
+		c.logger.Logf("Error refreshing auth token: %v", err)
+		return res, nil
+	}
+	if token == rejected {
+		return res, nil
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return res, nil
+		}
+	}
+
+	// Release the response before the request is re-attempted.
+	res.Body.Close()
+
+	retry.Header.Del("Authorization")
+	if token != "" {
+		if err := (bearerTokenCredentials{authToken: token}).ModifyRequest(retry); err != nil {
+			if retry.Body != nil {
+				retry.Body.Close()
+			}
+			return nil, err
+		}
+	}
+
+	c.logger.Log("Auth token rejected, retrying request with refreshed token")
+
+	return hc.Do(retry)
+}
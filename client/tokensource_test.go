@@ -0,0 +1,169 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// testTokenSource returns each of tokens in turn, advancing to the next token when refreshed.
+type testTokenSource struct {
+	mu         sync.Mutex
+	tokens     []string
+	i          int
+	refreshes  int
+	refreshErr error
+}
+
+func (ts *testTokenSource) Token(context.Context) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	return ts.tokens[ts.i], nil
+}
+
+func (ts *testTokenSource) Refresh(_ context.Context, rejected string) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	ts.refreshes++
+
+	if ts.refreshErr != nil {
+		return "", ts.refreshErr
+	}
+	if ts.tokens[ts.i] == rejected && ts.i+1 < len(ts.tokens) {
+		ts.i++
+	}
+	return ts.tokens[ts.i], nil
+}
+
+func TestTokenSourceRetry(t *testing.T) {
+	tests := []struct {
+		name          string
+		authToken     string
+		tokenSource   *testTokenSource
+		wantErr       error
+		wantRequests  int
+		wantRefreshes int
+	}{
+		{
+			name:         "AuthToken",
+			authToken:    "old",
+			wantErr:      ErrUnauthorized,
+			wantRequests: 1,
+		},
+		{
+			name:          "Refreshed",
+			tokenSource:   &testTokenSource{tokens: []string{"old", "new"}},
+			wantRequests:  2,
+			wantRefreshes: 1,
+		},
+		{
+			name:          "Current",
+			tokenSource:   &testTokenSource{tokens: []string{"new"}},
+			wantRequests:  1,
+			wantRefreshes: 0,
+		},
+		{
+			name:          "Unchanged",
+			tokenSource:   &testTokenSource{tokens: []string{"old"}},
+			wantErr:       ErrUnauthorized,
+			wantRequests:  1,
+			wantRefreshes: 1,
+		},
+		{
+			name:          "RefreshedRejected",
+			tokenSource:   &testTokenSource{tokens: []string{"old", "other", "new"}},
+			wantErr:       ErrUnauthorized,
+			wantRequests:  2,
+			wantRefreshes: 1,
+		},
+		{
+			name:          "RefreshError",
+			tokenSource:   &testTokenSource{tokens: []string{"old", "new"}, refreshErr: errors.New("refresh error")},
+			wantErr:       ErrUnauthorized,
+			wantRequests:  1,
+			wantRefreshes: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+
+				// The body of a re-attempted request must be intact.
+				if b, err := io.ReadAll(r.Body); err != nil || string(b) != `{"name":"value"}` {
+					t.Errorf("got body %q (error: %v)", b, err)
+				}
+
+				if r.Header.Get("Authorization") != "Bearer new" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer srv.Close()
+
+			cfg := &Config{BaseURL: srv.URL, AuthToken: tt.authToken, Logger: testLogger}
+			if tt.tokenSource != nil {
+				cfg.TokenSource = tt.tokenSource
+			}
+
+			c, err := NewClient(cfg)
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			_, err = c.doPOSTRequest(context.Background(), "v1/test", map[string]string{"name": "value"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+
+			if got, want := requests, tt.wantRequests; got != want {
+				t.Errorf("got %v requests, want %v", got, want)
+			}
+			if tt.tokenSource != nil {
+				if got, want := tt.tokenSource.refreshes, tt.wantRefreshes; got != want {
+					t.Errorf("got %v refreshes, want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTokenSourceError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	errToken := errors.New("token error")
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, TokenSource: errTokenSource{errToken}, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	if _, err := c.doGETRequest(context.Background(), "v1/test"); !errors.Is(err, errToken) {
+		t.Errorf("got error %v, want %v", err, errToken)
+	}
+}
+
+// errTokenSource returns err when a token is requested.
+type errTokenSource struct{ err error }
+
+func (ts errTokenSource) Token(context.Context) (string, error) { return "", ts.err }
+
+func (ts errTokenSource) Refresh(context.Context, string) (string, error) { return "", ts.err }
@@ -0,0 +1,86 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+// defaultMaxIdleConnsPerHost is the minimum number of idle connections per host retained by the
+// transport constructed by NewClient. The default used by net/http (2) throttles the concurrent
+// downloader, since connections are closed rather than re-used once more than two parts are
+// transferred concurrently.
+const defaultMaxIdleConnsPerHost = 16
+
+// TransportConfig overrides parameters of the HTTP transport constructed when Config.HTTPClient
+// is nil.
+type TransportConfig struct {
+	// MaxIdleConnsPerHost is the maximum number of idle connections retained per host. If zero,
+	// the larger of 16 and the default download concurrency is used.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is the maximum amount of time an idle connection is retained. If zero, the
+	// net/http default is used.
+	IdleConnTimeout time.Duration
+	// TLSSessionCacheSize is the capacity of the TLS session cache, which permits TLS sessions to
+	// be resumed when connecting to the same host. If zero, a default capacity is used. Set to a
+	// negative value to disable the cache.
+	TLSSessionCacheSize int
+	// DisableHTTP2 disables HTTP/2. With HTTP/2, concurrent requests to the same host are
+	// multiplexed over a single connection, which may limit throughput from some object stores.
+	DisableHTTP2 bool
+	// DialContext specifies the function used to establish connections (if supplied), allowing
+	// the client to connect via a proxy, an in-process listener or a Unix domain socket. If nil,
+	// a net.Dialer is used.
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// newTransport returns a http.Transport tuned for concurrent transfers of concurrency parts,
+// modified according to tc (if non-nil).
+func newTransport(concurrency uint, tc *TransportConfig) *http.Transport {
+	if tc == nil {
+		tc = &TransportConfig{}
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	t.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	if n := int(concurrency); n > t.MaxIdleConnsPerHost {
+		t.MaxIdleConnsPerHost = n
+	}
+	if tc.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = tc.MaxIdleConnsPerHost
+	}
+	if t.MaxIdleConns > 0 && t.MaxIdleConns < t.MaxIdleConnsPerHost {
+		t.MaxIdleConns = t.MaxIdleConnsPerHost
+	}
+
+	if tc.DialContext != nil {
+		t.DialContext = tc.DialContext
+	}
+
+	if tc.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = tc.IdleConnTimeout
+	}
+
+	if tc.TLSSessionCacheSize >= 0 {
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		t.TLSClientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(tc.TLSSessionCacheSize)
+	}
+
+	if tc.DisableHTTP2 {
+		// A non-nil, empty map disables HTTP/2.
+		t.ForceAttemptHTTP2 = false
+		t.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+
+	return t
+}
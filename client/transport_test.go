@@ -0,0 +1,58 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_newTransport(t *testing.T) {
+	tests := []struct {
+		name                    string
+		concurrency             uint
+		tc                      *TransportConfig
+		wantMaxIdleConnsPerHost int
+		wantIdleConnTimeout     time.Duration
+		wantSessionCache        bool
+		wantHTTP2               bool
+	}{
+		{"Default", 1, nil, defaultMaxIdleConnsPerHost, 90 * time.Second, true, true},
+		{"HighConcurrency", 64, nil, 64, 90 * time.Second, true, true},
+		{"MaxIdleConnsPerHost", 64, &TransportConfig{MaxIdleConnsPerHost: 4}, 4, 90 * time.Second, true, true},
+		{"IdleConnTimeout", 1, &TransportConfig{IdleConnTimeout: time.Second}, defaultMaxIdleConnsPerHost, time.Second, true, true},
+		{"NoSessionCache", 1, &TransportConfig{TLSSessionCacheSize: -1}, defaultMaxIdleConnsPerHost, 90 * time.Second, false, true},
+		{"DisableHTTP2", 1, &TransportConfig{DisableHTTP2: true}, defaultMaxIdleConnsPerHost, 90 * time.Second, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := newTransport(tt.concurrency, tt.tc)
+
+			if got, want := tr.MaxIdleConnsPerHost, tt.wantMaxIdleConnsPerHost; got != want {
+				t.Errorf("got MaxIdleConnsPerHost %v, want %v", got, want)
+			}
+
+			if tr.MaxIdleConns < tr.MaxIdleConnsPerHost {
+				t.Errorf("MaxIdleConns (%v) less than MaxIdleConnsPerHost (%v)", tr.MaxIdleConns, tr.MaxIdleConnsPerHost)
+			}
+
+			if got, want := tr.IdleConnTimeout, tt.wantIdleConnTimeout; got != want {
+				t.Errorf("got IdleConnTimeout %v, want %v", got, want)
+			}
+
+			hasCache := tr.TLSClientConfig != nil && tr.TLSClientConfig.ClientSessionCache != nil
+			if got, want := hasCache, tt.wantSessionCache; got != want {
+				t.Errorf("got session cache %v, want %v", got, want)
+			}
+
+			http2 := tr.ForceAttemptHTTP2 && tr.TLSNextProto == nil
+			if got, want := http2, tt.wantHTTP2; got != want {
+				t.Errorf("got HTTP/2 %v, want %v", got, want)
+			}
+		})
+	}
+}
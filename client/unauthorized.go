@@ -0,0 +1,100 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+var (
+	// ErrUnauthorized is returned when the server rejects a request with HTTP status 401.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrTokenExpired is returned when the server rejects a request with HTTP status 401, and the
+	// auth token has expired. Re-authenticating is likely to resolve the error. ErrTokenExpired
+	// matches ErrUnauthorized when used with errors.Is.
+	ErrTokenExpired = fmt.Errorf("%w: auth token expired", ErrUnauthorized)
+
+	// ErrInsufficientPermission is returned when the server rejects a request with HTTP status
+	// 401, and the auth token has not expired. Re-authenticating as the same user is unlikely to
+	// resolve the error. ErrInsufficientPermission matches ErrUnauthorized when used with
+	// errors.Is.
+	ErrInsufficientPermission = fmt.Errorf("%w: insufficient permission", ErrUnauthorized)
+)
+
+// tokenExpiry returns the expiry time encoded in the "exp" claim of JWT token. If token is not a
+// JWT, or does not contain an "exp" claim, ok is false.
+func tokenExpiry(token string) (exp time.Time, ok bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Exp *json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(b, &claims); err != nil || claims.Exp == nil {
+		return time.Time{}, false
+	}
+
+	f, err := claims.Exp.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(f), 0), true
+}
+
+// unauthorizedReason returns the sentinel error describing why a request made using auth token
+// was rejected with HTTP status 401, considering the error returned by the server, if any.
+func unauthorizedReason(token string, serverErr error, now time.Time) error {
+	if token == "" {
+		return ErrUnauthorized
+	}
+
+	if exp, ok := tokenExpiry(token); ok && !now.Before(exp) {
+		return ErrTokenExpired
+	}
+
+	var jerr *jsonresp.Error
+	if errors.As(serverErr, &jerr) && strings.Contains(strings.ToLower(jerr.Message), "expired") {
+		return ErrTokenExpired
+	}
+
+	return ErrInsufficientPermission
+}
+
+// unauthorizedError returns an error describing the HTTP status 401 response res, which was
+// received in response to a request made by the client. The error wraps one of
+// ErrTokenExpired, ErrInsufficientPermission or ErrUnauthorized, along with the error returned by
+// the server, if any. The response body is consumed.
+func (c *Client) unauthorizedError(res *http.Response) error {
+	serverErr := jsonresp.ReadError(res.Body)
+
+	token, host := c.authToken, c.baseURL.Host
+	if res.Request != nil {
+		token, host = requestToken(res.Request), res.Request.URL.Host
+	}
+
+	err := unauthorizedReason(token, serverErr, time.Now())
+	if serverErr != nil {
+		err = fmt.Errorf("%w: %v", err, serverErr)
+	}
+	c.emitAuthEvent(tokenEvent(AuthEventUnauthorized, host, nil, token, err))
+	return withResponseRequestID(err, res)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+// jwtWithExpiry returns an (unsigned) JWT with an "exp" claim of exp.
+func jwtWithExpiry(exp time.Time) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." +
+		enc.EncodeToString([]byte(fmt.Sprintf(`{"sub":"1234567890","exp":%d}`, exp.Unix()))) + "."
+}
+
+func Test_tokenExpiry(t *testing.T) {
+	exp := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantExp time.Time
+		wantOK  bool
+	}{
+		{"Expiry", jwtWithExpiry(exp), exp, true},
+		{"NoExpiry", testToken, time.Time{}, false},
+		{"NotJWT", "token", time.Time{}, false},
+		{"BadPayload", "a.!.c", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tokenExpiry(tt.token)
+			if ok != tt.wantOK {
+				t.Fatalf("got ok %v, want %v", ok, tt.wantOK)
+			}
+			if !got.Equal(tt.wantExp) {
+				t.Errorf("got expiry %v, want %v", got, tt.wantExp)
+			}
+		})
+	}
+}
+
+func Test_unauthorizedReason(t *testing.T) {
+	now := time.Date(2026, time.October, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		token     string
+		serverErr error
+		want      error
+	}{
+		{"NoToken", "", nil, ErrUnauthorized},
+		{"Expired", jwtWithExpiry(now.Add(-time.Hour)), nil, ErrTokenExpired},
+		{"NotExpired", jwtWithExpiry(now.Add(time.Hour)), nil, ErrInsufficientPermission},
+		{"NoExpiry", testToken, nil, ErrInsufficientPermission},
+		{"ServerExpired", testToken, &jsonresp.Error{Code: http.StatusUnauthorized, Message: "Token Expired"}, ErrTokenExpired},
+		{"ServerOther", testToken, &jsonresp.Error{Code: http.StatusUnauthorized, Message: "access denied"}, ErrInsufficientPermission},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unauthorizedReason(tt.token, tt.serverErr, now); got != tt.want {
+				t.Errorf("got error %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		message string
+		wantErr error
+	}{
+		{"Expired", jwtWithExpiry(time.Now().Add(-time.Hour)), "", ErrTokenExpired},
+		{"ServerExpired", testToken, "token expired", ErrTokenExpired},
+		{"InsufficientPermission", testToken, "forbidden", ErrInsufficientPermission},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if tt.message == "" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				if err := jsonresp.WriteError(w, tt.message, http.StatusUnauthorized); err != nil {
+					t.Errorf("error writing JSON error: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{AuthToken: tt.token, BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			_, err = c.GetEntity(context.Background(), "entity")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+			if !errors.Is(err, ErrUnauthorized) {
+				t.Errorf("got error %v, want %v", err, ErrUnauthorized)
+			}
+		})
+	}
+}
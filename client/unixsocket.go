@@ -0,0 +1,56 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+)
+
+const (
+	// unixSocketScheme is the scheme of a base URL that identifies a library server listening on
+	// a Unix domain socket (ie. "unix:///run/library.sock").
+	unixSocketScheme = "unix"
+
+	// unixSocketHost is the host to which requests are addressed when the library server listens
+	// on a Unix domain socket. The ".invalid" TLD is reserved (RFC 2606), so the host cannot
+	// collide with that of an OCI registry or object store to which the client is redirected.
+	unixSocketHost = "unix-socket.invalid"
+)
+
+// unixSocketPath returns the path of the Unix domain socket identified by u.
+func unixSocketPath(u *url.URL) (string, error) {
+	path := u.Path
+	if u.Opaque != "" {
+		path = u.Opaque
+	}
+
+	if u.Host != "" || path == "" {
+		return "", fmt.Errorf("malformed unix socket URL %q", u)
+	}
+	return path, nil
+}
+
+// dialFunc establishes a connection to addr on the named network.
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// unixSocketDialer returns a dialFunc that connects to the Unix domain socket at path when
+// addressing unixSocketHost. Connections to other hosts are established using dial, or a
+// net.Dialer if dial is nil.
+func unixSocketDialer(path string, dial dialFunc) dialFunc {
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if host, _, err := net.SplitHostPort(addr); err == nil && host == unixSocketHost {
+			return dial(ctx, "unix", path)
+		}
+		return dial(ctx, network, addr)
+	}
+}
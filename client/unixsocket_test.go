@@ -0,0 +1,123 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func TestUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "library.sock")
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("error listening on unix socket: %v", err)
+	}
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Host, unixSocketHost; got != want {
+			t.Errorf("got host %v, want %v", got, want)
+		}
+
+		if err := jsonresp.WriteResponse(w, VersionInfo{Version: "1.0.0", APIVersion: "2.0.0"}, http.StatusOK); err != nil {
+			t.Errorf("error writing JSON response: %v", err)
+		}
+	}))
+	srv.Listener = l
+	srv.Start()
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: "unix://" + path, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	vi, err := c.GetVersion(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := vi.Version, "1.0.0"; got != want {
+		t.Errorf("got version %v, want %v", got, want)
+	}
+}
+
+func TestUnixSocketURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       *Config
+		expectErr bool
+	}{
+		{"Absolute", &Config{BaseURL: "unix:///run/library.sock"}, false},
+		{"Relative", &Config{BaseURL: "unix:library.sock"}, false},
+		{"Host", &Config{BaseURL: "unix://host/run/library.sock"}, true},
+		{"NoPath", &Config{BaseURL: "unix://"}, true},
+		{"HTTPClient", &Config{BaseURL: "unix:///run/library.sock", HTTPClient: http.DefaultClient}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(tt.cfg)
+			if tt.expectErr && err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+
+			if got, want := c.baseURL.String(), "http://"+unixSocketHost+"/"; got != want {
+				t.Errorf("got base URL %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestTransportDialContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if err := jsonresp.WriteResponse(w, VersionInfo{Version: "1.0.0"}, http.StatusOK); err != nil {
+			t.Errorf("error writing JSON response: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	var dialed []string
+
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+
+		// Connect to the test server, regardless of the address requested.
+		var d net.Dialer
+		return d.DialContext(ctx, network, srv.Listener.Addr().String())
+	}
+
+	c, err := NewClient(&Config{
+		BaseURL:   "http://library.example.com",
+		Logger:    testLogger,
+		Transport: &TransportConfig{DialContext: dial},
+	})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	if _, err := c.GetVersion(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := dialed, []string{"library.example.com:80"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got dialed addresses %v, want %v", got, want)
+	}
+}
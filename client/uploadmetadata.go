@@ -0,0 +1,141 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// maxUploadMetadataSize bounds the size of a SIF definition file or labels object read to populate
+// the metadata of an uploaded image.
+const maxUploadMetadataSize = 1024 * 1024
+
+// descriptionLabels are the labels that hold the description of an image, in order of preference.
+var descriptionLabels = []string{
+	"org.opencontainers.image.description",
+	"org.label-schema.description",
+}
+
+// uploadMetadata reads the definition file and labels of the SIF image read from r, returning a
+// description and labels for the image. Metadata that is absent or malformed is skipped. On
+// return, r is positioned at the start of the image.
+func (c *Client) uploadMetadata(r io.ReadSeeker) (description string, labels map[string]string, err error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", nil, fmt.Errorf("error seeking to start stream: %v", err)
+	}
+
+	var header bytes.Buffer
+
+	f, err := readSIFHeader(r, &header)
+	if err != nil {
+		c.logger.Logf("Unable to read image metadata: %v", err)
+	} else {
+		defer func() {
+			if err := f.UnloadContainer(); err != nil {
+				c.logger.Logf("Failed to unload container: %v", err)
+			}
+		}()
+
+		if d, err := f.GetDescriptor(sif.WithDataType(sif.DataLabels)); err == nil {
+			b, err := readUploadObject(r, d)
+			if err != nil {
+				return "", nil, err
+			}
+			if err := json.Unmarshal(b, &labels); err != nil {
+				c.logger.Logf("Unable to parse image labels: %v", err)
+				labels = nil
+			}
+		}
+
+		for _, l := range descriptionLabels {
+			if v := strings.TrimSpace(labels[l]); v != "" {
+				description = v
+				break
+			}
+		}
+
+		if d, err := f.GetDescriptor(sif.WithDataType(sif.DataDeffile)); err == nil && description == "" {
+			b, err := readUploadObject(r, d)
+			if err != nil {
+				return "", nil, err
+			}
+			description = deffileDescription(b)
+		}
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", nil, fmt.Errorf("error seeking to start stream: %v", err)
+	}
+	return description, labels, nil
+}
+
+// readUploadObject reads the content of the data object described by d from r.
+func readUploadObject(r io.ReadSeeker, d sif.Descriptor) ([]byte, error) {
+	if d.Size() > maxUploadMetadataSize {
+		return nil, nil
+	}
+	if _, err := r.Seek(d.Offset(), io.SeekStart); err != nil {
+		return nil, fmt.Errorf("error seeking to SIF object %v: %v", d.ID(), err)
+	}
+
+	b := make([]byte, d.Size())
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, fmt.Errorf("error reading SIF object %v: %v", d.ID(), err)
+	}
+	return b, nil
+}
+
+// deffileDescription returns a description of an image derived from its definition file. The
+// first line of the %help section is preferred, followed by the bootstrap agent and source image
+// from the header.
+func deffileDescription(b []byte) string {
+	var bootstrap, from string
+
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for section := ""; s.Scan(); {
+		line := strings.TrimSpace(s.Text())
+
+		if strings.HasPrefix(line, "%") {
+			if section == "%help" {
+				break
+			}
+			section = strings.ToLower(strings.Fields(line)[0])
+			continue
+		}
+
+		switch section {
+		case "":
+			// Header keywords precede the first section.
+			if k, v, ok := strings.Cut(line, ":"); ok {
+				switch strings.ToLower(strings.TrimSpace(k)) {
+				case "bootstrap":
+					bootstrap = strings.TrimSpace(v)
+				case "from":
+					from = strings.TrimSpace(v)
+				}
+			}
+		case "%help":
+			if line != "" {
+				return line
+			}
+		}
+	}
+
+	switch {
+	case bootstrap != "" && from != "":
+		return fmt.Sprintf("Bootstrap: %v, From: %v", bootstrap, from)
+	case bootstrap != "":
+		return fmt.Sprintf("Bootstrap: %v", bootstrap)
+	}
+	return ""
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+const testDeffile = `Bootstrap: docker
+From: alpine:3.20
+
+%post
+    echo "From: nowhere"
+
+%help
+
+    A test image.
+    More help.
+
+%labels
+    Author test
+`
+
+func Test_deffileDescription(t *testing.T) {
+	tests := []struct {
+		name    string
+		deffile string
+		want    string
+	}{
+		{"Help", testDeffile, "A test image."},
+		{"NoHelp", "Bootstrap: docker\nFrom: alpine:3.20\n\n%post\n    From: nowhere\n", "Bootstrap: docker, From: alpine:3.20"},
+		{"BootstrapOnly", "bootstrap: scratch\n", "Bootstrap: scratch"},
+		{"EmptyHelp", "Bootstrap: docker\nFrom: alpine\n%help\n\n%post\n", "Bootstrap: docker, From: alpine"},
+		{"Empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deffileDescription([]byte(tt.deffile)); got != tt.want {
+				t.Errorf("got description %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// testMetadataSIF returns a SIF image containing a definition file and labels object, if specified.
+func testMetadataSIF(t *testing.T, deffile, labels string) []byte {
+	t.Helper()
+
+	var dis []sif.DescriptorInput
+	for _, o := range []struct {
+		t sif.DataType
+		s string
+	}{
+		{sif.DataDeffile, deffile},
+		{sif.DataLabels, labels},
+	} {
+		if o.s == "" {
+			continue
+		}
+		di, err := sif.NewDescriptorInput(o.t, strings.NewReader(o.s))
+		if err != nil {
+			t.Fatalf("error creating descriptor input: %v", err)
+		}
+		dis = append(dis, di)
+	}
+
+	var b sif.Buffer
+	f, err := sif.CreateContainer(&b, sif.OptCreateDeterministic(), sif.OptCreateWithDescriptors(dis...))
+	if err != nil {
+		t.Fatalf("error creating SIF: %v", err)
+	}
+	if err := f.UnloadContainer(); err != nil {
+		t.Fatalf("error unloading SIF: %v", err)
+	}
+	return b.Bytes()
+}
+
+func Test_uploadMetadata(t *testing.T) {
+	tests := []struct {
+		name            string
+		image           []byte
+		wantDescription string
+		wantLabels      map[string]string
+	}{
+		{
+			name:            "DescriptionLabel",
+			image:           testMetadataSIF(t, testDeffile, `{"org.label-schema.description":"Labelled image","Author":"test"}`),
+			wantDescription: "Labelled image",
+			wantLabels:      map[string]string{"org.label-schema.description": "Labelled image", "Author": "test"},
+		},
+		{
+			name:            "Deffile",
+			image:           testMetadataSIF(t, testDeffile, `{"Author":"test"}`),
+			wantDescription: "A test image.",
+			wantLabels:      map[string]string{"Author": "test"},
+		},
+		{
+			name:            "MalformedLabels",
+			image:           testMetadataSIF(t, testDeffile, `not json`),
+			wantDescription: "A test image.",
+		},
+		{
+			name:  "NoMetadata",
+			image: testSIF(t, "object"),
+		},
+		{
+			name:  "NotSIF",
+			image: []byte("not a SIF image"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(&Config{Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			r := bytes.NewReader(tt.image)
+
+			description, labels, err := c.uploadMetadata(r)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got, want := description, tt.wantDescription; got != want {
+				t.Errorf("got description %q, want %q", got, want)
+			}
+			if got, want := labels, tt.wantLabels; !reflect.DeepEqual(got, want) {
+				t.Errorf("got labels %v, want %v", got, want)
+			}
+
+			if pos, _ := r.Seek(0, io.SeekCurrent); pos != 0 {
+				t.Errorf("got position %v, want 0", pos)
+			}
+		})
+	}
+}
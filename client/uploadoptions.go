@@ -0,0 +1,206 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// UploadOptions specifies options applied to image uploads. Defaults are specified by
+// Config.Upload, and may be overridden for a single upload by passing UploadOption values to
+// UploadImage.
+type UploadOptions struct {
+	// DisableCreateMissing disables creation of the entity, collection and container of an image
+	// when they do not exist. By default, missing namespaces are created. Set in strict
+	// environments, so that a mistyped path is not mistaken for a new namespace.
+	DisableCreateMissing bool
+	// Private marks a container created by the upload as private. Containers that already exist
+	// are not modified.
+	Private bool
+	// DescriptionTemplate is a text/template used to construct the description of the image (if
+	// supplied). The template is executed with an UploadDescription.
+	DescriptionTemplate string
+	// SkipDedup disables the check for an image with the same checksum that has already been
+	// uploaded, so that the image content is always transferred.
+	SkipDedup bool
+	// ChecksumAlgorithms computed over the image prior to upload (if supplied). If nil, the
+	// algorithms specified by Config.ChecksumAlgorithms are used.
+	ChecksumAlgorithms []ChecksumAlgorithm
+	// RequireMultipart disables the fallback to the (legacy) single part uploader when the library
+	// server does not support multipart uploads, so that server misconfiguration is surfaced as a
+	// *MultipartUnsupportedError, rather than uploading large images in a single request. Images
+	// too small to be uploaded in multiple parts are unaffected.
+	RequireMultipart bool
+	// VerifyBlobs protects uploads to OCI registries that garbage collect untagged blobs, which
+	// may otherwise remove blobs during a long upload. The image blob and configuration are
+	// verified to be present in the registry before the image manifest is pushed, and again
+	// before each tag is applied. A tag is not applied if a blob is missing; instead, a
+	// *MissingBlobError is reported for it.
+	VerifyBlobs bool
+	// HashReadAhead is the number of buffers of the image read ahead of checksum computation. When
+	// non-zero, the image is read while checksums of previously read buffers are computed, and
+	// each checksum is computed independently of the others, reducing the time taken to checksum
+	// large images on fast storage. Slower storage benefits less from reading ahead. If zero, the
+	// image is not read ahead.
+	HashReadAhead int
+	// HashBufferSize is the size of each buffer read ahead of checksum computation. If zero,
+	// defaultHashBufferSize is used.
+	HashBufferSize int64
+}
+
+// defaultHashBufferSize is the size of each buffer read ahead of checksum computation, unless
+// specified by UploadOptions.HashBufferSize.
+const defaultHashBufferSize = 4 * 1024 * 1024
+
+// UploadDescription is the data with which UploadOptions.DescriptionTemplate is executed.
+type UploadDescription struct {
+	// Path of the image (ie. "entity/collection/container").
+	Path string
+	// Arch is the architecture of the image.
+	Arch string
+	// Tags applied to the image.
+	Tags []string
+	// Description specified to UploadImage or, if empty and Config.DescribeUploads is set, the
+	// description derived from the image metadata.
+	Description string
+	// Labels read from the SIF image (if present).
+	Labels map[string]string
+}
+
+// UploadOption overrides an upload option for a single upload.
+type UploadOption func(*UploadOptions)
+
+// OptUploadCreateMissing specifies whether missing entities, collections and containers are
+// created.
+func OptUploadCreateMissing(b bool) UploadOption {
+	return func(o *UploadOptions) {
+		o.DisableCreateMissing = !b
+	}
+}
+
+// OptUploadPrivate specifies whether a container created by the upload is private.
+func OptUploadPrivate(b bool) UploadOption {
+	return func(o *UploadOptions) {
+		o.Private = b
+	}
+}
+
+// OptUploadDescriptionTemplate specifies a text/template used to construct the description of
+// the image. The template is executed with an UploadDescription.
+func OptUploadDescriptionTemplate(text string) UploadOption {
+	return func(o *UploadOptions) {
+		o.DescriptionTemplate = text
+	}
+}
+
+// OptUploadSkipDedup specifies whether the image content is transferred even if an image with the
+// same checksum has already been uploaded.
+func OptUploadSkipDedup(b bool) UploadOption {
+	return func(o *UploadOptions) {
+		o.SkipDedup = b
+	}
+}
+
+// OptUploadChecksumAlgorithms specifies the checksum algorithms computed over the image prior to
+// upload. The SHA256 checksum is always computed, as it identifies the image.
+func OptUploadChecksumAlgorithms(algs ...ChecksumAlgorithm) UploadOption {
+	return func(o *UploadOptions) {
+		o.ChecksumAlgorithms = algs
+	}
+}
+
+// OptUploadRequireMultipart specifies whether the fallback to the single part uploader is
+// disabled when the library server does not support multipart uploads.
+func OptUploadRequireMultipart(b bool) UploadOption {
+	return func(o *UploadOptions) {
+		o.RequireMultipart = b
+	}
+}
+
+// OptUploadVerifyBlobs specifies whether the presence of blobs in the OCI registry is verified
+// before the image manifest is pushed, and before each tag is applied.
+func OptUploadVerifyBlobs(b bool) UploadOption {
+	return func(o *UploadOptions) {
+		o.VerifyBlobs = b
+	}
+}
+
+// OptUploadHashReadAhead specifies the number of buffers, of bufferSize bytes, of the image read
+// ahead of checksum computation prior to upload. If bufferSize is zero, a default size is used.
+func OptUploadHashReadAhead(n int, bufferSize int64) UploadOption {
+	return func(o *UploadOptions) {
+		o.HashReadAhead = n
+		o.HashBufferSize = bufferSize
+	}
+}
+
+// uploadOptions returns the options for an upload, applying opts to the defaults of the client,
+// and validates them. The checksum algorithms of the returned options are resolved to those
+// computed prior to upload.
+func (c *Client) uploadOptions(opts []UploadOption) (UploadOptions, error) {
+	o := c.upload
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if text := o.DescriptionTemplate; text != "" {
+		if _, err := parseDescriptionTemplate(text); err != nil {
+			return UploadOptions{}, fmt.Errorf("invalid upload options: %w", err)
+		}
+	}
+
+	if o.HashReadAhead < 0 {
+		return UploadOptions{}, fmt.Errorf("invalid upload options: negative hash read-ahead %d", o.HashReadAhead)
+	}
+	if o.HashBufferSize < 0 {
+		return UploadOptions{}, fmt.Errorf("invalid upload options: negative hash buffer size %d", o.HashBufferSize)
+	}
+
+	if o.ChecksumAlgorithms == nil {
+		o.ChecksumAlgorithms = c.checksumAlgorithms
+	} else {
+		algs, err := uploadChecksumAlgorithms(o.ChecksumAlgorithms)
+		if err != nil {
+			return UploadOptions{}, fmt.Errorf("invalid upload options: %w", err)
+		}
+		o.ChecksumAlgorithms = algs
+	}
+
+	return o, nil
+}
+
+// parseDescriptionTemplate parses the description template text.
+func parseDescriptionTemplate(text string) (*template.Template, error) {
+	t, err := template.New("description").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing description template: %w", err)
+	}
+	return t, nil
+}
+
+// templateDescription returns the description of the image read from r, constructed by executing
+// the description template text with d. The labels of d are read from the image. On return, r is
+// positioned at the start of the image.
+func (c *Client) templateDescription(r io.ReadSeeker, text string, d UploadDescription) (string, error) {
+	t, err := parseDescriptionTemplate(text)
+	if err != nil {
+		return "", err
+	}
+
+	if _, d.Labels, err = c.uploadMetadata(r); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if err := t.Execute(&sb, d); err != nil {
+		return "", fmt.Errorf("error executing description template: %w", err)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func Test_uploadOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       *UploadOptions
+		opts      []UploadOption
+		want      UploadOptions
+		expectErr bool
+	}{
+		{
+			name: "Defaults",
+			want: UploadOptions{ChecksumAlgorithms: defaultChecksumAlgorithms},
+		},
+		{
+			name: "Config",
+			cfg:  &UploadOptions{Private: true},
+			want: UploadOptions{Private: true, ChecksumAlgorithms: defaultChecksumAlgorithms},
+		},
+		{
+			name: "Options",
+			opts: []UploadOption{
+				OptUploadCreateMissing(false),
+				OptUploadPrivate(true),
+				OptUploadDescriptionTemplate("{{.Description}}"),
+				OptUploadSkipDedup(true),
+				OptUploadChecksumAlgorithms(ChecksumCRC32C),
+			},
+			want: UploadOptions{
+				DisableCreateMissing: true,
+				Private:              true,
+				DescriptionTemplate:  "{{.Description}}",
+				SkipDedup:            true,
+				ChecksumAlgorithms:   []ChecksumAlgorithm{ChecksumSHA256, ChecksumCRC32C},
+			},
+		},
+		{
+			name: "OptionsOverrideConfig",
+			cfg:  &UploadOptions{DisableCreateMissing: true, Private: true},
+			opts: []UploadOption{OptUploadCreateMissing(true), OptUploadPrivate(false)},
+			want: UploadOptions{ChecksumAlgorithms: defaultChecksumAlgorithms},
+		},
+		{
+			name:      "BadTemplate",
+			opts:      []UploadOption{OptUploadDescriptionTemplate("{{.Description")},
+			expectErr: true,
+		},
+		{
+			name: "HashReadAhead",
+			opts: []UploadOption{OptUploadHashReadAhead(8, 1<<20)},
+			want: UploadOptions{ChecksumAlgorithms: defaultChecksumAlgorithms, HashReadAhead: 8, HashBufferSize: 1 << 20},
+		},
+		{
+			name: "VerifyBlobs",
+			opts: []UploadOption{OptUploadVerifyBlobs(true)},
+			want: UploadOptions{ChecksumAlgorithms: defaultChecksumAlgorithms, VerifyBlobs: true},
+		},
+		{
+			name: "RequireMultipart",
+			opts: []UploadOption{OptUploadRequireMultipart(true)},
+			want: UploadOptions{ChecksumAlgorithms: defaultChecksumAlgorithms, RequireMultipart: true},
+		},
+		{
+			name:      "NegativeHashReadAhead",
+			opts:      []UploadOption{OptUploadHashReadAhead(-1, 0)},
+			expectErr: true,
+		},
+		{
+			name:      "NegativeHashBufferSize",
+			opts:      []UploadOption{OptUploadHashReadAhead(1, -1)},
+			expectErr: true,
+		},
+		{
+			name:      "BadChecksumAlgorithm",
+			opts:      []UploadOption{OptUploadChecksumAlgorithms("sha1")},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(&Config{Logger: testLogger, Upload: tt.cfg})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			got, err := c.uploadOptions(tt.opts)
+			if tt.expectErr && err == nil {
+				t.Fatal("unexpected success")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got options %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClientUploadOptions(t *testing.T) {
+	if _, err := NewClient(&Config{Upload: &UploadOptions{DescriptionTemplate: "{{"}}); err == nil {
+		t.Error("unexpected success")
+	}
+}
+
+func Test_templateDescription(t *testing.T) {
+	image := testMetadataSIF(t, "", `{"org.opencontainers.image.version": "1.2.3"}`)
+
+	c, err := NewClient(&Config{Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	d := UploadDescription{
+		Path:        "entity/collection/container",
+		Arch:        "amd64",
+		Tags:        []string{"latest", "v1"},
+		Description: "Container",
+	}
+
+	const text = `{{.Description}} {{index .Labels "org.opencontainers.image.version"}} ({{.Arch}}:{{range .Tags}} {{.}}{{end}})`
+
+	r := bytes.NewReader(image)
+
+	got, err := c.templateDescription(r, text, d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := "Container 1.2.3 (amd64: latest v1)"; got != want {
+		t.Errorf("got description %q, want %q", got, want)
+	}
+
+	if pos, _ := r.Seek(0, io.SeekCurrent); pos != 0 {
+		t.Errorf("got position %v, want 0", pos)
+	}
+
+	// Fields not present in UploadDescription cause execution to fail.
+	if _, err := c.templateDescription(bytes.NewReader(image), "{{.Version}}", d); err == nil {
+		t.Error("unexpected success")
+	}
+}
+
+func Test_ensureContainer(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        UploadOptions
+		wantErr     error
+		wantCreated bool
+		wantPrivate bool
+	}{
+		{"CreateMissing", UploadOptions{}, nil, true, false},
+		{"CreatePrivate", UploadOptions{Private: true}, nil, true, true},
+		{"NotFound", UploadOptions{DisableCreateMissing: true}, ErrNotFound, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *Container
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body interface{}
+
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/v1/entities/entity":
+					body = Entity{ID: "entityID", Name: "entity"}
+				case r.Method == http.MethodGet && r.URL.Path == "/v1/collections/entity/collection":
+					body = Collection{ID: "collectionID", Name: "collection"}
+				case r.Method == http.MethodGet && r.URL.Path == "/v1/containers/entity/collection/container":
+					w.WriteHeader(http.StatusNotFound)
+					return
+				case r.Method == http.MethodPost && r.URL.Path == "/v1/containers":
+					created = &Container{}
+					if err := json.NewDecoder(r.Body).Decode(created); err != nil {
+						t.Errorf("error decoding request: %v", err)
+					}
+					body = Container{ID: "containerID", Name: created.Name}
+				default:
+					t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				if err := jsonresp.WriteResponse(w, body, http.StatusOK); err != nil {
+					t.Errorf("error writing JSON response: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			container, err := c.ensureContainer(context.Background(), "entity", "collection", "container", tt.opts)
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if got, want := created != nil, tt.wantCreated; got != want {
+				t.Fatalf("got created %v, want %v", got, want)
+			}
+			if created == nil {
+				return
+			}
+
+			if got, want := created.Private, tt.wantPrivate; got != want {
+				t.Errorf("got private %v, want %v", got, want)
+			}
+			if got, want := container.ID, "containerID"; got != want {
+				t.Errorf("got container ID %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+// UploadPhase identifies a phase of an upload to an OCI registry.
+type UploadPhase string
+
+const (
+	// PhaseBlob is the upload of the image blob. It is skipped if the blob is already present.
+	PhaseBlob UploadPhase = "blob"
+	// PhaseConfig is the upload of the image configuration.
+	PhaseConfig UploadPhase = "config"
+	// PhaseManifest is the upload of the image manifest.
+	PhaseManifest UploadPhase = "manifest"
+	// PhaseSignature is the upload of a detached signature, if one is requested.
+	PhaseSignature UploadPhase = "signature"
+	// PhaseIndex is the upload of the image index for a tag. It is entered once per tag.
+	PhaseIndex UploadPhase = "index"
+)
+
+// PhaseUploadCallback is an UploadCallback that is also notified as an upload to an OCI registry
+// moves between phases. The phases following the image blob transfer little data, but may take
+// noticeable time against a slow registry, so implementations may use OnPhase to indicate that the
+// upload is being finalized.
+type PhaseUploadCallback interface {
+	UploadCallback
+
+	// OnPhase is called as phase p is entered. For PhaseIndex, tag is the tag being applied;
+	// otherwise it is empty.
+	OnPhase(p UploadPhase, tag string)
+}
+
+// onPhase notifies callback that phase p is entered, if callback is a PhaseUploadCallback.
+func onPhase(callback UploadCallback, p UploadPhase, tag string) {
+	if pc, ok := callback.(PhaseUploadCallback); ok {
+		pc.OnPhase(p, tag)
+	}
+}
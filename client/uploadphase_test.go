@@ -0,0 +1,130 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// phaseUploadCallback records the phases of an upload.
+type phaseUploadCallback struct {
+	defaultUploadCallback
+	phases []string
+}
+
+func (c *phaseUploadCallback) OnPhase(p UploadPhase, tag string) {
+	if tag != "" {
+		c.phases = append(c.phases, string(p)+":"+tag)
+		return
+	}
+	c.phases = append(c.phases, string(p))
+}
+
+// newTestOCIPushServer returns a server that acts as both a library server granting direct OCI
+// registry access to repository "name", and the OCI registry itself. Blobs are reported as present
+// in the registry if blobExists is non-nil and returns true when called.
+func newTestOCIPushServer(t *testing.T, blobExists func() bool) *httptest.Server {
+	t.Helper()
+
+	const sessionPath = "/v2/name/blobs/uploads/session"
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+
+	mux.HandleFunc("/v1/oci-redirect", func(w http.ResponseWriter, _ *http.Request) {
+		if err := json.NewEncoder(w).Encode(map[string]string{"token": "token", "url": srv.URL, "name": "name"}); err != nil {
+			t.Errorf("error encoding response: %v", err)
+		}
+	})
+	mux.HandleFunc("/v2/name/blobs/", func(w http.ResponseWriter, r *http.Request) {
+		if blobExists == nil || !blobExists() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", strings.TrimPrefix(r.URL.Path, "/v2/name/blobs/"))
+	})
+	mux.HandleFunc("/v2/name/blobs/uploads/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Location", sessionPath)
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc(sessionPath, func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.Copy(io.Discard, r.Body); err != nil {
+			t.Errorf("error reading request body: %v", err)
+		}
+		if r.Method == http.MethodPut {
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.Header().Set("Location", sessionPath)
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc("/v2/name/manifests/", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	return srv
+}
+
+func Test_ociUploadImagePhases(t *testing.T) {
+	image := testPartitionSIF(t, "amd64")
+	hash := strings.Replace(digest.FromBytes(image).String(), ":", ".", 1)
+
+	tests := []struct {
+		name       string
+		blobExists bool
+		tags       []string
+		want       []string
+	}{
+		{
+			name: "Upload",
+			tags: []string{"latest", "v1"},
+			want: []string{"blob", "config", "manifest", "index:latest", "index:v1"},
+		},
+		{
+			name:       "BlobExists",
+			blobExists: true,
+			tags:       []string{"latest"},
+			want:       []string{"config", "manifest", "index:latest"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestOCIPushServer(t, func() bool { return tt.blobExists })
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			cb := &phaseUploadCallback{}
+
+			_, err = c.ociUploadImage(context.Background(), bytes.NewReader(image), int64(len(image)), "name", "amd64", tt.tags, "", nil, hash, UploadOptions{}, cb)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got, want := cb.phases, tt.want; !reflect.DeepEqual(got, want) {
+				t.Errorf("got phases %v, want %v", got, want)
+			}
+		})
+	}
+}
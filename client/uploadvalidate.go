@@ -0,0 +1,73 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrInvalidImage is returned when upload validation is enabled, and the image to be uploaded is
+// not a well-formed SIF image of the declared architecture.
+var ErrInvalidImage = errors.New("invalid SIF image")
+
+// validateUploadImage verifies that the image read from r is a well-formed SIF image, containing a
+// primary system partition of architecture arch. On success, r is positioned at the start of the
+// image.
+func (c *Client) validateUploadImage(r io.ReadSeeker, arch string) error {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("error seeking to end of stream: %v", err)
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("error seeking to start stream: %v", err)
+	}
+
+	var header bytes.Buffer
+
+	f, err := readSIFHeader(r, &header)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidImage, err)
+	}
+	defer func() {
+		if err := f.UnloadContainer(); err != nil {
+			c.logger.Logf("Failed to unload container: %v", err)
+		}
+	}()
+
+	ds, err := f.GetDescriptors()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidImage, err)
+	}
+	for _, d := range ds {
+		if d.Offset() < 0 || d.Size() < 0 || d.Offset()+d.Size() > size {
+			return fmt.Errorf("%w: object %v exceeds image size", ErrInvalidImage, d.ID())
+		}
+	}
+
+	encrypted, err := getEncrypted(f)
+	if err != nil {
+		return fmt.Errorf("%w: error reading primary system partition: %w", ErrInvalidImage, err)
+	}
+
+	signed, err := getSigned(f)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidImage, err)
+	}
+
+	if got := f.PrimaryArch(); got != arch {
+		return fmt.Errorf("%w: image architecture %v does not match %v", ErrInvalidImage, got, arch)
+	}
+
+	c.logger.Logf("Validated SIF image (arch: %v, signed: %v, encrypted: %v)", f.PrimaryArch(), signed, encrypted)
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("error seeking to start stream: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// testPartitionSIF returns a SIF image containing a primary system partition of architecture arch.
+func testPartitionSIF(t *testing.T, arch string) []byte {
+	t.Helper()
+
+	di, err := sif.NewDescriptorInput(sif.DataPartition, strings.NewReader("partition"),
+		sif.OptPartitionMetadata(sif.FsSquash, sif.PartPrimSys, arch),
+	)
+	if err != nil {
+		t.Fatalf("error creating descriptor input: %v", err)
+	}
+
+	var b sif.Buffer
+	f, err := sif.CreateContainer(&b, sif.OptCreateDeterministic(), sif.OptCreateWithDescriptors(di))
+	if err != nil {
+		t.Fatalf("error creating SIF: %v", err)
+	}
+	if err := f.UnloadContainer(); err != nil {
+		t.Fatalf("error unloading SIF: %v", err)
+	}
+	return b.Bytes()
+}
+
+func Test_validateUploadImage(t *testing.T) {
+	image := testPartitionSIF(t, "amd64")
+
+	tests := []struct {
+		name    string
+		image   []byte
+		arch    string
+		wantErr bool
+	}{
+		{"Valid", image, "amd64", false},
+		{"ArchMismatch", image, "arm64", true},
+		{"NoPrimaryPartition", testSIF(t, "object"), "amd64", true},
+		{"NotSIF", []byte(strings.Repeat("x", 2*sifHeaderSize)), "amd64", true},
+		{"Truncated", image[:len(image)-1], "amd64", true},
+		{"Empty", nil, "amd64", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(&Config{Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			r := bytes.NewReader(tt.image)
+			if _, err := r.Seek(3, io.SeekStart); err != nil {
+				t.Fatal(err)
+			}
+
+			err = c.validateUploadImage(r, tt.arch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidImage) {
+					t.Errorf("got error %v, want %v", err, ErrInvalidImage)
+				}
+				return
+			}
+
+			if pos, _ := r.Seek(0, io.SeekCurrent); pos != 0 {
+				t.Errorf("got position %v, want 0", pos)
+			}
+		})
+	}
+}
+
+func TestUploadImageValidateUploads(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger, ValidateUploads: true})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	image := testPartitionSIF(t, "amd64")
+
+	_, err = c.UploadImage(context.Background(), bytes.NewReader(image), "entity/collection/container", "arm64", []string{"latest"}, "", nil)
+	if !errors.Is(err, ErrInvalidImage) {
+		t.Errorf("got error %v, want %v", err, ErrInvalidImage)
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// GetCollectionUsage returns a report of the storage consumed by the collection identified by ref
+// (ie. "entity/collection"), broken down by container and architecture. Returns ErrNotFound if
+// the collection is not found.
+func (c *Client) GetCollectionUsage(ctx context.Context, ref string) (*CollectionUsage, error) {
+	b, err := c.apiGet(ctx, "v1/collections/"+ref+"/_usage")
+	if err != nil {
+		return nil, err
+	}
+	var res CollectionUsageResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("error decoding collection usage: %v", err)
+	}
+	return &res.Data, nil
+}
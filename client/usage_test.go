@@ -0,0 +1,81 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func TestGetCollectionUsage(t *testing.T) {
+	lastAccessed := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	want := CollectionUsage{
+		UsageSummary: UsageSummary{Size: 3072, ImageCount: 3, LastAccessed: lastAccessed},
+		Containers: []ContainerUsage{
+			{
+				UsageSummary: UsageSummary{Size: 3072, ImageCount: 3, LastAccessed: lastAccessed},
+				Name:         "container",
+				Arches: map[string]UsageSummary{
+					"amd64": {Size: 2048, ImageCount: 2, LastAccessed: lastAccessed},
+					"arm64": {Size: 1024, ImageCount: 1},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		code    int
+		wantErr error
+	}{
+		{"OK", http.StatusOK, nil},
+		{"NotFound", http.StatusNotFound, ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got, want := r.URL.Path, "/v1/collections/entity/collection/_usage"; got != want {
+					t.Errorf("got path %v, want %v", got, want)
+				}
+
+				if tt.code != http.StatusOK {
+					w.WriteHeader(tt.code)
+					return
+				}
+				if err := jsonresp.WriteResponse(w, &want, http.StatusOK); err != nil {
+					t.Errorf("error writing JSON response: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+			if err != nil {
+				t.Fatalf("error initializing client: %v", err)
+			}
+
+			got, err := c.GetCollectionUsage(context.Background(), "entity/collection")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(*got, want) {
+				t.Errorf("got usage %+v, want %+v", *got, want)
+			}
+		})
+	}
+}
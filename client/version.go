@@ -18,6 +18,9 @@ const (
 	APIVersionV2Upload = "2.0.0-alpha.1"
 	// APIVersionV2ArchTags supports extended arch tags functionality.
 	APIVersionV2ArchTags = "2.0.0-alpha.2"
+	// APIVersionV2SHA256Upload identifies uploaded images by SHA256 checksum alone, so the MD5
+	// checksum of an image is not required.
+	APIVersionV2SHA256Upload = "2.1.0"
 )
 
 // VersionInfo contains version information.
@@ -29,12 +32,27 @@ type VersionInfo struct {
 // GetVersion gets version information from the Cloud-Library Service. The context controls the lifetime of
 // the request.
 func (c *Client) GetVersion(ctx context.Context) (vi VersionInfo, err error) {
+	// Identical concurrent requests (ie. API version probes) are coalesced.
+	v, err := coalesce(ctx, &c.inflightRequests, http.MethodGet+" version", func() (interface{}, error) {
+		return c.getVersion(ctx)
+	})
+	if err != nil {
+		return VersionInfo{}, err
+	}
+	return v.(VersionInfo), nil
+}
+
+// getVersion returns version information from the library server.
+func (c *Client) getVersion(ctx context.Context) (vi VersionInfo, err error) {
+	ctx, cancel := c.metadataContext(ctx)
+	defer cancel()
+
 	req, err := c.newRequest(ctx, http.MethodGet, "version", "", nil)
 	if err != nil {
 		return VersionInfo{}, err
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return VersionInfo{}, err
 	}
@@ -48,23 +66,43 @@ func (c *Client) GetVersion(ctx context.Context) (vi VersionInfo, err error) {
 
 // apiAtLeast returns true if cloud-library server supports requested (or greater) API version
 func (c *Client) apiAtLeast(ctx context.Context, reqVersion string) bool {
+	v, ok := c.apiVersion(ctx)
+	if !ok {
+		return false
+	}
+	minRequiredVers, err := semver.Make(reqVersion)
+	if err != nil {
+		c.logger.Logf("Unable to decode minimum required version: %v", err)
+		return false
+	}
+	return v.GTE(minRequiredVers)
+}
+
+// apiBetween returns true if cloud-library server supports API version minVersion (or greater),
+// but not maxVersion.
+func (c *Client) apiBetween(ctx context.Context, minVersion, maxVersion string) bool {
+	v, ok := c.apiVersion(ctx)
+	if !ok {
+		return false
+	}
+	return v.GTE(semver.MustParse(minVersion)) && v.LT(semver.MustParse(maxVersion))
+}
+
+// apiVersion returns the API version supported by the cloud-library server. If the version cannot
+// be determined, ok is false.
+func (c *Client) apiVersion(ctx context.Context) (v semver.Version, ok bool) {
 	// query cloud-library server for supported api version
 	vi, err := c.GetVersion(ctx)
 	if err != nil || vi.APIVersion == "" {
 		// unable to get cloud-library server API version, fallback to lowest
 		// common denominator
 		c.logger.Logf("Unable to determine remote API version: %v", err)
-		return false
+		return semver.Version{}, false
 	}
-	v, err := semver.Make(vi.APIVersion)
+	v, err = semver.Make(vi.APIVersion)
 	if err != nil {
 		c.logger.Logf("Unable to decode remote API version: %v", err)
-		return false
+		return semver.Version{}, false
 	}
-	minRequiredVers, err := semver.Make(reqVersion)
-	if err != nil {
-		c.logger.Logf("Unable to decode minimum required version: %v", err)
-		return false
-	}
-	return v.GTE(minRequiredVers)
+	return v, true
 }
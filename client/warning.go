@@ -0,0 +1,166 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ServerWarning describes warning and deprecation notices returned by the library server in the
+// "Warning", "Deprecation", "Sunset" and "Link" response headers.
+type ServerWarning struct {
+	// Method and Path of the request that resulted in the warning.
+	Method, Path string
+	// Messages contains the text of each "Warning" header.
+	Messages []string
+	// Deprecated is true if the server indicated the endpoint is deprecated.
+	Deprecated bool
+	// Sunset is the time after which the endpoint may become unavailable, or the zero time if not
+	// specified.
+	Sunset time.Time
+	// Link is a URL describing the deprecation or sunset, if specified.
+	Link string
+}
+
+// WarningHandler is called with warning and deprecation notices returned by the library server.
+type WarningHandler func(ServerWarning)
+
+// parseServerWarning parses the warning and deprecation headers present in res. The boolean
+// return value is false if no warning or deprecation headers are present.
+func parseServerWarning(res *http.Response) (ServerWarning, bool) {
+	w := ServerWarning{}
+
+	for _, v := range res.Header.Values("Warning") {
+		if text := parseWarningText(v); text != "" {
+			w.Messages = append(w.Messages, text)
+		}
+	}
+
+	// The "Deprecation" header contains "true", or the date of deprecation.
+	if v := res.Header.Get("Deprecation"); v != "" && v != "false" {
+		w.Deprecated = true
+	}
+
+	if v := res.Header.Get("Sunset"); v != "" {
+		if t, err := http.ParseTime(v); err == nil {
+			w.Sunset = t
+		}
+	}
+
+	if len(w.Messages) == 0 && !w.Deprecated && w.Sunset.IsZero() {
+		return ServerWarning{}, false
+	}
+
+	for _, v := range res.Header.Values("Link") {
+		if link, ok := parseDeprecationLink(v); ok {
+			w.Link = link
+			break
+		}
+	}
+
+	if req := res.Request; req != nil {
+		w.Method = req.Method
+		w.Path = req.URL.Path
+	}
+
+	return w, true
+}
+
+// parseWarningText returns the warn-text of a "Warning" header value of the form
+// `<warn-code> <warn-agent> "<warn-text>" ["<warn-date>"]`. If the value is not of that form, it
+// is returned unmodified.
+func parseWarningText(v string) string {
+	start := strings.IndexByte(v, '"')
+	if start < 0 {
+		return strings.TrimSpace(v)
+	}
+
+	end := strings.IndexByte(v[start+1:], '"')
+	if end < 0 {
+		return strings.TrimSpace(v)
+	}
+	return v[start+1 : start+1+end]
+}
+
+// parseDeprecationLink returns the URL of a "Link" header value with relation type "deprecation"
+// or "sunset" (ie. `<https://example.com/deprecation>; rel="deprecation"`).
+func parseDeprecationLink(v string) (string, bool) {
+	for _, link := range strings.Split(v, ",") {
+		parts := strings.Split(link, ";")
+
+		target := strings.TrimSpace(parts[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+
+		for _, param := range parts[1:] {
+			k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || !strings.EqualFold(k, "rel") {
+				continue
+			}
+
+			switch strings.ToLower(strings.Trim(v, `"`)) {
+			case "deprecation", "sunset":
+				return strings.Trim(target, "<>"), true
+			}
+		}
+	}
+	return "", false
+}
+
+// relayServerWarning relays warning and deprecation notices present in res (if any) via the
+// logger and warning handler. Each notice is logged once per Client, since it is likely to be
+// repeated for every request made to the endpoint.
+func (c *Client) relayServerWarning(res *http.Response) {
+	w, ok := parseServerWarning(res)
+	if !ok {
+		return
+	}
+
+	key := strings.Join(append([]string{w.Method, w.Path, w.Sunset.String()}, w.Messages...), "\n")
+	if _, loaded := c.warnings.LoadOrStore(key, struct{}{}); loaded {
+		return
+	}
+
+	for _, m := range w.Messages {
+		c.logger.Logf("Warning from server (%v %v): %v", w.Method, w.Path, m)
+	}
+	if w.Deprecated {
+		c.logger.Logf("Warning: endpoint %v %v is deprecated", w.Method, w.Path)
+	}
+	if !w.Sunset.IsZero() {
+		c.logger.Logf("Warning: endpoint %v %v will be retired after %v", w.Method, w.Path, w.Sunset.Format(time.RFC1123))
+	}
+	if w.Link != "" {
+		c.logger.Logf("See %v for details", w.Link)
+	}
+
+	if c.warningHandler != nil {
+		c.warningHandler(w)
+	}
+}
+
+// do sends a request to the library server, relaying any warnings present in the response.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res, err = c.retryUnauthorized(c.httpClient, req, res); err != nil {
+		return nil, err
+	}
+
+	c.relayServerWarning(res)
+
+	if err := c.decodeResponse(res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_parseServerWarning(t *testing.T) {
+	sunset := time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   ServerWarning
+		wantOK bool
+	}{
+		{
+			name:   "None",
+			header: http.Header{},
+			wantOK: false,
+		},
+		{
+			name:   "Warning",
+			header: http.Header{"Warning": {`299 - "Deprecated API" "Wed, 21 Oct 2026 07:28:00 GMT"`, "unstructured"}},
+			want:   ServerWarning{Method: http.MethodGet, Path: "/v1/test", Messages: []string{"Deprecated API", "unstructured"}},
+			wantOK: true,
+		},
+		{
+			name: "DeprecationSunset",
+			header: http.Header{
+				"Deprecation": {"true"},
+				"Sunset":      {sunset.Format(http.TimeFormat)},
+				"Link":        {`<https://example.com/next>; rel="next", <https://example.com/deprecation>; rel="deprecation"`},
+			},
+			want:   ServerWarning{Method: http.MethodGet, Path: "/v1/test", Deprecated: true, Sunset: sunset, Link: "https://example.com/deprecation"},
+			wantOK: true,
+		},
+		{
+			name:   "NotDeprecated",
+			header: http.Header{"Deprecation": {"false"}, "Link": {`<https://example.com/deprecation>; rel="deprecation"`}},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &http.Response{
+				Header:  tt.header,
+				Request: &http.Request{Method: http.MethodGet, URL: &url.URL{Path: "/v1/test"}},
+			}
+
+			got, ok := parseServerWarning(res)
+			if ok != tt.wantOK {
+				t.Fatalf("got ok %v, want %v", ok, tt.wantOK)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got warning %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWarningHandler(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Warning", `299 - "v1 endpoints will be retired"`)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"data":{"version":"1.0.0","apiVersion":"2.0.0"}}`))
+	}))
+	defer srv.Close()
+
+	var warnings []ServerWarning
+
+	c, err := NewClient(&Config{
+		BaseURL: srv.URL,
+		Logger:  testLogger,
+		WarningHandler: func(w ServerWarning) {
+			warnings = append(warnings, w)
+		},
+	})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	// Warnings should be relayed once, regardless of the number of requests made.
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetVersion(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if got, want := len(warnings), 1; got != want {
+		t.Fatalf("got %v warnings, want %v", got, want)
+	}
+	if got, want := warnings[0].Messages, []string{"v1 endpoints will be retired"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got messages %v, want %v", got, want)
+	}
+	if got, want := warnings[0].Path, "/version"; got != want {
+		t.Errorf("got path %v, want %v", got, want)
+	}
+}
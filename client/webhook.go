@@ -0,0 +1,147 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// WebhookSignatureHeader is the HTTP header containing the signature of a webhook payload, of
+	// the form "sha256=<hex>".
+	WebhookSignatureHeader = "X-Library-Signature"
+
+	// maxWebhookPayloadSize is the maximum size of a webhook payload read by ReadWebhookPayload.
+	maxWebhookPayloadSize = 1024 * 1024
+)
+
+// ErrInvalidWebhookSignature is returned when the signature of a webhook payload is missing or
+// does not match the payload.
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// webhooksPath returns the API path of the webhooks of the collection identified by ref.
+func webhooksPath(ref string) string {
+	return "v1/collections/" + strings.TrimPrefix(ref, "/") + "/_webhooks"
+}
+
+// CreateCollectionWebhook registers a webhook, notified of the events of the collection identified
+// by ref (ie. "entity/collection") specified by wr. The secret used to sign payloads is returned
+// in the Secret field of the result, and cannot be retrieved later.
+func (c *Client) CreateCollectionWebhook(ctx context.Context, ref string, wr WebhookRequest) (*Webhook, error) {
+	u, err := url.Parse(wr.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL %q", wr.URL)
+	}
+
+	if len(wr.Events) == 0 {
+		return nil, errors.New("at least one webhook event is required")
+	}
+	for _, e := range wr.Events {
+		switch e {
+		case WebhookEventTag, WebhookEventImage, WebhookEventDelete:
+		default:
+			return nil, fmt.Errorf("invalid webhook event %q", e)
+		}
+	}
+
+	b, err := c.apiCreate(ctx, webhooksPath(ref), wr)
+	if err != nil {
+		return nil, fmt.Errorf("error creating webhook: %w", err)
+	}
+	var res WebhookResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("error decoding webhook: %v", err)
+	}
+	return &res.Data, nil
+}
+
+// ListCollectionWebhooks returns the webhooks registered for the collection identified by ref (ie.
+// "entity/collection"). Secrets are not returned.
+func (c *Client) ListCollectionWebhooks(ctx context.Context, ref string) ([]Webhook, error) {
+	b, err := c.apiGet(ctx, webhooksPath(ref))
+	if err != nil {
+		return nil, err
+	}
+	var res WebhooksResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("error decoding webhooks: %v", err)
+	}
+	return res.Data, nil
+}
+
+// DeleteCollectionWebhook deletes the webhook identified by id from the collection identified by
+// ref (ie. "entity/collection"). Returns ErrNotFound if the webhook is not found.
+func (c *Client) DeleteCollectionWebhook(ctx context.Context, ref, id string) error {
+	if id == "" {
+		return errors.New("webhook ID is required")
+	}
+
+	if _, err := c.doDeleteRequest(ctx, webhooksPath(ref)+"/"+url.PathEscape(id)); err != nil {
+		return fmt.Errorf("error deleting webhook: %w", err)
+	}
+	return nil
+}
+
+// SignWebhookPayload returns the signature of payload using secret, in the form of the value of
+// WebhookSignatureHeader.
+func SignWebhookPayload(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature verifies that signature, the value of WebhookSignatureHeader, is the
+// signature of payload using secret. If not, an error wrapping ErrInvalidWebhookSignature is
+// returned.
+func VerifyWebhookSignature(payload []byte, secret, signature string) error {
+	alg, sum, ok := strings.Cut(signature, "=")
+	if !ok || alg != "sha256" {
+		return fmt.Errorf("%w: unsupported signature %q", ErrInvalidWebhookSignature, signature)
+	}
+
+	got, err := hex.DecodeString(sum)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWebhookSignature, err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidWebhookSignature
+	}
+	return nil
+}
+
+// ReadWebhookPayload reads the payload delivered to a webhook by r, verifies its signature using
+// secret, and decodes it. An error wrapping ErrInvalidWebhookSignature is returned if the
+// signature is missing or invalid.
+func ReadWebhookPayload(r *http.Request, secret string) (*WebhookPayload, error) {
+	b, err := io.ReadAll(limitResponse(r.Body, maxWebhookPayloadSize))
+	if err != nil {
+		return nil, fmt.Errorf("error reading webhook payload: %w", err)
+	}
+
+	if err := VerifyWebhookSignature(b, secret, r.Header.Get(WebhookSignatureHeader)); err != nil {
+		return nil, err
+	}
+
+	var p WebhookPayload
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, fmt.Errorf("error decoding webhook payload: %w", err)
+	}
+	return &p, nil
+}
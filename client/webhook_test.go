@@ -0,0 +1,154 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE.md file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	jsonresp "github.com/sylabs/json-resp"
+)
+
+func TestCollectionWebhooks(t *testing.T) {
+	var hooks []Webhook
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/collections/entity/collection/_webhooks":
+			var body WebhookRequest
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("error decoding request: %v", err)
+			}
+			hooks = append(hooks, Webhook{ID: "hookID", URL: body.URL, Events: body.Events})
+
+			if err := jsonresp.WriteResponse(w, Webhook{ID: "hookID", URL: body.URL, Events: body.Events, Secret: "secret"}, http.StatusCreated); err != nil {
+				t.Errorf("error writing JSON response: %v", err)
+			}
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/collections/entity/collection/_webhooks":
+			if err := jsonresp.WriteResponse(w, hooks, http.StatusOK); err != nil {
+				t.Errorf("error writing JSON response: %v", err)
+			}
+		case r.Method == http.MethodDelete && r.URL.Path == "/v1/collections/entity/collection/_webhooks/hookID" && len(hooks) > 0:
+			hooks = nil
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(&Config{BaseURL: srv.URL, Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	ctx := context.Background()
+	events := []WebhookEvent{WebhookEventTag, WebhookEventDelete}
+
+	hook, err := c.CreateCollectionWebhook(ctx, "entity/collection", WebhookRequest{URL: "https://example.com/hook", Events: events})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := hook.Secret, "secret"; got != want {
+		t.Errorf("got secret %v, want %v", got, want)
+	}
+
+	list, err := c.ListCollectionWebhooks(ctx, "entity/collection")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := list, []Webhook{{ID: "hookID", URL: "https://example.com/hook", Events: events}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got webhooks %+v, want %+v", got, want)
+	}
+
+	if err := c.DeleteCollectionWebhook(ctx, "entity/collection", "hookID"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.DeleteCollectionWebhook(ctx, "entity/collection", "hookID"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestCreateCollectionWebhookInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		wr   WebhookRequest
+	}{
+		{"NoURL", WebhookRequest{Events: []WebhookEvent{WebhookEventTag}}},
+		{"BadScheme", WebhookRequest{URL: "ftp://example.com", Events: []WebhookEvent{WebhookEventTag}}},
+		{"NoEvents", WebhookRequest{URL: "https://example.com"}},
+		{"BadEvent", WebhookRequest{URL: "https://example.com", Events: []WebhookEvent{"push"}}},
+	}
+
+	c, err := NewClient(&Config{Logger: testLogger})
+	if err != nil {
+		t.Fatalf("error initializing client: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.CreateCollectionWebhook(context.Background(), "entity/collection", tt.wr); err == nil {
+				t.Error("unexpected success")
+			}
+		})
+	}
+}
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	payload := []byte(`{"event":"tag"}`)
+	sig := SignWebhookPayload(payload, "secret")
+
+	tests := []struct {
+		name      string
+		payload   []byte
+		secret    string
+		signature string
+		wantErr   error
+	}{
+		{"OK", payload, "secret", sig, nil},
+		{"WrongSecret", payload, "other", sig, ErrInvalidWebhookSignature},
+		{"WrongPayload", []byte(`{"event":"delete"}`), "secret", sig, ErrInvalidWebhookSignature},
+		{"Missing", payload, "secret", "", ErrInvalidWebhookSignature},
+		{"Algorithm", payload, "secret", "sha1=00", ErrInvalidWebhookSignature},
+		{"BadHex", payload, "secret", "sha256=zz", ErrInvalidWebhookSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := VerifyWebhookSignature(tt.payload, tt.secret, tt.signature), tt.wantErr; !errors.Is(got, want) {
+				t.Errorf("got error %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestReadWebhookPayload(t *testing.T) {
+	payload := []byte(`{"id":"eventID","event":"tag","container":"entity/collection/container","tag":"latest"}`)
+
+	r := httptest.NewRequest(http.MethodPost, "/hook", bytes.NewReader(payload))
+	r.Header.Set(WebhookSignatureHeader, SignWebhookPayload(payload, "secret"))
+
+	p, err := ReadWebhookPayload(r, "secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := WebhookPayload{ID: "eventID", Event: WebhookEventTag, Container: "entity/collection/container", Tag: "latest"}
+	if !reflect.DeepEqual(*p, want) {
+		t.Errorf("got payload %+v, want %+v", *p, want)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/hook", bytes.NewReader(payload))
+	if _, err := ReadWebhookPayload(r, "secret"); !errors.Is(err, ErrInvalidWebhookSignature) {
+		t.Errorf("got error %v, want %v", err, ErrInvalidWebhookSignature)
+	}
+}